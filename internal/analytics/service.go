@@ -0,0 +1,282 @@
+package analytics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"ptchampion/internal/grading"
+	"ptchampion/internal/logging"
+	"ptchampion/internal/store"
+)
+
+// Series intervals
+const (
+	IntervalDay   = "day"
+	IntervalWeek  = "week"
+	IntervalMonth = "month"
+)
+
+// Projected metrics
+const (
+	MetricMaxReps   = "max_reps"
+	MetricMeanGrade = "mean_grade"
+	MetricRunPace   = "run_pace" // Seconds per mile
+)
+
+const (
+	defaultWindow = 4
+	maxWindow     = 52
+)
+
+// runMiles is the distance of a logged run: the 2-mile event of the standards that score it.
+const runMiles = 2
+
+// ErrInvalidInterval is returned for an interval other than day, week or month.
+var ErrInvalidInterval = errors.New("invalid interval")
+
+// ProgressOptions selects the series of a progress report.
+type ProgressOptions struct {
+	Interval     string     // day, week or month; default week
+	ExerciseType string     // Limit to one exercise type; empty for all
+	Window       int        // Buckets in the moving averages; default 4
+	TargetDate   *time.Time // Project trends to this date; nil for no projection
+}
+
+// SeriesPoint is one time bucket of an exercise series. Measurements are nil for buckets
+// without workouts; moving averages are nil when their window has no workouts.
+type SeriesPoint struct {
+	Start     time.Time
+	Workouts  int
+	MaxReps   *int32
+	Volume    int64
+	MeanGrade *float64
+	RunPace   *float64 // Seconds per mile, for runs
+
+	MaxRepsMovingAverage   *float64
+	MeanGradeMovingAverage *float64
+	RunPaceMovingAverage   *float64
+}
+
+// Projection is a least-squares linear trend of a metric, extended to the target date.
+type Projection struct {
+	Metric      string
+	SlopePerDay float64
+	Projected   float64 // Trend value at the target date
+	TargetDate  time.Time
+	Samples     int // Buckets the trend was fitted to
+}
+
+// ExerciseSeries is the progress of one exercise type.
+type ExerciseSeries struct {
+	ExerciseType string
+	Points       []*SeriesPoint
+	Projections  []*Projection // Performance metric and grade, when there are enough samples
+}
+
+// ProgressReport is a user's progress across exercise types.
+type ProgressReport struct {
+	Interval    string
+	Window      int
+	Series      []*ExerciseSeries
+	GeneratedAt time.Time
+}
+
+// Service defines the interface for workout analytics.
+type Service interface {
+	GetProgress(ctx context.Context, userID int32, opts ProgressOptions) (*ProgressReport, error)
+}
+
+type service struct {
+	analyticsStore store.AnalyticsStore
+	logger         logging.Logger
+}
+
+// NewService creates a new analytics service instance.
+func NewService(analyticsStore store.AnalyticsStore, logger logging.Logger) Service {
+	return &service{
+		analyticsStore: analyticsStore,
+		logger:         logger,
+	}
+}
+
+// GetProgress returns the user's workout series from their first workout to now, with
+// moving averages and, when a target date is given, trend projections.
+func (s *service) GetProgress(ctx context.Context, userID int32, opts ProgressOptions) (*ProgressReport, error) {
+	if opts.Interval == "" {
+		opts.Interval = IntervalWeek
+	}
+	switch opts.Interval {
+	case IntervalDay, IntervalWeek, IntervalMonth:
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrInvalidInterval, opts.Interval)
+	}
+	if opts.Window < 1 || opts.Window > maxWindow {
+		opts.Window = defaultWindow
+	}
+
+	buckets, err := s.analyticsStore.GetWorkoutTimeSeries(ctx, userID, opts.Interval, opts.ExerciseType)
+	if err != nil {
+		s.logger.Error(ctx, "Failed to get workout time series", "userID", userID, "interval", opts.Interval, "error", err)
+		return nil, fmt.Errorf("failed to retrieve workout time series: %w", err)
+	}
+
+	report := &ProgressReport{
+		Interval:    opts.Interval,
+		Window:      opts.Window,
+		Series:      buildSeries(buckets, opts.Window),
+		GeneratedAt: time.Now(),
+	}
+	if opts.TargetDate != nil {
+		for _, series := range report.Series {
+			series.Projections = projectSeries(series, *opts.TargetDate)
+		}
+	}
+	return report, nil
+}
+
+// buildSeries groups the buckets by exercise type and computes trailing moving averages
+// over the last window buckets.
+func buildSeries(buckets []*store.WorkoutSeriesBucket, window int) []*ExerciseSeries {
+	var out []*ExerciseSeries
+	var current *ExerciseSeries
+	for _, b := range buckets {
+		if current == nil || current.ExerciseType != b.ExerciseType {
+			current = &ExerciseSeries{ExerciseType: b.ExerciseType}
+			out = append(out, current)
+		}
+		point := &SeriesPoint{
+			Start:     b.Start,
+			Workouts:  b.Workouts,
+			MaxReps:   b.MaxReps,
+			Volume:    b.Volume,
+			MeanGrade: b.MeanGrade,
+		}
+		if isRun(b.ExerciseType) && b.MeanDurationSeconds != nil {
+			pace := *b.MeanDurationSeconds / runMiles
+			point.RunPace = &pace
+		}
+		current.Points = append(current.Points, point)
+	}
+
+	for _, series := range out {
+		for i, point := range series.Points {
+			from := i - window + 1
+			if from < 0 {
+				from = 0
+			}
+			trailing := series.Points[from : i+1]
+			point.MaxRepsMovingAverage = meanOf(trailing, maxRepsValue)
+			point.MeanGradeMovingAverage = meanOf(trailing, meanGradeValue)
+			point.RunPaceMovingAverage = meanOf(trailing, runPaceValue)
+		}
+	}
+	return out
+}
+
+func isRun(exerciseType string) bool {
+	return exerciseType == "running" || exerciseType == grading.ExerciseTypeRun
+}
+
+func maxRepsValue(p *SeriesPoint) *float64 {
+	if p.MaxReps == nil {
+		return nil
+	}
+	v := float64(*p.MaxReps)
+	return &v
+}
+
+func meanGradeValue(p *SeriesPoint) *float64 { return p.MeanGrade }
+
+func runPaceValue(p *SeriesPoint) *float64 { return p.RunPace }
+
+// meanOf averages the values present in the points, or returns nil when there are none.
+func meanOf(points []*SeriesPoint, value func(*SeriesPoint) *float64) *float64 {
+	var total float64
+	var n int
+	for _, p := range points {
+		if v := value(p); v != nil {
+			total += *v
+			n++
+		}
+	}
+	if n == 0 {
+		return nil
+	}
+	mean := total / float64(n)
+	return &mean
+}
+
+// projectSeries fits trends to the series' performance metric (run pace for runs, max reps
+// otherwise) and mean grade.
+func projectSeries(series *ExerciseSeries, target time.Time) []*Projection {
+	var projections []*Projection
+	if isRun(series.ExerciseType) {
+		if p := project(series.Points, runPaceValue, MetricRunPace, target); p != nil {
+			projections = append(projections, p)
+		}
+	} else if p := project(series.Points, maxRepsValue, MetricMaxReps, target); p != nil {
+		projections = append(projections, p)
+	}
+	if p := project(series.Points, meanGradeValue, MetricMeanGrade, target); p != nil {
+		if p.Projected > 100 {
+			p.Projected = 100
+		}
+		projections = append(projections, p)
+	}
+	return projections
+}
+
+// project fits a least-squares line to the metric over time and evaluates it at the
+// target date. It returns nil with fewer than two buckets with a value.
+func project(points []*SeriesPoint, value func(*SeriesPoint) *float64, metric string, target time.Time) *Projection {
+	var xs, ys []float64
+	for _, p := range points {
+		if v := value(p); v != nil {
+			xs = append(xs, daysSince(points[0].Start, p.Start))
+			ys = append(ys, *v)
+		}
+	}
+	if len(xs) < 2 {
+		return nil
+	}
+
+	slope, intercept, ok := LinearFit(xs, ys)
+	if !ok {
+		return nil
+	}
+	projected := math.Max(0, intercept+slope*daysSince(points[0].Start, target))
+	return &Projection{
+		Metric:      metric,
+		SlopePerDay: slope,
+		Projected:   projected,
+		TargetDate:  target,
+		Samples:     len(xs),
+	}
+}
+
+func daysSince(origin, t time.Time) float64 {
+	return t.Sub(origin).Hours() / 24
+}
+
+// LinearFit returns the least-squares slope and intercept of ys over xs. It reports false
+// when the xs do not vary.
+func LinearFit(xs, ys []float64) (slope, intercept float64, ok bool) {
+	n := float64(len(xs))
+	var sumX, sumY, sumXY, sumXX float64
+	for i := range xs {
+		sumX += xs[i]
+		sumY += ys[i]
+		sumXY += xs[i] * ys[i]
+		sumXX += xs[i] * xs[i]
+	}
+	denom := n*sumXX - sumX*sumX
+	if denom == 0 {
+		return 0, 0, false
+	}
+	slope = (n*sumXY - sumX*sumY) / denom
+	intercept = (sumY - slope*sumX) / n
+	return slope, intercept, true
+}
@@ -0,0 +1,79 @@
+package analytics
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"ptchampion/internal/store"
+)
+
+func int32Ptr(v int32) *int32 { return &v }
+
+func float64Ptr(v float64) *float64 { return &v }
+
+func week(n int) time.Time {
+	return time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC).AddDate(0, 0, 7*n)
+}
+
+func TestBuildSeries(t *testing.T) {
+	buckets := []*store.WorkoutSeriesBucket{
+		{ExerciseType: "pushup", Start: week(0), Workouts: 1, MaxReps: int32Ptr(40), Volume: 40, MeanGrade: float64Ptr(60)},
+		{ExerciseType: "pushup", Start: week(1)}, // No workouts
+		{ExerciseType: "pushup", Start: week(2), Workouts: 2, MaxReps: int32Ptr(50), Volume: 90, MeanGrade: float64Ptr(70)},
+		{ExerciseType: "pushup", Start: week(3), Workouts: 1, MaxReps: int32Ptr(60), Volume: 60, MeanGrade: float64Ptr(80)},
+		{ExerciseType: "running", Start: week(0), Workouts: 1, MeanGrade: float64Ptr(70), MeanDurationSeconds: float64Ptr(900)},
+	}
+
+	series := buildSeries(buckets, 2)
+	if len(series) != 2 {
+		t.Fatalf("got %d series, want 2", len(series))
+	}
+
+	pushups := series[0].Points
+	wantMovingAverages := []*float64{float64Ptr(40), float64Ptr(40), float64Ptr(50), float64Ptr(55)}
+	for i, want := range wantMovingAverages {
+		got := pushups[i].MaxRepsMovingAverage
+		if got == nil || *got != *want {
+			t.Errorf("week %d max reps moving average = %v, want %v", i, got, *want)
+		}
+	}
+	if pushups[1].MeanGrade != nil || pushups[1].Workouts != 0 {
+		t.Errorf("empty week = %+v, want no measurements", pushups[1])
+	}
+
+	run := series[1].Points[0]
+	if run.RunPace == nil || *run.RunPace != 450 {
+		t.Errorf("run pace = %v, want 450 seconds per mile", run.RunPace)
+	}
+	if run.MaxRepsMovingAverage != nil {
+		t.Errorf("run max reps moving average = %v, want nil", *run.MaxRepsMovingAverage)
+	}
+}
+
+func TestProjectSeries(t *testing.T) {
+	series := &ExerciseSeries{ExerciseType: "pushup", Points: []*SeriesPoint{
+		{Start: week(0), MaxReps: int32Ptr(40), MeanGrade: float64Ptr(60)},
+		{Start: week(1)},
+		{Start: week(2), MaxReps: int32Ptr(54), MeanGrade: float64Ptr(80)},
+	}}
+
+	projections := projectSeries(series, week(4))
+	if len(projections) != 2 {
+		t.Fatalf("got %d projections, want 2", len(projections))
+	}
+
+	reps := projections[0]
+	if reps.Metric != MetricMaxReps || reps.Samples != 2 || math.Abs(reps.SlopePerDay-1) > 1e-9 || math.Abs(reps.Projected-68) > 1e-9 {
+		t.Errorf("max reps projection = %+v, want slope 1/day projecting 68", reps)
+	}
+	grade := projections[1]
+	if grade.Metric != MetricMeanGrade || grade.Projected != 100 {
+		t.Errorf("grade projection = %+v, want capped at 100", grade)
+	}
+
+	single := &ExerciseSeries{ExerciseType: "pushup", Points: []*SeriesPoint{{Start: week(0), MaxReps: int32Ptr(40)}}}
+	if got := projectSeries(single, week(4)); len(got) != 0 {
+		t.Errorf("single sample projections = %d, want 0", len(got))
+	}
+}
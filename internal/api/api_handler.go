@@ -24,8 +24,8 @@ type ApiHandler struct {
 	// exerciseHandler    *handlers.ExerciseHandler // REMOVED - exercise handler deleted
 	leaderboardHandler *handlers.LeaderboardHandler
 	workoutHandler     *handlers.WorkoutHandler
-	// syncHandler    *handlers.SyncHandler // Assuming you might have this
-	genericHandler *handlers.Handler // For PostSync and FeaturesHandler, if they remain on generic handler
+	syncHandler        *handlers.SyncHandler
+	genericHandler     *handlers.Handler // For FeaturesHandler, if it remains on generic handler
 }
 
 // Ensure ApiHandler implements ServerInterface (compile-time check)
@@ -58,8 +58,8 @@ func NewApiHandler(cfg *config.Config, mainStore *db.Store, logger logging.Logge
 	// and creates its own internal tokenService.
 	userService := users.NewUserService(mainStore, leaderboardCache, logger)
 	// exerciseService := exercises.NewService(mainStore, logger) // REMOVED - no exercise handler
-	leaderboardService := leaderboards.NewService(mainStore, logger)
-	workoutService := workouts.NewService(mainStore, mainStore, logger) // WorkoutStore and ExerciseStore
+	leaderboardService := leaderboards.NewService(mainStore, mainStore, logger)
+	workoutService := workouts.NewService(mainStore, mainStore, mainStore, mainStore, leaderboardCache, logger) // WorkoutStore, ExerciseStore, UserStore and PersonalRecordStore
 
 	// Create location service
 	locationService := users.NewLocationService(mainStore.Queries, leaderboardCache, logger)
@@ -72,6 +72,7 @@ func NewApiHandler(cfg *config.Config, mainStore *db.Store, logger logging.Logge
 	// exerciseHandler := handlers.NewExerciseHandler(exerciseService, logger) // REMOVED
 	leaderboardHandler := handlers.NewLeaderboardHandler(leaderboardService, logger)
 	workoutHandler := handlers.NewWorkoutHandler(workoutService, logger)
+	syncHandler := handlers.NewSyncHandler(workoutService, logger)
 	genericHandler := handlers.NewHandler(cfg, mainStore.Queries, logger) // For legacy/generic handlers
 
 	return &ApiHandler{
@@ -82,6 +83,7 @@ func NewApiHandler(cfg *config.Config, mainStore *db.Store, logger logging.Logge
 		// exerciseHandler:    exerciseHandler, // REMOVED
 		leaderboardHandler: leaderboardHandler,
 		workoutHandler:     workoutHandler,
+		syncHandler:        syncHandler,
 		genericHandler:     genericHandler,
 	}
 }
@@ -119,7 +121,7 @@ func (h *ApiHandler) GetLeaderboardExerciseType(ctx echo.Context, exerciseType G
 
 func (h *ApiHandler) PostSync(ctx echo.Context) error {
 	// Call the updated handler method directly
-	return h.genericHandler.PostSync(ctx)
+	return h.syncHandler.PostSync(ctx)
 }
 
 func (h *ApiHandler) PatchUsersMe(ctx echo.Context) error {
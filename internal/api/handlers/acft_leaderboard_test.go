@@ -0,0 +1,320 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+
+	"ptchampion/internal/leaderboards"
+	"ptchampion/internal/logging"
+	"ptchampion/internal/store"
+	"ptchampion/internal/workouts"
+)
+
+// acftExercises are the stored exercises of the six ACFT events, by ID.
+var acftExercises = map[int32]*store.Exercise{
+	1: {ID: 1, Name: "Deadlift", Type: "deadlift"},
+	2: {ID: 2, Name: "Standing Power Throw", Type: "power_throw"},
+	3: {ID: 3, Name: "Hand-Release Push-up", Type: "hand_release_pushup"},
+	4: {ID: 4, Name: "Sprint-Drag-Carry", Type: "sprint_drag_carry"},
+	5: {ID: 5, Name: "Plank", Type: "plank"},
+	6: {ID: 6, Name: "Run", Type: "running"},
+}
+
+// memoryStore keeps workouts in memory for the handler tests. Methods the tests do not
+// reach fall through to the nil store.Store and panic.
+type memoryStore struct {
+	store.Store
+
+	mu          sync.Mutex
+	workouts    []*store.WorkoutRecord
+	awards      []store.AchievementAward
+	syncBatches []*store.WorkoutSyncBatch
+}
+
+func (m *memoryStore) GetExerciseDefinition(ctx context.Context, exerciseID int32) (*store.Exercise, error) {
+	exercise, ok := acftExercises[exerciseID]
+	if !ok {
+		return nil, store.ErrExerciseNotFound
+	}
+	return exercise, nil
+}
+
+func (m *memoryStore) GetUserByID(ctx context.Context, id string) (*store.User, error) {
+	return nil, store.ErrUserNotFound
+}
+
+func (m *memoryStore) CreateWorkoutRecordWithPersonalRecords(ctx context.Context, record *store.WorkoutRecord, candidates []store.PersonalRecordCandidate) (*store.WorkoutRecord, []*store.PersonalRecord, error) {
+	return m.addWorkout(record), nil, nil
+}
+
+func (m *memoryStore) CreateTestSession(ctx context.Context, session *store.TestSession) (*store.TestSession, error) {
+	for i, event := range session.Events {
+		session.Events[i] = m.addWorkout(event)
+	}
+	session.ID = 1
+	return session, nil
+}
+
+func (m *memoryStore) addWorkout(record *store.WorkoutRecord) *store.WorkoutRecord {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stored := *record
+	stored.ID = int32(len(m.workouts) + 1)
+	m.workouts = append(m.workouts, &stored)
+	return &stored
+}
+
+// GetAchievementStats counts the user's workouts only
+func (m *memoryStore) GetAchievementStats(ctx context.Context, userID int32) (*store.AchievementStats, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stats := &store.AchievementStats{}
+	for _, w := range m.workouts {
+		if w.UserID == userID {
+			stats.TotalWorkouts++
+		}
+	}
+	return stats, nil
+}
+
+func (m *memoryStore) ListUserAchievements(ctx context.Context, userID int32) ([]*store.Achievement, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	achievements := make([]*store.Achievement, len(m.awards))
+	for i, award := range m.awards {
+		achievements[i] = &store.Achievement{UserID: userID, BadgeID: award.BadgeID}
+	}
+	return achievements, nil
+}
+
+func (m *memoryStore) AwardAchievements(ctx context.Context, userID int32, workout *store.WorkoutRecord, awards []store.AchievementAward) ([]*store.Achievement, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	created := make([]*store.Achievement, len(awards))
+	for i, award := range awards {
+		m.awards = append(m.awards, award)
+		created[i] = &store.Achievement{UserID: userID, BadgeID: award.BadgeID}
+	}
+	return created, nil
+}
+
+// GetGlobalAggregateLeaderboardByTypes sums each user's best grade per exercise type
+// under the standard, ranking only users with a workout of every type
+func (m *memoryStore) GetGlobalAggregateLeaderboardByTypes(ctx context.Context, exerciseTypes []string, scoringStandard string, limit int, startDate time.Time, endDate time.Time) ([]*store.LeaderboardEntry, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	best := make(map[int32]map[string]int32)
+	for _, w := range m.workouts {
+		if w.ScoringStandard != scoringStandard {
+			continue
+		}
+		grade := w.Grade
+		if w.ExpectedGrade != nil {
+			grade = *w.ExpectedGrade
+		}
+		if best[w.UserID] == nil {
+			best[w.UserID] = make(map[string]int32)
+		}
+		if grade > best[w.UserID][w.ExerciseType] {
+			best[w.UserID][w.ExerciseType] = grade
+		}
+	}
+
+	var entries []*store.LeaderboardEntry
+	for userID, grades := range best {
+		entry := &store.LeaderboardEntry{UserID: strconv.Itoa(int(userID)), Username: "user" + strconv.Itoa(int(userID))}
+		complete := true
+		for _, exerciseType := range exerciseTypes {
+			grade, ok := grades[exerciseType]
+			complete = complete && ok
+			entry.Score += grade
+		}
+		if complete {
+			entries = append(entries, entry)
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Score > entries[j].Score })
+	if len(entries) > limit {
+		entries = entries[:limit]
+	}
+	return entries, nil
+}
+
+type testValidator struct {
+	validator *validator.Validate
+}
+
+func (v *testValidator) Validate(i interface{}) error {
+	return v.validator.Struct(i)
+}
+
+// newACFTTestServer wires the workout and leaderboard handlers to an in-memory store.
+// No feature flags are set on requests.
+func newACFTTestServer() (*echo.Echo, *WorkoutHandler, *LeaderboardHandler, *memoryStore) {
+	logger := logging.NewDefaultLogger()
+	s := &memoryStore{}
+
+	e := echo.New()
+	e.Validator = &testValidator{validator: validator.New()}
+	workoutHandler := NewWorkoutHandler(workouts.NewService(s, s, s, s, nil, logger), logger)
+	leaderboardHandler := NewLeaderboardHandler(leaderboards.NewService(s, s, logger), logger)
+	return e, workoutHandler, leaderboardHandler, s
+}
+
+// serve runs a handler as the user with the request body encoded as JSON.
+func serve(t *testing.T, e *echo.Echo, handler echo.HandlerFunc, userID int32, method, target string, body interface{}) *httptest.ResponseRecorder {
+	t.Helper()
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			t.Fatalf("failed to encode request: %v", err)
+		}
+	}
+	req := httptest.NewRequest(method, target, bytes.NewReader(payload))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.Set("user_id", userID)
+
+	if err := handler(c); err != nil {
+		e.HTTPErrorHandler(err, c)
+	}
+	return rec
+}
+
+// getACFTBoard reads the global ACFT leaderboard.
+func getACFTBoard(t *testing.T, e *echo.Echo, h *LeaderboardHandler) []LeaderboardAPIEntry {
+	t.Helper()
+	rec := serve(t, e, h.GetGlobalACFTLeaderboard, 1, http.MethodGet, "/leaderboards/global/acft", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("ACFT board status = %d, body %s", rec.Code, rec.Body.String())
+	}
+	var entries []LeaderboardAPIEntry
+	if err := json.Unmarshal(rec.Body.Bytes(), &entries); err != nil {
+		t.Fatalf("failed to decode ACFT board: %v", err)
+	}
+	return entries
+}
+
+func TestScoringStandardEnabledWithoutFlag(t *testing.T) {
+	e := echo.New()
+	for _, id := range []string{"", "apft-2013", "acft-2022", "usmc-pft-2022", "unknown"} {
+		c := e.NewContext(httptest.NewRequest(http.MethodPost, "/", nil), httptest.NewRecorder())
+		if !scoringStandardEnabled(c, id) {
+			t.Errorf("scoringStandardEnabled(%q) = false without the flag, want true", id)
+		}
+	}
+}
+
+func TestACFTBoardWithoutGradingFlag(t *testing.T) {
+	e, workoutHandler, leaderboardHandler, _ := newACFTTestServer()
+	completed := time.Now().Add(-time.Hour)
+
+	events := []LogWorkoutRequest{
+		{ExerciseID: 1, WeightLbs: int32Ptr(250), Grade: 80},
+		{ExerciseID: 2, DistanceMeters: float64Ptr(9.5), Grade: 80},
+		{ExerciseID: 3, Reps: int32Ptr(40), Grade: 80},
+		{ExerciseID: 4, DurationSeconds: int32Ptr(120), Grade: 80},
+		{ExerciseID: 5, DurationSeconds: int32Ptr(180), Grade: 80},
+		{ExerciseID: 6, DurationSeconds: int32Ptr(960), Grade: 80, ScoringStandard: leaderboards.ACFTStandardID},
+	}
+	for _, event := range events {
+		event.CompletedAt = completed
+		event.IsPublic = true
+		rec := serve(t, e, workoutHandler.LogWorkout, 7, http.MethodPost, "/workouts", event)
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("logging exercise %d: status = %d, body %s", event.ExerciseID, rec.Code, rec.Body.String())
+		}
+	}
+
+	entries := getACFTBoard(t, e, leaderboardHandler)
+	if len(entries) != 1 {
+		t.Fatalf("ACFT board has %d entries, want 1", len(entries))
+	}
+	if entries[0].UserID != "7" || entries[0].Rank != 1 {
+		t.Errorf("ACFT board entry = %+v, want user 7 ranked first", entries[0])
+	}
+}
+
+func TestACFTBoardFromTestSession(t *testing.T) {
+	e, workoutHandler, leaderboardHandler, _ := newACFTTestServer()
+
+	// The six events in the ACFT order with five minutes' rest between them
+	measurements := []TestEventRequest{
+		{ExerciseID: 1, WeightLbs: int32Ptr(250)},
+		{ExerciseID: 2, DistanceMeters: float64Ptr(9.5)},
+		{ExerciseID: 3, Reps: int32Ptr(40)},
+		{ExerciseID: 4, DurationSeconds: int32Ptr(120)},
+		{ExerciseID: 5, DurationSeconds: int32Ptr(180)},
+		{ExerciseID: 6, DurationSeconds: int32Ptr(960)},
+	}
+	at := time.Now().Add(-3 * time.Hour).Truncate(time.Hour)
+	session := CreateTestSessionRequest{ScoringStandard: leaderboards.ACFTStandardID, IsPublic: true}
+	for _, event := range measurements {
+		event.StartedAt = at
+		event.CompletedAt = at.Add(4 * time.Minute)
+		session.Events = append(session.Events, event)
+		at = event.CompletedAt.Add(5 * time.Minute)
+	}
+
+	rec := serve(t, e, workoutHandler.CreateTestSession, 7, http.MethodPost, "/test-sessions", session)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("test session status = %d, body %s", rec.Code, rec.Body.String())
+	}
+
+	entries := getACFTBoard(t, e, leaderboardHandler)
+	if len(entries) != 1 || entries[0].UserID != "7" {
+		t.Fatalf("ACFT board = %+v, want one entry for user 7", entries)
+	}
+	if entries[0].Score <= 0 {
+		t.Errorf("ACFT board score = %d, want the sum of the six event grades", entries[0].Score)
+	}
+}
+
+func TestTestSessionAwardsBadges(t *testing.T) {
+	e, workoutHandler, _, s := newACFTTestServer()
+
+	at := time.Now().Add(-3 * time.Hour).Truncate(time.Hour)
+	session := CreateTestSessionRequest{ScoringStandard: leaderboards.ACFTStandardID}
+	for i, event := range []TestEventRequest{
+		{ExerciseID: 1, WeightLbs: int32Ptr(250)},
+		{ExerciseID: 2, DistanceMeters: float64Ptr(9.5)},
+		{ExerciseID: 3, Reps: int32Ptr(40)},
+		{ExerciseID: 4, DurationSeconds: int32Ptr(120)},
+		{ExerciseID: 5, DurationSeconds: int32Ptr(180)},
+		{ExerciseID: 6, DurationSeconds: int32Ptr(960)},
+	} {
+		event.StartedAt = at.Add(time.Duration(i) * 9 * time.Minute)
+		event.CompletedAt = event.StartedAt.Add(4 * time.Minute)
+		session.Events = append(session.Events, event)
+	}
+
+	rec := serve(t, e, workoutHandler.CreateTestSession, 7, http.MethodPost, "/test-sessions", session)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("test session status = %d, body %s", rec.Code, rec.Body.String())
+	}
+	if len(s.awards) != 1 || s.awards[0].BadgeID != "first_workout" {
+		t.Errorf("awards = %+v, want first_workout", s.awards)
+	}
+}
+
+func int32Ptr(v int32) *int32 { return &v }
+
+func float64Ptr(v float64) *float64 { return &v }
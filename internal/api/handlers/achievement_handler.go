@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"ptchampion/internal/workouts"
+
+	"github.com/labstack/echo/v4"
+)
+
+// BadgeResponse defines the API response for a badge.
+type BadgeResponse struct {
+	ID            string   `json:"id"`
+	Name          string   `json:"name"`
+	Description   string   `json:"description"`
+	Kind          string   `json:"kind"` // workouts, total_reps, streak, max_score or time
+	ExerciseTypes []string `json:"exercise_types,omitempty"`
+	Target        float64  `json:"target"`
+}
+
+// BadgeProgressResponse defines the API response for a badge with the user's progress.
+type BadgeProgressResponse struct {
+	BadgeResponse
+	Earned   bool       `json:"earned"`
+	EarnedAt *time.Time `json:"earned_at,omitempty"`
+	Current  float64    `json:"current"` // Measured the way target is; seconds for time badges
+	Percent  float64    `json:"percent"`
+}
+
+// AchievementBackfillResponse defines the API response for an achievement backfill.
+type AchievementBackfillResponse struct {
+	Users   int `json:"users"`
+	Awarded int `json:"awarded"`
+	Failed  int `json:"failed"`
+}
+
+func mapBadgeToResponse(badge *workouts.Badge) BadgeResponse {
+	return BadgeResponse{
+		ID:            badge.ID,
+		Name:          badge.Name,
+		Description:   badge.Description,
+		Kind:          badge.Kind,
+		ExerciseTypes: badge.ExerciseTypes,
+		Target:        badge.Target,
+	}
+}
+
+func mapBadgesToResponse(badges []*workouts.Badge) []BadgeResponse {
+	resp := make([]BadgeResponse, len(badges))
+	for i, badge := range badges {
+		resp[i] = mapBadgeToResponse(badge)
+	}
+	return resp
+}
+
+// ListAchievements handles GET requests for every badge with the user's progress,
+// earned or locked.
+func (h *WorkoutHandler) ListAchievements(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, err := GetUserIDFromContext(c)
+	if err != nil {
+		h.logger.Error(ctx, "Could not get user ID from context for ListAchievements", "error", err)
+		return NewAPIError(http.StatusUnauthorized, ErrCodeUnauthorized, "Authentication error: User ID not found")
+	}
+
+	progress, err := h.service.ListAchievements(ctx, userID)
+	if err != nil {
+		h.logger.Error(ctx, "Service failed to list achievements", "userID", userID, "error", err)
+		return NewAPIError(http.StatusInternalServerError, ErrCodeInternalServer, "Failed to retrieve achievements")
+	}
+
+	resp := make([]BadgeProgressResponse, len(progress))
+	for i, p := range progress {
+		resp[i] = BadgeProgressResponse{
+			BadgeResponse: mapBadgeToResponse(p.Badge),
+			Earned:        p.Earned,
+			EarnedAt:      p.EarnedAt,
+			Current:       p.Current,
+			Percent:       p.Percent,
+		}
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// BackfillAchievements handles POST requests that award every user the badges their
+// workout history has earned. Admin only.
+func (h *WorkoutHandler) BackfillAchievements(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	result, err := h.service.BackfillAchievements(ctx)
+	if err != nil {
+		h.logger.Error(ctx, "Service failed to backfill achievements", "error", err)
+		return NewAPIError(http.StatusInternalServerError, ErrCodeInternalServer, "Failed to backfill achievements")
+	}
+
+	return c.JSON(http.StatusOK, AchievementBackfillResponse{
+		Users:   result.Users,
+		Awarded: result.Awarded,
+		Failed:  result.Failed,
+	})
+}
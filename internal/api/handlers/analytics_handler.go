@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"ptchampion/internal/analytics"
+	"ptchampion/internal/logging"
+
+	"github.com/labstack/echo/v4"
+)
+
+// SeriesPointResponse defines the API response for one time bucket of an exercise series.
+type SeriesPointResponse struct {
+	Start     time.Time `json:"start"`
+	Workouts  int       `json:"workouts"`
+	MaxReps   *int32    `json:"max_reps,omitempty"`
+	Volume    int64     `json:"volume"`
+	MeanGrade *float64  `json:"mean_grade,omitempty"`
+	RunPace   *float64  `json:"run_pace,omitempty"` // Seconds per mile
+
+	MaxRepsMovingAverage   *float64 `json:"max_reps_moving_average,omitempty"`
+	MeanGradeMovingAverage *float64 `json:"mean_grade_moving_average,omitempty"`
+	RunPaceMovingAverage   *float64 `json:"run_pace_moving_average,omitempty"`
+}
+
+// ProjectionResponse defines the API response for a metric's trend projection.
+type ProjectionResponse struct {
+	Metric      string  `json:"metric"` // max_reps, mean_grade or run_pace
+	SlopePerDay float64 `json:"slope_per_day"`
+	Projected   float64 `json:"projected"`
+	TargetDate  string  `json:"target_date"` // YYYY-MM-DD
+	Samples     int     `json:"samples"`
+}
+
+// ExerciseSeriesResponse defines the API response for the progress of one exercise type.
+type ExerciseSeriesResponse struct {
+	ExerciseType string                `json:"exercise_type"`
+	Points       []SeriesPointResponse `json:"points"`
+	Projections  []ProjectionResponse  `json:"projections,omitempty"`
+}
+
+// ProgressReportResponse defines the API response for a user's progress analytics.
+type ProgressReportResponse struct {
+	Interval    string                   `json:"interval"`
+	Window      int                      `json:"window"`
+	Series      []ExerciseSeriesResponse `json:"series"`
+	GeneratedAt time.Time                `json:"generated_at"`
+}
+
+// AnalyticsHandler handles workout analytics API requests.
+type AnalyticsHandler struct {
+	service analytics.Service
+	logger  logging.Logger
+}
+
+// NewAnalyticsHandler creates a new AnalyticsHandler instance.
+func NewAnalyticsHandler(service analytics.Service, logger logging.Logger) *AnalyticsHandler {
+	return &AnalyticsHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// GetProgress handles GET requests for the user's progress series. Optional query
+// parameters: interval (day, week or month; default week), exercise_type, window
+// (buckets in the moving averages; default 4) and target_date (YYYY-MM-DD) to project
+// trends to.
+func (h *AnalyticsHandler) GetProgress(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, err := GetUserIDFromContext(c)
+	if err != nil {
+		h.logger.Error(ctx, "Could not get user ID from context for GetProgress", "error", err)
+		return NewAPIError(http.StatusUnauthorized, ErrCodeUnauthorized, "Authentication error: User ID not found")
+	}
+
+	window, _ := strconv.Atoi(c.QueryParam("window"))
+	opts := analytics.ProgressOptions{
+		Interval:     c.QueryParam("interval"),
+		ExerciseType: c.QueryParam("exercise_type"),
+		Window:       window,
+	}
+	if targetStr := c.QueryParam("target_date"); targetStr != "" {
+		target, err := time.Parse("2006-01-02", targetStr)
+		if err != nil {
+			return NewAPIError(http.StatusBadRequest, ErrCodeBadRequest, "target_date must be formatted YYYY-MM-DD")
+		}
+		opts.TargetDate = &target
+	}
+
+	report, err := h.service.GetProgress(ctx, userID, opts)
+	if err != nil {
+		if errors.Is(err, analytics.ErrInvalidInterval) {
+			return NewAPIError(http.StatusBadRequest, ErrCodeBadRequest, "interval must be one of day, week or month")
+		}
+		h.logger.Error(ctx, "Service failed to get progress", "userID", userID, "error", err)
+		return NewAPIError(http.StatusInternalServerError, ErrCodeInternalServer, "Failed to retrieve progress")
+	}
+
+	resp := ProgressReportResponse{
+		Interval:    report.Interval,
+		Window:      report.Window,
+		Series:      make([]ExerciseSeriesResponse, len(report.Series)),
+		GeneratedAt: report.GeneratedAt,
+	}
+	for i, series := range report.Series {
+		points := make([]SeriesPointResponse, len(series.Points))
+		for j, p := range series.Points {
+			points[j] = SeriesPointResponse{
+				Start:                  p.Start,
+				Workouts:               p.Workouts,
+				MaxReps:                p.MaxReps,
+				Volume:                 p.Volume,
+				MeanGrade:              p.MeanGrade,
+				RunPace:                p.RunPace,
+				MaxRepsMovingAverage:   p.MaxRepsMovingAverage,
+				MeanGradeMovingAverage: p.MeanGradeMovingAverage,
+				RunPaceMovingAverage:   p.RunPaceMovingAverage,
+			}
+		}
+		projections := make([]ProjectionResponse, len(series.Projections))
+		for j, p := range series.Projections {
+			projections[j] = ProjectionResponse{
+				Metric:      p.Metric,
+				SlopePerDay: p.SlopePerDay,
+				Projected:   p.Projected,
+				TargetDate:  p.TargetDate.Format("2006-01-02"),
+				Samples:     p.Samples,
+			}
+		}
+		resp.Series[i] = ExerciseSeriesResponse{
+			ExerciseType: series.ExerciseType,
+			Points:       points,
+			Projections:  projections,
+		}
+	}
+	return c.JSON(http.StatusOK, resp)
+}
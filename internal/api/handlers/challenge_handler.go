@@ -0,0 +1,354 @@
+package handlers
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"ptchampion/internal/challenges"
+	"ptchampion/internal/logging"
+	"ptchampion/internal/store"
+
+	"github.com/labstack/echo/v4"
+)
+
+// CreateChallengeRequest defines the API request for creating a challenge.
+type CreateChallengeRequest struct {
+	Name            string    `json:"name" validate:"required,max=100"`
+	Description     *string   `json:"description,omitempty" validate:"omitempty,max=1000"`
+	ExerciseID      int32     `json:"exercise_id" validate:"required,gt=0"`
+	ScoringRule     string    `json:"scoring_rule" validate:"required,oneof=max_reps total_volume best_time"`
+	ParticipantType string    `json:"participant_type" validate:"required,oneof=individual team"`
+	StartsAt        time.Time `json:"starts_at" validate:"required"`
+	EndsAt          time.Time `json:"ends_at" validate:"required"`
+}
+
+// ChallengeEntryRequest defines the API request for joining or leaving a challenge.
+// Team challenges take the unit's organization_id; individual challenges take none.
+type ChallengeEntryRequest struct {
+	OrganizationID *int32 `json:"organization_id,omitempty" validate:"omitempty,gt=0"`
+}
+
+// ChallengeResponse defines the API response for a challenge.
+type ChallengeResponse struct {
+	ID              int32      `json:"id"`
+	CreatorID       int32      `json:"creator_id"`
+	Name            string     `json:"name"`
+	Description     *string    `json:"description,omitempty"`
+	ExerciseID      int32      `json:"exercise_id"`
+	ExerciseType    string     `json:"exercise_type"`
+	ScoringRule     string     `json:"scoring_rule"`
+	ParticipantType string     `json:"participant_type"`
+	StartsAt        time.Time  `json:"starts_at"`
+	EndsAt          time.Time  `json:"ends_at"`
+	FinalizedAt     *time.Time `json:"finalized_at,omitempty"`
+	Participants    int        `json:"participants"`
+	CreatedAt       time.Time  `json:"created_at"`
+}
+
+// PaginatedChallengesResponse defines the API response for a page of challenges.
+type PaginatedChallengesResponse struct {
+	Items      []ChallengeResponse `json:"items"`
+	TotalCount int64               `json:"totalCount"`
+	Page       int                 `json:"page"`
+	PageSize   int                 `json:"pageSize"`
+	TotalPages int                 `json:"totalPages"`
+}
+
+// ChallengeParticipantResponse defines the API response for a user or unit in a challenge.
+type ChallengeParticipantResponse struct {
+	ID             int32     `json:"id"`
+	UserID         *int32    `json:"user_id,omitempty"`
+	OrganizationID *int32    `json:"organization_id,omitempty"`
+	Name           string    `json:"name"`
+	JoinedAt       time.Time `json:"joined_at"`
+}
+
+// ChallengeStandingResponse defines the API response for a participant's place in a challenge.
+type ChallengeStandingResponse struct {
+	Rank         int32                        `json:"rank,omitempty"` // Omitted for participants without a result
+	Participant  ChallengeParticipantResponse `json:"participant"`
+	Score        *float64                     `json:"score,omitempty"`
+	Contributors int                          `json:"contributors"`
+}
+
+// ChallengeStandingsResponse defines the API response for a challenge's standings.
+type ChallengeStandingsResponse struct {
+	Challenge ChallengeResponse           `json:"challenge"`
+	Final     bool                        `json:"final"` // True once the results are frozen
+	Standings []ChallengeStandingResponse `json:"standings"`
+}
+
+// ChallengeHandler handles challenge-related API requests.
+type ChallengeHandler struct {
+	service challenges.Service
+	logger  logging.Logger
+}
+
+// NewChallengeHandler creates a new ChallengeHandler instance.
+func NewChallengeHandler(service challenges.Service, logger logging.Logger) *ChallengeHandler {
+	return &ChallengeHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+func mapChallengeToResponse(challenge *store.Challenge) ChallengeResponse {
+	return ChallengeResponse{
+		ID:              challenge.ID,
+		CreatorID:       challenge.CreatorID,
+		Name:            challenge.Name,
+		Description:     challenge.Description,
+		ExerciseID:      challenge.ExerciseID,
+		ExerciseType:    challenge.ExerciseType,
+		ScoringRule:     challenge.ScoringRule,
+		ParticipantType: challenge.ParticipantType,
+		StartsAt:        challenge.StartsAt,
+		EndsAt:          challenge.EndsAt,
+		FinalizedAt:     challenge.FinalizedAt,
+		Participants:    challenge.Participants,
+		CreatedAt:       challenge.CreatedAt,
+	}
+}
+
+func mapChallengeParticipantToResponse(participant *store.ChallengeParticipant) ChallengeParticipantResponse {
+	return ChallengeParticipantResponse{
+		ID:             participant.ID,
+		UserID:         participant.UserID,
+		OrganizationID: participant.OrganizationID,
+		Name:           participant.Name,
+		JoinedAt:       participant.JoinedAt,
+	}
+}
+
+// parseChallengeID reads the challenge_id path parameter.
+func (h *ChallengeHandler) parseChallengeID(c echo.Context) (int32, error) {
+	idStr := c.Param("challenge_id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		h.logger.Warn(c.Request().Context(), "Invalid challenge ID format", "challengeID", idStr, "error", err)
+		return 0, NewAPIError(http.StatusBadRequest, ErrCodeBadRequest, "Invalid challenge ID format")
+	}
+	return int32(id), nil
+}
+
+// challengeError maps the errors shared by the challenge endpoints to API errors,
+// or returns nil for errors that are not the client's.
+func challengeError(err error) error {
+	switch {
+	case err == store.ErrChallengeNotFound:
+		return NewAPIError(http.StatusNotFound, ErrCodeNotFound, "Challenge not found")
+	case err == store.ErrChallengeParticipantNotFound:
+		return NewAPIError(http.StatusNotFound, ErrCodeNotFound, "Not participating in this challenge")
+	case err == store.ErrExerciseNotFound:
+		return NewAPIError(http.StatusNotFound, ErrCodeNotFound, "Exercise not found")
+	case strings.Contains(err.Error(), "user does not have permission"):
+		return NewAPIError(http.StatusForbidden, ErrCodeForbidden, "Only unit leaders can enter or withdraw their unit")
+	case err == store.ErrChallengeParticipantExists:
+		return NewAPIError(http.StatusConflict, ErrCodeConflict, "Already participating in this challenge")
+	case err == challenges.ErrChallengeClosed:
+		return NewAPIError(http.StatusConflict, ErrCodeConflict, "Challenge has closed")
+	case errors.Is(err, challenges.ErrInvalidChallenge), err == challenges.ErrParticipantType:
+		return NewAPIError(http.StatusBadRequest, ErrCodeBadRequest, err.Error())
+	}
+	return nil
+}
+
+// CreateChallenge handles POST requests creating a challenge.
+func (h *ChallengeHandler) CreateChallenge(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, err := GetUserIDFromContext(c)
+	if err != nil {
+		h.logger.Error(ctx, "Could not get user ID from context for CreateChallenge", "error", err)
+		return NewAPIError(http.StatusUnauthorized, ErrCodeUnauthorized, "Authentication error: User ID not found")
+	}
+
+	var req CreateChallengeRequest
+	if err := c.Bind(&req); err != nil {
+		return NewAPIError(http.StatusBadRequest, ErrCodeBadRequest, "Invalid request body")
+	}
+	if err := c.Validate(req); err != nil {
+		return NewAPIError(http.StatusBadRequest, ErrCodeValidation, err.Error())
+	}
+
+	challenge, err := h.service.CreateChallenge(ctx, userID, &challenges.CreateChallengeData{
+		Name:            req.Name,
+		Description:     req.Description,
+		ExerciseID:      req.ExerciseID,
+		ScoringRule:     req.ScoringRule,
+		ParticipantType: req.ParticipantType,
+		StartsAt:        req.StartsAt,
+		EndsAt:          req.EndsAt,
+	})
+	if err != nil {
+		if apiErr := challengeError(err); apiErr != nil {
+			return apiErr
+		}
+		h.logger.Error(ctx, "Service failed to create challenge", "userID", userID, "error", err)
+		return NewAPIError(http.StatusInternalServerError, ErrCodeInternalServer, "Failed to create challenge")
+	}
+
+	return c.JSON(http.StatusCreated, mapChallengeToResponse(challenge))
+}
+
+// ListChallenges handles GET requests for challenges. Optional query parameters: status
+// (active, upcoming or closed), page and pageSize.
+func (h *ChallengeHandler) ListChallenges(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	status := c.QueryParam("status")
+	switch status {
+	case "", "active", "upcoming", "closed":
+	default:
+		return NewAPIError(http.StatusBadRequest, ErrCodeBadRequest, "status must be one of active, upcoming or closed")
+	}
+	page, _ := strconv.Atoi(c.QueryParam("page"))
+	pageSize, _ := strconv.Atoi(c.QueryParam("pageSize"))
+
+	result, err := h.service.ListChallenges(ctx, status, page, pageSize)
+	if err != nil {
+		h.logger.Error(ctx, "Service failed to list challenges", "status", status, "error", err)
+		return NewAPIError(http.StatusInternalServerError, ErrCodeInternalServer, "Failed to retrieve challenges")
+	}
+
+	items := make([]ChallengeResponse, len(result.Challenges))
+	for i, challenge := range result.Challenges {
+		items[i] = mapChallengeToResponse(challenge)
+	}
+
+	actualPage := page
+	if actualPage < 1 {
+		actualPage = 1
+	}
+	actualPageSize := pageSize
+	if actualPageSize < 1 || actualPageSize > 100 {
+		actualPageSize = 20
+	}
+
+	return c.JSON(http.StatusOK, PaginatedChallengesResponse{
+		Items:      items,
+		TotalCount: result.TotalCount,
+		Page:       actualPage,
+		PageSize:   actualPageSize,
+		TotalPages: int(math.Ceil(float64(result.TotalCount) / float64(actualPageSize))),
+	})
+}
+
+// GetChallenge handles GET requests for a single challenge.
+func (h *ChallengeHandler) GetChallenge(c echo.Context) error {
+	ctx := c.Request().Context()
+	challengeID, err := h.parseChallengeID(c)
+	if err != nil {
+		return err
+	}
+
+	challenge, err := h.service.GetChallenge(ctx, challengeID)
+	if err != nil {
+		if apiErr := challengeError(err); apiErr != nil {
+			return apiErr
+		}
+		h.logger.Error(ctx, "Service failed to get challenge", "challengeID", challengeID, "error", err)
+		return NewAPIError(http.StatusInternalServerError, ErrCodeInternalServer, "Failed to retrieve challenge")
+	}
+	return c.JSON(http.StatusOK, mapChallengeToResponse(challenge))
+}
+
+// JoinChallenge handles POST requests entering the user, or a unit they lead, in a challenge.
+func (h *ChallengeHandler) JoinChallenge(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, challengeID, req, err := h.bindEntry(c, "JoinChallenge")
+	if err != nil {
+		return err
+	}
+
+	participant, err := h.service.JoinChallenge(ctx, userID, challengeID, req.OrganizationID)
+	if err != nil {
+		if apiErr := challengeError(err); apiErr != nil {
+			return apiErr
+		}
+		h.logger.Error(ctx, "Service failed to join challenge", "userID", userID, "challengeID", challengeID, "error", err)
+		return NewAPIError(http.StatusInternalServerError, ErrCodeInternalServer, "Failed to join challenge")
+	}
+	return c.JSON(http.StatusCreated, mapChallengeParticipantToResponse(participant))
+}
+
+// LeaveChallenge handles POST requests withdrawing the user, or a unit they lead, from a challenge.
+func (h *ChallengeHandler) LeaveChallenge(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, challengeID, req, err := h.bindEntry(c, "LeaveChallenge")
+	if err != nil {
+		return err
+	}
+
+	if err := h.service.LeaveChallenge(ctx, userID, challengeID, req.OrganizationID); err != nil {
+		if apiErr := challengeError(err); apiErr != nil {
+			return apiErr
+		}
+		h.logger.Error(ctx, "Service failed to leave challenge", "userID", userID, "challengeID", challengeID, "error", err)
+		return NewAPIError(http.StatusInternalServerError, ErrCodeInternalServer, "Failed to leave challenge")
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// bindEntry reads the user, challenge ID and optional body shared by join and leave.
+func (h *ChallengeHandler) bindEntry(c echo.Context, op string) (int32, int32, *ChallengeEntryRequest, error) {
+	ctx := c.Request().Context()
+	userID, err := GetUserIDFromContext(c)
+	if err != nil {
+		h.logger.Error(ctx, "Could not get user ID from context for "+op, "error", err)
+		return 0, 0, nil, NewAPIError(http.StatusUnauthorized, ErrCodeUnauthorized, "Authentication error: User ID not found")
+	}
+
+	challengeID, err := h.parseChallengeID(c)
+	if err != nil {
+		return 0, 0, nil, err
+	}
+
+	var req ChallengeEntryRequest
+	if c.Request().ContentLength != 0 { // The body is optional for individual challenges
+		if err := c.Bind(&req); err != nil {
+			return 0, 0, nil, NewAPIError(http.StatusBadRequest, ErrCodeBadRequest, "Invalid request body")
+		}
+		if err := c.Validate(req); err != nil {
+			return 0, 0, nil, NewAPIError(http.StatusBadRequest, ErrCodeValidation, err.Error())
+		}
+	}
+	return userID, challengeID, &req, nil
+}
+
+// GetChallengeStandings handles GET requests for a challenge's standings: live while it
+// runs, and the frozen results once it has closed.
+func (h *ChallengeHandler) GetChallengeStandings(c echo.Context) error {
+	ctx := c.Request().Context()
+	challengeID, err := h.parseChallengeID(c)
+	if err != nil {
+		return err
+	}
+
+	standings, err := h.service.GetStandings(ctx, challengeID)
+	if err != nil {
+		if apiErr := challengeError(err); apiErr != nil {
+			return apiErr
+		}
+		h.logger.Error(ctx, "Service failed to get challenge standings", "challengeID", challengeID, "error", err)
+		return NewAPIError(http.StatusInternalServerError, ErrCodeInternalServer, "Failed to retrieve challenge standings")
+	}
+
+	resp := ChallengeStandingsResponse{
+		Challenge: mapChallengeToResponse(standings.Challenge),
+		Final:     standings.Final,
+		Standings: make([]ChallengeStandingResponse, len(standings.Standings)),
+	}
+	for i, standing := range standings.Standings {
+		resp.Standings[i] = ChallengeStandingResponse{
+			Rank:         standing.Rank,
+			Participant:  mapChallengeParticipantToResponse(standing.Participant),
+			Score:        standing.Score,
+			Contributors: standing.Contributors,
+		}
+	}
+	return c.JSON(http.StatusOK, resp)
+}
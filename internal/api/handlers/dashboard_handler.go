@@ -31,6 +31,20 @@ type DashboardStats struct {
 	RecentWorkouts   []WorkoutSummary `json:"recentWorkouts"`
 	ExerciseCounts   map[string]int   `json:"exerciseCounts"`
 	LastWorkoutDate  *time.Time       `json:"lastWorkoutDate"`
+	Streaks          *StreakSummary   `json:"streaks,omitempty"` // nil if streaks could not be computed
+}
+
+// StreakSummary represents a user's daily and weekly streaks in their timezone
+type StreakSummary struct {
+	Timezone         string  `json:"timezone"`
+	Today            string  `json:"today"`            // YYYY-MM-DD in the user's timezone
+	DailyCurrent     int     `json:"dailyCurrent"`
+	DailyLongest     int     `json:"dailyLongest"`
+	WeeklyCurrent    int     `json:"weeklyCurrent"`
+	WeeklyLongest    int     `json:"weeklyLongest"`
+	FreezesAvailable int     `json:"freezesAvailable"`
+	FreezesUsed      int     `json:"freezesUsed"`      // by the current daily streak
+	LastActiveDate   *string `json:"lastActiveDate"`   // YYYY-MM-DD, nil if no workouts
 }
 
 // WorkoutSummary represents a minimal workout for dashboard display
@@ -69,6 +83,14 @@ func (h *DashboardHandler) GetDashboardStats(c echo.Context) error {
 		RecentWorkouts:  make([]WorkoutSummary, len(stats.RecentWorkouts)),
 	}
 
+	// Streaks are best effort; the rest of the dashboard is still useful without them
+	streaks, err := h.workoutService.GetStreaks(ctx, userID)
+	if err != nil {
+		h.logger.Warn(ctx, "Failed to get streaks for dashboard", "error", err, "userID", userID)
+	} else {
+		response.Streaks = mapStreaksToSummary(streaks)
+	}
+
 	// Convert recent workouts to API format
 	for i, w := range stats.RecentWorkouts {
 		reps := 0
@@ -90,4 +112,40 @@ func (h *DashboardHandler) GetDashboardStats(c echo.Context) error {
 	}
 
 	return c.JSON(http.StatusOK, response)
-}
\ No newline at end of file
+}
+
+// GetStreaks returns the authenticated user's daily and weekly streaks
+func (h *DashboardHandler) GetStreaks(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, err := GetUserIDFromContext(c)
+	if err != nil {
+		h.logger.Error(ctx, "Could not get user ID from context for GetStreaks", "error", err)
+		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "User not found"})
+	}
+
+	streaks, err := h.workoutService.GetStreaks(ctx, userID)
+	if err != nil {
+		h.logger.Error(ctx, "Failed to get streaks", "error", err, "userID", userID)
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to retrieve streaks"})
+	}
+
+	return c.JSON(http.StatusOK, mapStreaksToSummary(streaks))
+}
+
+func mapStreaksToSummary(streaks *workouts.StreakStats) *StreakSummary {
+	summary := &StreakSummary{
+		Timezone:         streaks.Timezone,
+		Today:            streaks.Today.Format("2006-01-02"),
+		DailyCurrent:     streaks.Daily.Current,
+		DailyLongest:     streaks.Daily.Longest,
+		WeeklyCurrent:    streaks.Weekly.Current,
+		WeeklyLongest:    streaks.Weekly.Longest,
+		FreezesAvailable: streaks.Daily.FreezesAvailable,
+		FreezesUsed:      streaks.Daily.FreezesUsed,
+	}
+	if streaks.Daily.LastActiveDate != nil {
+		lastActive := streaks.Daily.LastActiveDate.Format("2006-01-02")
+		summary.LastActiveDate = &lastActive
+	}
+	return summary
+}
@@ -0,0 +1,335 @@
+package handlers
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"ptchampion/internal/feed"
+	"ptchampion/internal/logging"
+	"ptchampion/internal/store"
+
+	"github.com/labstack/echo/v4"
+)
+
+// ReactRequest defines the API request for reacting to an activity event.
+type ReactRequest struct {
+	Reaction string `json:"reaction" validate:"required,oneof=like fire strong salute"`
+}
+
+// CreateCommentRequest defines the API request for commenting on an activity event.
+type CreateCommentRequest struct {
+	Body string `json:"body" validate:"required,max=500"`
+}
+
+// ActivityEventResponse defines the API response for an event in a feed.
+type ActivityEventResponse struct {
+	ID            int32                  `json:"id"`
+	ActorUserID   int32                  `json:"actor_user_id"`
+	ActorUsername string                 `json:"actor_username"`
+	Type          string                 `json:"type"` // workout, personal_record, challenge_result or achievement
+	WorkoutID     *int32                 `json:"workout_id,omitempty"`
+	Payload       *store.ActivityPayload `json:"payload"`
+	Reactions     map[string]int         `json:"reactions"`
+	CommentCount  int                    `json:"comment_count"`
+	MyReaction    string                 `json:"my_reaction,omitempty"`
+	CreatedAt     time.Time              `json:"created_at"`
+}
+
+// FeedResponse defines the API response for a page of the caller's feed.
+type FeedResponse struct {
+	Items      []ActivityEventResponse `json:"items"`
+	NextCursor string                  `json:"next_cursor,omitempty"` // Absent on the last page
+}
+
+// ActivityCommentResponse defines the API response for a comment on an activity event.
+type ActivityCommentResponse struct {
+	ID        int32     `json:"id"`
+	EventID   int32     `json:"event_id"`
+	UserID    int32     `json:"user_id"`
+	Username  string    `json:"username"`
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// PaginatedActivityCommentsResponse defines the API response for a page of comments.
+type PaginatedActivityCommentsResponse struct {
+	Items      []ActivityCommentResponse `json:"items"`
+	TotalCount int64                     `json:"totalCount"`
+	Page       int                       `json:"page"`
+	PageSize   int                       `json:"pageSize"`
+	TotalPages int                       `json:"totalPages"`
+}
+
+// FeedHandler handles activity feed API requests.
+type FeedHandler struct {
+	service feed.Service
+	logger  logging.Logger
+}
+
+// NewFeedHandler creates a new FeedHandler instance.
+func NewFeedHandler(service feed.Service, logger logging.Logger) *FeedHandler {
+	return &FeedHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+func mapActivityEventToResponse(event *store.ActivityEvent) ActivityEventResponse {
+	return ActivityEventResponse{
+		ID:            event.ID,
+		ActorUserID:   event.ActorUserID,
+		ActorUsername: event.ActorUsername,
+		Type:          event.Type,
+		WorkoutID:     event.WorkoutID,
+		Payload:       event.Payload,
+		Reactions:     event.Reactions,
+		CommentCount:  event.Comments,
+		MyReaction:    event.UserReaction,
+		CreatedAt:     event.CreatedAt,
+	}
+}
+
+func mapActivityCommentToResponse(comment *store.ActivityComment) ActivityCommentResponse {
+	return ActivityCommentResponse{
+		ID:        comment.ID,
+		EventID:   comment.EventID,
+		UserID:    comment.UserID,
+		Username:  comment.Username,
+		Body:      comment.Body,
+		CreatedAt: comment.CreatedAt,
+	}
+}
+
+// parseIDParam reads a numeric path parameter.
+func (h *FeedHandler) parseIDParam(c echo.Context, name string) (int32, error) {
+	idStr := c.Param(name)
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		h.logger.Warn(c.Request().Context(), "Invalid ID format", name, idStr, "error", err)
+		return 0, NewAPIError(http.StatusBadRequest, ErrCodeBadRequest, "Invalid "+name+" format")
+	}
+	return int32(id), nil
+}
+
+// feedError maps the errors of the feed endpoints to API errors, or returns nil for
+// errors that are not the client's.
+func feedError(err error) error {
+	switch err {
+	case store.ErrActivityEventNotFound:
+		return NewAPIError(http.StatusNotFound, ErrCodeNotFound, "Activity not found")
+	case store.ErrActivityCommentNotFound:
+		return NewAPIError(http.StatusNotFound, ErrCodeNotFound, "Comment not found")
+	case feed.ErrInvalidCursor, feed.ErrInvalidReaction, feed.ErrInvalidComment:
+		return NewAPIError(http.StatusBadRequest, ErrCodeBadRequest, err.Error())
+	case feed.ErrPermissionDenied:
+		return NewAPIError(http.StatusForbidden, ErrCodeForbidden, err.Error())
+	}
+	return nil
+}
+
+// GetFeed handles GET requests for the caller's feed, newest first. Pass the next_cursor
+// of a page as cursor to read the next one.
+func (h *FeedHandler) GetFeed(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, err := GetUserIDFromContext(c)
+	if err != nil {
+		h.logger.Error(ctx, "Could not get user ID from context for GetFeed", "error", err)
+		return NewAPIError(http.StatusUnauthorized, ErrCodeUnauthorized, "Authentication error: User ID not found")
+	}
+
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+
+	page, err := h.service.GetFeed(ctx, userID, c.QueryParam("cursor"), limit)
+	if err != nil {
+		if apiErr := feedError(err); apiErr != nil {
+			return apiErr
+		}
+		h.logger.Error(ctx, "Service failed to get feed", "userID", userID, "error", err)
+		return NewAPIError(http.StatusInternalServerError, ErrCodeInternalServer, "Failed to retrieve feed")
+	}
+
+	items := make([]ActivityEventResponse, len(page.Events))
+	for i, event := range page.Events {
+		items[i] = mapActivityEventToResponse(event)
+	}
+	return c.JSON(http.StatusOK, FeedResponse{
+		Items:      items,
+		NextCursor: page.NextCursor,
+	})
+}
+
+// React handles PUT requests setting the caller's reaction to an event, replacing any
+// earlier one.
+func (h *FeedHandler) React(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, err := GetUserIDFromContext(c)
+	if err != nil {
+		h.logger.Error(ctx, "Could not get user ID from context for React", "error", err)
+		return NewAPIError(http.StatusUnauthorized, ErrCodeUnauthorized, "Authentication error: User ID not found")
+	}
+
+	eventID, err := h.parseIDParam(c, "event_id")
+	if err != nil {
+		return err
+	}
+
+	var req ReactRequest
+	if err := c.Bind(&req); err != nil {
+		return NewAPIError(http.StatusBadRequest, ErrCodeBadRequest, "Invalid request body")
+	}
+	if err := c.Validate(req); err != nil {
+		return NewAPIError(http.StatusBadRequest, ErrCodeValidation, err.Error())
+	}
+
+	event, err := h.service.React(ctx, userID, eventID, req.Reaction)
+	if err != nil {
+		if apiErr := feedError(err); apiErr != nil {
+			return apiErr
+		}
+		h.logger.Error(ctx, "Service failed to react to activity", "userID", userID, "eventID", eventID, "error", err)
+		return NewAPIError(http.StatusInternalServerError, ErrCodeInternalServer, "Failed to react to activity")
+	}
+
+	return c.JSON(http.StatusOK, mapActivityEventToResponse(event))
+}
+
+// Unreact handles DELETE requests removing the caller's reaction to an event.
+func (h *FeedHandler) Unreact(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, err := GetUserIDFromContext(c)
+	if err != nil {
+		h.logger.Error(ctx, "Could not get user ID from context for Unreact", "error", err)
+		return NewAPIError(http.StatusUnauthorized, ErrCodeUnauthorized, "Authentication error: User ID not found")
+	}
+
+	eventID, err := h.parseIDParam(c, "event_id")
+	if err != nil {
+		return err
+	}
+
+	event, err := h.service.Unreact(ctx, userID, eventID)
+	if err != nil {
+		if apiErr := feedError(err); apiErr != nil {
+			return apiErr
+		}
+		h.logger.Error(ctx, "Service failed to remove reaction", "userID", userID, "eventID", eventID, "error", err)
+		return NewAPIError(http.StatusInternalServerError, ErrCodeInternalServer, "Failed to remove reaction")
+	}
+
+	return c.JSON(http.StatusOK, mapActivityEventToResponse(event))
+}
+
+// ListComments handles GET requests for a page of an event's comments, oldest first.
+func (h *FeedHandler) ListComments(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, err := GetUserIDFromContext(c)
+	if err != nil {
+		h.logger.Error(ctx, "Could not get user ID from context for ListComments", "error", err)
+		return NewAPIError(http.StatusUnauthorized, ErrCodeUnauthorized, "Authentication error: User ID not found")
+	}
+
+	eventID, err := h.parseIDParam(c, "event_id")
+	if err != nil {
+		return err
+	}
+
+	page, _ := strconv.Atoi(c.QueryParam("page"))
+	pageSize, _ := strconv.Atoi(c.QueryParam("pageSize"))
+
+	comments, err := h.service.ListComments(ctx, userID, eventID, page, pageSize)
+	if err != nil {
+		if apiErr := feedError(err); apiErr != nil {
+			return apiErr
+		}
+		h.logger.Error(ctx, "Service failed to list comments", "userID", userID, "eventID", eventID, "error", err)
+		return NewAPIError(http.StatusInternalServerError, ErrCodeInternalServer, "Failed to retrieve comments")
+	}
+
+	items := make([]ActivityCommentResponse, len(comments.Comments))
+	for i, comment := range comments.Comments {
+		items[i] = mapActivityCommentToResponse(comment)
+	}
+
+	actualPage := page
+	if actualPage < 1 {
+		actualPage = 1
+	}
+	actualPageSize := pageSize
+	if actualPageSize < 1 || actualPageSize > 100 {
+		actualPageSize = 20
+	}
+
+	return c.JSON(http.StatusOK, PaginatedActivityCommentsResponse{
+		Items:      items,
+		TotalCount: comments.TotalCount,
+		Page:       actualPage,
+		PageSize:   actualPageSize,
+		TotalPages: int(math.Ceil(float64(comments.TotalCount) / float64(actualPageSize))),
+	})
+}
+
+// AddComment handles POST requests commenting on an event.
+func (h *FeedHandler) AddComment(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, err := GetUserIDFromContext(c)
+	if err != nil {
+		h.logger.Error(ctx, "Could not get user ID from context for AddComment", "error", err)
+		return NewAPIError(http.StatusUnauthorized, ErrCodeUnauthorized, "Authentication error: User ID not found")
+	}
+
+	eventID, err := h.parseIDParam(c, "event_id")
+	if err != nil {
+		return err
+	}
+
+	var req CreateCommentRequest
+	if err := c.Bind(&req); err != nil {
+		return NewAPIError(http.StatusBadRequest, ErrCodeBadRequest, "Invalid request body")
+	}
+	if err := c.Validate(req); err != nil {
+		return NewAPIError(http.StatusBadRequest, ErrCodeValidation, err.Error())
+	}
+
+	comment, err := h.service.AddComment(ctx, userID, eventID, req.Body)
+	if err != nil {
+		if apiErr := feedError(err); apiErr != nil {
+			return apiErr
+		}
+		h.logger.Error(ctx, "Service failed to add comment", "userID", userID, "eventID", eventID, "error", err)
+		return NewAPIError(http.StatusInternalServerError, ErrCodeInternalServer, "Failed to add comment")
+	}
+
+	return c.JSON(http.StatusCreated, mapActivityCommentToResponse(comment))
+}
+
+// DeleteComment handles DELETE requests for a comment. Comments can be deleted by their
+// author and by the user whose event they are on.
+func (h *FeedHandler) DeleteComment(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, err := GetUserIDFromContext(c)
+	if err != nil {
+		h.logger.Error(ctx, "Could not get user ID from context for DeleteComment", "error", err)
+		return NewAPIError(http.StatusUnauthorized, ErrCodeUnauthorized, "Authentication error: User ID not found")
+	}
+
+	eventID, err := h.parseIDParam(c, "event_id")
+	if err != nil {
+		return err
+	}
+	commentID, err := h.parseIDParam(c, "comment_id")
+	if err != nil {
+		return err
+	}
+
+	if err := h.service.DeleteComment(ctx, userID, eventID, commentID); err != nil {
+		if apiErr := feedError(err); apiErr != nil {
+			return apiErr
+		}
+		h.logger.Error(ctx, "Service failed to delete comment", "userID", userID, "commentID", commentID, "error", err)
+		return NewAPIError(http.StatusInternalServerError, ErrCodeInternalServer, "Failed to delete comment")
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,217 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"ptchampion/internal/grading"
+	"ptchampion/internal/store"
+	"ptchampion/internal/workouts"
+
+	"github.com/labstack/echo/v4"
+)
+
+// CreateGoalRequest defines the API request for setting a goal: a measurement in one
+// exercise (e.g. 70 push-ups) or a total test score (e.g. 270), by a date.
+type CreateGoalRequest struct {
+	Type            string  `json:"type" validate:"required,oneof=exercise total_score"`
+	ExerciseType    string  `json:"exercise_type,omitempty" validate:"required_if=Type exercise"`
+	ScoringStandard string  `json:"scoring_standard,omitempty"` // Registry ID; defaults to apft-2013
+	TargetValue     float64 `json:"target_value" validate:"required,gt=0"`
+	TargetDate      string  `json:"target_date" validate:"required"` // YYYY-MM-DD
+}
+
+// GoalEventProgressResponse defines the API response for one event of a total score goal.
+type GoalEventProgressResponse struct {
+	Event          string   `json:"event"`
+	CurrentScore   *int32   `json:"current_score,omitempty"`
+	ProjectedScore *float64 `json:"projected_score,omitempty"`
+}
+
+// GoalResponse defines the API response for a goal and its progress.
+type GoalResponse struct {
+	ID              int32                       `json:"id"`
+	Type            string                      `json:"type"`
+	ExerciseType    string                      `json:"exercise_type,omitempty"`
+	ScoringStandard string                      `json:"scoring_standard"`
+	TargetValue     float64                     `json:"target_value"`
+	TargetDate      string                      `json:"target_date"` // YYYY-MM-DD
+	CreatedAt       time.Time                   `json:"created_at"`
+	Status          string                      `json:"status"` // achieved, on_track, behind or missed
+	Current         *float64                    `json:"current,omitempty"`
+	Projected       *float64                    `json:"projected,omitempty"` // Trend value at the target date
+	Percent         float64                     `json:"percent"`
+	CurrentScore    *int32                      `json:"current_score,omitempty"` // Exercise goals only
+	TargetScore     int32                       `json:"target_score,omitempty"`  // Exercise goals only
+	Events          []GoalEventProgressResponse `json:"events,omitempty"`        // Total score goals only
+}
+
+func mapGoalProgressToResponse(p *workouts.GoalProgress) GoalResponse {
+	resp := GoalResponse{
+		ID:              p.Goal.ID,
+		Type:            p.Goal.Type,
+		ExerciseType:    p.Goal.ExerciseType,
+		ScoringStandard: p.Goal.ScoringStandard,
+		TargetValue:     p.Goal.TargetValue,
+		TargetDate:      p.Goal.TargetDate.Format("2006-01-02"),
+		CreatedAt:       p.Goal.CreatedAt,
+		Status:          p.Status,
+		Current:         p.Current,
+		Projected:       p.Projected,
+		Percent:         p.Percent,
+		CurrentScore:    p.CurrentScore,
+		TargetScore:     p.TargetScore,
+	}
+	for _, ev := range p.Events {
+		resp.Events = append(resp.Events, GoalEventProgressResponse{
+			Event:          ev.Event,
+			CurrentScore:   ev.CurrentScore,
+			ProjectedScore: ev.ProjectedScore,
+		})
+	}
+	return resp
+}
+
+// parseGoalID reads the goal_id path parameter.
+func (h *WorkoutHandler) parseGoalID(c echo.Context) (int32, error) {
+	goalIDStr := c.Param("goal_id")
+	goalID, err := strconv.Atoi(goalIDStr)
+	if err != nil {
+		h.logger.Warn(c.Request().Context(), "Invalid goal ID format", "goalID", goalIDStr, "error", err)
+		return 0, NewAPIError(http.StatusBadRequest, ErrCodeBadRequest, "Invalid goal ID format")
+	}
+	return int32(goalID), nil
+}
+
+// goalError maps the errors shared by the goal endpoints to API errors,
+// or returns nil for errors that are not the client's.
+func goalError(err error) error {
+	switch {
+	case err == store.ErrGoalNotFound:
+		return NewAPIError(http.StatusNotFound, ErrCodeNotFound, "Goal not found")
+	case strings.Contains(err.Error(), "user does not have permission"):
+		return NewAPIError(http.StatusForbidden, ErrCodeForbidden, "You do not have permission to access this goal")
+	case errors.Is(err, workouts.ErrInvalidGoal), errors.Is(err, grading.ErrUnknownStandard),
+		errors.Is(err, workouts.ErrEventNotInStandard):
+		return NewAPIError(http.StatusBadRequest, ErrCodeBadRequest, err.Error())
+	}
+	return nil
+}
+
+// CreateGoal handles POST requests setting a goal.
+func (h *WorkoutHandler) CreateGoal(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, err := GetUserIDFromContext(c)
+	if err != nil {
+		h.logger.Error(ctx, "Could not get user ID from context for CreateGoal", "error", err)
+		return NewAPIError(http.StatusUnauthorized, ErrCodeUnauthorized, "Authentication error: User ID not found")
+	}
+
+	var req CreateGoalRequest
+	if err := c.Bind(&req); err != nil {
+		return NewAPIError(http.StatusBadRequest, ErrCodeBadRequest, "Invalid request body")
+	}
+	if err := c.Validate(req); err != nil {
+		return NewAPIError(http.StatusBadRequest, ErrCodeValidation, err.Error())
+	}
+	targetDate, err := time.Parse("2006-01-02", req.TargetDate)
+	if err != nil {
+		return NewAPIError(http.StatusBadRequest, ErrCodeValidation, "target_date must be formatted YYYY-MM-DD")
+	}
+
+	if !scoringStandardEnabled(c, req.ScoringStandard) {
+		return NewAPIError(http.StatusBadRequest, ErrCodeBadRequest, "Scoring standard selection is not enabled")
+	}
+
+	progress, err := h.service.CreateGoal(ctx, userID, &workouts.CreateGoalData{
+		Type:            req.Type,
+		ExerciseType:    req.ExerciseType,
+		ScoringStandard: req.ScoringStandard,
+		TargetValue:     req.TargetValue,
+		TargetDate:      targetDate,
+	})
+	if err != nil {
+		if apiErr := goalError(err); apiErr != nil {
+			return apiErr
+		}
+		h.logger.Error(ctx, "Service failed to create goal", "userID", userID, "error", err)
+		return NewAPIError(http.StatusInternalServerError, ErrCodeInternalServer, "Failed to create goal")
+	}
+
+	return c.JSON(http.StatusCreated, mapGoalProgressToResponse(progress))
+}
+
+// ListGoals handles GET requests listing the user's goals with their status, soonest
+// target date first.
+func (h *WorkoutHandler) ListGoals(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, err := GetUserIDFromContext(c)
+	if err != nil {
+		h.logger.Error(ctx, "Could not get user ID from context for ListGoals", "error", err)
+		return NewAPIError(http.StatusUnauthorized, ErrCodeUnauthorized, "Authentication error: User ID not found")
+	}
+
+	goals, err := h.service.ListGoals(ctx, userID)
+	if err != nil {
+		h.logger.Error(ctx, "Service failed to list goals", "userID", userID, "error", err)
+		return NewAPIError(http.StatusInternalServerError, ErrCodeInternalServer, "Failed to retrieve goals")
+	}
+
+	resp := make([]GoalResponse, len(goals))
+	for i, goal := range goals {
+		resp[i] = mapGoalProgressToResponse(goal)
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// GetGoal handles GET requests for one of the user's goals.
+func (h *WorkoutHandler) GetGoal(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, err := GetUserIDFromContext(c)
+	if err != nil {
+		h.logger.Error(ctx, "Could not get user ID from context for GetGoal", "error", err)
+		return NewAPIError(http.StatusUnauthorized, ErrCodeUnauthorized, "Authentication error: User ID not found")
+	}
+
+	goalID, err := h.parseGoalID(c)
+	if err != nil {
+		return err
+	}
+
+	progress, err := h.service.GetGoal(ctx, userID, goalID)
+	if err != nil {
+		if apiErr := goalError(err); apiErr != nil {
+			return apiErr
+		}
+		h.logger.Error(ctx, "Service failed to get goal", "userID", userID, "goalID", goalID, "error", err)
+		return NewAPIError(http.StatusInternalServerError, ErrCodeInternalServer, "Failed to retrieve goal")
+	}
+	return c.JSON(http.StatusOK, mapGoalProgressToResponse(progress))
+}
+
+// DeleteGoal handles DELETE requests for one of the user's goals.
+func (h *WorkoutHandler) DeleteGoal(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, err := GetUserIDFromContext(c)
+	if err != nil {
+		h.logger.Error(ctx, "Could not get user ID from context for DeleteGoal", "error", err)
+		return NewAPIError(http.StatusUnauthorized, ErrCodeUnauthorized, "Authentication error: User ID not found")
+	}
+
+	goalID, err := h.parseGoalID(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.service.DeleteGoal(ctx, userID, goalID); err != nil {
+		if apiErr := goalError(err); apiErr != nil {
+			return apiErr
+		}
+		h.logger.Error(ctx, "Service failed to delete goal", "userID", userID, "goalID", goalID, "error", err)
+		return NewAPIError(http.StatusInternalServerError, ErrCodeInternalServer, "Failed to delete goal")
+	}
+	return c.NoContent(http.StatusNoContent)
+}
@@ -8,6 +8,7 @@ import (
 	"strconv"
 	"time"
 
+	"ptchampion/internal/grading"
 	db "ptchampion/internal/store/postgres"
 	redis_cache "ptchampion/internal/store/redis"
 
@@ -55,7 +56,7 @@ type GlobalLeaderboardResponse struct {
 // GetLocalLeaderboard returns a leaderboard of users within a specified radius
 func (s *LeaderboardService) GetLocalLeaderboard(ctx context.Context, exerciseType string, lat, lng, radius float64, startDate, endDate *time.Time, limit, offset int) (*LocalLeaderboardResponse, error) {
 	// Cache miss or error - query database using new method signature
-	entries, err := s.repo.GetLocalLeaderboard(ctx, exerciseType, lat, lng, radius, startDate, endDate, limit, offset)
+	entries, err := s.repo.GetLocalLeaderboard(ctx, exerciseType, grading.DefaultStandardIDForExercise(exerciseType), lat, lng, radius, startDate, endDate, limit, offset)
 	if err != nil {
 		return nil, err
 	}
@@ -91,7 +92,7 @@ func (s *LeaderboardService) GetLocalLeaderboard(ctx context.Context, exerciseTy
 // GetGlobalLeaderboard returns a global leaderboard for a specific exercise type
 func (s *LeaderboardService) GetGlobalLeaderboard(ctx context.Context, exerciseType string, startDate, endDate *time.Time, limit, offset int) (*GlobalLeaderboardResponse, error) {
 	// Cache miss or error - query database using new method signature
-	entries, err := s.repo.GetGlobalLeaderboard(ctx, exerciseType, startDate, endDate, limit, offset)
+	entries, err := s.repo.GetGlobalLeaderboard(ctx, exerciseType, grading.DefaultStandardIDForExercise(exerciseType), startDate, endDate, limit, offset)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"ptchampion/internal/leaderboards"
+
+	"github.com/labstack/echo/v4"
+)
+
+// GetFriendsExerciseLeaderboard handles GET /leaderboards/friends/exercise/:exerciseType
+func (h *LeaderboardHandler) GetFriendsExerciseLeaderboard(c echo.Context) error {
+	exerciseType := c.Param("exerciseType")
+	if exerciseType == "" {
+		return NewAPIError(http.StatusBadRequest, ErrCodeBadRequest, "Exercise type parameter is required")
+	}
+	return h.getFriendsLeaderboard(c, exerciseType)
+}
+
+// GetFriendsOverallLeaderboard handles GET /leaderboards/friends/overall
+func (h *LeaderboardHandler) GetFriendsOverallLeaderboard(c echo.Context) error {
+	return h.getFriendsLeaderboard(c, leaderboards.BoardOverall)
+}
+
+// GetFriendsACFTLeaderboard handles GET /leaderboards/friends/acft
+func (h *LeaderboardHandler) GetFriendsACFTLeaderboard(c echo.Context) error {
+	return h.getFriendsLeaderboard(c, leaderboards.BoardACFT)
+}
+
+func (h *LeaderboardHandler) getFriendsLeaderboard(c echo.Context, board string) error {
+	ctx := c.Request().Context()
+	userID, err := GetUserIDFromContext(c)
+	if err != nil {
+		h.logger.Error(ctx, "Could not get user ID from context for friends leaderboard", "error", err)
+		return NewAPIError(http.StatusUnauthorized, ErrCodeUnauthorized, "Authentication error: User ID not found")
+	}
+
+	limit, err := strconv.Atoi(c.QueryParam("limit"))
+	if err != nil || limit <= 0 {
+		limit = defaultLeaderboardLimit
+	}
+	timeFrame, err := parseTimeFrame(c)
+	if err != nil {
+		return err
+	}
+
+	h.logger.Debug(ctx, "GetFriendsLeaderboard called", "userID", userID, "board", board, "limit", limit, "timeFrame", timeFrame)
+
+	storeEntries, err := h.service.GetFriendsLeaderboard(ctx, userID, board, limit, timeFrame)
+	if err != nil {
+		h.logger.Error(ctx, "Error from GetFriendsLeaderboard service", "userID", userID, "board", board, "error", err)
+		return NewAPIError(http.StatusInternalServerError, ErrCodeInternalServer, "Failed to retrieve friends leaderboard")
+	}
+
+	apiEntries := make([]LeaderboardAPIEntry, len(storeEntries))
+	for i, entry := range storeEntries {
+		apiEntries[i] = mapStoreLeaderboardEntryToAPIEntry(entry)
+	}
+	return c.JSON(http.StatusOK, apiEntries)
+}
@@ -13,6 +13,7 @@ import (
 	db "ptchampion/internal/store/postgres"
 	"ptchampion/internal/store/redis"
 
+	"ptchampion/internal/grading"
 	"ptchampion/internal/leaderboards"
 	"ptchampion/internal/logging"
 	"ptchampion/internal/store" // For store.LeaderboardEntry
@@ -156,17 +157,18 @@ func (h *Handler) GetLeaderboard(c echo.Context) error {
 		limit = defaultLeaderboardLimit
 	}
 
-	// Get time frame from query param, default to all_time if not provided
-	timeFrame := c.QueryParam("time_frame")
-	if timeFrame == "" {
-		timeFrame = "all_time"
+	// Get time frame from query params, default to all_time if not provided
+	timeFrame, err := leaderboards.ParseTimeFrame(timeFrameQuery(c))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
+	now := time.Now()
 
 	// Check if we can use the Redis cache
 	redisClient := h.GetCacheClient()
 	if redisClient != nil {
 		cache := redis.NewLeaderboardCache(redisClient).WithTTL(leaderboardCacheTTL)
-		cacheKey := redis.GlobalLeaderboardKey(exerciseType, limit, timeFrame)
+		cacheKey := redis.GlobalLeaderboardKey(exerciseType, limit, timeFrame.CacheKey(now))
 
 		// Try to get from cache
 		var cachedEntries []LeaderboardEntry
@@ -188,25 +190,9 @@ func (h *Handler) GetLeaderboard(c echo.Context) error {
 		}
 		
 		// Set time frame parameters based on timeFrame
-		if timeFrame != "all_time" {
-			now := time.Now()
-			var startDate time.Time
-			
-			switch timeFrame {
-			case "weekly":
-				startDate = now.AddDate(0, 0, -7)
-			case "monthly":
-				startDate = now.AddDate(0, -1, 0)
-			case "yearly":
-				startDate = now.AddDate(-1, 0, 0)
-			default:
-				// For all_time or unknown values, don't set dates
-			}
-			
-			if !startDate.IsZero() {
-				aggregateParams.StartDate = sql.NullTime{Time: startDate, Valid: true}
-				aggregateParams.EndDate = sql.NullTime{Time: now, Valid: true}
-			}
+		if startDate, endDate := timeFrame.Dates(now); !startDate.IsZero() {
+			aggregateParams.StartDate = sql.NullTime{Time: startDate, Valid: true}
+			aggregateParams.EndDate = sql.NullTime{Time: endDate, Valid: true}
 		}
 		
 		// Fetch aggregate leaderboard data from database
@@ -244,7 +230,7 @@ func (h *Handler) GetLeaderboard(c echo.Context) error {
 		// Cache the result if Redis is available
 		if redisClient != nil && len(respEntries) > 0 {
 			cache := redis.NewLeaderboardCache(redisClient).WithTTL(leaderboardCacheTTL)
-			cacheKey := redis.GlobalLeaderboardKey(exerciseType, limit, timeFrame)
+			cacheKey := redis.GlobalLeaderboardKey(exerciseType, limit, timeFrame.CacheKey(now))
 			
 			ctx := c.Request().Context()
 			if err := cache.Set(ctx, cacheKey, respEntries); err != nil {
@@ -259,8 +245,9 @@ func (h *Handler) GetLeaderboard(c echo.Context) error {
 	
 	// Regular exercise type handling
 	params := db.GetLeaderboardByExerciseTypeParams{
-		Type:  exerciseType,
-		Limit: int32(limit),
+		Type:            exerciseType,
+		ScoringStandard: grading.DefaultStandardIDForExercise(exerciseType),
+		Limit:           int32(limit),
 	}
 
 	// Fetch leaderboard data from database
@@ -301,7 +288,7 @@ func (h *Handler) GetLeaderboard(c echo.Context) error {
 	// Cache the result if Redis is available
 	if redisClient != nil && len(respEntries) > 0 {
 		cache := redis.NewLeaderboardCache(redisClient).WithTTL(leaderboardCacheTTL)
-		cacheKey := redis.GlobalLeaderboardKey(exerciseType, limit, timeFrame)
+		cacheKey := redis.GlobalLeaderboardKey(exerciseType, limit, timeFrame.CacheKey(now))
 
 		ctx := c.Request().Context()
 		if err := cache.Set(ctx, cacheKey, respEntries); err != nil {
@@ -321,11 +308,13 @@ func (h *Handler) HandleGetLocalLeaderboard(c echo.Context) error {
 	latStr := c.QueryParam("latitude")
 	lonStr := c.QueryParam("longitude")
 	radiusStr := c.QueryParam("radius_meters") // Optional
-	timeFrame := c.QueryParam("time_frame")    // Time frame parameter
 
-	if timeFrame == "" {
-		timeFrame = "all_time" // Default if not provided
+	// Time frame parameters, default to all_time if not provided
+	timeFrame, err := leaderboards.ParseTimeFrame(timeFrameQuery(c))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
+	timeFrameKey := timeFrame.CacheKey(time.Now())
 
 	if exerciseIDStr == "" || latStr == "" || lonStr == "" {
 		return echo.NewHTTPError(http.StatusBadRequest, "Missing required query parameters: exercise_id, latitude, longitude")
@@ -357,7 +346,7 @@ func (h *Handler) HandleGetLocalLeaderboard(c echo.Context) error {
 
 	if redisClient != nil {
 		cache := redis.NewLeaderboardCache(redisClient).WithTTL(leaderboardCacheTTL)
-		cacheKey := redis.LocalLeaderboardKey(latitude, longitude, radiusMeters, exerciseIDStr, defaultLeaderboardLimit, timeFrame)
+		cacheKey := redis.LocalLeaderboardKey(latitude, longitude, radiusMeters, exerciseIDStr, defaultLeaderboardLimit, timeFrameKey)
 
 		// Try to get from cache
 		ctx := c.Request().Context()
@@ -383,7 +372,7 @@ func (h *Handler) HandleGetLocalLeaderboard(c echo.Context) error {
 				MAX(w.grade) AS best_score,
 				ROW_NUMBER() OVER (ORDER BY MAX(w.grade) DESC) AS rank
 			FROM workouts w
-			WHERE w.exercise_id = $1
+			WHERE w.exercise_id = $1 AND w.deleted_at IS NULL
 			GROUP BY w.user_id
 		)
 		SELECT 
@@ -397,7 +386,7 @@ func (h *Handler) HandleGetLocalLeaderboard(c echo.Context) error {
 			MAX(w.completed_at) AS last_updated
 		FROM ranked_workouts rw
 		JOIN users u ON rw.user_id = u.id
-		JOIN workouts w ON rw.user_id = w.user_id AND w.exercise_id = $1
+		JOIN workouts w ON rw.user_id = w.user_id AND w.exercise_id = $1 AND w.deleted_at IS NULL
 		WHERE u.last_location IS NOT NULL
 		AND ST_DWithin(u.last_location::geography, ST_GeographyFromText($2)::geography, $3)
 		GROUP BY u.id, u.username, u.first_name, u.last_name, rw.best_score, u.last_location
@@ -485,7 +474,7 @@ func (h *Handler) HandleGetLocalLeaderboard(c echo.Context) error {
 	// Cache the result if Redis is available
 	if redisClient != nil && len(respEntries) > 0 {
 		cache := redis.NewLeaderboardCache(redisClient).WithTTL(leaderboardCacheTTL)
-		cacheKey := redis.LocalLeaderboardKey(latitude, longitude, radiusMeters, exerciseIDStr, defaultLeaderboardLimit, timeFrame)
+		cacheKey := redis.LocalLeaderboardKey(latitude, longitude, radiusMeters, exerciseIDStr, defaultLeaderboardLimit, timeFrameKey)
 
 		// Don't store the cachedResult flag in Redis
 		cacheCopy := make([]LocalLeaderboardEntry, len(respEntries))
@@ -576,6 +565,27 @@ func mapStoreLeaderboardEntryToAPIEntry(storeEntry *store.LeaderboardEntry) Lead
 	}
 }
 
+// timeFrameQuery reads the time frame query parameters of a leaderboard request: time_frame,
+// timezone, week_start, and from and to for custom frames.
+func timeFrameQuery(c echo.Context) leaderboards.TimeFrameQuery {
+	return leaderboards.TimeFrameQuery{
+		Name:      c.QueryParam("time_frame"),
+		Timezone:  c.QueryParam("timezone"),
+		WeekStart: c.QueryParam("week_start"),
+		From:      c.QueryParam("from"),
+		To:        c.QueryParam("to"),
+	}
+}
+
+// parseTimeFrame parses the time frame query parameters of a leaderboard request.
+func parseTimeFrame(c echo.Context) (leaderboards.TimeFrame, error) {
+	timeFrame, err := leaderboards.ParseTimeFrame(timeFrameQuery(c))
+	if err != nil {
+		return leaderboards.TimeFrame{}, NewAPIError(http.StatusBadRequest, ErrCodeBadRequest, err.Error())
+	}
+	return timeFrame, nil
+}
+
 // GetGlobalExerciseLeaderboard handles GET /leaderboards/global/exercise/:exerciseType
 func (h *LeaderboardHandler) GetGlobalExerciseLeaderboard(c echo.Context) error {
 	ctx := c.Request().Context()
@@ -597,10 +607,9 @@ func (h *LeaderboardHandler) GetGlobalExerciseLeaderboard(c echo.Context) error
 		limit = defaultLeaderboardLimit
 	}
 
-	// Read the time_frame query parameter
-	timeFrame := c.QueryParam("time_frame")
-	if timeFrame == "" {
-		timeFrame = "all_time" // Default to all_time if not provided
+	timeFrame, err := parseTimeFrame(c)
+	if err != nil {
+		return err
 	}
 
 	h.logger.Debug(ctx, "GetGlobalExerciseLeaderboard called", "type", exerciseType, "limit", limit, "timeFrame", timeFrame)
@@ -669,10 +678,9 @@ func (h *LeaderboardHandler) GetGlobalAggregateLeaderboard(c echo.Context) error
 		limit = defaultLeaderboardLimit
 	}
 
-	// Read the time_frame query parameter
-	timeFrame := c.QueryParam("time_frame")
-	if timeFrame == "" {
-		timeFrame = "all_time" // Default to all_time if not provided
+	timeFrame, err := parseTimeFrame(c)
+	if err != nil {
+		return err
 	}
 
 	// Log which endpoint is being accessed (overall or aggregate)
@@ -755,10 +763,9 @@ func (h *LeaderboardHandler) GetLocalExerciseLeaderboard(c echo.Context) error {
 	lonStr := c.QueryParam("longitude")
 	radiusMetersStr := c.QueryParam("radius_meters")
 	limitStr := c.QueryParam("limit")
-	// Read the time_frame query parameter
-	timeFrame := c.QueryParam("time_frame")
-	if timeFrame == "" {
-		timeFrame = "all_time" // Default to all_time if not provided
+	timeFrame, err := parseTimeFrame(c)
+	if err != nil {
+		return err
 	}
 
 	if latStr == "" || lonStr == "" {
@@ -839,10 +846,9 @@ func (h *LeaderboardHandler) GetLocalAggregateLeaderboard(c echo.Context) error
 	lonStr := c.QueryParam("longitude")
 	radiusMetersStr := c.QueryParam("radius_meters")
 	limitStr := c.QueryParam("limit")
-	// Read the time_frame query parameter
-	timeFrame := c.QueryParam("time_frame")
-	if timeFrame == "" {
-		timeFrame = "all_time" // Default to all_time if not provided
+	timeFrame, err := parseTimeFrame(c)
+	if err != nil {
+		return err
 	}
 
 	if latStr == "" || lonStr == "" {
@@ -927,3 +933,77 @@ func (h *LeaderboardHandler) GetLocalAggregateLeaderboard(c echo.Context) error
 	}
 	return c.JSON(http.StatusOK, apiEntries)
 }
+
+// GetGlobalACFTLeaderboard handles GET /leaderboards/global/acft, ranking users by the
+// sum of their best scores in the six ACFT events
+func (h *LeaderboardHandler) GetGlobalACFTLeaderboard(c echo.Context) error {
+	ctx := c.Request().Context()
+	limit, err := strconv.Atoi(c.QueryParam("limit"))
+	if err != nil || limit <= 0 {
+		limit = defaultLeaderboardLimit
+	}
+	timeFrame, err := parseTimeFrame(c)
+	if err != nil {
+		return err
+	}
+
+	h.logger.Debug(ctx, "GetGlobalACFTLeaderboard called", "limit", limit, "timeFrame", timeFrame)
+
+	storeEntries, err := h.service.GetGlobalACFTLeaderboard(ctx, limit, timeFrame)
+	if err != nil {
+		h.logger.Error(ctx, "Error from GetGlobalACFTLeaderboard service", "timeFrame", timeFrame, "error", err)
+		return NewAPIError(http.StatusInternalServerError, ErrCodeInternalServer, "Failed to retrieve global ACFT leaderboard")
+	}
+
+	apiEntries := make([]LeaderboardAPIEntry, len(storeEntries))
+	for i, entry := range storeEntries {
+		apiEntries[i] = mapStoreLeaderboardEntryToAPIEntry(entry)
+	}
+	return c.JSON(http.StatusOK, apiEntries)
+}
+
+// GetLocalACFTLeaderboard handles GET /leaderboards/local/acft
+func (h *LeaderboardHandler) GetLocalACFTLeaderboard(c echo.Context) error {
+	ctx := c.Request().Context()
+	latStr := c.QueryParam("latitude")
+	lonStr := c.QueryParam("longitude")
+	if latStr == "" || lonStr == "" {
+		return NewAPIError(http.StatusBadRequest, ErrCodeBadRequest, "Missing required query parameters: latitude, longitude")
+	}
+
+	latitude, err := strconv.ParseFloat(latStr, 64)
+	if err != nil {
+		return NewAPIError(http.StatusBadRequest, ErrCodeBadRequest, "Invalid latitude parameter")
+	}
+	longitude, err := strconv.ParseFloat(lonStr, 64)
+	if err != nil {
+		return NewAPIError(http.StatusBadRequest, ErrCodeBadRequest, "Invalid longitude parameter")
+	}
+
+	radiusMeters, err := strconv.Atoi(c.QueryParam("radius_meters"))
+	if err != nil || radiusMeters <= 0 {
+		radiusMeters = defaultSearchRadiusMeters
+	}
+	limit, err := strconv.Atoi(c.QueryParam("limit"))
+	if err != nil || limit <= 0 {
+		limit = defaultLeaderboardLimit
+	}
+	timeFrame, err := parseTimeFrame(c)
+	if err != nil {
+		return err
+	}
+
+	h.logger.Debug(ctx, "GetLocalACFTLeaderboard called", "lat", latitude, "lon", longitude, "radiusM", radiusMeters, "limit", limit, "timeFrame", timeFrame)
+
+	storeEntries, err := h.service.GetLocalACFTLeaderboard(ctx, latitude, longitude, radiusMeters, limit, timeFrame)
+	if err != nil {
+		h.logger.Error(ctx, "Error from GetLocalACFTLeaderboard service", "timeFrame", timeFrame, "error", err)
+		return NewAPIError(http.StatusInternalServerError, ErrCodeInternalServer, "Failed to retrieve local ACFT leaderboard")
+	}
+
+	apiEntries := make([]LeaderboardAPIEntry, len(storeEntries))
+	for i, entry := range storeEntries {
+		apiEntries[i] = mapStoreLeaderboardEntryToAPIEntry(entry)
+	}
+	return c.JSON(http.StatusOK, apiEntries)
+}
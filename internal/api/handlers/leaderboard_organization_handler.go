@@ -0,0 +1,167 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"ptchampion/internal/leaderboards"
+	"ptchampion/internal/store"
+
+	"github.com/labstack/echo/v4"
+)
+
+// UnitLeaderboardAPIEntry is the response model for a unit on a unit-vs-unit leaderboard.
+type UnitLeaderboardAPIEntry struct {
+	Rank           int32   `json:"rank,omitempty"` // Omitted for units below the participation minimum
+	OrganizationID int32   `json:"organization_id"`
+	Name           string  `json:"name"`
+	Type           string  `json:"type"`
+	Score          float64 `json:"score"`
+	Participants   int     `json:"participants"`
+	Members        int     `json:"members"`
+	Qualified      bool    `json:"qualified"`
+}
+
+// organizationLeaderboardError maps the errors of the organization leaderboard endpoints
+// to API errors, or returns nil for errors that are not the client's.
+func organizationLeaderboardError(err error) error {
+	switch {
+	case err == store.ErrOrganizationNotFound:
+		return NewAPIError(http.StatusNotFound, ErrCodeNotFound, "Organization not found")
+	case strings.Contains(err.Error(), "user does not have permission"):
+		return NewAPIError(http.StatusForbidden, ErrCodeForbidden, "You are not a member of this organization")
+	case errors.Is(err, leaderboards.ErrInvalidUnitMetric):
+		return NewAPIError(http.StatusBadRequest, ErrCodeBadRequest, err.Error())
+	}
+	return nil
+}
+
+// parseOrganizationID reads the org_id path parameter.
+func (h *LeaderboardHandler) parseOrganizationID(c echo.Context) (int32, error) {
+	orgIDStr := c.Param("org_id")
+	orgID, err := strconv.Atoi(orgIDStr)
+	if err != nil {
+		h.logger.Warn(c.Request().Context(), "Invalid organization ID format", "orgID", orgIDStr, "error", err)
+		return 0, NewAPIError(http.StatusBadRequest, ErrCodeBadRequest, "Invalid organization ID format")
+	}
+	return int32(orgID), nil
+}
+
+// GetOrganizationExerciseLeaderboard handles GET /leaderboards/organization/:org_id/exercise/:exerciseType
+func (h *LeaderboardHandler) GetOrganizationExerciseLeaderboard(c echo.Context) error {
+	exerciseType := c.Param("exerciseType")
+	if exerciseType == "" {
+		return NewAPIError(http.StatusBadRequest, ErrCodeBadRequest, "Exercise type parameter is required")
+	}
+	return h.getOrganizationLeaderboard(c, exerciseType)
+}
+
+// GetOrganizationOverallLeaderboard handles GET /leaderboards/organization/:org_id/overall
+func (h *LeaderboardHandler) GetOrganizationOverallLeaderboard(c echo.Context) error {
+	return h.getOrganizationLeaderboard(c, leaderboards.BoardOverall)
+}
+
+// GetOrganizationACFTLeaderboard handles GET /leaderboards/organization/:org_id/acft
+func (h *LeaderboardHandler) GetOrganizationACFTLeaderboard(c echo.Context) error {
+	return h.getOrganizationLeaderboard(c, leaderboards.BoardACFT)
+}
+
+func (h *LeaderboardHandler) getOrganizationLeaderboard(c echo.Context, board string) error {
+	ctx := c.Request().Context()
+	userID, err := GetUserIDFromContext(c)
+	if err != nil {
+		h.logger.Error(ctx, "Could not get user ID from context for organization leaderboard", "error", err)
+		return NewAPIError(http.StatusUnauthorized, ErrCodeUnauthorized, "Authentication error: User ID not found")
+	}
+
+	orgID, err := h.parseOrganizationID(c)
+	if err != nil {
+		return err
+	}
+	limit, err := strconv.Atoi(c.QueryParam("limit"))
+	if err != nil || limit <= 0 {
+		limit = defaultLeaderboardLimit
+	}
+	timeFrame, err := parseTimeFrame(c)
+	if err != nil {
+		return err
+	}
+
+	h.logger.Debug(ctx, "GetOrganizationLeaderboard called", "orgID", orgID, "board", board, "limit", limit, "timeFrame", timeFrame)
+
+	storeEntries, err := h.service.GetOrganizationLeaderboard(ctx, userID, orgID, board, limit, timeFrame)
+	if err != nil {
+		if apiErr := organizationLeaderboardError(err); apiErr != nil {
+			return apiErr
+		}
+		h.logger.Error(ctx, "Error from GetOrganizationLeaderboard service", "orgID", orgID, "board", board, "error", err)
+		return NewAPIError(http.StatusInternalServerError, ErrCodeInternalServer, "Failed to retrieve organization leaderboard")
+	}
+
+	apiEntries := make([]LeaderboardAPIEntry, len(storeEntries))
+	for i, entry := range storeEntries {
+		apiEntries[i] = mapStoreLeaderboardEntryToAPIEntry(entry)
+	}
+	return c.JSON(http.StatusOK, apiEntries)
+}
+
+// GetUnitLeaderboard handles GET /leaderboards/organization/:org_id/units, ranking the
+// unit's direct subunits. Query parameters: board (exercise type, overall or acft;
+// default overall), metric (average or median), min_participants, min_participation
+// (percentage of members) and the time frame parameters.
+func (h *LeaderboardHandler) GetUnitLeaderboard(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, err := GetUserIDFromContext(c)
+	if err != nil {
+		h.logger.Error(ctx, "Could not get user ID from context for GetUnitLeaderboard", "error", err)
+		return NewAPIError(http.StatusUnauthorized, ErrCodeUnauthorized, "Authentication error: User ID not found")
+	}
+
+	orgID, err := h.parseOrganizationID(c)
+	if err != nil {
+		return err
+	}
+	board := c.QueryParam("board")
+	if board == "" {
+		board = leaderboards.BoardOverall
+	}
+	minParticipants, _ := strconv.Atoi(c.QueryParam("min_participants"))
+	minParticipation, _ := strconv.ParseFloat(c.QueryParam("min_participation"), 64)
+	timeFrame, err := parseTimeFrame(c)
+	if err != nil {
+		return err
+	}
+
+	opts := leaderboards.UnitRankingOptions{
+		Metric:               c.QueryParam("metric"),
+		MinParticipants:      minParticipants,
+		MinParticipationRate: minParticipation,
+	}
+	h.logger.Debug(ctx, "GetUnitLeaderboard called", "orgID", orgID, "board", board, "metric", opts.Metric, "timeFrame", timeFrame)
+
+	units, err := h.service.GetUnitLeaderboard(ctx, userID, orgID, board, opts, timeFrame)
+	if err != nil {
+		if apiErr := organizationLeaderboardError(err); apiErr != nil {
+			return apiErr
+		}
+		h.logger.Error(ctx, "Error from GetUnitLeaderboard service", "orgID", orgID, "board", board, "error", err)
+		return NewAPIError(http.StatusInternalServerError, ErrCodeInternalServer, "Failed to retrieve unit leaderboard")
+	}
+
+	apiEntries := make([]UnitLeaderboardAPIEntry, len(units))
+	for i, unit := range units {
+		apiEntries[i] = UnitLeaderboardAPIEntry{
+			Rank:           unit.Rank,
+			OrganizationID: unit.Organization.ID,
+			Name:           unit.Organization.Name,
+			Type:           unit.Organization.Type,
+			Score:          unit.Score,
+			Participants:   unit.Participants,
+			Members:        unit.Members,
+			Qualified:      unit.Qualified,
+		}
+	}
+	return c.JSON(http.StatusOK, apiEntries)
+}
@@ -0,0 +1,554 @@
+package handlers
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"ptchampion/internal/grading"
+	"ptchampion/internal/logging"
+	"ptchampion/internal/organizations"
+	"ptchampion/internal/store"
+
+	"github.com/labstack/echo/v4"
+)
+
+// CreateOrganizationRequest defines the API request for creating a unit.
+type CreateOrganizationRequest struct {
+	Name     string `json:"name" validate:"required,max=100"`
+	Type     string `json:"type" validate:"required,oneof=battalion company platoon squad"`
+	ParentID *int32 `json:"parent_id,omitempty" validate:"omitempty,gt=0"` // Omit for a top-level unit
+}
+
+// UpdateOrganizationRequest defines the API request for renaming a unit.
+type UpdateOrganizationRequest struct {
+	Name string `json:"name" validate:"required,max=100"`
+}
+
+// AddOrganizationMemberRequest defines the API request for inviting a user to a unit,
+// identified by user_id or username.
+type AddOrganizationMemberRequest struct {
+	UserID   int32  `json:"user_id,omitempty" validate:"required_without=Username,omitempty,gt=0"`
+	Username string `json:"username,omitempty" validate:"required_without=UserID"`
+	Role     string `json:"role" validate:"omitempty,oneof=member leader admin"` // Defaults to member
+}
+
+// UpdateOrganizationMemberRequest defines the API request for changing a member's role.
+type UpdateOrganizationMemberRequest struct {
+	Role string `json:"role" validate:"required,oneof=member leader admin"`
+}
+
+// UpdateOrganizationMembershipRequest defines the API request for the caller's own
+// membership settings in a unit.
+type UpdateOrganizationMembershipRequest struct {
+	ShareTestResults *bool `json:"share_test_results" validate:"required"`
+}
+
+// OrganizationResponse defines the API response for a unit.
+type OrganizationResponse struct {
+	ID        int32     `json:"id"`
+	ParentID  *int32    `json:"parent_id,omitempty"`
+	Name      string    `json:"name"`
+	Type      string    `json:"type"`
+	Role      string    `json:"role,omitempty"` // Caller's role, where known
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// OrganizationDetailsResponse defines the API response for a unit with its subunits.
+type OrganizationDetailsResponse struct {
+	OrganizationResponse
+	Children []OrganizationResponse `json:"children"`
+}
+
+// OrganizationMemberResponse defines the API response for a unit member.
+type OrganizationMemberResponse struct {
+	OrganizationID   int32     `json:"organization_id"`
+	UserID           int32     `json:"user_id"`
+	Username         string    `json:"username"`
+	FirstName        *string   `json:"first_name,omitempty"`
+	LastName         *string   `json:"last_name,omitempty"`
+	Role             string    `json:"role"`
+	Status           string    `json:"status"` // "pending" until the user accepts the invitation
+	JoinedAt         time.Time `json:"joined_at"`
+	ShareTestResults bool      `json:"share_test_results"` // Leaders may see the member's private test results
+}
+
+// PaginatedOrganizationMembersResponse defines the API response for a page of unit members.
+type PaginatedOrganizationMembersResponse struct {
+	Items      []OrganizationMemberResponse `json:"items"`
+	TotalCount int64                        `json:"totalCount"`
+	Page       int                          `json:"page"`
+	PageSize   int                          `json:"pageSize"`
+	TotalPages int                          `json:"totalPages"`
+}
+
+// OrganizationHandler handles organization-related API requests.
+type OrganizationHandler struct {
+	service organizations.Service
+	logger  logging.Logger
+}
+
+// NewOrganizationHandler creates a new OrganizationHandler instance.
+func NewOrganizationHandler(service organizations.Service, logger logging.Logger) *OrganizationHandler {
+	return &OrganizationHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+func mapOrganizationToResponse(org *store.Organization, role string) OrganizationResponse {
+	return OrganizationResponse{
+		ID:        org.ID,
+		ParentID:  org.ParentID,
+		Name:      org.Name,
+		Type:      org.Type,
+		Role:      role,
+		CreatedAt: org.CreatedAt,
+		UpdatedAt: org.UpdatedAt,
+	}
+}
+
+func mapOrganizationMemberToResponse(member *store.OrganizationMember) OrganizationMemberResponse {
+	return OrganizationMemberResponse{
+		OrganizationID:   member.OrganizationID,
+		UserID:           member.UserID,
+		Username:         member.Username,
+		FirstName:        member.FirstName,
+		LastName:         member.LastName,
+		Role:             member.Role,
+		Status:           member.Status,
+		JoinedAt:         member.JoinedAt,
+		ShareTestResults: member.SharesTestResults,
+	}
+}
+
+// parseIDParam reads a numeric path parameter.
+func (h *OrganizationHandler) parseIDParam(c echo.Context, name string) (int32, error) {
+	idStr := c.Param(name)
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		h.logger.Warn(c.Request().Context(), "Invalid ID format", "param", name, "value", idStr, "error", err)
+		return 0, NewAPIError(http.StatusBadRequest, ErrCodeBadRequest, "Invalid "+name+" format")
+	}
+	return int32(id), nil
+}
+
+// organizationError maps the errors shared by the organization endpoints to API errors,
+// or returns nil for errors that are not the client's.
+func organizationError(err error) error {
+	switch {
+	case err == store.ErrOrganizationNotFound:
+		return NewAPIError(http.StatusNotFound, ErrCodeNotFound, "Organization not found")
+	case err == store.ErrMembershipNotFound:
+		return NewAPIError(http.StatusNotFound, ErrCodeNotFound, "User is not a member of this organization")
+	case err == store.ErrUserNotFound:
+		return NewAPIError(http.StatusNotFound, ErrCodeNotFound, "User not found")
+	case strings.Contains(err.Error(), "user does not have permission"):
+		return NewAPIError(http.StatusForbidden, ErrCodeForbidden, "You do not have permission to manage this organization")
+	case err == store.ErrOrganizationHasChildren:
+		return NewAPIError(http.StatusConflict, ErrCodeConflict, "Delete the organization's subunits first")
+	case err == organizations.ErrLastAdmin:
+		return NewAPIError(http.StatusConflict, ErrCodeConflict, "A top-level organization must keep at least one admin")
+	case errors.Is(err, organizations.ErrInvalidHierarchy), errors.Is(err, organizations.ErrInvalidRole), errors.Is(err, organizations.ErrInvalidStatus),
+		errors.Is(err, grading.ErrUnknownStandard):
+		return NewAPIError(http.StatusBadRequest, ErrCodeBadRequest, err.Error())
+	}
+	return nil
+}
+
+// CreateOrganization handles POST requests creating a unit; the caller becomes its admin.
+func (h *OrganizationHandler) CreateOrganization(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, err := GetUserIDFromContext(c)
+	if err != nil {
+		h.logger.Error(ctx, "Could not get user ID from context for CreateOrganization", "error", err)
+		return NewAPIError(http.StatusUnauthorized, ErrCodeUnauthorized, "Authentication error: User ID not found")
+	}
+
+	var req CreateOrganizationRequest
+	if err := c.Bind(&req); err != nil {
+		return NewAPIError(http.StatusBadRequest, ErrCodeBadRequest, "Invalid request body")
+	}
+	if err := c.Validate(req); err != nil {
+		return NewAPIError(http.StatusBadRequest, ErrCodeValidation, err.Error())
+	}
+
+	org, err := h.service.CreateOrganization(ctx, userID, &organizations.CreateOrganizationData{
+		Name:     req.Name,
+		Type:     req.Type,
+		ParentID: req.ParentID,
+	})
+	if err != nil {
+		if apiErr := organizationError(err); apiErr != nil {
+			return apiErr
+		}
+		h.logger.Error(ctx, "Service failed to create organization", "userID", userID, "error", err)
+		return NewAPIError(http.StatusInternalServerError, ErrCodeInternalServer, "Failed to create organization")
+	}
+
+	return c.JSON(http.StatusCreated, mapOrganizationToResponse(org, store.OrganizationRoleAdmin))
+}
+
+// ListMyOrganizations handles GET requests for the units the user is a member of.
+func (h *OrganizationHandler) ListMyOrganizations(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, err := GetUserIDFromContext(c)
+	if err != nil {
+		h.logger.Error(ctx, "Could not get user ID from context for ListMyOrganizations", "error", err)
+		return NewAPIError(http.StatusUnauthorized, ErrCodeUnauthorized, "Authentication error: User ID not found")
+	}
+
+	memberships, err := h.service.ListUserOrganizations(ctx, userID)
+	if err != nil {
+		h.logger.Error(ctx, "Service failed to list organizations", "userID", userID, "error", err)
+		return NewAPIError(http.StatusInternalServerError, ErrCodeInternalServer, "Failed to retrieve organizations")
+	}
+
+	resp := make([]OrganizationResponse, len(memberships))
+	for i, m := range memberships {
+		resp[i] = mapOrganizationToResponse(m.Organization, m.Role)
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// ListMyOrganizationInvitations handles GET requests for the units the user has been
+// invited to and not yet joined.
+func (h *OrganizationHandler) ListMyOrganizationInvitations(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, err := GetUserIDFromContext(c)
+	if err != nil {
+		h.logger.Error(ctx, "Could not get user ID from context for ListMyOrganizationInvitations", "error", err)
+		return NewAPIError(http.StatusUnauthorized, ErrCodeUnauthorized, "Authentication error: User ID not found")
+	}
+
+	invitations, err := h.service.ListUserInvitations(ctx, userID)
+	if err != nil {
+		h.logger.Error(ctx, "Service failed to list organization invitations", "userID", userID, "error", err)
+		return NewAPIError(http.StatusInternalServerError, ErrCodeInternalServer, "Failed to retrieve organization invitations")
+	}
+
+	resp := make([]OrganizationResponse, len(invitations))
+	for i, m := range invitations {
+		resp[i] = mapOrganizationToResponse(m.Organization, m.Role) // The role offered
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// AcceptOrganizationInvitation handles POST requests joining a unit the user was invited to.
+func (h *OrganizationHandler) AcceptOrganizationInvitation(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, err := GetUserIDFromContext(c)
+	if err != nil {
+		h.logger.Error(ctx, "Could not get user ID from context for AcceptOrganizationInvitation", "error", err)
+		return NewAPIError(http.StatusUnauthorized, ErrCodeUnauthorized, "Authentication error: User ID not found")
+	}
+
+	orgID, err := h.parseIDParam(c, "org_id")
+	if err != nil {
+		return err
+	}
+
+	member, err := h.service.AcceptInvitation(ctx, userID, orgID)
+	if err != nil {
+		if err == store.ErrMembershipNotFound {
+			return NewAPIError(http.StatusNotFound, ErrCodeNotFound, "No pending invitation to this organization")
+		}
+		h.logger.Error(ctx, "Service failed to accept organization invitation", "userID", userID, "orgID", orgID, "error", err)
+		return NewAPIError(http.StatusInternalServerError, ErrCodeInternalServer, "Failed to accept organization invitation")
+	}
+
+	return c.JSON(http.StatusOK, mapOrganizationMemberToResponse(member))
+}
+
+// UpdateMyOrganizationMembership handles PATCH requests changing the caller's own
+// membership settings, such as sharing private test results with the unit's leaders.
+func (h *OrganizationHandler) UpdateMyOrganizationMembership(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, err := GetUserIDFromContext(c)
+	if err != nil {
+		h.logger.Error(ctx, "Could not get user ID from context for UpdateMyOrganizationMembership", "error", err)
+		return NewAPIError(http.StatusUnauthorized, ErrCodeUnauthorized, "Authentication error: User ID not found")
+	}
+
+	orgID, err := h.parseIDParam(c, "org_id")
+	if err != nil {
+		return err
+	}
+
+	var req UpdateOrganizationMembershipRequest
+	if err := c.Bind(&req); err != nil {
+		return NewAPIError(http.StatusBadRequest, ErrCodeBadRequest, "Invalid request body")
+	}
+	if err := c.Validate(req); err != nil {
+		return NewAPIError(http.StatusBadRequest, ErrCodeValidation, err.Error())
+	}
+
+	member, err := h.service.SetTestResultSharing(ctx, userID, orgID, *req.ShareTestResults)
+	if err != nil {
+		if apiErr := organizationError(err); apiErr != nil {
+			return apiErr
+		}
+		h.logger.Error(ctx, "Service failed to update organization membership", "userID", userID, "orgID", orgID, "error", err)
+		return NewAPIError(http.StatusInternalServerError, ErrCodeInternalServer, "Failed to update organization membership")
+	}
+
+	return c.JSON(http.StatusOK, mapOrganizationMemberToResponse(member))
+}
+
+// GetOrganization handles GET requests for a unit and its subunits.
+func (h *OrganizationHandler) GetOrganization(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, err := GetUserIDFromContext(c)
+	if err != nil {
+		h.logger.Error(ctx, "Could not get user ID from context for GetOrganization", "error", err)
+		return NewAPIError(http.StatusUnauthorized, ErrCodeUnauthorized, "Authentication error: User ID not found")
+	}
+
+	orgID, err := h.parseIDParam(c, "org_id")
+	if err != nil {
+		return err
+	}
+
+	details, err := h.service.GetOrganization(ctx, userID, orgID)
+	if err != nil {
+		if apiErr := organizationError(err); apiErr != nil {
+			return apiErr
+		}
+		h.logger.Error(ctx, "Service failed to get organization", "userID", userID, "orgID", orgID, "error", err)
+		return NewAPIError(http.StatusInternalServerError, ErrCodeInternalServer, "Failed to retrieve organization")
+	}
+
+	resp := OrganizationDetailsResponse{
+		OrganizationResponse: mapOrganizationToResponse(details.Organization, details.Role),
+		Children:             make([]OrganizationResponse, len(details.Children)),
+	}
+	for i, child := range details.Children {
+		resp.Children[i] = mapOrganizationToResponse(child, details.Role) // Roles are inherited
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// UpdateOrganization handles PATCH requests renaming a unit.
+func (h *OrganizationHandler) UpdateOrganization(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, err := GetUserIDFromContext(c)
+	if err != nil {
+		h.logger.Error(ctx, "Could not get user ID from context for UpdateOrganization", "error", err)
+		return NewAPIError(http.StatusUnauthorized, ErrCodeUnauthorized, "Authentication error: User ID not found")
+	}
+
+	orgID, err := h.parseIDParam(c, "org_id")
+	if err != nil {
+		return err
+	}
+
+	var req UpdateOrganizationRequest
+	if err := c.Bind(&req); err != nil {
+		return NewAPIError(http.StatusBadRequest, ErrCodeBadRequest, "Invalid request body")
+	}
+	if err := c.Validate(req); err != nil {
+		return NewAPIError(http.StatusBadRequest, ErrCodeValidation, err.Error())
+	}
+
+	org, err := h.service.RenameOrganization(ctx, userID, orgID, req.Name)
+	if err != nil {
+		if apiErr := organizationError(err); apiErr != nil {
+			return apiErr
+		}
+		h.logger.Error(ctx, "Service failed to update organization", "userID", userID, "orgID", orgID, "error", err)
+		return NewAPIError(http.StatusInternalServerError, ErrCodeInternalServer, "Failed to update organization")
+	}
+
+	return c.JSON(http.StatusOK, mapOrganizationToResponse(org, ""))
+}
+
+// DeleteOrganization handles DELETE requests for a unit without subunits.
+func (h *OrganizationHandler) DeleteOrganization(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, err := GetUserIDFromContext(c)
+	if err != nil {
+		h.logger.Error(ctx, "Could not get user ID from context for DeleteOrganization", "error", err)
+		return NewAPIError(http.StatusUnauthorized, ErrCodeUnauthorized, "Authentication error: User ID not found")
+	}
+
+	orgID, err := h.parseIDParam(c, "org_id")
+	if err != nil {
+		return err
+	}
+
+	if err := h.service.DeleteOrganization(ctx, userID, orgID); err != nil {
+		if apiErr := organizationError(err); apiErr != nil {
+			return apiErr
+		}
+		h.logger.Error(ctx, "Service failed to delete organization", "userID", userID, "orgID", orgID, "error", err)
+		return NewAPIError(http.StatusInternalServerError, ErrCodeInternalServer, "Failed to delete organization")
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// ListOrganizationMembers handles GET requests for a page of a unit's members. Set
+// include_descendants=true to include the members of all subunits, and status=pending
+// to list outstanding invitations instead (leaders only).
+func (h *OrganizationHandler) ListOrganizationMembers(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, err := GetUserIDFromContext(c)
+	if err != nil {
+		h.logger.Error(ctx, "Could not get user ID from context for ListOrganizationMembers", "error", err)
+		return NewAPIError(http.StatusUnauthorized, ErrCodeUnauthorized, "Authentication error: User ID not found")
+	}
+
+	orgID, err := h.parseIDParam(c, "org_id")
+	if err != nil {
+		return err
+	}
+
+	page, _ := strconv.Atoi(c.QueryParam("page"))
+	pageSize, _ := strconv.Atoi(c.QueryParam("pageSize"))
+	includeDescendants, _ := strconv.ParseBool(c.QueryParam("include_descendants"))
+
+	members, err := h.service.ListMembers(ctx, userID, orgID, includeDescendants, c.QueryParam("status"), page, pageSize)
+	if err != nil {
+		if apiErr := organizationError(err); apiErr != nil {
+			return apiErr
+		}
+		h.logger.Error(ctx, "Service failed to list organization members", "userID", userID, "orgID", orgID, "error", err)
+		return NewAPIError(http.StatusInternalServerError, ErrCodeInternalServer, "Failed to retrieve organization members")
+	}
+
+	items := make([]OrganizationMemberResponse, len(members.Members))
+	for i, member := range members.Members {
+		items[i] = mapOrganizationMemberToResponse(member)
+	}
+
+	actualPage := page
+	if actualPage < 1 {
+		actualPage = 1
+	}
+	actualPageSize := pageSize
+	if actualPageSize < 1 || actualPageSize > 100 {
+		actualPageSize = 20
+	}
+
+	return c.JSON(http.StatusOK, PaginatedOrganizationMembersResponse{
+		Items:      items,
+		TotalCount: members.TotalCount,
+		Page:       actualPage,
+		PageSize:   actualPageSize,
+		TotalPages: int(math.Ceil(float64(members.TotalCount) / float64(actualPageSize))),
+	})
+}
+
+// AddOrganizationMember handles POST requests inviting a user to a unit. The user
+// joins once they accept; members already in the unit have their role changed.
+func (h *OrganizationHandler) AddOrganizationMember(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, err := GetUserIDFromContext(c)
+	if err != nil {
+		h.logger.Error(ctx, "Could not get user ID from context for AddOrganizationMember", "error", err)
+		return NewAPIError(http.StatusUnauthorized, ErrCodeUnauthorized, "Authentication error: User ID not found")
+	}
+
+	orgID, err := h.parseIDParam(c, "org_id")
+	if err != nil {
+		return err
+	}
+
+	var req AddOrganizationMemberRequest
+	if err := c.Bind(&req); err != nil {
+		return NewAPIError(http.StatusBadRequest, ErrCodeBadRequest, "Invalid request body")
+	}
+	if err := c.Validate(req); err != nil {
+		return NewAPIError(http.StatusBadRequest, ErrCodeValidation, err.Error())
+	}
+	if req.Role == "" {
+		req.Role = store.OrganizationRoleMember
+	}
+
+	member, err := h.service.AddMember(ctx, userID, orgID, &organizations.AddMemberData{
+		UserID:   req.UserID,
+		Username: req.Username,
+		Role:     req.Role,
+	})
+	if err != nil {
+		if apiErr := organizationError(err); apiErr != nil {
+			return apiErr
+		}
+		h.logger.Error(ctx, "Service failed to add organization member", "userID", userID, "orgID", orgID, "error", err)
+		return NewAPIError(http.StatusInternalServerError, ErrCodeInternalServer, "Failed to add organization member")
+	}
+
+	return c.JSON(http.StatusOK, mapOrganizationMemberToResponse(member))
+}
+
+// UpdateOrganizationMember handles PATCH requests changing a member's role.
+func (h *OrganizationHandler) UpdateOrganizationMember(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, err := GetUserIDFromContext(c)
+	if err != nil {
+		h.logger.Error(ctx, "Could not get user ID from context for UpdateOrganizationMember", "error", err)
+		return NewAPIError(http.StatusUnauthorized, ErrCodeUnauthorized, "Authentication error: User ID not found")
+	}
+
+	orgID, err := h.parseIDParam(c, "org_id")
+	if err != nil {
+		return err
+	}
+	memberID, err := h.parseIDParam(c, "user_id")
+	if err != nil {
+		return err
+	}
+
+	var req UpdateOrganizationMemberRequest
+	if err := c.Bind(&req); err != nil {
+		return NewAPIError(http.StatusBadRequest, ErrCodeBadRequest, "Invalid request body")
+	}
+	if err := c.Validate(req); err != nil {
+		return NewAPIError(http.StatusBadRequest, ErrCodeValidation, err.Error())
+	}
+
+	member, err := h.service.UpdateMemberRole(ctx, userID, orgID, memberID, req.Role)
+	if err != nil {
+		if apiErr := organizationError(err); apiErr != nil {
+			return apiErr
+		}
+		h.logger.Error(ctx, "Service failed to update organization member", "userID", userID, "orgID", orgID, "memberID", memberID, "error", err)
+		return NewAPIError(http.StatusInternalServerError, ErrCodeInternalServer, "Failed to update organization member")
+	}
+
+	return c.JSON(http.StatusOK, mapOrganizationMemberToResponse(member))
+}
+
+// RemoveOrganizationMember handles DELETE requests removing a user from a unit or
+// withdrawing their invitation. Users decline an invitation by removing themselves.
+func (h *OrganizationHandler) RemoveOrganizationMember(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, err := GetUserIDFromContext(c)
+	if err != nil {
+		h.logger.Error(ctx, "Could not get user ID from context for RemoveOrganizationMember", "error", err)
+		return NewAPIError(http.StatusUnauthorized, ErrCodeUnauthorized, "Authentication error: User ID not found")
+	}
+
+	orgID, err := h.parseIDParam(c, "org_id")
+	if err != nil {
+		return err
+	}
+	memberID, err := h.parseIDParam(c, "user_id")
+	if err != nil {
+		return err
+	}
+
+	if err := h.service.RemoveMember(ctx, userID, orgID, memberID); err != nil {
+		if apiErr := organizationError(err); apiErr != nil {
+			return apiErr
+		}
+		h.logger.Error(ctx, "Service failed to remove organization member", "userID", userID, "orgID", orgID, "memberID", memberID, "error", err)
+		return NewAPIError(http.StatusInternalServerError, ErrCodeInternalServer, "Failed to remove organization member")
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"ptchampion/internal/organizations"
+
+	"github.com/labstack/echo/v4"
+)
+
+// ScoreBucketResponse defines the API response for one bucket of a score distribution.
+type ScoreBucketResponse struct {
+	Min   int `json:"min"`
+	Max   int `json:"max"`
+	Count int `json:"count"`
+}
+
+// EventReadinessResponse defines the API response for one event across a unit's latest tests.
+type EventReadinessResponse struct {
+	ExerciseType string                `json:"exercise_type"`
+	Tested       int                   `json:"tested"`
+	Passing      int                   `json:"passing"`
+	AverageScore float64               `json:"average_score"`
+	Distribution []ScoreBucketResponse `json:"distribution"`
+}
+
+// MemberReadinessResponse defines the API response for a member's latest test.
+type MemberReadinessResponse struct {
+	OrganizationMemberResponse
+	LastTestAt  *time.Time `json:"last_test_at,omitempty"`
+	TotalScore  int32      `json:"total_score,omitempty"`
+	Passed      bool       `json:"passed"`
+	LowestEvent string     `json:"lowest_event,omitempty"`
+	LowestScore int32      `json:"lowest_score,omitempty"`
+}
+
+// ReadinessTrendPointResponse defines the API response for one month of the readiness trend.
+type ReadinessTrendPointResponse struct {
+	Month             string  `json:"month"` // YYYY-MM
+	Tests             int     `json:"tests"`
+	AverageTotalScore float64 `json:"average_total_score"`
+	PassRate          float64 `json:"pass_rate"`
+}
+
+// ReadinessDashboardResponse defines the API response for a unit's readiness dashboard.
+type ReadinessDashboardResponse struct {
+	Organization    OrganizationResponse          `json:"organization"`
+	ScoringStandard string                        `json:"scoring_standard"` // Standard of the tests the dashboard covers
+	Members         int                           `json:"members"`
+	Tested          int                           `json:"tested"`
+	Passing         int                           `json:"passing"`
+	PassRate        float64                       `json:"pass_rate"`
+	Events          []EventReadinessResponse      `json:"events"`
+	AtRisk          []MemberReadinessResponse     `json:"at_risk"`
+	NotTested       []MemberReadinessResponse     `json:"not_tested"`
+	Trend           []ReadinessTrendPointResponse `json:"trend"`
+	StaleDays       int                           `json:"stale_days"`
+	GeneratedAt     time.Time                     `json:"generated_at"`
+}
+
+func mapMemberReadinessToResponse(members []*organizations.MemberReadiness) []MemberReadinessResponse {
+	resp := make([]MemberReadinessResponse, len(members))
+	for i, m := range members {
+		resp[i] = MemberReadinessResponse{
+			OrganizationMemberResponse: mapOrganizationMemberToResponse(m.Member),
+			LastTestAt:                 m.LastTestAt,
+			TotalScore:                 m.TotalScore,
+			Passed:                     m.Passed,
+			LowestEvent:                m.LowestEvent,
+			LowestScore:                m.LowestScore,
+		}
+	}
+	return resp
+}
+
+// GetReadinessDashboard handles GET requests for the readiness of a unit and its
+// subunits. Optional query parameters: stale_days (default 180), months (default 12) and
+// standard (default: the standard of the unit's latest test).
+func (h *OrganizationHandler) GetReadinessDashboard(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, err := GetUserIDFromContext(c)
+	if err != nil {
+		h.logger.Error(ctx, "Could not get user ID from context for GetReadinessDashboard", "error", err)
+		return NewAPIError(http.StatusUnauthorized, ErrCodeUnauthorized, "Authentication error: User ID not found")
+	}
+
+	orgID, err := h.parseIDParam(c, "org_id")
+	if err != nil {
+		return err
+	}
+
+	staleDays, _ := strconv.Atoi(c.QueryParam("stale_days"))
+	months, _ := strconv.Atoi(c.QueryParam("months"))
+
+	dashboard, err := h.service.GetReadinessDashboard(ctx, userID, orgID, organizations.ReadinessOptions{
+		StaleDays:       staleDays,
+		TrendMonths:     months,
+		ScoringStandard: c.QueryParam("standard"),
+	})
+	if err != nil {
+		if apiErr := organizationError(err); apiErr != nil {
+			return apiErr
+		}
+		h.logger.Error(ctx, "Service failed to get readiness dashboard", "userID", userID, "orgID", orgID, "error", err)
+		return NewAPIError(http.StatusInternalServerError, ErrCodeInternalServer, "Failed to retrieve readiness dashboard")
+	}
+
+	resp := ReadinessDashboardResponse{
+		Organization:    mapOrganizationToResponse(dashboard.Organization, ""),
+		ScoringStandard: dashboard.ScoringStandard,
+		Members:         dashboard.Members,
+		Tested:          dashboard.Tested,
+		Passing:         dashboard.Passing,
+		PassRate:        dashboard.PassRate,
+		Events:          make([]EventReadinessResponse, len(dashboard.Events)),
+		AtRisk:          mapMemberReadinessToResponse(dashboard.AtRisk),
+		NotTested:       mapMemberReadinessToResponse(dashboard.NotTested),
+		Trend:           make([]ReadinessTrendPointResponse, len(dashboard.Trend)),
+		StaleDays:       dashboard.StaleDays,
+		GeneratedAt:     dashboard.GeneratedAt,
+	}
+	for i, ev := range dashboard.Events {
+		buckets := make([]ScoreBucketResponse, len(ev.Distribution))
+		for j, b := range ev.Distribution {
+			buckets[j] = ScoreBucketResponse{Min: b.Min, Max: b.Max, Count: b.Count}
+		}
+		resp.Events[i] = EventReadinessResponse{
+			ExerciseType: ev.ExerciseType,
+			Tested:       ev.Tested,
+			Passing:      ev.Passing,
+			AverageScore: ev.AverageScore,
+			Distribution: buckets,
+		}
+	}
+	for i, point := range dashboard.Trend {
+		resp.Trend[i] = ReadinessTrendPointResponse{
+			Month:             point.Month.Format("2006-01"),
+			Tests:             point.Tests,
+			AverageTotalScore: point.AverageTotalScore,
+			PassRate:          point.PassRate,
+		}
+	}
+	return c.JSON(http.StatusOK, resp)
+}
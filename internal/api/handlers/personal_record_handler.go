@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"ptchampion/internal/store"
+
+	"github.com/labstack/echo/v4"
+)
+
+// PersonalRecordResponse defines the API response for a personal record.
+type PersonalRecordResponse struct {
+	ID            int32     `json:"id"`
+	ExerciseType  string    `json:"exercise_type"`
+	Metric        string    `json:"metric"` // reps, duration_seconds, weight_lbs, distance_meters or grade
+	Value         float64   `json:"value"`
+	PreviousValue *float64  `json:"previous_value,omitempty"` // Omitted for a first record
+	WorkoutID     int32     `json:"workout_id"`
+	AchievedAt    time.Time `json:"achieved_at"`
+}
+
+// PaginatedPersonalRecordsResponse defines the API response for a page of personal record history.
+type PaginatedPersonalRecordsResponse struct {
+	Items      []PersonalRecordResponse `json:"items"`
+	TotalCount int64                    `json:"totalCount"`
+	Page       int                      `json:"page"`
+	PageSize   int                      `json:"pageSize"`
+	TotalPages int                      `json:"totalPages"`
+}
+
+func mapPersonalRecordsToResponse(records []*store.PersonalRecord) []PersonalRecordResponse {
+	resp := make([]PersonalRecordResponse, len(records))
+	for i, r := range records {
+		resp[i] = PersonalRecordResponse{
+			ID:            r.ID,
+			ExerciseType:  r.ExerciseType,
+			Metric:        r.Metric,
+			Value:         r.Value,
+			PreviousValue: r.PreviousValue,
+			WorkoutID:     r.WorkoutID,
+			AchievedAt:    r.AchievedAt,
+		}
+	}
+	return resp
+}
+
+// GetPersonalRecords handles GET requests for the user's current personal records.
+func (h *WorkoutHandler) GetPersonalRecords(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, err := GetUserIDFromContext(c)
+	if err != nil {
+		h.logger.Error(ctx, "Could not get user ID from context for GetPersonalRecords", "error", err)
+		return NewAPIError(http.StatusUnauthorized, ErrCodeUnauthorized, "Authentication error: User ID not found")
+	}
+
+	records, err := h.service.GetPersonalRecords(ctx, userID)
+	if err != nil {
+		h.logger.Error(ctx, "Service failed to get personal records", "userID", userID, "error", err)
+		return NewAPIError(http.StatusInternalServerError, ErrCodeInternalServer, "Failed to retrieve personal records")
+	}
+	return c.JSON(http.StatusOK, mapPersonalRecordsToResponse(records))
+}
+
+// GetPersonalRecordHistory handles GET requests for every personal record the user has set,
+// newest first. Optional query parameters: exerciseType, page and pageSize.
+func (h *WorkoutHandler) GetPersonalRecordHistory(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, err := GetUserIDFromContext(c)
+	if err != nil {
+		h.logger.Error(ctx, "Could not get user ID from context for GetPersonalRecordHistory", "error", err)
+		return NewAPIError(http.StatusUnauthorized, ErrCodeUnauthorized, "Authentication error: User ID not found")
+	}
+
+	exerciseType := c.QueryParam("exerciseType")
+	page, _ := strconv.Atoi(c.QueryParam("page"))
+	pageSize, _ := strconv.Atoi(c.QueryParam("pageSize"))
+
+	history, err := h.service.GetPersonalRecordHistory(ctx, userID, exerciseType, page, pageSize)
+	if err != nil {
+		h.logger.Error(ctx, "Service failed to get personal record history", "userID", userID, "error", err)
+		return NewAPIError(http.StatusInternalServerError, ErrCodeInternalServer, "Failed to retrieve personal record history")
+	}
+
+	actualPage := page
+	if actualPage < 1 {
+		actualPage = 1
+	}
+	actualPageSize := pageSize
+	if actualPageSize < 1 || actualPageSize > 100 {
+		actualPageSize = 20
+	}
+
+	return c.JSON(http.StatusOK, PaginatedPersonalRecordsResponse{
+		Items:      mapPersonalRecordsToResponse(history.Records),
+		TotalCount: history.TotalCount,
+		Page:       actualPage,
+		PageSize:   actualPageSize,
+		TotalPages: int(math.Ceil(float64(history.TotalCount) / float64(actualPageSize))),
+	})
+}
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"ptchampion/internal/api/middleware"
+	"ptchampion/internal/grading"
+	"ptchampion/internal/logging"
+)
+
+// ScoringHandler handles scoring standard API requests
+type ScoringHandler struct {
+	registry *grading.Registry
+	logger   logging.Logger
+}
+
+// NewScoringHandler creates a new scoring handler instance
+func NewScoringHandler(registry *grading.Registry, logger logging.Logger) *ScoringHandler {
+	return &ScoringHandler{
+		registry: registry,
+		logger:   logger,
+	}
+}
+
+// ScoringStandardResponse describes a scoring standard available for grading workouts
+type ScoringStandardResponse struct {
+	ID           string                  `json:"id"`
+	Name         string                  `json:"name"`
+	Version      string                  `json:"version"`
+	Description  string                  `json:"description"`
+	PassPoints   int                     `json:"pass_points"`
+	IsDefault    bool                    `json:"is_default"`
+	Experimental bool                    `json:"experimental"` // Selectable only when the grading formula flag is on
+	Events       []grading.StandardEvent `json:"events"`
+	Test         TestProtocolResponse    `json:"test"`
+}
+
+// TestProtocolResponse describes how a full test is taken under a standard
+type TestProtocolResponse struct {
+	Sequence       [][]string `json:"sequence"` // Event IDs in order; alternates share a slot
+	MinRestSeconds int        `json:"min_rest_seconds"`
+	MaxRestSeconds int        `json:"max_rest_seconds"`
+}
+
+// ListStandards returns the registered scoring standards
+func (h *ScoringHandler) ListStandards(c echo.Context) error {
+	standards := h.registry.List()
+
+	response := make([]ScoringStandardResponse, 0, len(standards))
+	for _, s := range standards {
+		response = append(response, ScoringStandardResponse{
+			ID:           s.ID(),
+			Name:         s.Name(),
+			Version:      s.Version(),
+			Description:  s.Description(),
+			PassPoints:   s.PassPoints(),
+			IsDefault:    s.ID() == grading.DefaultStandardID,
+			Experimental: s.Experimental(),
+			Events:       s.Events(),
+			Test: TestProtocolResponse{
+				Sequence:       s.Protocol().Sequence,
+				MinRestSeconds: int(s.Protocol().MinRest.Seconds()),
+				MaxRestSeconds: int(s.Protocol().MaxRest.Seconds()),
+			},
+		})
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// scoringStandardEnabled reports whether a request may select the scoring standard.
+// Experimental standards are rolled out behind the grading formula flag, which is off
+// unless the flag service enables it for the user; the released standards are always
+// available. Unknown IDs are left for the services to reject.
+func scoringStandardEnabled(c echo.Context, standardID string) bool {
+	standard, err := grading.LookupStandard(standardID)
+	if err != nil || !standard.Experimental() {
+		return true
+	}
+	return middleware.FlagEnabled(c, middleware.FlagGradingFormulaV2, false)
+}
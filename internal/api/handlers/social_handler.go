@@ -0,0 +1,320 @@
+package handlers
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"ptchampion/internal/logging"
+	"ptchampion/internal/social"
+	"ptchampion/internal/store"
+
+	"github.com/labstack/echo/v4"
+)
+
+// SendFriendRequestRequest defines the API request for asking a user, identified by
+// user_id or username, to be friends.
+type SendFriendRequestRequest struct {
+	UserID   int32  `json:"user_id,omitempty" validate:"required_without=Username,omitempty,gt=0"`
+	Username string `json:"username,omitempty" validate:"required_without=UserID"`
+}
+
+// FriendshipResponse defines the API response for the relationship between two users.
+type FriendshipResponse struct {
+	ID          int32     `json:"id"`
+	RequesterID int32     `json:"requester_id"`
+	AddresseeID int32     `json:"addressee_id"`
+	Status      string    `json:"status"` // pending or accepted
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// FriendResponse defines the API response for a friend, request or block, described by
+// the other user.
+type FriendResponse struct {
+	UserID    int32     `json:"user_id"`
+	Username  string    `json:"username"`
+	FirstName *string   `json:"first_name,omitempty"`
+	LastName  *string   `json:"last_name,omitempty"`
+	Status    string    `json:"status"`
+	Direction string    `json:"direction"` // outgoing when the caller sent the request or made the block
+	Since     time.Time `json:"since"`
+}
+
+// PaginatedFriendsResponse defines the API response for a page of friends, requests or blocks.
+type PaginatedFriendsResponse struct {
+	Items      []FriendResponse `json:"items"`
+	TotalCount int64            `json:"totalCount"`
+	Page       int              `json:"page"`
+	PageSize   int              `json:"pageSize"`
+	TotalPages int              `json:"totalPages"`
+}
+
+// SocialHandler handles friendship-related API requests.
+type SocialHandler struct {
+	service social.Service
+	logger  logging.Logger
+}
+
+// NewSocialHandler creates a new SocialHandler instance.
+func NewSocialHandler(service social.Service, logger logging.Logger) *SocialHandler {
+	return &SocialHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+func mapFriendshipToResponse(friendship *store.Friendship) FriendshipResponse {
+	return FriendshipResponse{
+		ID:          friendship.ID,
+		RequesterID: friendship.RequesterID,
+		AddresseeID: friendship.AddresseeID,
+		Status:      friendship.Status,
+		CreatedAt:   friendship.CreatedAt,
+		UpdatedAt:   friendship.UpdatedAt,
+	}
+}
+
+func mapFriendToResponse(friend *store.Friend) FriendResponse {
+	direction := store.FriendshipDirectionIncoming
+	if friend.Friendship.AddresseeID == friend.UserID {
+		direction = store.FriendshipDirectionOutgoing
+	}
+	return FriendResponse{
+		UserID:    friend.UserID,
+		Username:  friend.Username,
+		FirstName: friend.FirstName,
+		LastName:  friend.LastName,
+		Status:    friend.Friendship.Status,
+		Direction: direction,
+		Since:     friend.Friendship.UpdatedAt,
+	}
+}
+
+// parseUserIDParam reads the user_id path parameter.
+func (h *SocialHandler) parseUserIDParam(c echo.Context) (int32, error) {
+	idStr := c.Param("user_id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		h.logger.Warn(c.Request().Context(), "Invalid user ID format", "userID", idStr, "error", err)
+		return 0, NewAPIError(http.StatusBadRequest, ErrCodeBadRequest, "Invalid user_id format")
+	}
+	return int32(id), nil
+}
+
+// socialError maps the errors of the friendship endpoints to API errors, or returns nil
+// for errors that are not the client's.
+func socialError(err error) error {
+	switch err {
+	case store.ErrUserNotFound:
+		return NewAPIError(http.StatusNotFound, ErrCodeNotFound, "User not found")
+	case store.ErrFriendshipNotFound:
+		return NewAPIError(http.StatusNotFound, ErrCodeNotFound, "Friendship not found")
+	case social.ErrInvalidFriendRequest:
+		return NewAPIError(http.StatusBadRequest, ErrCodeBadRequest, err.Error())
+	case social.ErrAlreadyFriends, social.ErrFriendRequestExists:
+		return NewAPIError(http.StatusConflict, ErrCodeConflict, err.Error())
+	case social.ErrUserBlocked:
+		return NewAPIError(http.StatusConflict, ErrCodeConflict, "Unblock this user before sending a friend request")
+	}
+	return nil
+}
+
+// ListFriends handles GET requests for a page of the caller's friends.
+func (h *SocialHandler) ListFriends(c echo.Context) error {
+	return h.listFriendships(c, store.FriendshipFilters{Status: store.FriendshipStatusAccepted})
+}
+
+// ListFriendRequests handles GET requests for a page of the caller's pending friend
+// requests. Set direction=incoming or direction=outgoing to list one side only.
+func (h *SocialHandler) ListFriendRequests(c echo.Context) error {
+	direction := c.QueryParam("direction")
+	switch direction {
+	case "", store.FriendshipDirectionIncoming, store.FriendshipDirectionOutgoing:
+	default:
+		return NewAPIError(http.StatusBadRequest, ErrCodeBadRequest, "direction must be incoming or outgoing")
+	}
+	return h.listFriendships(c, store.FriendshipFilters{Status: store.FriendshipStatusPending, Direction: direction})
+}
+
+// ListBlockedUsers handles GET requests for a page of the users the caller has blocked.
+func (h *SocialHandler) ListBlockedUsers(c echo.Context) error {
+	return h.listFriendships(c, store.FriendshipFilters{Status: store.FriendshipStatusBlocked})
+}
+
+func (h *SocialHandler) listFriendships(c echo.Context, filters store.FriendshipFilters) error {
+	ctx := c.Request().Context()
+	userID, err := GetUserIDFromContext(c)
+	if err != nil {
+		h.logger.Error(ctx, "Could not get user ID from context for friendship list", "error", err)
+		return NewAPIError(http.StatusUnauthorized, ErrCodeUnauthorized, "Authentication error: User ID not found")
+	}
+
+	page, _ := strconv.Atoi(c.QueryParam("page"))
+	pageSize, _ := strconv.Atoi(c.QueryParam("pageSize"))
+
+	friends, err := h.service.ListFriendships(ctx, userID, filters, page, pageSize)
+	if err != nil {
+		h.logger.Error(ctx, "Service failed to list friendships", "userID", userID, "status", filters.Status, "error", err)
+		return NewAPIError(http.StatusInternalServerError, ErrCodeInternalServer, "Failed to retrieve friends")
+	}
+
+	items := make([]FriendResponse, len(friends.Friends))
+	for i, friend := range friends.Friends {
+		items[i] = mapFriendToResponse(friend)
+	}
+
+	actualPage := page
+	if actualPage < 1 {
+		actualPage = 1
+	}
+	actualPageSize := pageSize
+	if actualPageSize < 1 || actualPageSize > 100 {
+		actualPageSize = 20
+	}
+
+	return c.JSON(http.StatusOK, PaginatedFriendsResponse{
+		Items:      items,
+		TotalCount: friends.TotalCount,
+		Page:       actualPage,
+		PageSize:   actualPageSize,
+		TotalPages: int(math.Ceil(float64(friends.TotalCount) / float64(actualPageSize))),
+	})
+}
+
+// SendFriendRequest handles POST requests asking a user to be friends. A request to a
+// user who has already asked the caller accepts theirs.
+func (h *SocialHandler) SendFriendRequest(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, err := GetUserIDFromContext(c)
+	if err != nil {
+		h.logger.Error(ctx, "Could not get user ID from context for SendFriendRequest", "error", err)
+		return NewAPIError(http.StatusUnauthorized, ErrCodeUnauthorized, "Authentication error: User ID not found")
+	}
+
+	var req SendFriendRequestRequest
+	if err := c.Bind(&req); err != nil {
+		return NewAPIError(http.StatusBadRequest, ErrCodeBadRequest, "Invalid request body")
+	}
+	if err := c.Validate(req); err != nil {
+		return NewAPIError(http.StatusBadRequest, ErrCodeValidation, err.Error())
+	}
+
+	friendship, err := h.service.SendFriendRequest(ctx, userID, &social.FriendRequestData{
+		UserID:   req.UserID,
+		Username: req.Username,
+	})
+	if err != nil {
+		if apiErr := socialError(err); apiErr != nil {
+			return apiErr
+		}
+		h.logger.Error(ctx, "Service failed to send friend request", "userID", userID, "error", err)
+		return NewAPIError(http.StatusInternalServerError, ErrCodeInternalServer, "Failed to send friend request")
+	}
+
+	return c.JSON(http.StatusCreated, mapFriendshipToResponse(friendship))
+}
+
+// AcceptFriendRequest handles POST requests accepting the friend request of the user in the path.
+func (h *SocialHandler) AcceptFriendRequest(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, err := GetUserIDFromContext(c)
+	if err != nil {
+		h.logger.Error(ctx, "Could not get user ID from context for AcceptFriendRequest", "error", err)
+		return NewAPIError(http.StatusUnauthorized, ErrCodeUnauthorized, "Authentication error: User ID not found")
+	}
+
+	requesterID, err := h.parseUserIDParam(c)
+	if err != nil {
+		return err
+	}
+
+	friendship, err := h.service.AcceptFriendRequest(ctx, userID, requesterID)
+	if err != nil {
+		if apiErr := socialError(err); apiErr != nil {
+			return apiErr
+		}
+		h.logger.Error(ctx, "Service failed to accept friend request", "userID", userID, "requesterID", requesterID, "error", err)
+		return NewAPIError(http.StatusInternalServerError, ErrCodeInternalServer, "Failed to accept friend request")
+	}
+
+	return c.JSON(http.StatusOK, mapFriendshipToResponse(friendship))
+}
+
+// RemoveFriend handles DELETE requests unfriending the user in the path, or cancelling
+// or declining a pending request between them.
+func (h *SocialHandler) RemoveFriend(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, err := GetUserIDFromContext(c)
+	if err != nil {
+		h.logger.Error(ctx, "Could not get user ID from context for RemoveFriend", "error", err)
+		return NewAPIError(http.StatusUnauthorized, ErrCodeUnauthorized, "Authentication error: User ID not found")
+	}
+
+	otherUserID, err := h.parseUserIDParam(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.service.RemoveFriend(ctx, userID, otherUserID); err != nil {
+		if apiErr := socialError(err); apiErr != nil {
+			return apiErr
+		}
+		h.logger.Error(ctx, "Service failed to remove friend", "userID", userID, "otherUserID", otherUserID, "error", err)
+		return NewAPIError(http.StatusInternalServerError, ErrCodeInternalServer, "Failed to remove friend")
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// BlockUser handles POST requests blocking the user in the path. Blocking ends any
+// friendship or pending request between the users.
+func (h *SocialHandler) BlockUser(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, err := GetUserIDFromContext(c)
+	if err != nil {
+		h.logger.Error(ctx, "Could not get user ID from context for BlockUser", "error", err)
+		return NewAPIError(http.StatusUnauthorized, ErrCodeUnauthorized, "Authentication error: User ID not found")
+	}
+
+	otherUserID, err := h.parseUserIDParam(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.service.BlockUser(ctx, userID, otherUserID); err != nil {
+		if apiErr := socialError(err); apiErr != nil {
+			return apiErr
+		}
+		h.logger.Error(ctx, "Service failed to block user", "userID", userID, "otherUserID", otherUserID, "error", err)
+		return NewAPIError(http.StatusInternalServerError, ErrCodeInternalServer, "Failed to block user")
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// UnblockUser handles DELETE requests lifting the caller's block of the user in the path.
+func (h *SocialHandler) UnblockUser(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, err := GetUserIDFromContext(c)
+	if err != nil {
+		h.logger.Error(ctx, "Could not get user ID from context for UnblockUser", "error", err)
+		return NewAPIError(http.StatusUnauthorized, ErrCodeUnauthorized, "Authentication error: User ID not found")
+	}
+
+	otherUserID, err := h.parseUserIDParam(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.service.UnblockUser(ctx, userID, otherUserID); err != nil {
+		if apiErr := socialError(err); apiErr != nil {
+			return apiErr
+		}
+		h.logger.Error(ctx, "Service failed to unblock user", "userID", userID, "otherUserID", otherUserID, "error", err)
+		return NewAPIError(http.StatusInternalServerError, ErrCodeInternalServer, "Failed to unblock user")
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
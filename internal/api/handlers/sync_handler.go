@@ -1,163 +1,215 @@
 package handlers
 
 import (
-	"log"
 	"net/http"
 	"time"
 
-	dbStore "ptchampion/internal/store/postgres"
+	"ptchampion/internal/logging"
+	"ptchampion/internal/store"
+	"ptchampion/internal/workouts"
 
 	"github.com/labstack/echo/v4"
 )
 
 // SyncPayload defines the structure for synchronization requests
 type SyncPayload struct {
-	LastSyncedAt *time.Time     `json:"last_synced_at,omitempty"`
-	Exercises    []SyncExercise `json:"exercises,omitempty"`
+	DeviceID     string         `json:"device_id" validate:"required,max=255"`
+	LastSyncedAt *time.Time     `json:"last_synced_at,omitempty"` // synced_at from the previous response
+	Exercises    []SyncExercise `json:"exercises,omitempty" validate:"dive"`
 }
 
-// SyncExercise defines an exercise record for synchronization
+// SyncExercise defines an exercise record for synchronization: a create, an edit of a
+// record the client pulled, or a delete
 type SyncExercise struct {
-	ID            int32      `json:"id,omitempty"` // Local ID, not used by server
-	ExerciseID    int32      `json:"exercise_id" validate:"required,gt=0"`
-	Reps          *int32     `json:"reps,omitempty"`
-	TimeInSeconds *int32     `json:"time_in_seconds,omitempty"`
-	Distance      *int32     `json:"distance,omitempty"`
-	Notes         *string    `json:"notes,omitempty"`
-	CreatedAt     *time.Time `json:"created_at,omitempty"`
+	ID              int32      `json:"id,omitempty"`                         // Local ID, not used by server
+	ClientID        string     `json:"client_id" validate:"required,max=64"` // Client-generated ID; creates are idempotent on it
+	WorkoutID       int32      `json:"workout_id,omitempty"`                 // Server ID from a previous sync; absent for records created on this device
+	BaseVersion     int32      `json:"base_version,omitempty"`               // Server version the edit or delete is based on; absent for new records
+	Deleted         bool       `json:"deleted,omitempty"`                    // Delete the record; the remaining fields are ignored
+	ExerciseID      int32      `json:"exercise_id" validate:"required_unless=Deleted true,omitempty,gt=0"`
+	Reps            *int32     `json:"reps,omitempty" validate:"omitempty,min=0"`
+	TimeInSeconds   *int32     `json:"time_in_seconds,omitempty" validate:"omitempty,min=0"`
+	WeightLbs       *int32     `json:"weight_lbs,omitempty" validate:"omitempty,gt=0"`
+	DistanceMeters  *float64   `json:"distance_meters,omitempty" validate:"omitempty,gt=0"`
+	Distance        *int32     `json:"distance,omitempty"` // Deprecated, ignored
+	FormScore       *int32     `json:"form_score,omitempty" validate:"omitempty,min=0,max=100"`
+	IsPublic        *bool      `json:"is_public,omitempty"`                                                    // Defaults to true; edits sending neither field keep their visibility
+	Visibility      *string    `json:"visibility,omitempty" validate:"omitempty,oneof=private friends public"` // Overrides is_public
+	ScoringStandard string     `json:"scoring_standard,omitempty"`                                             // Registry ID; defaults per exercise
+	CompletedAt     *time.Time `json:"completed_at,omitempty"`
+	CreatedAt       *time.Time `json:"created_at,omitempty"` // Used as the completion time when completed_at is absent
 }
 
 // LogExerciseResponse defines the response structure for logged exercises
 type LogExerciseResponse struct {
-	ID            int32      `json:"id"`
-	UserID        int32      `json:"user_id"`
-	ExerciseID    int32      `json:"exercise_id"`
-	ExerciseName  string     `json:"exercise_name"`
-	ExerciseType  string     `json:"exercise_type"`
-	Reps          *int32     `json:"reps,omitempty"`
-	TimeInSeconds *int32     `json:"time_in_seconds,omitempty"`
-	Distance      *int32     `json:"distance,omitempty"`
-	Notes         *string    `json:"notes,omitempty"`
-	Grade         int32      `json:"grade"`
-	CreatedAt     *time.Time `json:"created_at,omitempty"`
+	ID             int32      `json:"id"`
+	ClientID       string     `json:"client_id,omitempty"`
+	DeviceID       string     `json:"device_id,omitempty"`
+	UserID         int32      `json:"user_id"`
+	ExerciseID     int32      `json:"exercise_id"`
+	ExerciseName   string     `json:"exercise_name"`
+	ExerciseType   string     `json:"exercise_type"`
+	Reps           *int32     `json:"reps,omitempty"`
+	TimeInSeconds  *int32     `json:"time_in_seconds,omitempty"`
+	WeightLbs      *int32     `json:"weight_lbs,omitempty"`
+	DistanceMeters *float64   `json:"distance_meters,omitempty"`
+	Distance       *int32     `json:"distance,omitempty"`
+	Grade          int32      `json:"grade"`
+	IsPublic       bool       `json:"is_public"`
+	Visibility     string     `json:"visibility"`
+	CompletedAt    *time.Time `json:"completed_at,omitempty"`
+	CreatedAt      *time.Time `json:"created_at,omitempty"`
+	UpdatedAt      *time.Time `json:"updated_at,omitempty"`
+	Version        int32      `json:"version,omitempty"`    // Send as base_version when pushing an edit or delete
+	DeletedAt      *time.Time `json:"deleted_at,omitempty"` // Set on tombstones; the client should drop the record
+}
+
+// SyncResult reports the outcome of one pushed exercise
+type SyncResult struct {
+	ClientID string               `json:"client_id"`
+	Status   string               `json:"status"`            // created, updated, conflict or rejected
+	Reason   string               `json:"reason,omitempty"`  // Why the push conflicted or was rejected
+	Workout  *LogExerciseResponse `json:"workout,omitempty"` // Server copy, which replaces the client's on conflict; absent when rejected
 }
 
 // SyncResponse defines the response for synchronization requests
 type SyncResponse struct {
-	SyncedAt time.Time             `json:"synced_at"`
-	Workouts []LogExerciseResponse `json:"workouts,omitempty"`
+	SyncedAt time.Time             `json:"synced_at"`         // Send as last_synced_at on the next sync
+	Results  []SyncResult          `json:"results"`           // One per pushed exercise, in request order
+	Changes  []LogExerciseResponse `json:"changes,omitempty"` // Records changed on the server since last_synced_at, including deletes
+}
+
+// SyncHandler handles offline synchronization requests
+type SyncHandler struct {
+	service workouts.Service
+	logger  logging.Logger
 }
 
-// PostSync handles synchronization of exercise data between client and server
-func (h *Handler) PostSync(c echo.Context) error {
-	// 1. Get user ID from context
-	userID, ok := c.Get("user_id").(int32)
-	if !ok {
-		log.Printf("ERROR: Could not get user ID from context")
-		return echo.NewHTTPError(http.StatusInternalServerError, "Authentication error")
+// NewSyncHandler creates a new SyncHandler instance
+func NewSyncHandler(service workouts.Service, logger logging.Logger) *SyncHandler {
+	return &SyncHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+func mapStoreWorkoutRecordToSyncResponse(record *store.WorkoutRecord) LogExerciseResponse {
+	return LogExerciseResponse{
+		ID:             record.ID,
+		ClientID:       record.ClientID,
+		DeviceID:       record.DeviceID,
+		UserID:         record.UserID,
+		ExerciseID:     record.ExerciseID,
+		ExerciseName:   record.ExerciseName,
+		ExerciseType:   record.ExerciseType,
+		Reps:           record.Reps,
+		TimeInSeconds:  record.DurationSeconds,
+		WeightLbs:      record.WeightLbs,
+		DistanceMeters: record.DistanceMeters,
+		Grade:          record.Grade,
+		IsPublic:       record.IsPublic,
+		Visibility:     record.Visibility,
+		CompletedAt:    &record.CompletedAt,
+		CreatedAt:      &record.CreatedAt,
+		UpdatedAt:      &record.UpdatedAt,
+		Version:        record.Version,
+		DeletedAt:      record.DeletedAt,
+	}
+}
+
+// PostSync handles synchronization of exercise data between client and server.
+// Pushed creates, edits and deletes are applied in one transaction with server-computed
+// grades. Edits and deletes must name the version they are based on; a stale version
+// is reported as a conflict carrying the server copy, which wins. Each pushed exercise
+// gets an outcome, and the response also carries every record changed since
+// last_synced_at, with deleted records as tombstones.
+func (h *SyncHandler) PostSync(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, err := GetUserIDFromContext(c)
+	if err != nil {
+		h.logger.Error(ctx, "Could not get user ID from context for PostSync", "error", err)
+		return NewAPIError(http.StatusUnauthorized, ErrCodeUnauthorized, "Authentication error: User ID not found")
 	}
 
-	// 2. Parse request body
 	var req SyncPayload
 	if err := c.Bind(&req); err != nil {
-		log.Printf("ERROR: Failed to decode sync request: %v", err)
-		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+		h.logger.Warn(ctx, "Failed to decode sync request", "error", err)
+		return NewAPIError(http.StatusBadRequest, ErrCodeBadRequest, "Invalid request body")
 	}
-
-	// 3. Validate request
 	if err := c.Validate(req); err != nil {
-		log.Printf("INFO: Invalid sync request: %v", err)
-		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		return NewAPIError(http.StatusBadRequest, ErrCodeValidation, err.Error())
 	}
 
-	// 4. Begin transaction
-	// This section may need adjustment based on how your dbStore handles transactions
-	// For now, we'll use direct queries without transactions
-
-	// 5. Process incoming exercises (if any)
-	responseExercises := []LogExerciseResponse{}
-
-	if len(req.Exercises) > 0 {
-		for _, syncEx := range req.Exercises {
-			// Fetch exercise details to get name and type
-			exercise, err := h.Queries.GetExercise(c.Request().Context(), syncEx.ExerciseID)
-			if err != nil {
-				log.Printf("ERROR: Failed to get exercise %d details during sync: %v", syncEx.ExerciseID, err)
-				continue // Skip this exercise but continue processing others
-			}
-
-			// Calculate grade if possible
-			var grade int32 = 0 // Default to 0 if we can't calculate
-			if syncEx.Reps != nil || syncEx.TimeInSeconds != nil {
-				// Logic to calculate grade based on exercise type and performance
-				// This is simplified - should use the grading package
-				grade = 70 // Placeholder
-			}
-
-			// Prepare DB params for LogWorkout
-			params := dbStore.LogWorkoutParams{
-				UserID:          userID,
-				ExerciseID:      syncEx.ExerciseID,
-				ExerciseType:    exercise.Type,
-				Repetitions:     int32PtrToNullInt32(syncEx.Reps),
-				DurationSeconds: int32PtrToNullInt32(syncEx.TimeInSeconds),
-				Grade:           grade,
-				FormScore:       int32PtrToNullInt32(nil), // Default form score
-				CompletedAt:     time.Now(),
-				IsPublic:        true, // Default to public
-			}
-
-			// Save to database using LogWorkout
-			loggedEx, err := h.Queries.LogWorkout(c.Request().Context(), params)
-			if err != nil {
-				log.Printf("ERROR: Failed to log synced exercise %d for user %d: %v",
-					syncEx.ExerciseID, userID, err)
-				continue // Skip this exercise but continue processing others
-			}
-
-			// Add to response
-			respEx := LogExerciseResponse{
-				ID:            loggedEx.ID,
-				UserID:        loggedEx.UserID,
-				ExerciseID:    loggedEx.ExerciseID,
-				ExerciseName:  exercise.Name,
-				ExerciseType:  exercise.Type,
-				Reps:          nullInt32ToInt32Ptr(loggedEx.Repetitions),
-				TimeInSeconds: nullInt32ToInt32Ptr(loggedEx.DurationSeconds),
-				Distance:      nil, // No longer supported in workouts table
-				Notes:         nil, // No longer supported in workouts table
-				Grade:         loggedEx.Grade,
-				CreatedAt:     &loggedEx.CreatedAt,
-			}
-			responseExercises = append(responseExercises, respEx)
-		}
+	data := &workouts.SyncWorkoutsData{
+		DeviceID:     req.DeviceID,
+		LastSyncedAt: req.LastSyncedAt,
+		Pushes:       make([]*workouts.SyncPushData, len(req.Exercises)),
 	}
+	for i, ex := range req.Exercises {
+		data.Pushes[i] = &workouts.SyncPushData{
+			ClientID:       ex.ClientID,
+			WorkoutID:      ex.WorkoutID,
+			BaseVersion:    ex.BaseVersion,
+			Deleted:        ex.Deleted,
+			KeepVisibility: ex.IsPublic == nil && ex.Visibility == nil,
+		}
+		if ex.Deleted {
+			continue
+		}
 
-	// 6. Get exercises from server since last sync (if lastSyncedAt provided)
-	if req.LastSyncedAt != nil {
-		// Logic to fetch exercises created since lastSyncedAt
-		// This is a placeholder and would need actual implementation
-	}
+		isPublic := true
+		if ex.IsPublic != nil {
+			isPublic = *ex.IsPublic
+		}
+		var visibility string
+		if ex.Visibility != nil {
+			visibility = *ex.Visibility
+		}
+		var completedAt time.Time
+		if ex.CompletedAt != nil {
+			completedAt = *ex.CompletedAt
+		} else if ex.CreatedAt != nil {
+			completedAt = *ex.CreatedAt
+		}
 
-	// 7. Update user's last synced timestamp
-	now := time.Now()
-	// This would typically update the last_synced_at field for the user
-	// For now, we're commenting this out since we need to see the actual DB schema
-	/*
-		_, err := h.Queries.UpdateUserLastSynced(c.Request().Context(), dbStore.UpdateUserLastSyncedParams{
-			ID:          userID,
-			LastSyncedAt: now,
-		})
-		if err != nil {
-			log.Printf("ERROR: Failed to update last synced time for user %d: %v", userID, err)
-			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to complete sync")
+		data.Pushes[i].Workout = &workouts.LogWorkoutData{
+			ExerciseID:      ex.ExerciseID,
+			Reps:            ex.Reps,
+			DurationSeconds: ex.TimeInSeconds,
+			WeightLbs:       ex.WeightLbs,
+			DistanceMeters:  ex.DistanceMeters,
+			FormScore:       ex.FormScore,
+			CompletedAt:     completedAt,
+			IsPublic:        isPublic,
+			Visibility:      visibility,
+			ScoringStandard: ex.ScoringStandard,
 		}
-	*/
+	}
+
+	result, err := h.service.SyncWorkouts(ctx, userID, data)
+	if err != nil {
+		h.logger.Error(ctx, "Service failed to sync workouts", "userID", userID, "deviceID", req.DeviceID, "error", err)
+		return NewAPIError(http.StatusInternalServerError, ErrCodeInternalServer, "Failed to complete sync")
+	}
 
-	// 9. Return response
 	resp := SyncResponse{
-		SyncedAt: now,
-		Workouts: responseExercises,
+		SyncedAt: result.SyncedAt,
+		Results:  make([]SyncResult, len(result.Outcomes)),
+		Changes:  make([]LogExerciseResponse, len(result.Changes)),
+	}
+	for i, outcome := range result.Outcomes {
+		resp.Results[i] = SyncResult{
+			ClientID: outcome.ClientID,
+			Status:   outcome.Status,
+			Reason:   outcome.Reason,
+		}
+		if outcome.Record != nil {
+			workout := mapStoreWorkoutRecordToSyncResponse(outcome.Record)
+			resp.Results[i].Workout = &workout
+		}
+	}
+	for i, record := range result.Changes {
+		resp.Changes[i] = mapStoreWorkoutRecordToSyncResponse(record)
 	}
 
 	return c.JSON(http.StatusOK, resp)
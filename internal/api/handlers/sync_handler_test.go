@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"ptchampion/internal/logging"
+	"ptchampion/internal/store"
+	"ptchampion/internal/workouts"
+)
+
+// SyncWorkoutRecords records the batch and applies every push as an edit
+func (m *memoryStore) SyncWorkoutRecords(ctx context.Context, batch *store.WorkoutSyncBatch) (*store.WorkoutSyncResult, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.syncBatches = append(m.syncBatches, batch)
+	result := &store.WorkoutSyncResult{SyncedAt: time.Now()}
+	for _, push := range batch.Pushes {
+		result.Outcomes = append(result.Outcomes, &store.WorkoutSyncOutcome{
+			ClientID: push.ClientID,
+			Status:   store.SyncStatusUpdated,
+			Record:   push.Record,
+		})
+	}
+	return result, nil
+}
+
+func TestPostSyncEditVisibility(t *testing.T) {
+	e, _, _, s := newACFTTestServer()
+	logger := logging.NewDefaultLogger()
+	h := NewSyncHandler(workouts.NewService(s, s, s, s, nil, logger), logger)
+
+	completed := time.Now().Add(-time.Hour)
+	edit := func(clientID string, isPublic *bool, visibility *string) SyncExercise {
+		return SyncExercise{
+			ClientID:      clientID,
+			WorkoutID:     1,
+			BaseVersion:   1,
+			ExerciseID:    6,
+			TimeInSeconds: int32Ptr(960),
+			IsPublic:      isPublic,
+			Visibility:    visibility,
+			CompletedAt:   &completed,
+		}
+	}
+	friends := store.WorkoutVisibilityFriends
+	public := true
+	payload := SyncPayload{
+		DeviceID: "phone",
+		Exercises: []SyncExercise{
+			edit("omitted", nil, nil),
+			edit("friends", &public, &friends),
+			edit("public", &public, nil),
+		},
+	}
+
+	rec := serve(t, e, h.PostSync, 7, http.MethodPost, "/sync", payload)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("sync status = %d, body %s", rec.Code, rec.Body.String())
+	}
+	if len(s.syncBatches) != 1 || len(s.syncBatches[0].Pushes) != 3 {
+		t.Fatalf("store got %d batches, want one with three pushes", len(s.syncBatches))
+	}
+	pushes := s.syncBatches[0].Pushes
+
+	if !pushes[0].KeepVisibility {
+		t.Errorf("edit without visibility: KeepVisibility = false, want the stored visibility kept")
+	}
+	tests := []struct {
+		push       *store.WorkoutSyncPush
+		visibility string
+		isPublic   bool
+	}{
+		{pushes[1], store.WorkoutVisibilityFriends, false},
+		{pushes[2], store.WorkoutVisibilityPublic, true},
+	}
+	for _, tt := range tests {
+		if tt.push.KeepVisibility {
+			t.Errorf("%s: KeepVisibility = true for an edit that sets visibility", tt.push.ClientID)
+		}
+		if tt.push.Record.Visibility != tt.visibility || tt.push.Record.IsPublic != tt.isPublic {
+			t.Errorf("%s: visibility = %q, is_public = %v, want %q, %v", tt.push.ClientID,
+				tt.push.Record.Visibility, tt.push.Record.IsPublic, tt.visibility, tt.isPublic)
+		}
+	}
+}
+
+func TestPostSyncRejectsUnknownVisibility(t *testing.T) {
+	e, _, _, s := newACFTTestServer()
+	logger := logging.NewDefaultLogger()
+	h := NewSyncHandler(workouts.NewService(s, s, s, s, nil, logger), logger)
+
+	everyone := "everyone"
+	completed := time.Now().Add(-time.Hour)
+	payload := SyncPayload{
+		DeviceID: "phone",
+		Exercises: []SyncExercise{{
+			ClientID:      "a",
+			ExerciseID:    6,
+			TimeInSeconds: int32Ptr(960),
+			Visibility:    &everyone,
+			CompletedAt:   &completed,
+		}},
+	}
+
+	rec := serve(t, e, h.PostSync, 7, http.MethodPost, "/sync", payload)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("sync status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+}
@@ -0,0 +1,209 @@
+package handlers
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"ptchampion/internal/grading"
+	"ptchampion/internal/store"
+	"ptchampion/internal/workouts"
+
+	"github.com/labstack/echo/v4"
+)
+
+// TestEventRequest defines one event of a test session request.
+type TestEventRequest struct {
+	ExerciseID      int32     `json:"exercise_id" validate:"required,gt=0"`
+	Reps            *int32    `json:"reps,omitempty" validate:"omitempty,min=0"`
+	DurationSeconds *int32    `json:"duration_seconds,omitempty" validate:"omitempty,min=0"`
+	WeightLbs       *int32    `json:"weight_lbs,omitempty" validate:"omitempty,gt=0"`
+	DistanceMeters  *float64  `json:"distance_meters,omitempty" validate:"omitempty,gt=0"`
+	FormScore       *int32    `json:"form_score,omitempty" validate:"omitempty,min=0,max=100"`
+	StartedAt       time.Time `json:"started_at" validate:"required"`
+	CompletedAt     time.Time `json:"completed_at" validate:"required"`
+}
+
+// CreateTestSessionRequest defines the API request for recording a full PT test.
+// Events are listed in the order they were taken; grades are computed server-side.
+type CreateTestSessionRequest struct {
+	ScoringStandard string             `json:"scoring_standard,omitempty"` // Registry ID; defaults to apft-2013
+	IsPublic        bool               `json:"is_public"`
+	Events          []TestEventRequest `json:"events" validate:"required,min=1,dive"`
+}
+
+// TestSessionResponse defines the API response for a test session.
+type TestSessionResponse struct {
+	ID              int32             `json:"id"`
+	UserID          int32             `json:"user_id"`
+	ScoringStandard string            `json:"scoring_standard"`
+	ScoringVersion  string            `json:"scoring_version"`
+	TestDate        string            `json:"test_date"` // YYYY-MM-DD
+	StartedAt       time.Time         `json:"started_at"`
+	CompletedAt     time.Time         `json:"completed_at"`
+	TotalScore      int32             `json:"total_score"`
+	Passed          bool              `json:"passed"`
+	IsPublic        bool              `json:"is_public"`
+	CreatedAt       time.Time         `json:"created_at"`
+	Events          []WorkoutResponse `json:"events"`
+}
+
+// PaginatedTestSessionsResponse defines the API response for a list of test sessions.
+type PaginatedTestSessionsResponse struct {
+	Items      []TestSessionResponse `json:"items"`
+	TotalCount int64                 `json:"totalCount"`
+	Page       int                   `json:"page"`
+	PageSize   int                   `json:"pageSize"`
+	TotalPages int                   `json:"totalPages"`
+}
+
+func mapStoreTestSessionToResponse(session *store.TestSession) TestSessionResponse {
+	events := make([]WorkoutResponse, len(session.Events))
+	for i, record := range session.Events {
+		events[i] = mapStoreWorkoutRecordToResponse(record)
+	}
+	return TestSessionResponse{
+		ID:              session.ID,
+		UserID:          session.UserID,
+		ScoringStandard: session.ScoringStandard,
+		ScoringVersion:  session.ScoringVersion,
+		TestDate:        session.TestDate.Format("2006-01-02"),
+		StartedAt:       session.StartedAt,
+		CompletedAt:     session.CompletedAt,
+		TotalScore:      session.TotalScore,
+		Passed:          session.Passed,
+		IsPublic:        session.IsPublic,
+		CreatedAt:       session.CreatedAt,
+		Events:          events,
+	}
+}
+
+// CreateTestSession handles POST requests recording a full PT test taken on one day.
+func (h *WorkoutHandler) CreateTestSession(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, err := GetUserIDFromContext(c)
+	if err != nil {
+		h.logger.Error(ctx, "Could not get user ID from context for CreateTestSession", "error", err)
+		return NewAPIError(http.StatusUnauthorized, ErrCodeUnauthorized, "Authentication error: User ID not found")
+	}
+
+	var req CreateTestSessionRequest
+	if err := c.Bind(&req); err != nil {
+		return NewAPIError(http.StatusBadRequest, ErrCodeBadRequest, "Invalid request body")
+	}
+	if err := c.Validate(req); err != nil {
+		return NewAPIError(http.StatusBadRequest, ErrCodeValidation, err.Error())
+	}
+
+	if !scoringStandardEnabled(c, req.ScoringStandard) {
+		return NewAPIError(http.StatusBadRequest, ErrCodeBadRequest, "Scoring standard selection is not enabled")
+	}
+
+	data := &workouts.CreateTestSessionData{
+		ScoringStandard: req.ScoringStandard,
+		IsPublic:        req.IsPublic,
+		Events:          make([]workouts.TestEventData, len(req.Events)),
+	}
+	for i, ev := range req.Events {
+		data.Events[i] = workouts.TestEventData{
+			ExerciseID:      ev.ExerciseID,
+			Reps:            ev.Reps,
+			DurationSeconds: ev.DurationSeconds,
+			WeightLbs:       ev.WeightLbs,
+			DistanceMeters:  ev.DistanceMeters,
+			FormScore:       ev.FormScore,
+			StartedAt:       ev.StartedAt,
+			CompletedAt:     ev.CompletedAt,
+		}
+	}
+
+	session, err := h.service.CreateTestSession(ctx, userID, data)
+	if err != nil {
+		switch {
+		case errors.Is(err, workouts.ErrTestEventOrder), errors.Is(err, workouts.ErrTestRestInterval),
+			errors.Is(err, workouts.ErrTestSessionSpansDays):
+			return NewAPIError(http.StatusUnprocessableEntity, ErrCodeValidation, err.Error())
+		case errors.Is(err, grading.ErrUnknownStandard), errors.Is(err, workouts.ErrMissingMeasurement),
+			errors.Is(err, workouts.ErrInvalidMeasurement), errors.Is(err, store.ErrExerciseNotFound):
+			return NewAPIError(http.StatusBadRequest, ErrCodeBadRequest, err.Error())
+		}
+		h.logger.Error(ctx, "Service failed to create test session", "userID", userID, "error", err)
+		return NewAPIError(http.StatusInternalServerError, ErrCodeInternalServer, "Failed to record test session")
+	}
+
+	return c.JSON(http.StatusCreated, mapStoreTestSessionToResponse(session))
+}
+
+// GetTestSession handles GET requests for one of the user's test sessions.
+func (h *WorkoutHandler) GetTestSession(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, err := GetUserIDFromContext(c)
+	if err != nil {
+		h.logger.Error(ctx, "Could not get user ID from context for GetTestSession", "error", err)
+		return NewAPIError(http.StatusUnauthorized, ErrCodeUnauthorized, "Authentication error: User ID not found")
+	}
+
+	sessionIDStr := c.Param("session_id")
+	sessionID, err := strconv.Atoi(sessionIDStr)
+	if err != nil {
+		h.logger.Warn(ctx, "Invalid test session ID format", "testSessionID", sessionIDStr, "error", err)
+		return NewAPIError(http.StatusBadRequest, ErrCodeBadRequest, "Invalid test session ID format")
+	}
+
+	session, err := h.service.GetTestSession(ctx, userID, int32(sessionID))
+	if err != nil {
+		if err == store.ErrTestSessionNotFound {
+			return NewAPIError(http.StatusNotFound, ErrCodeNotFound, "Test session not found")
+		} else if strings.Contains(err.Error(), "user does not have permission") {
+			return NewAPIError(http.StatusForbidden, ErrCodeForbidden, "You do not have permission to view this test session")
+		}
+		h.logger.Error(ctx, "Service failed to get test session", "userID", userID, "testSessionID", sessionID, "error", err)
+		return NewAPIError(http.StatusInternalServerError, ErrCodeInternalServer, "Failed to retrieve test session")
+	}
+
+	return c.JSON(http.StatusOK, mapStoreTestSessionToResponse(session))
+}
+
+// ListTestSessions handles GET requests listing the user's test sessions, newest first.
+func (h *WorkoutHandler) ListTestSessions(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, err := GetUserIDFromContext(c)
+	if err != nil {
+		h.logger.Error(ctx, "Could not get user ID from context for ListTestSessions", "error", err)
+		return NewAPIError(http.StatusUnauthorized, ErrCodeUnauthorized, "Authentication error: User ID not found")
+	}
+
+	page, _ := strconv.Atoi(c.QueryParam("page"))
+	pageSize, _ := strconv.Atoi(c.QueryParam("pageSize"))
+
+	paginated, err := h.service.ListUserTestSessions(ctx, userID, page, pageSize)
+	if err != nil {
+		h.logger.Error(ctx, "Service failed to list test sessions", "userID", userID, "error", err)
+		return NewAPIError(http.StatusInternalServerError, ErrCodeInternalServer, "Failed to retrieve test sessions")
+	}
+
+	items := make([]TestSessionResponse, len(paginated.Sessions))
+	for i, session := range paginated.Sessions {
+		items[i] = mapStoreTestSessionToResponse(session)
+	}
+
+	actualPage := page
+	if actualPage < 1 {
+		actualPage = 1
+	}
+	actualPageSize := pageSize
+	if actualPageSize < 1 || actualPageSize > 100 {
+		actualPageSize = 20
+	}
+
+	return c.JSON(http.StatusOK, PaginatedTestSessionsResponse{
+		Items:      items,
+		TotalCount: paginated.TotalCount,
+		Page:       actualPage,
+		PageSize:   actualPageSize,
+		TotalPages: int(math.Ceil(float64(paginated.TotalCount) / float64(actualPageSize))),
+	})
+}
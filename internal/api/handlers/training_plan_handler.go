@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"ptchampion/internal/grading"
+	"ptchampion/internal/store"
+	"ptchampion/internal/workouts"
+
+	"github.com/labstack/echo/v4"
+)
+
+// GenerateTrainingPlanRequest defines the API request for generating a training plan.
+type GenerateTrainingPlanRequest struct {
+	ScoringStandard string `json:"scoring_standard,omitempty"` // Registry ID; defaults to the latest test's standard
+	Weeks           int    `json:"weeks,omitempty" validate:"omitempty,min=2,max=12"`
+}
+
+// TrainingPlanEventResponse defines the API response for the baseline of one planned event.
+type TrainingPlanEventResponse struct {
+	Event string   `json:"event"`
+	Score *int32   `json:"score,omitempty"`
+	Value *float64 `json:"value,omitempty"`
+	Gap   int32    `json:"gap"`   // Points short of the maximum
+	Focus string   `json:"focus"` // improve, maintain or baseline
+}
+
+// TrainingSessionResponse defines the API response for one scheduled session.
+type TrainingSessionResponse struct {
+	ID            int32    `json:"id"`
+	ScheduledDate string   `json:"scheduled_date"` // YYYY-MM-DD
+	Week          int      `json:"week"`
+	Event         string   `json:"event"`
+	Focus         string   `json:"focus"`
+	Sets          int      `json:"sets"`
+	TargetValue   *float64 `json:"target_value,omitempty"` // Per set, in the event's unit
+	Status        string   `json:"status"`                 // completed, missed or scheduled
+	WorkoutID     *int32   `json:"workout_id,omitempty"`
+	TargetMet     bool     `json:"target_met"`
+}
+
+// TrainingPlanResponse defines the API response for a training plan and its progress.
+type TrainingPlanResponse struct {
+	ID              int32                       `json:"id"`
+	ScoringStandard string                      `json:"scoring_standard"`
+	TestSessionID   *int32                      `json:"test_session_id,omitempty"`
+	StartDate       string                      `json:"start_date"` // YYYY-MM-DD
+	Weeks           int                         `json:"weeks"`
+	CreatedAt       time.Time                   `json:"created_at"`
+	Adapted         bool                        `json:"adapted"` // Regenerated from a test taken since the last request
+	Events          []TrainingPlanEventResponse `json:"events"`
+	Sessions        []TrainingSessionResponse   `json:"sessions"`
+	Completed       int                         `json:"completed"`
+	Missed          int                         `json:"missed"`
+	Adherence       float64                     `json:"adherence"`
+}
+
+func mapTrainingPlanToResponse(p *workouts.TrainingPlanProgress) TrainingPlanResponse {
+	resp := TrainingPlanResponse{
+		ID:              p.Plan.ID,
+		ScoringStandard: p.Plan.ScoringStandard,
+		TestSessionID:   p.Plan.TestSessionID,
+		StartDate:       p.Plan.StartDate.Format("2006-01-02"),
+		Weeks:           p.Plan.Weeks,
+		CreatedAt:       p.Plan.CreatedAt,
+		Adapted:         p.Adapted,
+		Events:          make([]TrainingPlanEventResponse, len(p.Plan.Events)),
+		Sessions:        make([]TrainingSessionResponse, len(p.Sessions)),
+		Completed:       p.Completed,
+		Missed:          p.Missed,
+		Adherence:       p.Adherence,
+	}
+	for i, ev := range p.Plan.Events {
+		resp.Events[i] = TrainingPlanEventResponse{
+			Event: ev.Event,
+			Score: ev.Score,
+			Value: ev.Value,
+			Gap:   ev.Gap,
+			Focus: ev.Focus,
+		}
+	}
+	for i, sp := range p.Sessions {
+		resp.Sessions[i] = TrainingSessionResponse{
+			ID:            sp.Session.ID,
+			ScheduledDate: sp.Session.ScheduledDate.Format("2006-01-02"),
+			Week:          sp.Session.Week,
+			Event:         sp.Session.Event,
+			Focus:         sp.Session.Focus,
+			Sets:          sp.Session.Sets,
+			TargetValue:   sp.Session.TargetValue,
+			Status:        sp.Status,
+			WorkoutID:     sp.WorkoutID,
+			TargetMet:     sp.TargetMet,
+		}
+	}
+	return resp
+}
+
+// GenerateTrainingPlan handles POST requests generating a new training plan, which
+// replaces the user's active plan.
+func (h *WorkoutHandler) GenerateTrainingPlan(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, err := GetUserIDFromContext(c)
+	if err != nil {
+		h.logger.Error(ctx, "Could not get user ID from context for GenerateTrainingPlan", "error", err)
+		return NewAPIError(http.StatusUnauthorized, ErrCodeUnauthorized, "Authentication error: User ID not found")
+	}
+
+	var req GenerateTrainingPlanRequest
+	if err := c.Bind(&req); err != nil {
+		return NewAPIError(http.StatusBadRequest, ErrCodeBadRequest, "Invalid request body")
+	}
+	if err := c.Validate(req); err != nil {
+		return NewAPIError(http.StatusBadRequest, ErrCodeValidation, err.Error())
+	}
+
+	if !scoringStandardEnabled(c, req.ScoringStandard) {
+		return NewAPIError(http.StatusBadRequest, ErrCodeBadRequest, "Scoring standard selection is not enabled")
+	}
+
+	plan, err := h.service.GenerateTrainingPlan(ctx, userID, &workouts.GenerateTrainingPlanData{
+		ScoringStandard: req.ScoringStandard,
+		Weeks:           req.Weeks,
+	})
+	if err != nil {
+		if errors.Is(err, workouts.ErrInvalidTrainingPlan) || errors.Is(err, grading.ErrUnknownStandard) {
+			return NewAPIError(http.StatusBadRequest, ErrCodeBadRequest, err.Error())
+		}
+		h.logger.Error(ctx, "Service failed to generate training plan", "userID", userID, "error", err)
+		return NewAPIError(http.StatusInternalServerError, ErrCodeInternalServer, "Failed to generate training plan")
+	}
+
+	return c.JSON(http.StatusCreated, mapTrainingPlanToResponse(plan))
+}
+
+// GetTrainingPlan handles GET requests for the user's active training plan.
+func (h *WorkoutHandler) GetTrainingPlan(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, err := GetUserIDFromContext(c)
+	if err != nil {
+		h.logger.Error(ctx, "Could not get user ID from context for GetTrainingPlan", "error", err)
+		return NewAPIError(http.StatusUnauthorized, ErrCodeUnauthorized, "Authentication error: User ID not found")
+	}
+
+	plan, err := h.service.GetTrainingPlan(ctx, userID)
+	if err != nil {
+		if err == store.ErrTrainingPlanNotFound {
+			return NewAPIError(http.StatusNotFound, ErrCodeNotFound, "No active training plan")
+		}
+		h.logger.Error(ctx, "Service failed to get training plan", "userID", userID, "error", err)
+		return NewAPIError(http.StatusInternalServerError, ErrCodeInternalServer, "Failed to retrieve training plan")
+	}
+
+	return c.JSON(http.StatusOK, mapTrainingPlanToResponse(plan))
+}
@@ -23,6 +23,7 @@ type UserResponse struct {
 	LastName    string    `json:"last_name"`     // Added
 	Gender      string    `json:"gender"`        // 'male' or 'female'
 	DateOfBirth time.Time `json:"date_of_birth"` // For age calculation
+	Timezone    string    `json:"timezone"`      // IANA name; streaks and daily boundaries use it
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
 }
@@ -35,6 +36,7 @@ type UpdateCurrentUserRequest struct {
 	LastName    *string `json:"last_name,omitempty" validate:"omitempty,required"`
 	Gender      *string `json:"gender,omitempty" validate:"omitempty,oneof=male female"`
 	DateOfBirth *string `json:"date_of_birth,omitempty" validate:"omitempty"` // Format: YYYY-MM-DD
+	Timezone    *string `json:"timezone,omitempty" validate:"omitempty"`      // IANA name, e.g. Asia/Seoul
 	// Note: DisplayName is not directly settable via store.User, derived from First/Last Name
 	// Note: Password changes handled separately
 }
@@ -55,6 +57,7 @@ func mapStoreUserToUserResponse(user *store.User) UserResponse {
 		LastName:    user.LastName,
 		Gender:      user.Gender,
 		DateOfBirth: user.DateOfBirth,
+		Timezone:    user.Timezone,
 		CreatedAt:   user.CreatedAt,
 		UpdatedAt:   user.UpdatedAt,
 	}
@@ -149,6 +152,7 @@ func (h *UserHandler) UpdateCurrentUser(c echo.Context) error {
 		LastName:    req.LastName,
 		Gender:      req.Gender,
 		DateOfBirth: req.DateOfBirth,
+		Timezone:    req.Timezone,
 	}
 
 	// 4. Call the user service to update the profile
@@ -157,6 +161,9 @@ func (h *UserHandler) UpdateCurrentUser(c echo.Context) error {
 		if errors.Is(err, store.ErrUserNotFound) {
 			return NewAPIError(http.StatusNotFound, ErrCodeNotFound, "User not found")
 		}
+		if errors.Is(err, users.ErrInvalidTimezone) {
+			return NewAPIError(http.StatusBadRequest, ErrCodeValidation, err.Error())
+		}
 		if errors.Is(err, store.ErrEmailTaken) { // Check for the specific email taken/validation error
 			h.logger.Warn(ctx, "Failed to update user profile due to email issue", "userID", userIDStr, "error", err)
 			// Use HTTP 409 Conflict for email already in use, or HTTP 400 for general validation if preferred.
@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"ptchampion/internal/grading"
+	"ptchampion/internal/store"
+	"ptchampion/internal/workouts"
+
+	"github.com/labstack/echo/v4"
+)
+
+// UpdateWorkoutRequest defines the API request for editing a workout. Omitted fields
+// keep their value; the grade is recomputed by the server.
+type UpdateWorkoutRequest struct {
+	Reps            *int32     `json:"reps,omitempty" validate:"omitempty,min=0"`
+	DurationSeconds *int32     `json:"duration_seconds,omitempty" validate:"omitempty,min=0"`
+	WeightLbs       *int32     `json:"weight_lbs,omitempty" validate:"omitempty,gt=0"`
+	DistanceMeters  *float64   `json:"distance_meters,omitempty" validate:"omitempty,gt=0"`
+	FormScore       *int32     `json:"form_score,omitempty" validate:"omitempty,min=0,max=100"`
+	CompletedAt     *time.Time `json:"completed_at,omitempty"`
+	IsPublic        *bool      `json:"is_public,omitempty"`
+	Visibility      *string    `json:"visibility,omitempty" validate:"omitempty,oneof=private friends public"` // Overrides is_public
+}
+
+// WorkoutAuditEntryResponse defines the API response for one change to a workout.
+type WorkoutAuditEntryResponse struct {
+	ID          int32                               `json:"id"`
+	ActorUserID int32                               `json:"actor_user_id"`
+	Action      string                              `json:"action"`  // update or delete
+	Version     int32                               `json:"version"` // Workout version after the change
+	Changes     map[string]store.WorkoutFieldChange `json:"changes"` // Old and new value per changed field
+	CreatedAt   time.Time                           `json:"created_at"`
+}
+
+// parseWorkoutID reads the workout_id path parameter.
+func (h *WorkoutHandler) parseWorkoutID(c echo.Context) (int32, error) {
+	workoutIDStr := c.Param("workout_id")
+	workoutID, err := strconv.Atoi(workoutIDStr)
+	if err != nil {
+		h.logger.Warn(c.Request().Context(), "Invalid workout ID format", "workoutID", workoutIDStr, "error", err)
+		return 0, NewAPIError(http.StatusBadRequest, ErrCodeBadRequest, "Invalid workout ID format")
+	}
+	return int32(workoutID), nil
+}
+
+// workoutChangeError maps the errors shared by the workout edit endpoints to API errors,
+// or returns nil for errors that are not the client's.
+func workoutChangeError(err error) error {
+	switch {
+	case err == store.ErrWorkoutRecordNotFound:
+		return NewAPIError(http.StatusNotFound, ErrCodeNotFound, "Workout record not found")
+	case strings.Contains(err.Error(), "user does not have permission"):
+		return NewAPIError(http.StatusForbidden, ErrCodeForbidden, "You do not have permission to modify this workout")
+	case err == store.ErrWorkoutInTestSession:
+		return NewAPIError(http.StatusConflict, ErrCodeConflict, "Workouts recorded as part of a test session cannot be changed")
+	}
+	return nil
+}
+
+// UpdateWorkout handles PATCH requests editing one of the user's workouts.
+func (h *WorkoutHandler) UpdateWorkout(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, err := GetUserIDFromContext(c)
+	if err != nil {
+		h.logger.Error(ctx, "Could not get user ID from context for UpdateWorkout", "error", err)
+		return NewAPIError(http.StatusUnauthorized, ErrCodeUnauthorized, "Authentication error: User ID not found")
+	}
+
+	workoutID, err := h.parseWorkoutID(c)
+	if err != nil {
+		return err
+	}
+
+	var req UpdateWorkoutRequest
+	if err := c.Bind(&req); err != nil {
+		return NewAPIError(http.StatusBadRequest, ErrCodeBadRequest, "Invalid request body")
+	}
+	if err := c.Validate(req); err != nil {
+		return NewAPIError(http.StatusBadRequest, ErrCodeValidation, err.Error())
+	}
+
+	updated, err := h.service.UpdateWorkout(ctx, userID, workoutID, &workouts.UpdateWorkoutData{
+		Reps:            req.Reps,
+		DurationSeconds: req.DurationSeconds,
+		WeightLbs:       req.WeightLbs,
+		DistanceMeters:  req.DistanceMeters,
+		FormScore:       req.FormScore,
+		CompletedAt:     req.CompletedAt,
+		IsPublic:        req.IsPublic,
+		Visibility:      req.Visibility,
+	})
+	if err != nil {
+		if apiErr := workoutChangeError(err); apiErr != nil {
+			return apiErr
+		}
+		if errors.Is(err, workouts.ErrMissingMeasurement) || errors.Is(err, workouts.ErrInvalidMeasurement) ||
+			errors.Is(err, grading.ErrUnknownStandard) || errors.Is(err, workouts.ErrInvalidVisibility) {
+			return NewAPIError(http.StatusBadRequest, ErrCodeBadRequest, err.Error())
+		}
+		h.logger.Error(ctx, "Service failed to update workout", "userID", userID, "workoutID", workoutID, "error", err)
+		return NewAPIError(http.StatusInternalServerError, ErrCodeInternalServer, "Failed to update workout")
+	}
+
+	return c.JSON(http.StatusOK, mapStoreWorkoutRecordToResponse(updated))
+}
+
+// DeleteWorkout handles DELETE requests for one of the user's workouts.
+func (h *WorkoutHandler) DeleteWorkout(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, err := GetUserIDFromContext(c)
+	if err != nil {
+		h.logger.Error(ctx, "Could not get user ID from context for DeleteWorkout", "error", err)
+		return NewAPIError(http.StatusUnauthorized, ErrCodeUnauthorized, "Authentication error: User ID not found")
+	}
+
+	workoutID, err := h.parseWorkoutID(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.service.DeleteWorkout(ctx, userID, workoutID); err != nil {
+		if apiErr := workoutChangeError(err); apiErr != nil {
+			return apiErr
+		}
+		h.logger.Error(ctx, "Service failed to delete workout", "userID", userID, "workoutID", workoutID, "error", err)
+		return NewAPIError(http.StatusInternalServerError, ErrCodeInternalServer, "Failed to delete workout")
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// GetWorkoutAuditLog handles GET requests for the change history of one of the user's workouts.
+func (h *WorkoutHandler) GetWorkoutAuditLog(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, err := GetUserIDFromContext(c)
+	if err != nil {
+		h.logger.Error(ctx, "Could not get user ID from context for GetWorkoutAuditLog", "error", err)
+		return NewAPIError(http.StatusUnauthorized, ErrCodeUnauthorized, "Authentication error: User ID not found")
+	}
+
+	workoutID, err := h.parseWorkoutID(c)
+	if err != nil {
+		return err
+	}
+
+	entries, err := h.service.GetWorkoutAuditLog(ctx, userID, workoutID)
+	if err != nil {
+		if apiErr := workoutChangeError(err); apiErr != nil {
+			return apiErr
+		}
+		h.logger.Error(ctx, "Service failed to get workout audit log", "userID", userID, "workoutID", workoutID, "error", err)
+		return NewAPIError(http.StatusInternalServerError, ErrCodeInternalServer, "Failed to retrieve workout history")
+	}
+
+	resp := make([]WorkoutAuditEntryResponse, len(entries))
+	for i, entry := range entries {
+		resp[i] = WorkoutAuditEntryResponse{
+			ID:          entry.ID,
+			ActorUserID: entry.ActorUserID,
+			Action:      entry.Action,
+			Version:     entry.Version,
+			Changes:     entry.Changes,
+			CreatedAt:   entry.CreatedAt,
+		}
+	}
+	return c.JSON(http.StatusOK, resp)
+}
@@ -1,12 +1,16 @@
 package handlers
 
 import (
+	"compress/gzip"
+	"errors"
+	"io"
 	"math"
 	"net/http"
 	"strconv"
 	"strings"
 	"time"
 
+	"ptchampion/internal/grading"
 	"ptchampion/internal/logging"
 	"ptchampion/internal/store" // For store.WorkoutRecord, store.PaginatedWorkoutRecords
 	"ptchampion/internal/workouts"
@@ -21,10 +25,14 @@ type LogWorkoutRequest struct {
 	ExerciseID      int32     `json:"exercise_id" validate:"required,gt=0"`
 	Reps            *int32    `json:"reps,omitempty" validate:"omitempty,min=0"`
 	DurationSeconds *int32    `json:"duration_seconds,omitempty" validate:"omitempty,min=0"`
+	WeightLbs       *int32    `json:"weight_lbs,omitempty" validate:"omitempty,gt=0"`
+	DistanceMeters  *float64  `json:"distance_meters,omitempty" validate:"omitempty,gt=0"`
 	Grade           int32     `json:"grade" validate:"required,min=0,max=100"`
 	FormScore       *int32    `json:"form_score,omitempty" validate:"omitempty,min=0,max=100"`
 	CompletedAt     time.Time `json:"completed_at" validate:"required"`
 	IsPublic        bool      `json:"is_public"`
+	Visibility      string    `json:"visibility,omitempty" validate:"omitempty,oneof=private friends public"` // Overrides is_public
+	ScoringStandard string    `json:"scoring_standard,omitempty"` // Registry ID, e.g. "acft-2022"; defaults to apft-2013
 }
 
 // WorkoutResponse defines the API response for a single workout record.
@@ -37,11 +45,31 @@ type WorkoutResponse struct {
 	ExerciseType    string    `json:"exercise_type"`
 	Reps            *int32    `json:"reps,omitempty"`
 	DurationSeconds *int32    `json:"duration_seconds,omitempty"`
+	WeightLbs       *int32    `json:"weight_lbs,omitempty"`
+	DistanceMeters  *float64  `json:"distance_meters,omitempty"`
 	FormScore       *int32    `json:"form_score,omitempty"`
 	Grade           int32     `json:"grade"`
 	IsPublic        bool      `json:"is_public"`
+	Visibility      string    `json:"visibility"`
 	CompletedAt     time.Time `json:"completed_at"`
 	CreatedAt       time.Time `json:"created_at"`
+
+	VerificationStatus string     `json:"verification_status,omitempty"`
+	ExpectedGrade      *int32     `json:"expected_grade,omitempty"`
+	GradeDiscrepancy   *int32     `json:"grade_discrepancy,omitempty"`
+	ScoringStandard    string     `json:"scoring_standard,omitempty"`
+	ScoringVersion     string     `json:"scoring_version,omitempty"`
+	ReplayReps         *int32     `json:"replay_reps,omitempty"`
+	ReplayFormScore    *int32     `json:"replay_form_score,omitempty"`
+	ReplayedAt         *time.Time `json:"replayed_at,omitempty"`
+}
+
+// LogWorkoutResponse defines the API response for a newly logged workout.
+type LogWorkoutResponse struct {
+	WorkoutResponse
+	IsPersonalRecord bool                     `json:"is_personal_record"`
+	PersonalRecords  []PersonalRecordResponse `json:"personal_records"` // Records the workout set
+	Achievements     []BadgeResponse          `json:"achievements"`     // Badges the workout earned
 }
 
 // PaginatedWorkoutsResponse defines the API response for a list of workout records.
@@ -55,10 +83,15 @@ type PaginatedWorkoutsResponse struct {
 }
 
 // UpdateWorkoutVisibilityRequest defines the API request for updating visibility.
+// Visibility, when given, overrides IsPublic.
 type UpdateWorkoutVisibilityRequest struct {
-	IsPublic bool `json:"is_public"`
+	IsPublic   bool   `json:"is_public"`
+	Visibility string `json:"visibility,omitempty" validate:"omitempty,oneof=private friends public"`
 }
 
+// maxFrameUploadBytes bounds the decompressed size of an uploaded pose-frame stream.
+const maxFrameUploadBytes = 64 << 20
+
 // WorkoutHandler handles workout-related API requests.
 type WorkoutHandler struct {
 	service workouts.Service
@@ -82,11 +115,23 @@ func mapStoreWorkoutRecordToResponse(record *store.WorkoutRecord) WorkoutRespons
 		ExerciseType:    record.ExerciseType,
 		Reps:            record.Reps,
 		DurationSeconds: record.DurationSeconds,
+		WeightLbs:       record.WeightLbs,
+		DistanceMeters:  record.DistanceMeters,
 		FormScore:       record.FormScore,
 		Grade:           record.Grade,
 		IsPublic:        record.IsPublic,
+		Visibility:      record.Visibility,
 		CompletedAt:     record.CompletedAt,
 		CreatedAt:       record.CreatedAt,
+
+		VerificationStatus: record.VerificationStatus,
+		ExpectedGrade:      record.ExpectedGrade,
+		GradeDiscrepancy:   record.GradeDiscrepancy,
+		ScoringStandard:    record.ScoringStandard,
+		ScoringVersion:     record.ScoringVersion,
+		ReplayReps:         record.ReplayReps,
+		ReplayFormScore:    record.ReplayFormScore,
+		ReplayedAt:         record.ReplayedAt,
 	}
 }
 
@@ -103,23 +148,42 @@ func (h *WorkoutHandler) LogWorkout(c echo.Context) error {
 
 	userID := c.Get("user_id").(int32)
 
+	if !scoringStandardEnabled(c, req.ScoringStandard) {
+		return echo.NewHTTPError(http.StatusBadRequest, "Scoring standard selection is not enabled")
+	}
+
 	// Client provides pre-calculated grade
 	serviceData := &workouts.LogWorkoutData{
 		ExerciseID:      req.ExerciseID,
 		Reps:            req.Reps,
 		DurationSeconds: req.DurationSeconds,
+		WeightLbs:       req.WeightLbs,
+		DistanceMeters:  req.DistanceMeters,
 		Grade:           req.Grade,
 		FormScore:       req.FormScore,
 		CompletedAt:     req.CompletedAt,
 		IsPublic:        req.IsPublic,
+		Visibility:      req.Visibility,
+		ScoringStandard: req.ScoringStandard,
 	}
 
-	workout, err := h.service.LogWorkout(c.Request().Context(), userID, serviceData)
+	result, err := h.service.LogWorkout(c.Request().Context(), userID, serviceData)
 	if err != nil {
+		if errors.Is(err, grading.ErrUnknownStandard) || errors.Is(err, workouts.ErrEventNotInStandard) ||
+			errors.Is(err, workouts.ErrMissingMeasurement) || errors.Is(err, workouts.ErrInvalidMeasurement) ||
+			errors.Is(err, workouts.ErrInvalidVisibility) {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
 		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
 	}
 
-	return c.JSON(http.StatusCreated, mapStoreWorkoutRecordToResponse(workout))
+	resp := LogWorkoutResponse{
+		WorkoutResponse:  mapStoreWorkoutRecordToResponse(result.Workout),
+		IsPersonalRecord: len(result.PersonalRecords) > 0,
+		PersonalRecords:  mapPersonalRecordsToResponse(result.PersonalRecords),
+		Achievements:     mapBadgesToResponse(result.Achievements),
+	}
+	return c.JSON(http.StatusCreated, resp)
 }
 
 // ListUserWorkouts handles GET requests to list a user's workout records.
@@ -212,9 +276,20 @@ func (h *WorkoutHandler) UpdateWorkoutVisibility(c echo.Context) error {
 		return NewAPIError(http.StatusBadRequest, ErrCodeBadRequest, "Invalid request body")
 	}
 
-	// No validation needed for a single boolean? Add if more complex later.
+	if err := c.Validate(req); err != nil {
+		h.logger.Warn(ctx, "Invalid UpdateWorkoutVisibility request", "error", err)
+		return NewAPIError(http.StatusBadRequest, ErrCodeValidation, err.Error())
+	}
+
+	visibility := req.Visibility
+	if visibility == "" {
+		visibility = store.WorkoutVisibilityPrivate
+		if req.IsPublic {
+			visibility = store.WorkoutVisibilityPublic
+		}
+	}
 
-	err = h.service.UpdateWorkoutVisibility(ctx, userID, int32(workoutID), req.IsPublic)
+	err = h.service.UpdateWorkoutVisibility(ctx, userID, int32(workoutID), visibility)
 	if err != nil {
 		if err == store.ErrWorkoutRecordNotFound {
 			return NewAPIError(http.StatusNotFound, ErrCodeNotFound, "Workout record not found")
@@ -227,3 +302,93 @@ func (h *WorkoutHandler) UpdateWorkoutVisibility(c echo.Context) error {
 
 	return c.NoContent(http.StatusOK)
 }
+
+// ListMismatchedWorkouts handles admin GET requests listing workouts whose client grade
+// failed server-side verification.
+func (h *WorkoutHandler) ListMismatchedWorkouts(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	page, _ := strconv.Atoi(c.QueryParam("page"))
+	pageSize, _ := strconv.Atoi(c.QueryParam("pageSize"))
+
+	paginatedResults, err := h.service.ListMismatchedWorkouts(ctx, page, pageSize)
+	if err != nil {
+		h.logger.Error(ctx, "Service failed to list mismatched workouts", "error", err)
+		return NewAPIError(http.StatusInternalServerError, ErrCodeInternalServer, "Failed to retrieve mismatched workout records")
+	}
+
+	apiItems := make([]WorkoutResponse, len(paginatedResults.Records))
+	for i, record := range paginatedResults.Records {
+		apiItems[i] = mapStoreWorkoutRecordToResponse(record)
+	}
+
+	actualPage := page
+	if actualPage < 1 {
+		actualPage = 1
+	}
+	actualPageSize := pageSize
+	if actualPageSize < 1 || actualPageSize > 100 {
+		actualPageSize = 20
+	}
+
+	return c.JSON(http.StatusOK, PaginatedWorkoutsResponse{
+		Items:      apiItems,
+		TotalCount: paginatedResults.TotalCount,
+		Page:       actualPage,
+		PageSize:   actualPageSize,
+		TotalPages: int(math.Ceil(float64(paginatedResults.TotalCount) / float64(actualPageSize))),
+	})
+}
+
+// UploadWorkoutFrames handles POST requests carrying the recorded keypoint frames for a
+// workout. The body is a newline-delimited JSON stream of frames, optionally gzip-compressed
+// (Content-Encoding: gzip). The frames are replayed through the server-side grader.
+func (h *WorkoutHandler) UploadWorkoutFrames(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, err := GetUserIDFromContext(c)
+	if err != nil {
+		h.logger.Error(ctx, "Could not get user ID from context for UploadWorkoutFrames", "error", err)
+		return NewAPIError(http.StatusUnauthorized, ErrCodeUnauthorized, "Authentication error: User ID not found")
+	}
+
+	workoutIDStr := c.Param("workout_id")
+	workoutID, err := strconv.Atoi(workoutIDStr)
+	if err != nil {
+		h.logger.Warn(ctx, "Invalid workout ID format", "workoutID", workoutIDStr, "error", err)
+		return NewAPIError(http.StatusBadRequest, ErrCodeBadRequest, "Invalid workout ID format")
+	}
+
+	var body io.Reader = c.Request().Body
+	if strings.EqualFold(c.Request().Header.Get(echo.HeaderContentEncoding), "gzip") {
+		gz, err := gzip.NewReader(body)
+		if err != nil {
+			return NewAPIError(http.StatusBadRequest, ErrCodeBadRequest, "Invalid gzip body")
+		}
+		defer gz.Close()
+		body = gz
+	}
+
+	poses, err := grading.DecodePoseFrames(io.LimitReader(body, maxFrameUploadBytes))
+	if err != nil {
+		h.logger.Warn(ctx, "Failed to decode pose frames", "workoutID", workoutID, "error", err)
+		return NewAPIError(http.StatusBadRequest, ErrCodeBadRequest, "Invalid frame stream")
+	}
+
+	record, err := h.service.ReplayWorkoutFrames(ctx, userID, int32(workoutID), poses)
+	if err != nil {
+		switch {
+		case err == store.ErrWorkoutRecordNotFound:
+			return NewAPIError(http.StatusNotFound, ErrCodeNotFound, "Workout record not found")
+		case strings.Contains(err.Error(), "user does not have permission"):
+			return NewAPIError(http.StatusForbidden, ErrCodeForbidden, "You do not have permission to modify this workout")
+		case errors.Is(err, workouts.ErrReplayRepMismatch):
+			return NewAPIError(http.StatusUnprocessableEntity, ErrCodeValidation, "Replayed rep count does not match submitted reps")
+		case errors.Is(err, workouts.ErrReplayUnsupported), errors.Is(err, grading.ErrInvalidInput):
+			return NewAPIError(http.StatusBadRequest, ErrCodeBadRequest, err.Error())
+		}
+		h.logger.Error(ctx, "Service failed to replay workout frames", "userID", userID, "workoutID", workoutID, "error", err)
+		return NewAPIError(http.StatusInternalServerError, ErrCodeInternalServer, "Failed to replay workout frames")
+	}
+
+	return c.JSON(http.StatusOK, mapStoreWorkoutRecordToResponse(record))
+}
@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// AdminOnlyMiddleware restricts access to the configured admin user IDs.
+// It must run after JWTAuthMiddleware so the user ID is already in the context.
+func AdminOnlyMiddleware(adminUserIDs []int32) echo.MiddlewareFunc {
+	admins := make(map[int32]struct{}, len(adminUserIDs))
+	for _, id := range adminUserIDs {
+		admins[id] = struct{}{}
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			userID, ok := GetUserID(c)
+			if !ok {
+				return echo.ErrUnauthorized
+			}
+
+			if _, isAdmin := admins[userID]; !isAdmin {
+				log.Printf("WARN: User %d attempted to access admin endpoint %s", userID, c.Request().URL.Path)
+				return echo.NewHTTPError(http.StatusForbidden, "Admin access required")
+			}
+
+			return next(c)
+		}
+	}
+}
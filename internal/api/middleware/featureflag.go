@@ -171,6 +171,29 @@ func (m *FeatureFlagMiddleware) IsFlagEnabled(c echo.Context, flagName string, d
 	return defaultValue
 }
 
+// FlagEnabled checks a boolean feature flag using the client installed on the context
+// by Middleware. It returns defaultValue when feature flags are not configured.
+func FlagEnabled(c echo.Context, flagName string, defaultValue bool) bool {
+	m, ok := c.Get("featureFlagClient").(*FeatureFlagMiddleware)
+	if !ok || m == nil {
+		return defaultValue
+	}
+	return m.IsFlagEnabled(c, flagName, defaultValue)
+}
+
+// RequireFlag hides the routes it wraps unless the boolean feature flag is enabled,
+// responding as if they did not exist.
+func RequireFlag(flagName string, defaultValue bool) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if !FlagEnabled(c, flagName, defaultValue) {
+				return echo.NewHTTPError(http.StatusNotFound, "Not found")
+			}
+			return next(c)
+		}
+	}
+}
+
 // GetFlagString retrieves a string feature flag value
 func (m *FeatureFlagMiddleware) GetFlagString(c echo.Context, flagName string, defaultValue string) string {
 	value := m.GetFlag(c, flagName, defaultValue)
@@ -6,12 +6,18 @@ import (
 
 	"github.com/labstack/echo/v4"
 
+	"ptchampion/internal/analytics"
 	"ptchampion/internal/api/handlers"
 	"ptchampion/internal/api/middleware"
 	"ptchampion/internal/auth"
+	"ptchampion/internal/challenges"
 	"ptchampion/internal/config"
+	"ptchampion/internal/feed"
+	"ptchampion/internal/grading"
 	"ptchampion/internal/leaderboards"
 	"ptchampion/internal/logging"
+	"ptchampion/internal/organizations"
+	"ptchampion/internal/social"
 	db "ptchampion/internal/store/postgres"
 	"ptchampion/internal/store/redis"
 	"ptchampion/internal/users"
@@ -69,17 +75,45 @@ func RegisterRoutes(e *echo.Echo, cfg *config.Config, store *db.Store, tokenServ
 	// TODO: Create minimal exercise handler for definitions endpoint if needed
 
 	// Instantiate Leaderboard Service and Leaderboard Handler
-	leaderboardService := leaderboards.NewService(store, logger)
+	leaderboardService := leaderboards.NewService(store, store, logger)
 	leaderboardHandler := handlers.NewLeaderboardHandler(leaderboardService, logger)
 
 	// Instantiate Workout Service and Workout Handler
-	// store implements both store.WorkoutStore and store.ExerciseStore
-	workoutService := workouts.NewService(store, store, logger)
+	// store implements store.WorkoutStore, store.ExerciseStore, store.UserStore and store.PersonalRecordStore
+	workoutService := workouts.NewService(store, store, store, store, leaderboardCache, logger)
 	workoutHandler := handlers.NewWorkoutHandler(workoutService, logger)
 	
+	// Instantiate Organization Service and Organization Handler
+	// store implements store.OrganizationStore and store.UserStore
+	organizationService := organizations.NewService(store, store, logger)
+	organizationHandler := handlers.NewOrganizationHandler(organizationService, logger)
+
+	// Instantiate Social Service and Social Handler
+	// store implements store.SocialStore and store.UserStore
+	socialService := social.NewService(store, store, logger)
+	socialHandler := handlers.NewSocialHandler(socialService, logger)
+
+	// Instantiate Feed Service and Feed Handler
+	// store implements store.FeedStore
+	feedService := feed.NewService(store, logger)
+	feedHandler := handlers.NewFeedHandler(feedService, logger)
+
+	// Instantiate Challenge Service and Challenge Handler
+	// store implements store.ChallengeStore and store.ExerciseStore
+	challengeService := challenges.NewService(store, store, organizationService, logger)
+	challengeHandler := handlers.NewChallengeHandler(challengeService, logger)
+
+	// Instantiate Analytics Service and Analytics Handler
+	// store implements store.AnalyticsStore
+	analyticsService := analytics.NewService(store, logger)
+	analyticsHandler := handlers.NewAnalyticsHandler(analyticsService, logger)
+
 	// Instantiate Dashboard Handler (uses workout service)
 	dashboardHandler := handlers.NewDashboardHandler(workoutService, logger)
 
+	// Scoring standards are embedded in the grading package
+	scoringHandler := handlers.NewScoringHandler(grading.DefaultRegistry(), logger)
+
 	// Create API group
 	apiGroup := e.Group("/api/v1")
 
@@ -99,6 +133,9 @@ func RegisterRoutes(e *echo.Echo, cfg *config.Config, store *db.Store, tokenServ
 	// Register social auth routes
 	RegisterSocialAuthRoutes(authGroup, socialAuthHandler)
 
+	// Scoring standards are public reference data
+	apiGroup.GET("/scoring/standards", scoringHandler.ListStandards)
+
 	// Create a separate group for protected routes
 	protectedGroup := apiGroup.Group("", authMiddleware)
 
@@ -111,14 +148,50 @@ func RegisterRoutes(e *echo.Echo, cfg *config.Config, store *db.Store, tokenServ
 	// Workout Routes
 	workoutRoutesGroup := protectedGroup.Group("/workouts")
 	RegisterWorkoutRoutes(workoutRoutesGroup, store, logger, workoutHandler)
+
+	// Test Session Routes (full PT tests, handled by the workout handler)
+	testSessionRoutesGroup := protectedGroup.Group("/test-sessions")
+	RegisterTestSessionRoutes(testSessionRoutesGroup, store, logger, workoutHandler)
+
+	// Goal Routes (targets measured against workouts, handled by the workout handler)
+	goalRoutesGroup := protectedGroup.Group("/goals")
+	RegisterGoalRoutes(goalRoutesGroup, store, logger, workoutHandler)
+
+	// Training Plan Routes (generated from the latest grades, handled by the workout handler)
+	trainingPlanRoutesGroup := protectedGroup.Group("/training-plans")
+	RegisterTrainingPlanRoutes(trainingPlanRoutesGroup, store, logger, workoutHandler)
 	
 	// Dashboard Routes
 	protectedGroup.GET("/dashboard/stats", dashboardHandler.GetDashboardStats)
+	protectedGroup.GET("/dashboard/streaks", dashboardHandler.GetStreaks)
+
+	// Analytics Routes
+	protectedGroup.GET("/analytics/progress", analyticsHandler.GetProgress)
 
 	// Leaderboard Routes
 	leaderboardRoutesGroup := protectedGroup.Group("/leaderboards")
 	RegisterLeaderboardRoutes(leaderboardRoutesGroup, store, logger, leaderboardHandler)
 
+	// Organization Routes (units and their members)
+	organizationRoutesGroup := protectedGroup.Group("/organizations")
+	RegisterOrganizationRoutes(organizationRoutesGroup, store, logger, organizationHandler)
+
+	// Friend Routes (friends, friend requests and blocks)
+	friendRoutesGroup := protectedGroup.Group("/friends")
+	RegisterFriendRoutes(friendRoutesGroup, store, logger, socialHandler)
+
+	// Feed Routes (activity of the caller and their friends, with reactions and comments)
+	feedRoutesGroup := protectedGroup.Group("/feed")
+	RegisterFeedRoutes(feedRoutesGroup, store, logger, feedHandler)
+
+	// Challenge Routes (hidden unless the team challenges flag is on)
+	challengeRoutesGroup := protectedGroup.Group("/challenges", middleware.RequireFlag(middleware.FlagTeamChallenges, true))
+	RegisterChallengeRoutes(challengeRoutesGroup, store, logger, challengeHandler)
+
+	// Admin Routes
+	adminRoutesGroup := protectedGroup.Group("/admin", middleware.AdminOnlyMiddleware(cfg.AdminUserIDs))
+	RegisterAdminRoutes(adminRoutesGroup, store, logger, workoutHandler)
+
 	// Exercise Routes - Temporarily disabled until we create a minimal handler
 	// exerciseRoutesGroup := protectedGroup.Group("/exercises")
 	// RegisterExerciseRoutes(exerciseRoutesGroup, store, logger, exerciseHandler)
@@ -148,7 +221,35 @@ func RegisterUserRoutes(g *echo.Group, store *db.Store, logger logging.Logger, u
 func RegisterWorkoutRoutes(g *echo.Group, store *db.Store, logger logging.Logger, workoutHandler *handlers.WorkoutHandler) {
 	g.GET("", workoutHandler.ListUserWorkouts)
 	g.POST("", workoutHandler.LogWorkout)
+	g.GET("/personal-records", workoutHandler.GetPersonalRecords)
+	g.GET("/personal-records/history", workoutHandler.GetPersonalRecordHistory)
+	g.GET("/achievements", workoutHandler.ListAchievements)
+	g.PATCH("/:workout_id", workoutHandler.UpdateWorkout)
+	g.DELETE("/:workout_id", workoutHandler.DeleteWorkout)
+	g.GET("/:workout_id/audit", workoutHandler.GetWorkoutAuditLog)
 	g.PATCH("/:workout_id/visibility", workoutHandler.UpdateWorkoutVisibility)
+	g.POST("/:workout_id/frames", workoutHandler.UploadWorkoutFrames)
+}
+
+// RegisterTestSessionRoutes registers test session routes under the given group (e.g., /api/v1/test-sessions)
+func RegisterTestSessionRoutes(g *echo.Group, store *db.Store, logger logging.Logger, workoutHandler *handlers.WorkoutHandler) {
+	g.GET("", workoutHandler.ListTestSessions)
+	g.POST("", workoutHandler.CreateTestSession)
+	g.GET("/:session_id", workoutHandler.GetTestSession)
+}
+
+// RegisterGoalRoutes registers goal routes under the given group (e.g., /api/v1/goals)
+func RegisterGoalRoutes(g *echo.Group, store *db.Store, logger logging.Logger, workoutHandler *handlers.WorkoutHandler) {
+	g.GET("", workoutHandler.ListGoals)
+	g.POST("", workoutHandler.CreateGoal)
+	g.GET("/:goal_id", workoutHandler.GetGoal)
+	g.DELETE("/:goal_id", workoutHandler.DeleteGoal)
+}
+
+// RegisterTrainingPlanRoutes registers training plan routes under the given group (e.g., /api/v1/training-plans)
+func RegisterTrainingPlanRoutes(g *echo.Group, store *db.Store, logger logging.Logger, workoutHandler *handlers.WorkoutHandler) {
+	g.POST("", workoutHandler.GenerateTrainingPlan)
+	g.GET("/current", workoutHandler.GetTrainingPlan)
 }
 
 // RegisterLeaderboardRoutes registers leaderboard-related routes under the given group (e.g., /api/v1/leaderboards)
@@ -157,11 +258,24 @@ func RegisterLeaderboardRoutes(g *echo.Group, store *db.Store, logger logging.Lo
 	g.GET("/global/exercise/:exerciseType", leaderboardHandler.GetGlobalExerciseLeaderboard)
 	g.GET("/global/aggregate", leaderboardHandler.GetGlobalAggregateLeaderboard)
 	g.GET("/global/overall", leaderboardHandler.GetGlobalAggregateLeaderboard) // NEW route for global "Overall"
+	g.GET("/global/acft", leaderboardHandler.GetGlobalACFTLeaderboard)          // ACFT six-event total
 
 	// Local leaderboards
 	g.GET("/local/exercise/:exerciseType", leaderboardHandler.GetLocalExerciseLeaderboard)
 	g.GET("/local/aggregate", leaderboardHandler.GetLocalAggregateLeaderboard)
 	g.GET("/local/overall", leaderboardHandler.GetLocalAggregateLeaderboard) // NEW route for local "Overall"
+	g.GET("/local/acft", leaderboardHandler.GetLocalACFTLeaderboard)          // ACFT six-event total
+
+	// Organization leaderboards: members of a unit and its subunits, and subunits against each other
+	g.GET("/organization/:org_id/exercise/:exerciseType", leaderboardHandler.GetOrganizationExerciseLeaderboard)
+	g.GET("/organization/:org_id/overall", leaderboardHandler.GetOrganizationOverallLeaderboard)
+	g.GET("/organization/:org_id/acft", leaderboardHandler.GetOrganizationACFTLeaderboard)
+	g.GET("/organization/:org_id/units", leaderboardHandler.GetUnitLeaderboard)
+
+	// Friends leaderboards: the caller and their friends, counting friends-only workouts
+	g.GET("/friends/exercise/:exerciseType", leaderboardHandler.GetFriendsExerciseLeaderboard)
+	g.GET("/friends/overall", leaderboardHandler.GetFriendsOverallLeaderboard)
+	g.GET("/friends/acft", leaderboardHandler.GetFriendsACFTLeaderboard)
 
 	// Support for legacy routes if needed - these can be removed in the future
 	g.GET("/overall", leaderboardHandler.GetGlobalAggregateLeaderboard)      // Map to aggregate
@@ -169,6 +283,61 @@ func RegisterLeaderboardRoutes(g *echo.Group, store *db.Store, logger logging.Lo
 	g.GET("/local", leaderboardHandler.GetLocalExerciseLeaderboard)          // Map to local exercise type (requires exercise type param)
 }
 
+// RegisterOrganizationRoutes registers organization routes under the given group (e.g., /api/v1/organizations)
+func RegisterOrganizationRoutes(g *echo.Group, store *db.Store, logger logging.Logger, organizationHandler *handlers.OrganizationHandler) {
+	g.GET("", organizationHandler.ListMyOrganizations)
+	g.POST("", organizationHandler.CreateOrganization)
+	g.GET("/invitations", organizationHandler.ListMyOrganizationInvitations)
+	g.GET("/:org_id", organizationHandler.GetOrganization)
+	g.PATCH("/:org_id", organizationHandler.UpdateOrganization)
+	g.DELETE("/:org_id", organizationHandler.DeleteOrganization)
+	g.GET("/:org_id/readiness", organizationHandler.GetReadinessDashboard) // Leaders only
+	g.GET("/:org_id/members", organizationHandler.ListOrganizationMembers)
+	g.POST("/:org_id/members", organizationHandler.AddOrganizationMember) // Invites; the user must accept
+	g.POST("/:org_id/invitation/accept", organizationHandler.AcceptOrganizationInvitation)
+	g.PATCH("/:org_id/membership", organizationHandler.UpdateMyOrganizationMembership) // Caller's own settings
+	g.PATCH("/:org_id/members/:user_id", organizationHandler.UpdateOrganizationMember)
+	g.DELETE("/:org_id/members/:user_id", organizationHandler.RemoveOrganizationMember)
+}
+
+// RegisterFriendRoutes registers friendship routes under the given group (e.g., /api/v1/friends)
+func RegisterFriendRoutes(g *echo.Group, store *db.Store, logger logging.Logger, socialHandler *handlers.SocialHandler) {
+	g.GET("", socialHandler.ListFriends)
+	g.GET("/requests", socialHandler.ListFriendRequests)
+	g.POST("/requests", socialHandler.SendFriendRequest)
+	g.POST("/requests/:user_id/accept", socialHandler.AcceptFriendRequest)
+	g.GET("/blocks", socialHandler.ListBlockedUsers)
+	g.POST("/blocks/:user_id", socialHandler.BlockUser)
+	g.DELETE("/blocks/:user_id", socialHandler.UnblockUser)
+	g.DELETE("/:user_id", socialHandler.RemoveFriend)
+}
+
+// RegisterFeedRoutes registers activity feed routes under the given group (e.g., /api/v1/feed)
+func RegisterFeedRoutes(g *echo.Group, store *db.Store, logger logging.Logger, feedHandler *handlers.FeedHandler) {
+	g.GET("", feedHandler.GetFeed)
+	g.PUT("/:event_id/reaction", feedHandler.React)
+	g.DELETE("/:event_id/reaction", feedHandler.Unreact)
+	g.GET("/:event_id/comments", feedHandler.ListComments)
+	g.POST("/:event_id/comments", feedHandler.AddComment)
+	g.DELETE("/:event_id/comments/:comment_id", feedHandler.DeleteComment)
+}
+
+// RegisterChallengeRoutes registers challenge routes under the given group (e.g., /api/v1/challenges)
+func RegisterChallengeRoutes(g *echo.Group, store *db.Store, logger logging.Logger, challengeHandler *handlers.ChallengeHandler) {
+	g.GET("", challengeHandler.ListChallenges)
+	g.POST("", challengeHandler.CreateChallenge)
+	g.GET("/:challenge_id", challengeHandler.GetChallenge)
+	g.POST("/:challenge_id/join", challengeHandler.JoinChallenge)
+	g.POST("/:challenge_id/leave", challengeHandler.LeaveChallenge)
+	g.GET("/:challenge_id/standings", challengeHandler.GetChallengeStandings)
+}
+
+// RegisterAdminRoutes registers admin-only routes under the given group (e.g., /api/v1/admin)
+func RegisterAdminRoutes(g *echo.Group, store *db.Store, logger logging.Logger, workoutHandler *handlers.WorkoutHandler) {
+	g.GET("/workouts/mismatches", workoutHandler.ListMismatchedWorkouts)
+	g.POST("/achievements/backfill", workoutHandler.BackfillAchievements)
+}
+
 // RegisterExerciseRoutes registers exercise-related routes under the given group (e.g., /api/v1/exercises)
 // TEMPORARILY DISABLED - will be re-enabled when we create a minimal exercise handler
 /*
@@ -0,0 +1,411 @@
+package challenges
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"ptchampion/internal/grading"
+	"ptchampion/internal/logging"
+	"ptchampion/internal/organizations"
+	"ptchampion/internal/store"
+)
+
+// maxChallengeWindow bounds how long a challenge may run.
+const maxChallengeWindow = 366 * 24 * time.Hour
+
+// ErrPermissionDenied is returned when a user may not enter or withdraw a unit.
+var ErrPermissionDenied = errors.New("user does not have permission to manage this unit's challenge entry")
+
+// ErrInvalidChallenge is returned for a challenge whose window or scoring rule is not valid.
+var ErrInvalidChallenge = errors.New("invalid challenge")
+
+// ErrChallengeClosed is returned when joining or leaving a challenge after it has ended.
+var ErrChallengeClosed = errors.New("challenge has closed")
+
+// ErrParticipantType is returned when a user joins a team challenge, or a unit an individual one.
+var ErrParticipantType = errors.New("participant does not match the challenge's participant type")
+
+// CreateChallengeData defines a new challenge at the service layer.
+type CreateChallengeData struct {
+	Name            string
+	Description     *string
+	ExerciseID      int32
+	ScoringRule     string // One of the store.ChallengeRule* constants
+	ParticipantType string // One of the store.ChallengeParticipant* constants
+	StartsAt        time.Time
+	EndsAt          time.Time
+}
+
+// ChallengeStandings is a challenge's leaderboard, live while it runs and frozen once it closes.
+type ChallengeStandings struct {
+	Challenge *store.Challenge
+	Standings []*store.ChallengeStanding
+	Final     bool // The standings are the frozen results
+}
+
+// Service defines the interface for challenge business logic.
+type Service interface {
+	CreateChallenge(ctx context.Context, userID int32, data *CreateChallengeData) (*store.Challenge, error)
+	GetChallenge(ctx context.Context, challengeID int32) (*store.Challenge, error)
+	ListChallenges(ctx context.Context, status string, page, pageSize int) (*store.PaginatedChallenges, error)
+	// JoinChallenge enters the user in an individual challenge, or the unit orgID in a
+	// team challenge, which requires a leader role in the unit.
+	JoinChallenge(ctx context.Context, userID int32, challengeID int32, orgID *int32) (*store.ChallengeParticipant, error)
+	LeaveChallenge(ctx context.Context, userID int32, challengeID int32, orgID *int32) error
+	GetStandings(ctx context.Context, challengeID int32) (*ChallengeStandings, error)
+}
+
+type service struct {
+	challengeStore store.ChallengeStore
+	exerciseStore  store.ExerciseStore
+	orgService     organizations.Service // To check roles for team entries
+	logger         logging.Logger
+}
+
+// NewService creates a new challenge service instance.
+func NewService(challengeStore store.ChallengeStore, exerciseStore store.ExerciseStore, orgService organizations.Service, logger logging.Logger) Service {
+	return &service{
+		challengeStore: challengeStore,
+		exerciseStore:  exerciseStore,
+		orgService:     orgService,
+		logger:         logger,
+	}
+}
+
+// validateScoringRule checks the rule measures what the exercise is scored by:
+// repetition rules need a repetition exercise and best_time a timed one where
+// faster is better.
+func validateScoringRule(rule string, exerciseType string) error {
+	event := exerciseType
+	if event == "running" {
+		event = grading.ExerciseTypeRun
+	}
+	metric, err := grading.MetricFor(event)
+	if err != nil {
+		return fmt.Errorf("%w: exercise %q cannot be scored", ErrInvalidChallenge, exerciseType)
+	}
+
+	switch rule {
+	case store.ChallengeRuleMaxReps, store.ChallengeRuleTotalVolume:
+		if metric != grading.MetricReps {
+			return fmt.Errorf("%w: %s requires a repetition exercise", ErrInvalidChallenge, rule)
+		}
+	case store.ChallengeRuleBestTime:
+		if metric != grading.MetricDuration || event == grading.ExerciseTypePlank {
+			return fmt.Errorf("%w: %s requires a timed exercise", ErrInvalidChallenge, rule)
+		}
+	default:
+		return fmt.Errorf("%w: unknown scoring rule %q", ErrInvalidChallenge, rule)
+	}
+	return nil
+}
+
+// validateWindow checks the challenge ends after it starts, has not already ended and
+// runs no longer than maxChallengeWindow.
+func validateWindow(startsAt, endsAt, now time.Time) error {
+	switch {
+	case !endsAt.After(startsAt):
+		return fmt.Errorf("%w: ends_at must be after starts_at", ErrInvalidChallenge)
+	case !endsAt.After(now):
+		return fmt.Errorf("%w: ends_at must be in the future", ErrInvalidChallenge)
+	case endsAt.Sub(startsAt) > maxChallengeWindow:
+		return fmt.Errorf("%w: challenges may run for at most a year", ErrInvalidChallenge)
+	}
+	return nil
+}
+
+// CreateChallenge validates and stores a new challenge created by the user.
+func (s *service) CreateChallenge(ctx context.Context, userID int32, data *CreateChallengeData) (*store.Challenge, error) {
+	if data.ParticipantType != store.ChallengeParticipantIndividual && data.ParticipantType != store.ChallengeParticipantTeam {
+		return nil, fmt.Errorf("%w: unknown participant type %q", ErrInvalidChallenge, data.ParticipantType)
+	}
+	if err := validateWindow(data.StartsAt, data.EndsAt, time.Now()); err != nil {
+		return nil, err
+	}
+
+	exercise, err := s.exerciseStore.GetExerciseDefinition(ctx, data.ExerciseID)
+	if err != nil {
+		if err == store.ErrExerciseNotFound {
+			return nil, err
+		}
+		s.logger.Error(ctx, "Failed to get exercise definition", "exerciseID", data.ExerciseID, "error", err)
+		return nil, fmt.Errorf("failed to retrieve exercise: %w", err)
+	}
+	if err := validateScoringRule(data.ScoringRule, exercise.Type); err != nil {
+		return nil, err
+	}
+
+	challenge, err := s.challengeStore.CreateChallenge(ctx, &store.Challenge{
+		CreatorID:       userID,
+		Name:            data.Name,
+		Description:     data.Description,
+		ExerciseID:      data.ExerciseID,
+		ExerciseType:    exercise.Type,
+		ScoringRule:     data.ScoringRule,
+		ParticipantType: data.ParticipantType,
+		StartsAt:        data.StartsAt,
+		EndsAt:          data.EndsAt,
+	})
+	if err != nil {
+		s.logger.Error(ctx, "Failed to create challenge", "userID", userID, "error", err)
+		return nil, fmt.Errorf("failed to create challenge: %w", err)
+	}
+	s.logger.Info(ctx, "Challenge created", "challengeID", challenge.ID, "userID", userID, "rule", challenge.ScoringRule)
+	return challenge, nil
+}
+
+// GetChallenge returns a challenge, finalizing it first if it has ended.
+func (s *service) GetChallenge(ctx context.Context, challengeID int32) (*store.Challenge, error) {
+	challenge, err := s.getChallenge(ctx, challengeID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.finalizeIfEnded(ctx, challenge); err != nil {
+		return nil, err
+	}
+	return challenge, nil
+}
+
+// ListChallenges returns a page of challenges; status is "active", "upcoming", "closed" or empty for all.
+func (s *service) ListChallenges(ctx context.Context, status string, page, pageSize int) (*store.PaginatedChallenges, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 { // Max page size constraint
+		pageSize = 20 // Default page size
+	}
+	limit := int32(pageSize)
+	offset := int32((page - 1) * pageSize)
+
+	challenges, err := s.challengeStore.ListChallenges(ctx, store.ChallengeFilters{Status: status, At: time.Now()}, limit, offset)
+	if err != nil {
+		s.logger.Error(ctx, "Failed to list challenges", "status", status, "error", err)
+		return nil, fmt.Errorf("failed to retrieve challenges: %w", err)
+	}
+	return challenges, nil
+}
+
+// JoinChallenge enters the user or, for team challenges, the user's unit.
+func (s *service) JoinChallenge(ctx context.Context, userID int32, challengeID int32, orgID *int32) (*store.ChallengeParticipant, error) {
+	challenge, participantUserID, err := s.prepareEntry(ctx, userID, challengeID, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	participant, err := s.challengeStore.AddChallengeParticipant(ctx, challenge.ID, participantUserID, orgID)
+	if err != nil {
+		if err == store.ErrChallengeParticipantExists {
+			return nil, err
+		}
+		s.logger.Error(ctx, "Failed to join challenge", "challengeID", challengeID, "userID", userID, "error", err)
+		return nil, fmt.Errorf("failed to join challenge: %w", err)
+	}
+	s.logger.Info(ctx, "Challenge joined", "challengeID", challengeID, "participantID", participant.ID, "userID", userID)
+	return participant, nil
+}
+
+// LeaveChallenge withdraws the user or, for team challenges, the user's unit.
+func (s *service) LeaveChallenge(ctx context.Context, userID int32, challengeID int32, orgID *int32) error {
+	challenge, participantUserID, err := s.prepareEntry(ctx, userID, challengeID, orgID)
+	if err != nil {
+		return err
+	}
+
+	participant, err := s.challengeStore.GetChallengeParticipant(ctx, challenge.ID, participantUserID, orgID)
+	if err != nil {
+		if err == store.ErrChallengeParticipantNotFound {
+			return err
+		}
+		s.logger.Error(ctx, "Failed to get challenge participant", "challengeID", challengeID, "userID", userID, "error", err)
+		return fmt.Errorf("failed to retrieve challenge participant: %w", err)
+	}
+	if err := s.challengeStore.RemoveChallengeParticipant(ctx, challenge.ID, participant.ID); err != nil {
+		if err == store.ErrChallengeParticipantNotFound {
+			return err
+		}
+		s.logger.Error(ctx, "Failed to leave challenge", "challengeID", challengeID, "participantID", participant.ID, "error", err)
+		return fmt.Errorf("failed to leave challenge: %w", err)
+	}
+	s.logger.Info(ctx, "Challenge left", "challengeID", challengeID, "participantID", participant.ID, "userID", userID)
+	return nil
+}
+
+// prepareEntry checks a join or leave is allowed and returns the challenge and the
+// participant's user ID, which is nil for team entries.
+func (s *service) prepareEntry(ctx context.Context, userID int32, challengeID int32, orgID *int32) (*store.Challenge, *int32, error) {
+	challenge, err := s.getChallenge(ctx, challengeID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !time.Now().Before(challenge.EndsAt) {
+		return nil, nil, ErrChallengeClosed
+	}
+
+	if challenge.ParticipantType == store.ChallengeParticipantIndividual {
+		if orgID != nil {
+			return nil, nil, ErrParticipantType
+		}
+		return challenge, &userID, nil
+	}
+
+	if orgID == nil {
+		return nil, nil, ErrParticipantType
+	}
+	role, err := s.orgService.EffectiveRole(ctx, userID, *orgID)
+	if err != nil {
+		s.logger.Error(ctx, "Failed to get organization role", "userID", userID, "orgID", *orgID, "error", err)
+		return nil, nil, err
+	}
+	if role != store.OrganizationRoleLeader && role != store.OrganizationRoleAdmin {
+		return nil, nil, ErrPermissionDenied
+	}
+	return challenge, nil, nil
+}
+
+// GetStandings returns the live standings of a running challenge, or the frozen results
+// once it has ended.
+func (s *service) GetStandings(ctx context.Context, challengeID int32) (*ChallengeStandings, error) {
+	challenge, err := s.getChallenge(ctx, challengeID)
+	if err != nil {
+		return nil, err
+	}
+
+	final, err := s.finalizeIfEnded(ctx, challenge)
+	if err != nil {
+		return nil, err
+	}
+	if final {
+		results, err := s.challengeStore.GetChallengeResults(ctx, challengeID)
+		if err != nil {
+			s.logger.Error(ctx, "Failed to get challenge results", "challengeID", challengeID, "error", err)
+			return nil, fmt.Errorf("failed to retrieve challenge results: %w", err)
+		}
+		return &ChallengeStandings{Challenge: challenge, Standings: results, Final: true}, nil
+	}
+
+	standings, err := s.liveStandings(ctx, challenge)
+	if err != nil {
+		return nil, err
+	}
+	return &ChallengeStandings{Challenge: challenge, Standings: standings}, nil
+}
+
+func (s *service) getChallenge(ctx context.Context, challengeID int32) (*store.Challenge, error) {
+	challenge, err := s.challengeStore.GetChallengeByID(ctx, challengeID)
+	if err != nil {
+		if err == store.ErrChallengeNotFound {
+			return nil, err
+		}
+		s.logger.Error(ctx, "Failed to get challenge", "challengeID", challengeID, "error", err)
+		return nil, fmt.Errorf("failed to retrieve challenge: %w", err)
+	}
+	return challenge, nil
+}
+
+// liveStandings scores the challenge from the participants' workouts so far.
+func (s *service) liveStandings(ctx context.Context, challenge *store.Challenge) ([]*store.ChallengeStanding, error) {
+	participants, err := s.challengeStore.ListChallengeParticipants(ctx, challenge.ID)
+	if err != nil {
+		s.logger.Error(ctx, "Failed to list challenge participants", "challengeID", challenge.ID, "error", err)
+		return nil, fmt.Errorf("failed to retrieve challenge participants: %w", err)
+	}
+	results, err := s.challengeStore.GetChallengeMemberResults(ctx, challenge)
+	if err != nil {
+		s.logger.Error(ctx, "Failed to get challenge results", "challengeID", challenge.ID, "error", err)
+		return nil, fmt.Errorf("failed to retrieve challenge results: %w", err)
+	}
+	return computeStandings(participants, results, challenge.ScoringRule), nil
+}
+
+// finalizeIfEnded freezes the results of a challenge that has ended and reports whether
+// its results are final. Finalization happens on the first read after the window closes;
+// concurrent readers race harmlessly since only one finalization is stored. The store
+// decides the window has closed by the database clock, so the challenge is re-read and
+// only reported final once its results are actually frozen.
+func (s *service) finalizeIfEnded(ctx context.Context, challenge *store.Challenge) (bool, error) {
+	if challenge.FinalizedAt != nil {
+		return true, nil
+	}
+	if time.Now().Before(challenge.EndsAt) {
+		return false, nil
+	}
+
+	standings, err := s.liveStandings(ctx, challenge)
+	if err != nil {
+		return false, err
+	}
+	finalized, err := s.challengeStore.FinalizeChallenge(ctx, challenge.ID, standings)
+	if err != nil {
+		s.logger.Error(ctx, "Failed to finalize challenge", "challengeID", challenge.ID, "error", err)
+		return false, fmt.Errorf("failed to finalize challenge: %w", err)
+	}
+	if finalized {
+		s.logger.Info(ctx, "Challenge finalized", "challengeID", challenge.ID, "participants", len(standings))
+	}
+
+	// Another reader may have finalized it, or the database clock may not have reached the end yet
+	stored, err := s.getChallenge(ctx, challenge.ID)
+	if err != nil {
+		return false, err
+	}
+	challenge.FinalizedAt = stored.FinalizedAt
+	return challenge.FinalizedAt != nil, nil
+}
+
+// computeStandings scores and ranks the participants. Individuals score their own result;
+// teams score the sum of their members' results for total_volume and the average for the
+// other rules, so unit size does not decide best-effort challenges. best_time ranks the
+// lowest score first. Tied participants share a rank, and participants without a result
+// are listed last, unranked.
+func computeStandings(participants []*store.ChallengeParticipant, results []*store.ChallengeMemberResult, rule string) []*store.ChallengeStanding {
+	byParticipant := make(map[int32][]float64, len(participants))
+	for _, r := range results {
+		byParticipant[r.ParticipantID] = append(byParticipant[r.ParticipantID], r.Value)
+	}
+
+	standings := make([]*store.ChallengeStanding, len(participants))
+	for i, p := range participants {
+		standing := &store.ChallengeStanding{Participant: p}
+		values := byParticipant[p.ID]
+		if len(values) > 0 {
+			var total float64
+			for _, v := range values {
+				total += v
+			}
+			score := total
+			if rule != store.ChallengeRuleTotalVolume {
+				score = total / float64(len(values))
+			}
+			standing.Score = &score
+			standing.Contributors = len(values)
+		}
+		standings[i] = standing
+	}
+
+	lowerIsBetter := rule == store.ChallengeRuleBestTime
+	sort.SliceStable(standings, func(i, j int) bool {
+		a, b := standings[i].Score, standings[j].Score
+		if a == nil || b == nil {
+			return a != nil && b == nil
+		}
+		if lowerIsBetter {
+			return *a < *b
+		}
+		return *a > *b
+	})
+
+	for i, standing := range standings {
+		if standing.Score == nil {
+			break
+		}
+		if i > 0 && *standings[i-1].Score == *standing.Score {
+			standing.Rank = standings[i-1].Rank
+		} else {
+			standing.Rank = int32(i + 1)
+		}
+	}
+	return standings
+}
@@ -0,0 +1,199 @@
+package challenges
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"ptchampion/internal/logging"
+	"ptchampion/internal/store"
+)
+
+func participants(names ...string) []*store.ChallengeParticipant {
+	out := make([]*store.ChallengeParticipant, len(names))
+	for i, name := range names {
+		out[i] = &store.ChallengeParticipant{ID: int32(i + 1), Name: name}
+	}
+	return out
+}
+
+func result(participantID int32, value float64) *store.ChallengeMemberResult {
+	return &store.ChallengeMemberResult{ParticipantID: participantID, UserID: participantID*100 + int32(value), Value: value}
+}
+
+func TestComputeStandings(t *testing.T) {
+	teams := participants("Alpha", "Bravo", "Charlie", "Delta")
+	results := []*store.ChallengeMemberResult{
+		result(1, 40), result(1, 20), // Sum 60, average 30
+		result(2, 50),                // Sum 50, average 50
+		result(3, 30), result(3, 30), // Sum 60, average 30
+		// Delta logged nothing
+	}
+
+	tests := []struct {
+		name      string
+		rule      string
+		wantOrder []string
+		wantRanks []int32
+	}{
+		{
+			name:      "total volume sums members",
+			rule:      store.ChallengeRuleTotalVolume,
+			wantOrder: []string{"Alpha", "Charlie", "Bravo", "Delta"},
+			wantRanks: []int32{1, 1, 3, 0},
+		},
+		{
+			name:      "max reps averages members",
+			rule:      store.ChallengeRuleMaxReps,
+			wantOrder: []string{"Bravo", "Alpha", "Charlie", "Delta"},
+			wantRanks: []int32{1, 2, 2, 0},
+		},
+		{
+			name:      "best time ranks lowest first",
+			rule:      store.ChallengeRuleBestTime,
+			wantOrder: []string{"Alpha", "Charlie", "Bravo", "Delta"},
+			wantRanks: []int32{1, 1, 3, 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			standings := computeStandings(teams, results, tt.rule)
+			if len(standings) != len(tt.wantOrder) {
+				t.Fatalf("got %d standings, want %d", len(standings), len(tt.wantOrder))
+			}
+			for i, standing := range standings {
+				if standing.Participant.Name != tt.wantOrder[i] || standing.Rank != tt.wantRanks[i] {
+					t.Errorf("standing %d = %s rank %d, want %s rank %d", i, standing.Participant.Name, standing.Rank, tt.wantOrder[i], tt.wantRanks[i])
+				}
+			}
+		})
+	}
+
+	standings := computeStandings(teams, results, store.ChallengeRuleTotalVolume)
+	if delta := standings[3]; delta.Score != nil || delta.Contributors != 0 {
+		t.Errorf("Delta score/contributors = %v/%d, want nil/0", delta.Score, delta.Contributors)
+	}
+	if alpha := standings[0]; alpha.Contributors != 2 {
+		t.Errorf("Alpha contributors = %d, want 2", alpha.Contributors)
+	}
+}
+
+func TestValidateScoringRule(t *testing.T) {
+	tests := []struct {
+		rule         string
+		exerciseType string
+		wantErr      bool
+	}{
+		{store.ChallengeRuleMaxReps, "pushup", false},
+		{store.ChallengeRuleTotalVolume, "pullup", false},
+		{store.ChallengeRuleBestTime, "running", false},
+		{store.ChallengeRuleBestTime, "pushup", true},
+		{store.ChallengeRuleBestTime, "plank", true}, // Longer is better
+		{store.ChallengeRuleMaxReps, "running", true},
+		{store.ChallengeRuleMaxReps, "deadlift", true},
+		{"fastest", "running", true},
+		{store.ChallengeRuleMaxReps, "yoga", true},
+	}
+
+	for _, tt := range tests {
+		err := validateScoringRule(tt.rule, tt.exerciseType)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("validateScoringRule(%q, %q) error = %v, wantErr %v", tt.rule, tt.exerciseType, err, tt.wantErr)
+		}
+		if err != nil && !errors.Is(err, ErrInvalidChallenge) {
+			t.Errorf("validateScoringRule(%q, %q) error = %v, want ErrInvalidChallenge", tt.rule, tt.exerciseType, err)
+		}
+	}
+}
+
+func TestValidateWindow(t *testing.T) {
+	now := time.Date(2025, 7, 1, 12, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+
+	if err := validateWindow(now, now.Add(7*day), now); err != nil {
+		t.Errorf("week-long window: %v", err)
+	}
+	if err := validateWindow(now.Add(-7*day), now.Add(-day), now); err == nil {
+		t.Error("window in the past: want error")
+	}
+	if err := validateWindow(now.Add(day), now.Add(day), now); err == nil {
+		t.Error("empty window: want error")
+	}
+	if err := validateWindow(now, now.Add(400*day), now); err == nil {
+		t.Error("window over a year: want error")
+	}
+}
+
+// finalizeStore is a challenge store whose FinalizeChallenge stores nothing, as when the
+// database clock has not reached the end of the window or another reader finalized first.
+// Methods the tests do not reach fall through to the nil store.ChallengeStore and panic.
+type finalizeStore struct {
+	store.ChallengeStore
+
+	challenge  *store.Challenge
+	results    []*store.ChallengeStanding
+	concurrent bool // Another reader finalizes the challenge first
+}
+
+func (f *finalizeStore) GetChallengeByID(ctx context.Context, challengeID int32) (*store.Challenge, error) {
+	stored := *f.challenge
+	return &stored, nil
+}
+
+func (f *finalizeStore) ListChallengeParticipants(ctx context.Context, challengeID int32) ([]*store.ChallengeParticipant, error) {
+	return participants("alpha", "bravo"), nil
+}
+
+func (f *finalizeStore) GetChallengeMemberResults(ctx context.Context, challenge *store.Challenge) ([]*store.ChallengeMemberResult, error) {
+	return []*store.ChallengeMemberResult{result(1, 40), result(2, 50)}, nil
+}
+
+func (f *finalizeStore) FinalizeChallenge(ctx context.Context, challengeID int32, standings []*store.ChallengeStanding) (bool, error) {
+	if f.concurrent {
+		finalizedAt := time.Now()
+		f.challenge.FinalizedAt = &finalizedAt
+	}
+	return false, nil
+}
+
+func (f *finalizeStore) GetChallengeResults(ctx context.Context, challengeID int32) ([]*store.ChallengeStanding, error) {
+	return f.results, nil
+}
+
+func TestGetStandingsNotFinalizedByStore(t *testing.T) {
+	ended := time.Now().Add(-time.Second)
+	challenge := &store.Challenge{ID: 1, ScoringRule: store.ChallengeRuleMaxReps, EndsAt: ended}
+	svc := NewService(&finalizeStore{challenge: challenge}, nil, nil, logging.NewDefaultLogger())
+
+	standings, err := svc.GetStandings(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("GetStandings: %v", err)
+	}
+	if standings.Final || standings.Challenge.FinalizedAt != nil {
+		t.Errorf("Final = %v, FinalizedAt = %v, want live standings while the store has not finalized",
+			standings.Final, standings.Challenge.FinalizedAt)
+	}
+	if len(standings.Standings) != 2 || standings.Standings[0].Participant.Name != "bravo" {
+		t.Errorf("standings = %+v, want live standings led by bravo", standings.Standings)
+	}
+}
+
+func TestGetStandingsFinalizedConcurrently(t *testing.T) {
+	ended := time.Now().Add(-time.Minute)
+	s := &finalizeStore{
+		challenge:  &store.Challenge{ID: 1, ScoringRule: store.ChallengeRuleMaxReps, EndsAt: ended},
+		results:    []*store.ChallengeStanding{{Participant: participants("alpha")[0], Rank: 1}},
+		concurrent: true,
+	}
+	svc := NewService(s, nil, nil, logging.NewDefaultLogger())
+
+	standings, err := svc.GetStandings(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("GetStandings: %v", err)
+	}
+	if !standings.Final || standings.Challenge.FinalizedAt == nil || len(standings.Standings) != 1 {
+		t.Errorf("Final = %v with %d standings, want the frozen results", standings.Final, len(standings.Standings))
+	}
+}
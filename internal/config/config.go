@@ -53,6 +53,9 @@ type Config struct {
 	// Database operation timeout (default 3 seconds)
 	DBTimeout time.Duration `envconfig:"DB_TIMEOUT" default:"3s"`
 
+	// Users allowed to access admin endpoints (comma-separated user IDs)
+	AdminUserIDs []int32 `envconfig:"ADMIN_USER_IDS"`
+
 	// OAuth Configuration
 	GoogleOAuth GoogleOAuthConfig
 	AppleOAuth  AppleOAuthConfig
@@ -84,7 +84,8 @@ func (s *service) LogExercise(ctx context.Context, userID int32, reqData *LogExe
 		return nil, fmt.Errorf("missing required performance metric (duration, reps, or distance) for exercise type %s", exerciseDef.Type)
 	}
 
-	calculatedGrade, err := grading.CalculateScore(exerciseDef.Type, performanceValue)
+	// Exercise logs are graded against the default tables; workouts apply the user's normed profile
+	calculatedGrade, err := grading.CalculateScore(exerciseDef.Type, performanceValue, grading.Profile{})
 	if err != nil {
 		s.logger.Error(ctx, "Failed to calculate grade", "userID", userID, "exerciseType", exerciseDef.Type, "error", err)
 		return nil, fmt.Errorf("failed to calculate exercise grade: %w", err)
@@ -0,0 +1,245 @@
+package feed
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"ptchampion/internal/logging"
+	"ptchampion/internal/store"
+)
+
+// ErrInvalidCursor is returned for a feed cursor that was not issued by GetFeed.
+var ErrInvalidCursor = errors.New("invalid feed cursor")
+
+// ErrInvalidReaction is returned for a reaction that is not one of the store.Reaction* constants.
+var ErrInvalidReaction = errors.New("invalid reaction")
+
+// ErrInvalidComment is returned for a comment that is empty or too long.
+var ErrInvalidComment = errors.New("comment must be between 1 and 500 characters")
+
+// ErrPermissionDenied is returned when a user deletes a comment that is neither theirs
+// nor on their event.
+var ErrPermissionDenied = errors.New("user does not have permission to delete this comment")
+
+// maxCommentLength is the longest comment allowed, in characters.
+const maxCommentLength = 500
+
+// Page is a page of a user's feed.
+type Page struct {
+	Events     []*store.ActivityEvent
+	NextCursor string // Opaque cursor for the next page; empty on the last page
+}
+
+// Service defines the interface for activity feed business logic.
+type Service interface {
+	// GetFeed returns the user's feed, newest first, from the cursor of the previous page
+	// (empty for the first page).
+	GetFeed(ctx context.Context, userID int32, cursor string, limit int) (*Page, error)
+	// React sets the user's reaction to an event they can see, replacing any earlier one.
+	React(ctx context.Context, userID int32, eventID int32, reaction string) (*store.ActivityEvent, error)
+	Unreact(ctx context.Context, userID int32, eventID int32) (*store.ActivityEvent, error)
+	AddComment(ctx context.Context, userID int32, eventID int32, body string) (*store.ActivityComment, error)
+	// DeleteComment removes a comment. Comments can be deleted by their author and by the
+	// user whose event they are on.
+	DeleteComment(ctx context.Context, userID int32, eventID int32, commentID int32) error
+	ListComments(ctx context.Context, userID int32, eventID int32, page, pageSize int) (*store.PaginatedActivityComments, error)
+}
+
+type service struct {
+	feedStore store.FeedStore
+	logger    logging.Logger
+}
+
+// NewService creates a new feed service instance.
+func NewService(feedStore store.FeedStore, logger logging.Logger) Service {
+	return &service{
+		feedStore: feedStore,
+		logger:    logger,
+	}
+}
+
+// encodeCursor returns the cursor for the page of events before eventID.
+func encodeCursor(eventID int32) string {
+	if eventID == 0 {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.Itoa(int(eventID))))
+}
+
+// decodeCursor returns the event ID a cursor reads before; 0 for the empty cursor.
+func decodeCursor(cursor string) (int32, error) {
+	if cursor == "" {
+		return 0, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, ErrInvalidCursor
+	}
+	id, err := strconv.ParseInt(string(raw), 10, 32)
+	if err != nil || id <= 0 {
+		return 0, ErrInvalidCursor
+	}
+	return int32(id), nil
+}
+
+// validReaction reports whether reaction is one of the store.Reaction* constants.
+func validReaction(reaction string) bool {
+	switch reaction {
+	case store.ReactionLike, store.ReactionFire, store.ReactionStrong, store.ReactionSalute:
+		return true
+	}
+	return false
+}
+
+// commentBody trims a comment and checks its length.
+func commentBody(body string) (string, error) {
+	body = strings.TrimSpace(body)
+	if body == "" || utf8.RuneCountInString(body) > maxCommentLength {
+		return "", ErrInvalidComment
+	}
+	return body, nil
+}
+
+// GetFeed implements Service.
+func (s *service) GetFeed(ctx context.Context, userID int32, cursor string, limit int) (*Page, error) {
+	before, err := decodeCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+	if limit < 1 || limit > 100 { // Max page size constraint
+		limit = 20 // Default page size
+	}
+
+	feed, err := s.feedStore.GetFeed(ctx, userID, before, int32(limit))
+	if err != nil {
+		s.logger.Error(ctx, "Failed to get feed", "userID", userID, "error", err)
+		return nil, fmt.Errorf("failed to retrieve feed: %w", err)
+	}
+	return &Page{
+		Events:     feed.Events,
+		NextCursor: encodeCursor(feed.NextCursor),
+	}, nil
+}
+
+// React implements Service.
+func (s *service) React(ctx context.Context, userID int32, eventID int32, reaction string) (*store.ActivityEvent, error) {
+	if !validReaction(reaction) {
+		return nil, ErrInvalidReaction
+	}
+	if _, err := s.getEvent(ctx, userID, eventID); err != nil {
+		return nil, err
+	}
+
+	if err := s.feedStore.SetActivityReaction(ctx, eventID, userID, reaction); err != nil {
+		s.logger.Error(ctx, "Failed to set reaction", "userID", userID, "eventID", eventID, "error", err)
+		return nil, fmt.Errorf("failed to react to activity: %w", err)
+	}
+	return s.getEvent(ctx, userID, eventID)
+}
+
+// Unreact implements Service.
+func (s *service) Unreact(ctx context.Context, userID int32, eventID int32) (*store.ActivityEvent, error) {
+	if _, err := s.getEvent(ctx, userID, eventID); err != nil {
+		return nil, err
+	}
+
+	if err := s.feedStore.DeleteActivityReaction(ctx, eventID, userID); err != nil {
+		s.logger.Error(ctx, "Failed to delete reaction", "userID", userID, "eventID", eventID, "error", err)
+		return nil, fmt.Errorf("failed to remove reaction: %w", err)
+	}
+	return s.getEvent(ctx, userID, eventID)
+}
+
+// AddComment implements Service.
+func (s *service) AddComment(ctx context.Context, userID int32, eventID int32, body string) (*store.ActivityComment, error) {
+	body, err := commentBody(body)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.getEvent(ctx, userID, eventID); err != nil {
+		return nil, err
+	}
+
+	comment, err := s.feedStore.CreateActivityComment(ctx, &store.ActivityComment{
+		EventID: eventID,
+		UserID:  userID,
+		Body:    body,
+	})
+	if err != nil {
+		s.logger.Error(ctx, "Failed to create comment", "userID", userID, "eventID", eventID, "error", err)
+		return nil, fmt.Errorf("failed to add comment: %w", err)
+	}
+	s.logger.Info(ctx, "Comment added", "userID", userID, "eventID", eventID, "commentID", comment.ID)
+	return comment, nil
+}
+
+// DeleteComment implements Service.
+func (s *service) DeleteComment(ctx context.Context, userID int32, eventID int32, commentID int32) error {
+	event, err := s.getEvent(ctx, userID, eventID)
+	if err != nil {
+		return err
+	}
+	comment, err := s.feedStore.GetActivityComment(ctx, eventID, commentID)
+	if err != nil {
+		if err == store.ErrActivityCommentNotFound {
+			return err
+		}
+		s.logger.Error(ctx, "Failed to get comment", "eventID", eventID, "commentID", commentID, "error", err)
+		return fmt.Errorf("failed to retrieve comment: %w", err)
+	}
+	if comment.UserID != userID && event.ActorUserID != userID {
+		s.logger.Warn(ctx, "User attempted to delete another user's comment", "userID", userID, "commentID", commentID)
+		return ErrPermissionDenied
+	}
+
+	if err := s.feedStore.DeleteActivityComment(ctx, commentID); err != nil {
+		if err == store.ErrActivityCommentNotFound {
+			return err
+		}
+		s.logger.Error(ctx, "Failed to delete comment", "userID", userID, "commentID", commentID, "error", err)
+		return fmt.Errorf("failed to delete comment: %w", err)
+	}
+	s.logger.Info(ctx, "Comment deleted", "userID", userID, "eventID", eventID, "commentID", commentID)
+	return nil
+}
+
+// ListComments implements Service.
+func (s *service) ListComments(ctx context.Context, userID int32, eventID int32, page, pageSize int) (*store.PaginatedActivityComments, error) {
+	if _, err := s.getEvent(ctx, userID, eventID); err != nil {
+		return nil, err
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 { // Max page size constraint
+		pageSize = 20 // Default page size
+	}
+	limit := int32(pageSize)
+	offset := int32((page - 1) * pageSize)
+
+	comments, err := s.feedStore.ListActivityComments(ctx, userID, eventID, limit, offset)
+	if err != nil {
+		s.logger.Error(ctx, "Failed to list comments", "userID", userID, "eventID", eventID, "error", err)
+		return nil, fmt.Errorf("failed to retrieve comments: %w", err)
+	}
+	return comments, nil
+}
+
+// getEvent returns an event the user can see.
+func (s *service) getEvent(ctx context.Context, userID int32, eventID int32) (*store.ActivityEvent, error) {
+	event, err := s.feedStore.GetActivityEvent(ctx, userID, eventID)
+	if err != nil {
+		if err == store.ErrActivityEventNotFound {
+			return nil, err
+		}
+		s.logger.Error(ctx, "Failed to get activity event", "userID", userID, "eventID", eventID, "error", err)
+		return nil, fmt.Errorf("failed to retrieve activity event: %w", err)
+	}
+	return event, nil
+}
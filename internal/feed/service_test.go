@@ -0,0 +1,87 @@
+package feed
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	if got := encodeCursor(0); got != "" {
+		t.Errorf("encodeCursor(0) = %q, want empty", got)
+	}
+
+	for _, id := range []int32{1, 42, 2147483647} {
+		got, err := decodeCursor(encodeCursor(id))
+		if err != nil {
+			t.Fatalf("decodeCursor(encodeCursor(%d)) error = %v", id, err)
+		}
+		if got != id {
+			t.Errorf("decodeCursor(encodeCursor(%d)) = %d", id, got)
+		}
+	}
+}
+
+// encodeCursorString encodes raw cursor content the way encodeCursor does.
+func encodeCursorString(raw string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func TestDecodeCursor(t *testing.T) {
+	tests := []struct {
+		name    string
+		cursor  string
+		want    int32
+		wantErr bool
+	}{
+		{name: "empty reads from the newest", cursor: "", want: 0},
+		{name: "not base64", cursor: "!!", wantErr: true},
+		{name: "not a number", cursor: encodeCursorString("abc"), wantErr: true},
+		{name: "zero", cursor: encodeCursorString("0"), wantErr: true},
+		{name: "negative", cursor: encodeCursorString("-5"), wantErr: true},
+		{name: "overflows int32", cursor: encodeCursorString("2147483648"), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodeCursor(tt.cursor)
+			if tt.wantErr {
+				if err != ErrInvalidCursor {
+					t.Errorf("decodeCursor(%q) error = %v, want ErrInvalidCursor", tt.cursor, err)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("decodeCursor(%q) = %d, %v, want %d", tt.cursor, got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestCommentBody(t *testing.T) {
+	if got, err := commentBody("  nice run  "); err != nil || got != "nice run" {
+		t.Errorf("commentBody() = %q, %v, want trimmed body", got, err)
+	}
+	if _, err := commentBody("   "); err != ErrInvalidComment {
+		t.Errorf("commentBody(blank) error = %v, want ErrInvalidComment", err)
+	}
+	if _, err := commentBody(strings.Repeat("é", maxCommentLength)); err != nil {
+		t.Errorf("commentBody(%d characters) error = %v", maxCommentLength, err)
+	}
+	if _, err := commentBody(strings.Repeat("a", maxCommentLength+1)); err != ErrInvalidComment {
+		t.Errorf("commentBody(too long) error = %v, want ErrInvalidComment", err)
+	}
+}
+
+func TestValidReaction(t *testing.T) {
+	for _, reaction := range []string{"like", "fire", "strong", "salute"} {
+		if !validReaction(reaction) {
+			t.Errorf("validReaction(%q) = false", reaction)
+		}
+	}
+	for _, reaction := range []string{"", "LIKE", "love"} {
+		if validReaction(reaction) {
+			t.Errorf("validReaction(%q) = true", reaction)
+		}
+	}
+}
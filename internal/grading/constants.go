@@ -6,6 +6,16 @@ const (
 	ExerciseTypeSitup  = "situp"
 	ExerciseTypePullup = "pullup"
 	ExerciseTypeRun    = "run"
+
+	// ACFT events; the ACFT 2-mile run uses ExerciseTypeRun
+	ExerciseTypeDeadlift          = "deadlift"
+	ExerciseTypePowerThrow        = "power_throw"
+	ExerciseTypeHandReleasePushup = "hand_release_pushup"
+	ExerciseTypeSprintDragCarry   = "sprint_drag_carry"
+	ExerciseTypePlank             = "plank"
+
+	// USMC PFT 3-mile run, stored as its own exercise type
+	ExerciseTypeRun3Mile = "run_3mile"
 )
 
 // Error Types
@@ -21,6 +31,7 @@ const (
 	ErrMissingJoint        GradingError = "required joint is missing or has low confidence"
 	ErrInvalidPose         GradingError = "invalid pose for exercise"
 	ErrPoorForm            GradingError = "poor exercise form detected"
+	ErrUnknownStandard     GradingError = "unknown scoring standard"
 )
 
 // Push-up Form Thresholds
@@ -35,8 +46,8 @@ const (
 
 // Sit-up Form Thresholds
 const (
-	SitupHipAngleDownMax      float64 = 90.0  // Max hip angle at bottom position
-	SitupHipAngleUpMin        float64 = 150.0 // Min hip angle at top position
+	SitupHipAngleDownMax      float64 = 90.0  // Max hip angle (shoulder-hip-knee) for the torso to count as raised
+	SitupHipAngleUpMin        float64 = 150.0 // Min hip angle for the shoulders to count as back on the ground
 	SitupRequiredConfidence   float64 = 0.5   // Min confidence for key joints
 	SitupTorsoRotationMaxDiff float64 = 0.15  // Max allowed torso rotation
 )
@@ -44,8 +55,11 @@ const (
 // Pull-up Form Thresholds
 const (
 	PullupChinOverBarThreshold float64 = 0.05  // Chin must be this much over the bar
-	PullupArmExtensionMax      float64 = 140.0 // Max elbow angle at bottom position
+	PullupArmExtensionMax      float64 = 140.0 // Elbow angle the arms must open to at bottom position
 	PullupRequiredConfidence   float64 = 0.5   // Min confidence for key joints
+	PullupMinPositivePhaseSec  float64 = 0.5   // Faster pulls indicate kipping
+	PullupMinNegativePhaseSec  float64 = 0.3   // Faster descents indicate dropping off the top
+	PullupElbowSymmetryMaxDiff float64 = 30.0  // Max left/right elbow angle difference
 )
 
 // Run tracking thresholds
@@ -57,3 +71,11 @@ const (
 	RunningCadenceMinSPM     float64 = 150.0 // Minimum cadence in steps per minute
 	RunningGroundContactMaxS float64 = 0.3   // Maximum ground contact time in seconds
 )
+
+// Run analysis parameters
+const (
+	RunTwoMileDistanceM  float64 = 3218.688 // Official APFT run distance (2 miles)
+	RunSplitDistanceM    float64 = 402.336  // Split length for per-split metrics (quarter mile)
+	RunEarthRadiusM      float64 = 6371000  // Mean Earth radius used for GPS distances
+	RunMinSampleInterval float64 = 0.001    // Samples closer together than this are ignored
+)
@@ -1,39 +1,14 @@
 package grading
 
-// CalculateRunScore calculates the run score with proper handling of intermediate times
-// This function addresses the issue where times between map entries weren't properly scored
+// CalculateRunScore calculates the 2-mile run score under the default standard.
+// Times are rounded to the nearest 6-second step; times between table entries
+// earn the score of the next slower time (conservative approach).
 func CalculateRunScore(seconds int) int {
-	// Round to nearest 6-second interval for lookup
-	roundedSeconds := ((seconds + 3) / 6) * 6
-
-	if score, exists := runScoreMap[roundedSeconds]; exists {
-		return score
-	}
-
-	// If exact match not found, find closest scores
-	var lowerTime, upperTime int
-	for time := range runScoreMap {
-		if time <= seconds && time > lowerTime {
-			lowerTime = time
-		}
-		if time >= seconds && (upperTime == 0 || time < upperTime) {
-			upperTime = time
-		}
-	}
-
-	// Use the score from the next slower time (conservative approach)
-	if upperTime > 0 {
-		return runScoreMap[upperTime]
-	}
-
-	// Fallback
-	if seconds < 660 {
-		return 100
-	} else if seconds > 1170 {
+	score, err := defaultStandard.Score(ExerciseTypeRun, float64(seconds), Profile{})
+	if err != nil {
 		return 0
 	}
-
-	return 0
+	return score
 }
 
 // CalculateRunScoreSeconds is an alias for CalculateRunScore for consistency
@@ -0,0 +1,27 @@
+package grading
+
+// Metric identifies the workout measurement an exercise is scored on
+type Metric string
+
+const (
+	MetricReps     Metric = "reps"             // Repetition count
+	MetricDuration Metric = "duration_seconds" // Elapsed time; lower is better except for planks
+	MetricWeight   Metric = "weight_lbs"       // Weight lifted in pounds
+	MetricDistance Metric = "distance_meters"  // Distance thrown in meters
+)
+
+// MetricFor returns the measurement used to score an exercise type.
+func MetricFor(exerciseType string) (Metric, error) {
+	switch exerciseType {
+	case ExerciseTypePushup, ExerciseTypeSitup, ExerciseTypePullup, ExerciseTypeHandReleasePushup:
+		return MetricReps, nil
+	case ExerciseTypeRun, ExerciseTypeRun3Mile, ExerciseTypeSprintDragCarry, ExerciseTypePlank:
+		return MetricDuration, nil
+	case ExerciseTypeDeadlift:
+		return MetricWeight, nil
+	case ExerciseTypePowerThrow:
+		return MetricDistance, nil
+	default:
+		return "", ErrUnknownExerciseType
+	}
+}
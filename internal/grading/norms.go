@@ -0,0 +1,50 @@
+package grading
+
+import "time"
+
+// Gender values used to select normed scoring tables
+const (
+	GenderMale   = "male"
+	GenderFemale = "female"
+)
+
+// MinScoringAge is the youngest age covered by the normed scoring tables.
+const MinScoringAge = 17
+
+// Profile identifies the scoring standard that applies to an athlete.
+// The zero value selects the default (non-normed) tables.
+type Profile struct {
+	Gender string // GenderMale or GenderFemale; empty when unknown
+	Age    int    // Age in whole years; zero when unknown
+}
+
+// NewProfile builds a scoring profile from a gender and date of birth,
+// computing the age as of the given time. A zero date of birth leaves the age unknown.
+func NewProfile(gender string, dateOfBirth time.Time, at time.Time) Profile {
+	profile := Profile{Gender: gender}
+	if dateOfBirth.IsZero() {
+		return profile
+	}
+
+	age := at.Year() - dateOfBirth.Year()
+	// Adjust if birthday hasn't occurred yet this year
+	if at.Month() < dateOfBirth.Month() || (at.Month() == dateOfBirth.Month() && at.Day() < dateOfBirth.Day()) {
+		age--
+	}
+	profile.Age = age
+	return profile
+}
+
+// Normed reports whether the profile has enough information to select a normed table.
+func (p Profile) Normed() bool {
+	return (p.Gender == GenderMale || p.Gender == GenderFemale) && p.Age >= MinScoringAge
+}
+
+// AgeBracket returns the label of the default standard's age bracket for the
+// profile (e.g. "22-26", "62+").
+func (p Profile) AgeBracket() string {
+	if p.Age < MinScoringAge {
+		return ""
+	}
+	return defaultStandard.ageBracketLabel(p.Age)
+}
@@ -0,0 +1,65 @@
+package grading
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCalculateScoreWithProfile(t *testing.T) {
+	male19 := Profile{Gender: GenderMale, Age: 19}
+	female24 := Profile{Gender: GenderFemale, Age: 24}
+	male45 := Profile{Gender: GenderMale, Age: 45}
+
+	tests := []struct {
+		name         string
+		exerciseType string
+		value        float64
+		profile      Profile
+		want         int
+	}{
+		{"default table without profile", ExerciseTypePushup, 46, Profile{}, 68},
+		{"female max pushups", ExerciseTypePushup, 46, female24, 100},
+		{"female minimum pass", ExerciseTypePushup, 17, female24, 60},
+		{"male minimum pass", ExerciseTypePushup, 42, male19, 60},
+		{"above max clamps to 100", ExerciseTypePushup, 90, male19, 100},
+		{"zero reps", ExerciseTypeSitup, 0, male19, 0},
+		{"older run max", ExerciseTypeRun, 846, male45, 100},
+		{"older run pass", ExerciseTypeRun, 1122, male45, 60},
+		{"slow run clamps to 0", ExerciseTypeRun, 3000, female24, 0},
+		{"age unknown falls back to default", ExerciseTypePushup, 46, Profile{Gender: GenderFemale}, 68},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CalculateScore(tt.exerciseType, tt.value, tt.profile)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("score = %d, want %d", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := CalculateScore("plank", 10, male19); err != ErrUnknownExerciseType {
+		t.Errorf("err = %v, want ErrUnknownExerciseType", err)
+	}
+}
+
+func TestNewProfile(t *testing.T) {
+	at := time.Date(2025, 6, 15, 0, 0, 0, 0, time.UTC)
+
+	p := NewProfile(GenderFemale, time.Date(2000, 6, 16, 0, 0, 0, 0, time.UTC), at)
+	if p.Age != 24 || p.AgeBracket() != "22-26" {
+		t.Errorf("profile = %+v (%s), want age 24 in 22-26", p, p.AgeBracket())
+	}
+
+	p = NewProfile(GenderMale, time.Date(1960, 1, 1, 0, 0, 0, 0, time.UTC), at)
+	if p.AgeBracket() != "62+" {
+		t.Errorf("bracket = %s, want 62+", p.AgeBracket())
+	}
+
+	if NewProfile(GenderMale, time.Time{}, at).Normed() {
+		t.Error("profile without date of birth should not be normed")
+	}
+}
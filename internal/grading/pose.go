@@ -13,7 +13,8 @@ type Joint struct {
 
 // Pose represents a detected human pose with joint locations.
 type Pose struct {
-	Joints map[string]Joint
+	Joints    map[string]Joint
+	Timestamp float64 // Capture time in seconds; zero when unknown
 }
 
 // GradingResult represents the outcome of grading a single exercise pose.
@@ -36,6 +37,7 @@ type ExerciseState struct {
 	FormIssues        []string
 	PositivePhaseTime float64 // For timing concentric phase (push/pull up)
 	NegativePhaseTime float64 // For timing eccentric phase (lowering)
+	PhaseStartTime    float64 // Timestamp at which the current timed phase began
 }
 
 // NewPose creates a new pose with initialized joint map.
@@ -0,0 +1,178 @@
+package grading
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Pull-up state constants
+const (
+	PullupStateUp      = "up"   // Chin over the bar
+	PullupStateDown    = "down" // Dead hang, arms extended
+	PullupStateInvalid = "invalid"
+	PullupStateBetween = "between"
+)
+
+// Required joints for pull-up analysis
+var PullupRequiredJoints = []string{
+	"nose",
+	"leftShoulder", "rightShoulder",
+	"leftElbow", "rightElbow",
+	"leftWrist", "rightWrist",
+}
+
+// GradePullup analyzes a pose for pull-up form and counts repetitions.
+// The bar line is inferred from the wrist positions; a rep is counted when the
+// chin clears the bar after starting from a dead hang. When the pose carries a
+// timestamp, phase durations are recorded in the state and overly fast pulls
+// are rejected as kipping.
+func GradePullup(pose *Pose, state *ExerciseState) (*GradingResult, error) {
+	if pose == nil || state == nil {
+		return nil, ErrInvalidInput
+	}
+
+	result := &GradingResult{
+		IsValid:    false,
+		RepCounted: false,
+		FormScore:  0.0,
+		Feedback:   "",
+		State:      state.CurrentPhase,
+	}
+
+	// Verify required joints have sufficient confidence
+	hasRequiredJoints, missingJoints := VerifyJointConfidence(pose, PullupRequiredJoints, PullupRequiredConfidence)
+	if !hasRequiredJoints {
+		result.Feedback = fmt.Sprintf("Cannot see clearly: %s", strings.Join(missingJoints, ", "))
+		state.CurrentPhase = PullupStateInvalid
+		result.State = PullupStateInvalid
+		return result, nil
+	}
+
+	// Extract validated points
+	nose := pose.Joints["nose"]
+	leftShoulder := pose.Joints["leftShoulder"]
+	rightShoulder := pose.Joints["rightShoulder"]
+	leftElbow := pose.Joints["leftElbow"]
+	rightElbow := pose.Joints["rightElbow"]
+	leftWrist := pose.Joints["leftWrist"]
+	rightWrist := pose.Joints["rightWrist"]
+
+	// 1. Calculate Key Angles & Positions
+	leftElbowAngle := CalculateAngle(leftWrist, leftElbow, leftShoulder)
+	rightElbowAngle := CalculateAngle(rightWrist, rightElbow, rightShoulder)
+	avgElbowAngle := (leftElbowAngle + rightElbowAngle) / 2.0
+
+	// The hands sit on the bar, so the bar line runs through both wrists.
+	// Evaluate it at the nose X to tolerate a tilted camera.
+	barYAtNose := InterpolateY(leftWrist.X, leftWrist.Y, rightWrist.X, rightWrist.Y, nose.X)
+	// Positive clearance means the nose is above the bar (image Y grows downward)
+	chinClearance := barYAtNose - nose.Y
+
+	// Form issues are tracked per frame
+	state.FormIssues = []string{}
+
+	// 2. State Machine Logic
+	previousState := state.CurrentPhase
+
+	// Determine potential next state based on chin position and arm extension
+	var potentialState string
+	if chinClearance >= PullupChinOverBarThreshold {
+		potentialState = PullupStateUp
+	} else if avgElbowAngle >= PullupArmExtensionMax {
+		potentialState = PullupStateDown
+	} else {
+		potentialState = PullupStateBetween
+	}
+
+	// Update current state
+	state.CurrentPhase = potentialState
+	timed := pose.Timestamp > 0
+
+	// Track minimum elbow angle during the rep
+	state.MinElbowAngle = min(state.MinElbowAngle, avgElbowAngle)
+
+	// Start the phase timers when leaving the bottom or the top
+	if previousState == PullupStateDown && state.CurrentPhase != PullupStateDown {
+		state.PhaseStartTime = pose.Timestamp
+	}
+	if previousState == PullupStateUp && state.CurrentPhase != PullupStateUp {
+		state.PhaseStartTime = pose.Timestamp
+	}
+
+	// PreviousPhase remembers the last end position (up or down) reached, which
+	// tells whether the athlete started this pull from a full hang.
+	if state.CurrentPhase == PullupStateUp && previousState != PullupStateUp {
+		if timed && state.PhaseStartTime > 0 {
+			state.PositivePhaseTime = pose.Timestamp - state.PhaseStartTime
+		}
+
+		switch {
+		case state.PreviousPhase != PullupStateDown:
+			// Didn't extend fully before this pull
+			result.Feedback = "Extend arms fully at the bottom"
+		case timed && state.PositivePhaseTime > 0 && state.PositivePhaseTime < PullupMinPositivePhaseSec:
+			state.FormIssues = append(state.FormIssues, "Kipping detected")
+			result.Feedback = "Avoid kipping, pull with control"
+		default:
+			state.RepCount++
+			result.RepCounted = true
+			result.Feedback = fmt.Sprintf("Rep Counted! (%d)", state.RepCount)
+		}
+
+		state.PreviousPhase = PullupStateUp
+		state.MinElbowAngle = 180.0
+	}
+
+	if state.CurrentPhase == PullupStateDown && previousState != PullupStateDown {
+		if timed && state.PhaseStartTime > 0 && state.PreviousPhase == PullupStateUp {
+			state.NegativePhaseTime = pose.Timestamp - state.PhaseStartTime
+			if state.NegativePhaseTime < PullupMinNegativePhaseSec {
+				result.Feedback = "Control the descent"
+			}
+		}
+
+		if state.PreviousPhase == PullupStateDown {
+			// Left the hang and came back without clearing the bar
+			state.FormIssues = append(state.FormIssues, "Half rep")
+			result.Feedback = "Pull chin over the bar for rep to count"
+		}
+
+		state.PreviousPhase = PullupStateDown
+		state.WentLowEnough = true
+	}
+
+	// 3. Provide general feedback based on state if no specific message already set
+	if result.Feedback == "" {
+		switch state.CurrentPhase {
+		case PullupStateUp:
+			result.Feedback = "Lower to full extension"
+		case PullupStateDown:
+			result.Feedback = "Pull up"
+		case PullupStateBetween:
+			result.Feedback = "Keep moving"
+		case PullupStateInvalid:
+			result.Feedback = "Fix pose"
+		}
+	}
+
+	// Calculate form score based on arm symmetry and pull tempo
+	formScore := 1.0
+	elbowDiff := abs(leftElbowAngle - rightElbowAngle)
+	if elbowDiff > 0 {
+		// Reduce score based on uneven pulling (0 to 0.5 reduction)
+		symmetryPenalty := min(0.5, elbowDiff/PullupElbowSymmetryMaxDiff*0.5)
+		formScore -= symmetryPenalty
+	}
+
+	if timed && state.PositivePhaseTime > 0 && state.PositivePhaseTime < PullupMinPositivePhaseSec {
+		// Reduce score for an explosive, kipping pull (0 to 0.5 reduction)
+		kipPenalty := min(0.5, (PullupMinPositivePhaseSec-state.PositivePhaseTime)/PullupMinPositivePhaseSec*0.5)
+		formScore -= kipPenalty
+	}
+
+	result.IsValid = true
+	result.State = state.CurrentPhase
+	result.FormScore = max(0, formScore) // Ensure non-negative
+
+	return result, nil
+}
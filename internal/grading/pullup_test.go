@@ -0,0 +1,168 @@
+package grading
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+// pullupFrame describes one synthetic pull-up frame.
+type pullupFrame struct {
+	elbowAngle float64 // Elbow angle in degrees for both arms
+	clearance  float64 // Height of the nose above the bar line
+	ts         float64 // Capture time in seconds
+}
+
+// pullupPose builds a front-view pull-up pose hanging from a bar at y=0.2.
+func pullupPose(f pullupFrame) *Pose {
+	const barY = 0.2
+	theta := f.elbowAngle * math.Pi / 180
+
+	pose := NewPose()
+	pose.Timestamp = f.ts
+	for _, side := range []struct {
+		prefix string
+		x, dir float64
+	}{{"left", 0.4, -1}, {"right", 0.6, 1}} {
+		wrist := Joint{X: side.x, Y: barY, Confidence: 0.9}
+		// Forearm hangs straight down from the wrist
+		elbow := Joint{X: side.x, Y: barY + 0.15, Confidence: 0.9}
+		// Upper arm opens from the forearm by the elbow angle, mirrored per side
+		shoulder := Joint{
+			X:          elbow.X - side.dir*0.15*math.Sin(theta),
+			Y:          elbow.Y - 0.15*math.Cos(theta),
+			Confidence: 0.9,
+		}
+		pose.Joints[side.prefix+"Wrist"] = wrist
+		pose.Joints[side.prefix+"Elbow"] = elbow
+		pose.Joints[side.prefix+"Shoulder"] = shoulder
+	}
+	pose.Joints["nose"] = Joint{X: 0.5, Y: barY - f.clearance, Confidence: 0.9}
+	return pose
+}
+
+func TestGradePullup(t *testing.T) {
+	tests := []struct {
+		name       string
+		frames     []pullupFrame
+		wantReps   int
+		wantState  string
+		wantInFeed string
+		wantIssue  string
+	}{
+		{
+			name: "single controlled rep",
+			frames: []pullupFrame{
+				{170, -0.3, 0.1}, {120, -0.15, 0.5}, {60, 0.08, 1.2}, {120, -0.15, 1.8}, {170, -0.3, 2.4},
+			},
+			wantReps:  1,
+			wantState: PullupStateDown,
+		},
+		{
+			name: "two reps without timestamps",
+			frames: []pullupFrame{
+				{170, -0.3, 0}, {60, 0.08, 0}, {170, -0.3, 0}, {60, 0.08, 0}, {170, -0.3, 0},
+			},
+			wantReps:  2,
+			wantState: PullupStateDown,
+		},
+		{
+			name: "kipping pull rejected",
+			frames: []pullupFrame{
+				{170, -0.3, 0.1}, {120, -0.15, 0.2}, {60, 0.08, 0.3},
+			},
+			wantReps:   0,
+			wantState:  PullupStateUp,
+			wantInFeed: "kipping",
+			wantIssue:  "Kipping detected",
+		},
+		{
+			name: "chin never clears the bar",
+			frames: []pullupFrame{
+				{170, -0.3, 0.1}, {90, -0.05, 0.8}, {170, -0.3, 1.6},
+			},
+			wantReps:   0,
+			wantState:  PullupStateDown,
+			wantInFeed: "Pull chin over the bar",
+			wantIssue:  "Half rep",
+		},
+		{
+			name: "no full extension between reps",
+			frames: []pullupFrame{
+				{170, -0.3, 0.1}, {60, 0.08, 1.0}, {110, -0.1, 1.6}, {60, 0.08, 2.4},
+			},
+			wantReps:   1,
+			wantState:  PullupStateUp,
+			wantInFeed: "Extend arms fully",
+		},
+		{
+			name: "fast descent flagged",
+			frames: []pullupFrame{
+				{170, -0.3, 0.1}, {60, 0.08, 1.0}, {120, -0.15, 1.1}, {170, -0.3, 1.2},
+			},
+			wantReps:   1,
+			wantState:  PullupStateDown,
+			wantInFeed: "Control the descent",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := NewExerciseState()
+			var result *GradingResult
+			var err error
+			for _, f := range tt.frames {
+				result, err = GradePullup(pullupPose(f), state)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			}
+
+			if state.RepCount != tt.wantReps {
+				t.Errorf("rep count = %d, want %d", state.RepCount, tt.wantReps)
+			}
+			if result.State != tt.wantState {
+				t.Errorf("state = %q, want %q", result.State, tt.wantState)
+			}
+			if !result.IsValid {
+				t.Errorf("expected a valid result, got %+v", result)
+			}
+			if tt.wantInFeed != "" && !strings.Contains(result.Feedback, tt.wantInFeed) {
+				t.Errorf("feedback = %q, want it to contain %q", result.Feedback, tt.wantInFeed)
+			}
+			if tt.wantIssue != "" && !strings.Contains(strings.Join(state.FormIssues, ","), tt.wantIssue) {
+				t.Errorf("form issues = %v, want %q", state.FormIssues, tt.wantIssue)
+			}
+		})
+	}
+}
+
+func TestGradePullupPhaseTiming(t *testing.T) {
+	state := NewExerciseState()
+	frames := []pullupFrame{{170, -0.3, 0.1}, {120, -0.15, 0.5}, {60, 0.08, 1.5}, {120, -0.15, 2.0}, {170, -0.3, 3.5}}
+	for _, f := range frames {
+		if _, err := GradePullup(pullupPose(f), state); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if math.Abs(state.PositivePhaseTime-1.0) > 1e-9 {
+		t.Errorf("positive phase = %f, want 1.0", state.PositivePhaseTime)
+	}
+	if math.Abs(state.NegativePhaseTime-1.5) > 1e-9 {
+		t.Errorf("negative phase = %f, want 1.5", state.NegativePhaseTime)
+	}
+}
+
+func TestGradePullupMissingJoints(t *testing.T) {
+	pose := pullupPose(pullupFrame{170, -0.3, 0})
+	delete(pose.Joints, "nose")
+
+	result, err := GradePullup(pose, NewExerciseState())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.IsValid || result.State != PullupStateInvalid {
+		t.Errorf("expected invalid result for missing joints, got %+v", result)
+	}
+}
@@ -0,0 +1,128 @@
+package grading
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+)
+
+// MaxReplayFrames caps the number of frames accepted for a single session replay
+// (20 minutes of capture at 30 frames per second).
+const MaxReplayFrames = 36000
+
+// maxFrameLineBytes bounds the size of a single encoded frame in a frame stream.
+const maxFrameLineBytes = 64 * 1024
+
+// GraderFunc grades a single pose and advances the exercise state.
+type GraderFunc func(pose *Pose, state *ExerciseState) (*GradingResult, error)
+
+// ReplayResult summarizes a full session replayed through a grader.
+type ReplayResult struct {
+	RepCount    int     // Reps counted by the state machine
+	FormScore   float64 // Mean form score over valid frames (0-1.0)
+	FrameCount  int     // Total frames replayed
+	ValidFrames int     // Frames the grader accepted as valid
+}
+
+// GraderFor returns the pose grader for the given exercise type.
+// Only rep-based exercises can be graded from pose frames.
+func GraderFor(exerciseType string) (GraderFunc, error) {
+	switch exerciseType {
+	case ExerciseTypePushup:
+		return GradePushup, nil
+	case ExerciseTypeSitup:
+		return GradeSitup, nil
+	case ExerciseTypePullup:
+		return GradePullup, nil
+	default:
+		return nil, ErrUnknownExerciseType
+	}
+}
+
+// ReplayPoses runs a recorded sequence of poses through the grader for the exercise
+// type, using a fresh state, and returns the resulting rep count and form score.
+func ReplayPoses(exerciseType string, poses []*Pose) (*ReplayResult, error) {
+	grader, err := GraderFor(exerciseType)
+	if err != nil {
+		return nil, err
+	}
+	if len(poses) == 0 || len(poses) > MaxReplayFrames {
+		return nil, ErrInvalidInput
+	}
+
+	state := NewExerciseState()
+	replay := &ReplayResult{}
+	formTotal := 0.0
+
+	for _, pose := range poses {
+		result, err := grader(pose, state)
+		if err != nil {
+			return nil, err
+		}
+		replay.FrameCount++
+		if result.IsValid {
+			replay.ValidFrames++
+			formTotal += result.FormScore
+		}
+	}
+
+	replay.RepCount = state.RepCount
+	if replay.ValidFrames > 0 {
+		replay.FormScore = formTotal / float64(replay.ValidFrames)
+	}
+	return replay, nil
+}
+
+// poseFrame is the wire format of a single keypoint frame, matching the
+// pose JSON consumed by the WASM grading module.
+type poseFrame struct {
+	Timestamp float64 `json:"timestamp"`
+	Keypoints []struct {
+		Name       string  `json:"name"`
+		X          float64 `json:"x"`
+		Y          float64 `json:"y"`
+		Confidence float64 `json:"confidence"`
+	} `json:"keypoints"`
+}
+
+// DecodePoseFrames reads a newline-delimited JSON stream of keypoint frames.
+// Blank lines are skipped; frames must carry finite coordinates.
+func DecodePoseFrames(r io.Reader) ([]*Pose, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 4096), maxFrameLineBytes)
+
+	poses := make([]*Pose, 0)
+	line := 0
+	for scanner.Scan() {
+		line++
+		raw := scanner.Bytes()
+		if len(raw) == 0 {
+			continue
+		}
+		if len(poses) >= MaxReplayFrames {
+			return nil, fmt.Errorf("frame stream exceeds %d frames: %w", MaxReplayFrames, ErrInvalidInput)
+		}
+
+		var frame poseFrame
+		if err := json.Unmarshal(raw, &frame); err != nil {
+			return nil, fmt.Errorf("invalid frame on line %d: %w", line, err)
+		}
+
+		pose := NewPose()
+		pose.Timestamp = frame.Timestamp
+		for _, kp := range frame.Keypoints {
+			if math.IsNaN(kp.X) || math.IsNaN(kp.Y) || math.IsInf(kp.X, 0) || math.IsInf(kp.Y, 0) {
+				return nil, fmt.Errorf("invalid keypoint %q on line %d: %w", kp.Name, line, ErrInvalidInput)
+			}
+			pose.Joints[kp.Name] = Joint{X: kp.X, Y: kp.Y, Confidence: kp.Confidence}
+		}
+		poses = append(poses, pose)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read frame stream: %w", err)
+	}
+
+	return poses, nil
+}
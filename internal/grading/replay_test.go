@@ -0,0 +1,69 @@
+package grading
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// encodeFrames renders poses in the newline-delimited keypoint frame format.
+func encodeFrames(t *testing.T, poses []*Pose) string {
+	t.Helper()
+	var b strings.Builder
+	for _, pose := range poses {
+		frame := poseFrame{Timestamp: pose.Timestamp}
+		for name, j := range pose.Joints {
+			frame.Keypoints = append(frame.Keypoints, struct {
+				Name       string  `json:"name"`
+				X          float64 `json:"x"`
+				Y          float64 `json:"y"`
+				Confidence float64 `json:"confidence"`
+			}{name, j.X, j.Y, j.Confidence})
+		}
+		line, err := json.Marshal(frame)
+		if err != nil {
+			t.Fatalf("marshal frame: %v", err)
+		}
+		b.Write(line)
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+func TestReplayPosesFromFrameStream(t *testing.T) {
+	var poses []*Pose
+	for _, angle := range []float64{160, 80, 160, 85, 160} {
+		poses = append(poses, situpPose(angle, 0))
+	}
+
+	decoded, err := DecodePoseFrames(strings.NewReader(encodeFrames(t, poses)))
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(decoded) != len(poses) {
+		t.Fatalf("decoded %d frames, want %d", len(decoded), len(poses))
+	}
+
+	result, err := ReplayPoses(ExerciseTypeSitup, decoded)
+	if err != nil {
+		t.Fatalf("replay: %v", err)
+	}
+	if result.RepCount != 2 {
+		t.Errorf("rep count = %d, want 2", result.RepCount)
+	}
+	if result.ValidFrames != len(poses) || result.FormScore < 0.99 {
+		t.Errorf("unexpected replay result %+v", result)
+	}
+}
+
+func TestReplayPosesRejectsUnsupportedInput(t *testing.T) {
+	if _, err := ReplayPoses(ExerciseTypeRun, []*Pose{NewPose()}); err != ErrUnknownExerciseType {
+		t.Errorf("err = %v, want ErrUnknownExerciseType", err)
+	}
+	if _, err := ReplayPoses(ExerciseTypePushup, nil); err != ErrInvalidInput {
+		t.Errorf("err = %v, want ErrInvalidInput", err)
+	}
+	if _, err := DecodePoseFrames(strings.NewReader("{not json}\n")); err == nil {
+		t.Error("expected error for malformed frame")
+	}
+}
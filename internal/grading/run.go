@@ -0,0 +1,199 @@
+package grading
+
+import (
+	"math"
+)
+
+// RunSample is a single GPS/accelerometer reading captured during a run.
+type RunSample struct {
+	Timestamp           float64 // Seconds since the start of the run
+	Latitude            float64 // Degrees
+	Longitude           float64 // Degrees
+	StepCount           int     // Cumulative step count from the accelerometer; zero when unavailable
+	VerticalOscillation float64 // Meters; zero when unavailable
+	GroundContactTime   float64 // Seconds; zero when unavailable
+}
+
+// RunSplit holds metrics for one fixed-distance split of a run.
+type RunSplit struct {
+	Index          int     // Zero-based split number
+	DistanceMeters float64 // Distance covered in the split (the last split may be partial)
+	DurationSec    float64 // Time taken for the split
+	PaceSecPerMile float64 // Average pace over the split
+	CadenceSPM     float64 // Steps per minute; zero without step data
+	StrideLengthM  float64 // Meters per step; zero without step data
+	Walking        bool    // Average speed fell below RunningMinSpeedMPS
+}
+
+// RunSegment is a contiguous stretch of the run between two timestamps.
+type RunSegment struct {
+	StartSec       float64
+	EndSec         float64
+	DistanceMeters float64
+}
+
+// RunAnalysis is the result of analyzing a run's sample series.
+type RunAnalysis struct {
+	TotalDistanceMeters float64
+	TotalDurationSec    float64
+	Splits              []RunSplit
+	WalkingSegments     []RunSegment
+	AvgCadenceSPM       float64 // Zero without step data
+	AvgStrideLengthM    float64 // Zero without step data
+	Completed           bool    // Whether the official 2-mile distance was covered
+	TwoMileTimeSec      int     // Time at which 2 miles were reached; zero if not completed
+	Score               int     // Points from CalculateRunScore; zero if not completed
+	FormIssues          []string
+}
+
+// AnalyzeRun derives pace, cadence and stride metrics from a run's samples.
+// Samples must be ordered by timestamp. The official 2-mile time is
+// interpolated between the samples that straddle the distance and scored
+// with CalculateRunScore.
+func AnalyzeRun(samples []RunSample) (*RunAnalysis, error) {
+	if len(samples) < 2 {
+		return nil, ErrInvalidInput
+	}
+
+	analysis := &RunAnalysis{
+		Splits:          make([]RunSplit, 0),
+		WalkingSegments: make([]RunSegment, 0),
+		FormIssues:      make([]string, 0),
+	}
+
+	hasSteps := samples[len(samples)-1].StepCount > samples[0].StepCount
+	splitStart := samples[0]
+	splitDistance := 0.0
+	var walking *RunSegment
+	var oscSum, contactSum float64
+	var oscCount, contactCount int
+
+	prev := samples[0]
+	for _, cur := range samples[1:] {
+		dt := cur.Timestamp - prev.Timestamp
+		if dt < 0 {
+			return nil, ErrInvalidInput
+		}
+		if dt < RunMinSampleInterval {
+			continue
+		}
+
+		d := HaversineDistance(prev.Latitude, prev.Longitude, cur.Latitude, cur.Longitude)
+
+		// Interpolate the exact moment the official distance was reached
+		if !analysis.Completed && analysis.TotalDistanceMeters+d >= RunTwoMileDistanceM {
+			frac := 1.0
+			if d > 0 {
+				frac = (RunTwoMileDistanceM - analysis.TotalDistanceMeters) / d
+			}
+			finish := prev.Timestamp - samples[0].Timestamp + frac*dt
+			analysis.Completed = true
+			analysis.TwoMileTimeSec = int(math.Round(finish))
+		}
+		analysis.TotalDistanceMeters += d
+
+		// Group consecutive slow intervals into walking segments
+		if d/dt < RunningMinSpeedMPS {
+			if walking == nil {
+				walking = &RunSegment{StartSec: prev.Timestamp}
+			}
+			walking.EndSec = cur.Timestamp
+			walking.DistanceMeters += d
+		} else if walking != nil {
+			analysis.WalkingSegments = append(analysis.WalkingSegments, *walking)
+			walking = nil
+		}
+
+		if cur.VerticalOscillation > 0 {
+			oscSum += cur.VerticalOscillation
+			oscCount++
+		}
+		if cur.GroundContactTime > 0 {
+			contactSum += cur.GroundContactTime
+			contactCount++
+		}
+
+		// Close the split once it reaches the split distance
+		splitDistance += d
+		if splitDistance >= RunSplitDistanceM {
+			analysis.Splits = append(analysis.Splits, newRunSplit(len(analysis.Splits), splitStart, cur, splitDistance, hasSteps))
+			splitStart = cur
+			splitDistance = 0
+		}
+		prev = cur
+	}
+
+	if walking != nil {
+		analysis.WalkingSegments = append(analysis.WalkingSegments, *walking)
+	}
+
+	last := samples[len(samples)-1]
+	if splitDistance > 0 {
+		analysis.Splits = append(analysis.Splits, newRunSplit(len(analysis.Splits), splitStart, last, splitDistance, hasSteps))
+	}
+
+	analysis.TotalDurationSec = last.Timestamp - samples[0].Timestamp
+	if hasSteps && analysis.TotalDurationSec > 0 {
+		steps := float64(last.StepCount - samples[0].StepCount)
+		analysis.AvgCadenceSPM = steps / analysis.TotalDurationSec * 60
+		analysis.AvgStrideLengthM = analysis.TotalDistanceMeters / steps
+
+		if analysis.AvgCadenceSPM < RunningCadenceMinSPM {
+			analysis.FormIssues = append(analysis.FormIssues, "Cadence below running pace")
+		}
+		if analysis.AvgStrideLengthM < RunningMinStrideLengthM {
+			analysis.FormIssues = append(analysis.FormIssues, "Stride too short")
+		}
+		// A stride is two steps
+		if 2*analysis.TotalDurationSec/steps > RunningMaxStrideTimeSec {
+			analysis.FormIssues = append(analysis.FormIssues, "Stride time too long")
+		}
+	}
+	if oscCount > 0 && oscSum/float64(oscCount) > RunningVerticalOscMaxM {
+		analysis.FormIssues = append(analysis.FormIssues, "Excessive vertical bounce")
+	}
+	if contactCount > 0 && contactSum/float64(contactCount) > RunningGroundContactMaxS {
+		analysis.FormIssues = append(analysis.FormIssues, "Ground contact time too long")
+	}
+	if len(analysis.WalkingSegments) > 0 {
+		analysis.FormIssues = append(analysis.FormIssues, "Walking detected")
+	}
+
+	if analysis.Completed {
+		analysis.Score = CalculateRunScore(analysis.TwoMileTimeSec)
+	}
+
+	return analysis, nil
+}
+
+// newRunSplit computes the metrics for the split between two samples.
+func newRunSplit(index int, start, end RunSample, distance float64, hasSteps bool) RunSplit {
+	split := RunSplit{
+		Index:          index,
+		DistanceMeters: distance,
+		DurationSec:    end.Timestamp - start.Timestamp,
+	}
+	if distance > 0 {
+		split.PaceSecPerMile = split.DurationSec / distance * (RunTwoMileDistanceM / 2)
+	}
+	if split.DurationSec > 0 {
+		split.Walking = distance/split.DurationSec < RunningMinSpeedMPS
+		if steps := end.StepCount - start.StepCount; hasSteps && steps > 0 {
+			split.CadenceSPM = float64(steps) / split.DurationSec * 60
+			split.StrideLengthM = distance / float64(steps)
+		}
+	}
+	return split
+}
+
+// HaversineDistance returns the great-circle distance in meters between two
+// latitude/longitude points given in degrees.
+func HaversineDistance(lat1, lon1, lat2, lon2 float64) float64 {
+	toRad := math.Pi / 180
+	dLat := (lat2 - lat1) * toRad
+	dLon := (lon2 - lon1) * toRad
+
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1*toRad)*math.Cos(lat2*toRad)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * RunEarthRadiusM * math.Asin(math.Sqrt(min(1, a)))
+}
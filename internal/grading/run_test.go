@@ -0,0 +1,118 @@
+package grading
+
+import (
+	"math"
+	"testing"
+)
+
+// metersPerDegreeLat converts a north-south distance to degrees of latitude.
+const metersPerDegreeLat = RunEarthRadiusM * math.Pi / 180
+
+// runSamples builds one sample per second running due north at the given speeds.
+// Each speed applies for the given number of seconds, and steps are generated
+// at the given cadence.
+func runSamples(cadenceSPM float64, legs ...[2]float64) []RunSample {
+	samples := []RunSample{{Timestamp: 0, Latitude: 40, Longitude: -75}}
+	lat, t, steps := 40.0, 0.0, 0.0
+	for _, leg := range legs {
+		speed, seconds := leg[0], leg[1]
+		for i := 0; i < int(seconds); i++ {
+			t++
+			lat += speed / metersPerDegreeLat
+			steps += cadenceSPM / 60
+			samples = append(samples, RunSample{
+				Timestamp: t,
+				Latitude:  lat,
+				Longitude: -75,
+				StepCount: int(steps),
+			})
+		}
+	}
+	return samples
+}
+
+func TestAnalyzeRunSteadyPace(t *testing.T) {
+	// 3218.688 m at 4 m/s reaches two miles at 804.672 s
+	samples := runSamples(170, [2]float64{4, 900})
+
+	analysis, err := AnalyzeRun(samples)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !analysis.Completed {
+		t.Fatalf("expected run to be completed, distance %f", analysis.TotalDistanceMeters)
+	}
+	if analysis.TwoMileTimeSec != 805 {
+		t.Errorf("two mile time = %d, want 805", analysis.TwoMileTimeSec)
+	}
+	if want := CalculateRunScore(805); analysis.Score != want {
+		t.Errorf("score = %d, want %d", analysis.Score, want)
+	}
+	if len(analysis.WalkingSegments) != 0 {
+		t.Errorf("walking segments = %v, want none", analysis.WalkingSegments)
+	}
+	if math.Abs(analysis.AvgCadenceSPM-170) > 1 {
+		t.Errorf("cadence = %f, want ~170", analysis.AvgCadenceSPM)
+	}
+	if math.Abs(analysis.AvgStrideLengthM-4*60/170.0) > 0.02 {
+		t.Errorf("stride = %f, want ~%f", analysis.AvgStrideLengthM, 4*60/170.0)
+	}
+	if len(analysis.FormIssues) != 0 {
+		t.Errorf("form issues = %v, want none", analysis.FormIssues)
+	}
+
+	// 3600 m in quarter-mile splits: 8 full splits plus a partial one
+	if len(analysis.Splits) != 9 {
+		t.Fatalf("splits = %d, want 9", len(analysis.Splits))
+	}
+	first := analysis.Splits[0]
+	if math.Abs(first.PaceSecPerMile-1609.344/4) > 5 {
+		t.Errorf("first split pace = %f, want ~402", first.PaceSecPerMile)
+	}
+	if first.Walking {
+		t.Error("first split should not be walking")
+	}
+}
+
+func TestAnalyzeRunWalkingSegment(t *testing.T) {
+	samples := runSamples(160, [2]float64{3.5, 300}, [2]float64{1.2, 120}, [2]float64{3.5, 300})
+
+	analysis, err := AnalyzeRun(samples)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(analysis.WalkingSegments) != 1 {
+		t.Fatalf("walking segments = %d, want 1", len(analysis.WalkingSegments))
+	}
+	seg := analysis.WalkingSegments[0]
+	if seg.StartSec != 300 || seg.EndSec != 420 {
+		t.Errorf("walking segment = %+v, want 300s-420s", seg)
+	}
+	if analysis.Completed || analysis.Score != 0 {
+		t.Errorf("run short of two miles should not be scored, got %+v", analysis)
+	}
+
+	found := false
+	for _, issue := range analysis.FormIssues {
+		if issue == "Walking detected" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("form issues = %v, want walking flagged", analysis.FormIssues)
+	}
+}
+
+func TestAnalyzeRunInvalidInput(t *testing.T) {
+	if _, err := AnalyzeRun(nil); err != ErrInvalidInput {
+		t.Errorf("err = %v, want ErrInvalidInput", err)
+	}
+
+	samples := runSamples(170, [2]float64{4, 10})
+	samples[5].Timestamp = 1
+	if _, err := AnalyzeRun(samples); err != ErrInvalidInput {
+		t.Errorf("err = %v, want ErrInvalidInput for out-of-order samples", err)
+	}
+}
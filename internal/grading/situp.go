@@ -0,0 +1,163 @@
+package grading
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Sit-up state constants
+const (
+	SitupStateUp      = "up"   // Torso raised, hip fully flexed
+	SitupStateDown    = "down" // Shoulders back on the ground, hip extended
+	SitupStateInvalid = "invalid"
+	SitupStateBetween = "between"
+)
+
+// Required joints for sit-up analysis
+var SitupRequiredJoints = []string{
+	"leftShoulder", "rightShoulder",
+	"leftHip", "rightHip",
+	"leftKnee", "rightKnee",
+}
+
+// GradeSitup analyzes a pose for sit-up form and counts repetitions.
+// The hip angle (shoulder-hip-knee) drives the state machine: a rep is counted
+// when the athlete returns to the ground after closing the hip to at most
+// SitupHipAngleDownMax degrees.
+func GradeSitup(pose *Pose, state *ExerciseState) (*GradingResult, error) {
+	if pose == nil || state == nil {
+		return nil, ErrInvalidInput
+	}
+
+	result := &GradingResult{
+		IsValid:    false,
+		RepCounted: false,
+		FormScore:  0.0,
+		Feedback:   "",
+		State:      state.CurrentPhase,
+	}
+
+	// Verify required joints have sufficient confidence
+	hasRequiredJoints, missingJoints := VerifyJointConfidence(pose, SitupRequiredJoints, SitupRequiredConfidence)
+	if !hasRequiredJoints {
+		result.Feedback = fmt.Sprintf("Cannot see clearly: %s", strings.Join(missingJoints, ", "))
+		state.CurrentPhase = SitupStateInvalid
+		result.State = SitupStateInvalid
+		return result, nil
+	}
+
+	// Extract validated points
+	leftShoulder := pose.Joints["leftShoulder"]
+	rightShoulder := pose.Joints["rightShoulder"]
+	leftHip := pose.Joints["leftHip"]
+	rightHip := pose.Joints["rightHip"]
+	leftKnee := pose.Joints["leftKnee"]
+	rightKnee := pose.Joints["rightKnee"]
+
+	// 1. Calculate Key Angles
+	leftHipAngle := CalculateAngle(leftShoulder, leftHip, leftKnee)
+	rightHipAngle := CalculateAngle(rightShoulder, rightHip, rightKnee)
+	avgHipAngle := (leftHipAngle + rightHipAngle) / 2.0
+
+	// 2. Form checks - track issues in formIssues slice
+	state.FormIssues = []string{}
+
+	// 2a. Torso rotation: when the athlete twists, one shoulder moves closer
+	// to its hip than the other. Compare both torso sides relative to their mean.
+	leftTorso := Distance(leftShoulder, leftHip)
+	rightTorso := Distance(rightShoulder, rightHip)
+	avgTorso := (leftTorso + rightTorso) / 2.0
+	if avgTorso < 0.001 {
+		avgTorso = 1.0 // Prevent division by zero
+	}
+	torsoRotation := abs(leftTorso-rightTorso) / avgTorso
+
+	if torsoRotation > SitupTorsoRotationMaxDiff {
+		state.FormIssues = append(state.FormIssues, "Keep torso square, avoid twisting")
+	}
+
+	// If form issues exist, set state to invalid and provide feedback
+	if len(state.FormIssues) > 0 {
+		state.CurrentPhase = SitupStateInvalid
+		result.State = SitupStateInvalid
+		result.Feedback = strings.Join(state.FormIssues, ". ")
+		result.IsValid = false
+		return result, nil
+	}
+
+	// 3. State Machine Logic
+	previousState := state.CurrentPhase
+
+	// Determine potential next state based on hip angle
+	var potentialState string
+	if avgHipAngle <= SitupHipAngleDownMax {
+		potentialState = SitupStateUp
+	} else if avgHipAngle >= SitupHipAngleUpMin {
+		potentialState = SitupStateDown
+	} else {
+		potentialState = SitupStateBetween
+	}
+
+	// Update current state
+	state.CurrentPhase = potentialState
+
+	// Track minimum hip angle while the torso is off the ground
+	if state.CurrentPhase != SitupStateDown {
+		state.MinHipAngle = min(state.MinHipAngle, avgHipAngle)
+	}
+
+	// Check if the torso came up far enough during this attempt
+	if state.MinHipAngle <= SitupHipAngleDownMax {
+		state.WentLowEnough = true
+	}
+
+	// Check for Rep Completion: back on the ground after being off it
+	if state.CurrentPhase == SitupStateDown && (previousState == SitupStateUp || previousState == SitupStateBetween) {
+		if state.WentLowEnough {
+			state.RepCount++
+			result.RepCounted = true
+			result.Feedback = fmt.Sprintf("Rep Counted! (%d)", state.RepCount)
+		} else {
+			// Didn't sit up far enough before lowering
+			result.Feedback = "Sit up higher for rep to count"
+		}
+		// Reset state for next rep attempt
+		state.MinHipAngle = 180.0
+		state.WentLowEnough = false
+	}
+
+	// 4. Provide general feedback based on state if no specific message already set
+	if result.Feedback == "" {
+		switch state.CurrentPhase {
+		case SitupStateUp:
+			result.Feedback = "Lower back down"
+		case SitupStateDown:
+			result.Feedback = "Sit up"
+		case SitupStateBetween:
+			result.Feedback = "Keep moving"
+		case SitupStateInvalid:
+			result.Feedback = "Fix pose"
+		}
+	}
+
+	// Calculate form score based on torso rotation and left/right symmetry
+	formScore := 1.0
+	if torsoRotation > 0 {
+		// Reduce score based on torso rotation (0 to 0.5 reduction)
+		rotationPenalty := min(0.5, torsoRotation/SitupTorsoRotationMaxDiff*0.5)
+		formScore -= rotationPenalty
+	}
+
+	hipAsymmetry := abs(leftHipAngle - rightHipAngle)
+	if hipAsymmetry > 0 {
+		// Reduce score based on hip angle asymmetry (0 to 0.5 reduction)
+		asymmetryPenalty := min(0.5, hipAsymmetry/(SitupHipAngleUpMin-SitupHipAngleDownMax)*0.5)
+		formScore -= asymmetryPenalty
+	}
+
+	result.IsValid = true
+	result.State = state.CurrentPhase
+	result.FormScore = max(0, formScore) // Ensure non-negative
+
+	return result, nil
+}
@@ -0,0 +1,131 @@
+package grading
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+// situpPose builds a side-view sit-up pose with the given hip angle in degrees.
+// twist shortens the right torso side by the given fraction to simulate rotation.
+func situpPose(hipAngle, twist float64) *Pose {
+	hip := Joint{X: 0.5, Y: 0.6, Confidence: 0.9}
+	// Thigh points up and forward at 45 degrees from the hip
+	thighDir := math.Pi / 4
+	knee := Joint{X: hip.X + 0.2*math.Cos(thighDir), Y: hip.Y - 0.2*math.Sin(thighDir), Confidence: 0.9}
+
+	// The torso opens away from the thigh by hipAngle degrees
+	torsoDir := thighDir + hipAngle*math.Pi/180
+	shoulderAt := func(length float64) Joint {
+		return Joint{X: hip.X + length*math.Cos(torsoDir), Y: hip.Y - length*math.Sin(torsoDir), Confidence: 0.9}
+	}
+
+	pose := NewPose()
+	pose.Joints["leftHip"] = hip
+	pose.Joints["rightHip"] = hip
+	pose.Joints["leftKnee"] = knee
+	pose.Joints["rightKnee"] = knee
+	pose.Joints["leftShoulder"] = shoulderAt(0.3)
+	pose.Joints["rightShoulder"] = shoulderAt(0.3 * (1 - twist))
+	return pose
+}
+
+func TestGradeSitup(t *testing.T) {
+	tests := []struct {
+		name       string
+		hipAngles  []float64
+		twist      float64
+		wantReps   int
+		wantState  string
+		wantValid  bool
+		wantInFeed string
+	}{
+		{
+			name:      "single full rep",
+			hipAngles: []float64{160, 120, 80, 120, 160},
+			wantReps:  1,
+			wantState: SitupStateDown,
+			wantValid: true,
+		},
+		{
+			name:      "three full reps",
+			hipAngles: []float64{160, 80, 160, 85, 160, 70, 155},
+			wantReps:  3,
+			wantState: SitupStateDown,
+			wantValid: true,
+		},
+		{
+			name:       "half rep not counted",
+			hipAngles:  []float64{160, 120, 100, 120, 160},
+			wantReps:   0,
+			wantState:  SitupStateDown,
+			wantValid:  true,
+			wantInFeed: "Sit up higher",
+		},
+		{
+			name:      "rep not counted until back on the ground",
+			hipAngles: []float64{160, 80, 120},
+			wantReps:  0,
+			wantState: SitupStateBetween,
+			wantValid: true,
+		},
+		{
+			name:       "twisting flagged as invalid",
+			hipAngles:  []float64{160, 80},
+			twist:      0.3,
+			wantReps:   0,
+			wantState:  SitupStateInvalid,
+			wantValid:  false,
+			wantInFeed: "twisting",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := NewExerciseState()
+			var result *GradingResult
+			var err error
+			for _, angle := range tt.hipAngles {
+				result, err = GradeSitup(situpPose(angle, tt.twist), state)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			}
+
+			if state.RepCount != tt.wantReps {
+				t.Errorf("rep count = %d, want %d", state.RepCount, tt.wantReps)
+			}
+			if result.State != tt.wantState {
+				t.Errorf("state = %q, want %q", result.State, tt.wantState)
+			}
+			if result.IsValid != tt.wantValid {
+				t.Errorf("valid = %v, want %v", result.IsValid, tt.wantValid)
+			}
+			if tt.wantInFeed != "" && !strings.Contains(result.Feedback, tt.wantInFeed) {
+				t.Errorf("feedback = %q, want it to contain %q", result.Feedback, tt.wantInFeed)
+			}
+			if result.IsValid && (result.FormScore < 0.99 || result.FormScore > 1.0) {
+				t.Errorf("form score = %f, want ~1.0 for a symmetric pose", result.FormScore)
+			}
+		})
+	}
+}
+
+func TestGradeSitupMissingJoints(t *testing.T) {
+	pose := situpPose(160, 0)
+	delete(pose.Joints, "leftKnee")
+
+	result, err := GradeSitup(pose, NewExerciseState())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.IsValid || result.State != SitupStateInvalid {
+		t.Errorf("expected invalid result for missing joints, got %+v", result)
+	}
+}
+
+func TestGradeSitupNilInput(t *testing.T) {
+	if _, err := GradeSitup(nil, NewExerciseState()); err != ErrInvalidInput {
+		t.Errorf("err = %v, want ErrInvalidInput", err)
+	}
+}
@@ -0,0 +1,507 @@
+package grading
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// DefaultStandardID is the standard used when a workout does not name one.
+const DefaultStandardID = "apft-2013"
+
+// genderAny keys norms that apply to every gender.
+const genderAny = "any"
+
+//go:embed standards/*.json
+var standardFiles embed.FS
+
+// ScoringStandard converts event performances into points under a named,
+// versioned fitness test standard.
+type ScoringStandard interface {
+	ID() string
+	Name() string
+	Version() string
+	Description() string
+	// PassPoints is the minimum passing score for a single event.
+	PassPoints() int
+	// Experimental reports whether the standard is a new formula still being rolled out,
+	// which users select only when the grading formula flag is on for them.
+	Experimental() bool
+	Events() []StandardEvent
+	// Protocol describes how a full test is administered under the standard.
+	Protocol() TestProtocol
+	// Score returns the points (0-100) for a performance value in the given event.
+	// It returns ErrUnknownExerciseType when the standard does not include the event.
+	Score(event string, value float64, profile Profile) (int, error)
+}
+
+// StandardEvent describes an event scored by a standard.
+type StandardEvent struct {
+	ID             string `json:"id"`
+	Name           string `json:"name"`
+	Unit           string `json:"unit"`
+	HigherIsBetter bool   `json:"higher_is_better"`
+}
+
+// TestProtocol describes the administration of a full test: the events in the order
+// they are taken and the rest allowed between the end of one event and the start of
+// the next.
+type TestProtocol struct {
+	// Sequence lists the events of the test in order. Each slot names the events
+	// accepted in that position; alternates (e.g. push-ups for pull-ups) share a slot.
+	Sequence [][]string
+	MinRest  time.Duration
+	MaxRest  time.Duration
+}
+
+// standardFile is the on-disk format of an embedded standard.
+type standardFile struct {
+	ID             string      `json:"id"`
+	Name           string      `json:"name"`
+	Version        string      `json:"version"`
+	Description    string      `json:"description"`
+	Experimental   bool        `json:"experimental"`
+	AgeBrackets    []int       `json:"age_brackets"`
+	PassPoints     int         `json:"pass_points"`
+	DefaultProfile profileFile `json:"default_profile"`
+	Test           testFile    `json:"test"`
+	Events         []eventFile `json:"events"`
+}
+
+type testFile struct {
+	Sequence       [][]string `json:"sequence"`
+	MinRestSeconds int        `json:"min_rest_seconds"`
+	MaxRestSeconds int        `json:"max_rest_seconds"`
+}
+
+type profileFile struct {
+	Gender string `json:"gender"`
+	Age    int    `json:"age"`
+}
+
+type eventFile struct {
+	StandardEvent
+	// RoundTo rounds values to the nearest multiple before a table lookup (e.g. 6s run steps)
+	RoundTo int                     `json:"round_to"`
+	Table   map[string]int          `json:"table"`
+	Norms   map[string][][2]float64 `json:"norms"`
+}
+
+// normAnchors are the performance values that earn the passing and maximum points.
+type normAnchors struct {
+	pass float64
+	max  float64
+}
+
+type tableEvent struct {
+	StandardEvent
+	roundTo   int
+	tableKeys []int // sorted ascending
+	table     map[int]int
+	norms     map[string][]normAnchors // by gender, one entry per age bracket
+}
+
+// tableStandard is a ScoringStandard backed by lookup tables and per-bracket anchors.
+type tableStandard struct {
+	id             string
+	name           string
+	version        string
+	description    string
+	experimental   bool
+	ageBrackets    []int
+	passPoints     int
+	defaultProfile Profile
+	protocol       TestProtocol
+	events         []*tableEvent
+	eventsByID     map[string]*tableEvent
+}
+
+func (s *tableStandard) ID() string          { return s.id }
+func (s *tableStandard) Name() string        { return s.name }
+func (s *tableStandard) Version() string     { return s.version }
+func (s *tableStandard) Description() string { return s.description }
+func (s *tableStandard) PassPoints() int     { return s.passPoints }
+func (s *tableStandard) Experimental() bool  { return s.experimental }
+
+func (s *tableStandard) Protocol() TestProtocol { return s.protocol }
+
+func (s *tableStandard) Events() []StandardEvent {
+	events := make([]StandardEvent, len(s.events))
+	for i, ev := range s.events {
+		events[i] = ev.StandardEvent
+	}
+	return events
+}
+
+// Score uses the age- and gender-normed anchors when the profile allows it.
+// Otherwise the flat table applies, or the anchors of the default profile for
+// standards that do not publish one.
+func (s *tableStandard) Score(event string, value float64, profile Profile) (int, error) {
+	ev, ok := s.eventsByID[event]
+	if !ok {
+		return 0, ErrUnknownExerciseType
+	}
+
+	if profile.Normed() {
+		if anchors, ok := s.anchorsFor(ev, profile); ok {
+			return s.interpolate(anchors, value), nil
+		}
+	}
+	if len(ev.tableKeys) > 0 {
+		return ev.lookup(value), nil
+	}
+	anchors, ok := s.anchorsFor(ev, s.defaultProfile)
+	if !ok {
+		return 0, fmt.Errorf("standard %s has no default scoring for %s: %w", s.id, event, ErrInvalidInput)
+	}
+	return s.interpolate(anchors, value), nil
+}
+
+// ageBracketIndex returns the index of the bracket containing age.
+func (s *tableStandard) ageBracketIndex(age int) int {
+	idx := 0
+	for i, start := range s.ageBrackets {
+		if age >= start {
+			idx = i
+		}
+	}
+	return idx
+}
+
+// ageBracketLabel returns the label of the bracket containing age (e.g. "22-26", "62+").
+func (s *tableStandard) ageBracketLabel(age int) string {
+	idx := s.ageBracketIndex(age)
+	if idx == len(s.ageBrackets)-1 {
+		return fmt.Sprintf("%d+", s.ageBrackets[idx])
+	}
+	return fmt.Sprintf("%d-%d", s.ageBrackets[idx], s.ageBrackets[idx+1]-1)
+}
+
+func (s *tableStandard) anchorsFor(ev *tableEvent, profile Profile) (normAnchors, bool) {
+	brackets, ok := ev.norms[profile.Gender]
+	if !ok {
+		brackets, ok = ev.norms[genderAny]
+	}
+	if !ok {
+		return normAnchors{}, false
+	}
+	return brackets[s.ageBracketIndex(profile.Age)], true
+}
+
+// interpolate converts a performance value to points along the line through the
+// passing and maximum anchors. The same formula serves higher-is-better events
+// and timed events because the slope sign follows the anchors.
+func (s *tableStandard) interpolate(anchors normAnchors, value float64) int {
+	slope := float64(100-s.passPoints) / (anchors.max - anchors.pass)
+	points := float64(s.passPoints) + (value-anchors.pass)*slope
+	if points >= 100 {
+		return 100
+	}
+	if points <= 0 {
+		return 0
+	}
+	return int(points)
+}
+
+// lookup steps through the flat table: the best listed performance the value
+// reaches earns its points, and values short of every entry score zero.
+func (ev *tableEvent) lookup(value float64) int {
+	v := int(value)
+	if ev.roundTo > 0 {
+		v = ((v + ev.roundTo/2) / ev.roundTo) * ev.roundTo
+	}
+
+	if ev.HigherIsBetter {
+		// Greatest key <= v
+		i := sort.SearchInts(ev.tableKeys, v+1) - 1
+		if i < 0 {
+			return 0
+		}
+		return ev.table[ev.tableKeys[i]]
+	}
+	// Smallest key >= v
+	i := sort.SearchInts(ev.tableKeys, v)
+	if i == len(ev.tableKeys) {
+		return 0
+	}
+	return ev.table[ev.tableKeys[i]]
+}
+
+// parseStandard validates a standard file and builds its scoring tables.
+func parseStandard(data []byte) (*tableStandard, error) {
+	var f standardFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("failed to decode standard: %w", err)
+	}
+	if f.ID == "" || f.Version == "" {
+		return nil, fmt.Errorf("standard is missing id or version")
+	}
+	if len(f.AgeBrackets) == 0 {
+		return nil, fmt.Errorf("standard %s has no age brackets", f.ID)
+	}
+	if f.PassPoints <= 0 || f.PassPoints >= 100 {
+		return nil, fmt.Errorf("standard %s has invalid pass points %d", f.ID, f.PassPoints)
+	}
+
+	s := &tableStandard{
+		id:             f.ID,
+		name:           f.Name,
+		version:        f.Version,
+		description:    f.Description,
+		experimental:   f.Experimental,
+		ageBrackets:    f.AgeBrackets,
+		passPoints:     f.PassPoints,
+		defaultProfile: Profile{Gender: f.DefaultProfile.Gender, Age: f.DefaultProfile.Age},
+		eventsByID:     make(map[string]*tableEvent, len(f.Events)),
+	}
+
+	for _, e := range f.Events {
+		if e.ID == "" {
+			return nil, fmt.Errorf("standard %s has an event without an id", f.ID)
+		}
+		if _, dup := s.eventsByID[e.ID]; dup {
+			return nil, fmt.Errorf("standard %s lists event %s twice", f.ID, e.ID)
+		}
+
+		ev := &tableEvent{
+			StandardEvent: e.StandardEvent,
+			roundTo:       e.RoundTo,
+			table:         make(map[int]int, len(e.Table)),
+			norms:         make(map[string][]normAnchors, len(e.Norms)),
+		}
+		for key, points := range e.Table {
+			k, err := strconv.Atoi(key)
+			if err != nil {
+				return nil, fmt.Errorf("standard %s event %s has invalid table key %q", f.ID, e.ID, key)
+			}
+			ev.table[k] = points
+			ev.tableKeys = append(ev.tableKeys, k)
+		}
+		sort.Ints(ev.tableKeys)
+
+		for gender, pairs := range e.Norms {
+			if len(pairs) != len(f.AgeBrackets) {
+				return nil, fmt.Errorf("standard %s event %s has %d %s brackets, want %d",
+					f.ID, e.ID, len(pairs), gender, len(f.AgeBrackets))
+			}
+			anchors := make([]normAnchors, len(pairs))
+			for i, p := range pairs {
+				if p[0] == p[1] {
+					return nil, fmt.Errorf("standard %s event %s has equal anchors in bracket %d", f.ID, e.ID, i)
+				}
+				anchors[i] = normAnchors{pass: p[0], max: p[1]}
+			}
+			ev.norms[gender] = anchors
+		}
+
+		if len(ev.tableKeys) == 0 {
+			if _, ok := s.anchorsFor(ev, s.defaultProfile); !ok {
+				return nil, fmt.Errorf("standard %s event %s has neither a table nor default norms", f.ID, e.ID)
+			}
+		}
+
+		s.events = append(s.events, ev)
+		s.eventsByID[e.ID] = ev
+	}
+
+	if err := s.parseProtocol(f.Test); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// parseProtocol validates the test protocol against the standard's events.
+func (s *tableStandard) parseProtocol(t testFile) error {
+	if len(t.Sequence) == 0 {
+		return fmt.Errorf("standard %s has no test sequence", s.id)
+	}
+	if t.MinRestSeconds < 0 || t.MaxRestSeconds < t.MinRestSeconds {
+		return fmt.Errorf("standard %s has invalid rest interval %d-%ds", s.id, t.MinRestSeconds, t.MaxRestSeconds)
+	}
+
+	seen := make(map[string]bool)
+	for i, slot := range t.Sequence {
+		if len(slot) == 0 {
+			return fmt.Errorf("standard %s has an empty test slot %d", s.id, i)
+		}
+		for _, event := range slot {
+			if _, ok := s.eventsByID[event]; !ok {
+				return fmt.Errorf("standard %s test sequence names unknown event %s", s.id, event)
+			}
+			if seen[event] {
+				return fmt.Errorf("standard %s test sequence lists event %s twice", s.id, event)
+			}
+			seen[event] = true
+		}
+	}
+
+	s.protocol = TestProtocol{
+		Sequence: t.Sequence,
+		MinRest:  time.Duration(t.MinRestSeconds) * time.Second,
+		MaxRest:  time.Duration(t.MaxRestSeconds) * time.Second,
+	}
+	return nil
+}
+
+// Registry holds the scoring standards available for grading, keyed by ID.
+type Registry struct {
+	mu        sync.RWMutex
+	standards map[string]ScoringStandard
+}
+
+// NewRegistry creates an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{standards: make(map[string]ScoringStandard)}
+}
+
+// Register adds a standard; IDs must be unique.
+func (r *Registry) Register(s ScoringStandard) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.standards[s.ID()]; exists {
+		return fmt.Errorf("scoring standard %s is already registered", s.ID())
+	}
+	r.standards[s.ID()] = s
+	return nil
+}
+
+// Get returns the standard with the given ID, or ErrUnknownStandard.
+func (r *Registry) Get(id string) (ScoringStandard, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	s, ok := r.standards[id]
+	if !ok {
+		return nil, ErrUnknownStandard
+	}
+	return s, nil
+}
+
+// List returns all registered standards ordered by ID.
+func (r *Registry) List() []ScoringStandard {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	list := make([]ScoringStandard, 0, len(r.standards))
+	for _, s := range r.standards {
+		list = append(list, s)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].ID() < list[j].ID() })
+	return list
+}
+
+// loadStandards parses every standard file in fsys into a new registry.
+func loadStandards(fsys fs.FS, dir string) (*Registry, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read standards: %w", err)
+	}
+
+	registry := NewRegistry()
+	for _, entry := range entries {
+		if entry.IsDir() || path.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		data, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", entry.Name(), err)
+		}
+		standard, err := parseStandard(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", entry.Name(), err)
+		}
+		if err := registry.Register(standard); err != nil {
+			return nil, err
+		}
+	}
+	return registry, nil
+}
+
+// defaultRegistry holds the embedded standards; an invalid data file is a build defect.
+var defaultRegistry = mustLoadStandards(standardFiles, "standards")
+
+// defaultStandard is the standard behind CalculateScore and CalculateRunScore.
+var defaultStandard = mustDefaultStandard(defaultRegistry)
+
+func mustLoadStandards(fsys fs.FS, dir string) *Registry {
+	registry, err := loadStandards(fsys, dir)
+	if err != nil {
+		panic(fmt.Sprintf("grading: invalid embedded scoring standards: %v", err))
+	}
+	return registry
+}
+
+func mustDefaultStandard(registry *Registry) *tableStandard {
+	standard, err := registry.Get(DefaultStandardID)
+	if err != nil {
+		panic(fmt.Sprintf("grading: default scoring standard %s is not embedded", DefaultStandardID))
+	}
+	return standard.(*tableStandard)
+}
+
+// DefaultRegistry returns the registry of embedded scoring standards.
+func DefaultRegistry() *Registry {
+	return defaultRegistry
+}
+
+// DefaultStandard returns the standard used when none is requested.
+func DefaultStandard() ScoringStandard {
+	return defaultStandard
+}
+
+// LookupStandard resolves a standard ID in the default registry.
+// An empty ID selects the default standard.
+func LookupStandard(id string) (ScoringStandard, error) {
+	if id == "" {
+		return defaultStandard, nil
+	}
+	return defaultRegistry.Get(id)
+}
+
+// DefaultStandardFor returns the standard used to score an event when none is
+// requested: the default standard if it includes the event, otherwise the first
+// registered standard (by ID) that does. It returns ErrUnknownExerciseType when
+// no embedded standard scores the event.
+func DefaultStandardFor(event string) (ScoringStandard, error) {
+	if _, ok := defaultStandard.eventsByID[event]; ok {
+		return defaultStandard, nil
+	}
+	for _, s := range defaultRegistry.List() {
+		for _, e := range s.Events() {
+			if e.ID == event {
+				return s, nil
+			}
+		}
+	}
+	return nil, ErrUnknownExerciseType
+}
+
+// EventForExerciseType returns the event a stored exercise type is scored as. Workouts
+// store the 2-mile run as "running"; every other exercise type, the USMC 3-mile run
+// included, is stored under its event ID.
+func EventForExerciseType(exerciseType string) string {
+	if exerciseType == "running" {
+		return ExerciseTypeRun
+	}
+	return exerciseType
+}
+
+// DefaultStandardIDForExercise returns the ID of the standard that scores a stored
+// exercise type when none is requested, or DefaultStandardID when no standard scores it.
+// Leaderboards rank an exercise under this standard only, as grades under different
+// standards are not comparable.
+func DefaultStandardIDForExercise(exerciseType string) string {
+	standard, err := DefaultStandardFor(EventForExerciseType(exerciseType))
+	if err != nil {
+		return DefaultStandardID
+	}
+	return standard.ID()
+}
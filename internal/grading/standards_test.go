@@ -0,0 +1,141 @@
+package grading
+
+import (
+	"testing"
+	"testing/fstest"
+)
+
+func TestEmbeddedStandards(t *testing.T) {
+	want := []string{"acft-2022", "apft-2013", "usmc-pft-2022"}
+	list := DefaultRegistry().List()
+	if len(list) != len(want) {
+		t.Fatalf("registered standards = %d, want %d", len(list), len(want))
+	}
+	for i, s := range list {
+		if s.ID() != want[i] {
+			t.Errorf("standard %d = %s, want %s", i, s.ID(), want[i])
+		}
+		if s.Version() == "" || len(s.Events()) == 0 {
+			t.Errorf("standard %s is missing a version or events", s.ID())
+		}
+		if len(s.Protocol().Sequence) == 0 || s.Protocol().MaxRest <= 0 {
+			t.Errorf("standard %s is missing a test protocol", s.ID())
+		}
+	}
+
+	if s, err := LookupStandard(""); err != nil || s.ID() != DefaultStandardID {
+		t.Errorf("LookupStandard(\"\") = %v, %v; want the default standard", s, err)
+	}
+	if _, err := LookupStandard("apft-1980"); err != ErrUnknownStandard {
+		t.Errorf("err = %v, want ErrUnknownStandard", err)
+	}
+}
+
+func TestDefaultStandardFor(t *testing.T) {
+	tests := map[string]string{
+		ExerciseTypePushup:            "apft-2013",
+		ExerciseTypeRun:               "apft-2013",
+		ExerciseTypeDeadlift:          "acft-2022",
+		ExerciseTypeHandReleasePushup: "acft-2022",
+		ExerciseTypePlank:             "acft-2022",
+	}
+	for event, want := range tests {
+		s, err := DefaultStandardFor(event)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", event, err)
+		}
+		if s.ID() != want {
+			t.Errorf("%s: standard = %s, want %s", event, s.ID(), want)
+		}
+	}
+
+	if _, err := DefaultStandardFor("burpee"); err != ErrUnknownExerciseType {
+		t.Errorf("err = %v, want ErrUnknownExerciseType", err)
+	}
+}
+
+func TestDefaultStandardIDForExercise(t *testing.T) {
+	tests := map[string]string{
+		"running": "apft-2013", // Stored name of the run event
+		"plank":   "acft-2022",
+		"burpee":  DefaultStandardID,
+	}
+	for exerciseType, want := range tests {
+		if got := DefaultStandardIDForExercise(exerciseType); got != want {
+			t.Errorf("%s: standard = %s, want %s", exerciseType, got, want)
+		}
+	}
+}
+
+func TestStandardScore(t *testing.T) {
+	male19 := Profile{Gender: GenderMale, Age: 19}
+	female30 := Profile{Gender: GenderFemale, Age: 30}
+	male45 := Profile{Gender: GenderMale, Age: 45}
+
+	tests := []struct {
+		name     string
+		standard string
+		event    string
+		value    float64
+		profile  Profile
+		want     int
+	}{
+		{"acft deadlift minimum", "acft-2022", "deadlift", 140, male19, 60},
+		{"acft deadlift maximum", "acft-2022", "deadlift", 340, male19, 100},
+		{"acft power throw midpoint", "acft-2022", "power_throw", 9.3, male19, 80},
+		{"acft sprint-drag-carry maximum", "acft-2022", "sprint_drag_carry", 112, female30, 100},
+		{"acft plank shared across genders", "acft-2022", "plank", 80, female30, 60},
+		{"acft unknown profile uses default", "acft-2022", "deadlift", 140, Profile{}, 60},
+		{"usmc pull-up minimum", "usmc-pft-2022", "pullup", 5, male45, 40},
+		{"usmc 3-mile maximum", "usmc-pft-2022", "run_3mile", 1080, male19, 100},
+		{"usmc slow run clamps to 0", "usmc-pft-2022", "run_3mile", 4000, male19, 0},
+		{"apft run table", "apft-2013", ExerciseTypeRun, 780, Profile{}, 76},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			standard, err := LookupStandard(tt.standard)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			got, err := standard.Score(tt.event, tt.value, tt.profile)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("score = %d, want %d", got, tt.want)
+			}
+		})
+	}
+
+	usmc, _ := LookupStandard("usmc-pft-2022")
+	if _, err := usmc.Score(ExerciseTypeSitup, 50, male19); err != ErrUnknownExerciseType {
+		t.Errorf("err = %v, want ErrUnknownExerciseType", err)
+	}
+}
+
+func TestLoadStandardsRejectsInvalidFiles(t *testing.T) {
+	tests := map[string]string{
+		"missing version": `{"id": "x", "age_brackets": [17], "pass_points": 60, "events": []}`,
+		"bracket count": `{"id": "x", "version": "1", "age_brackets": [17, 22], "pass_points": 60,
+			"events": [{"id": "pushup", "norms": {"male": [[10, 20]]}}]}`,
+		"no default scoring": `{"id": "x", "version": "1", "age_brackets": [17], "pass_points": 60,
+			"default_profile": {"gender": "male", "age": 17},
+			"events": [{"id": "pushup", "norms": {"female": [[10, 20]]}}]}`,
+		"unknown test event": `{"id": "x", "version": "1", "age_brackets": [17], "pass_points": 60,
+			"test": {"sequence": [["pushup"], ["run"]], "max_rest_seconds": 600},
+			"events": [{"id": "pushup", "table": {"10": 60}}]}`,
+		"inverted rest interval": `{"id": "x", "version": "1", "age_brackets": [17], "pass_points": 60,
+			"test": {"sequence": [["pushup"]], "min_rest_seconds": 600, "max_rest_seconds": 300},
+			"events": [{"id": "pushup", "table": {"10": 60}}]}`,
+	}
+
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			fsys := fstest.MapFS{"standards/x.json": {Data: []byte(data)}}
+			if _, err := loadStandards(fsys, "standards"); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
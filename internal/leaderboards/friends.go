@@ -0,0 +1,29 @@
+package leaderboards
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"ptchampion/internal/store"
+)
+
+// GetFriendsLeaderboard retrieves the leaderboard of the user and their friends. Workouts
+// shared with friends count alongside public ones.
+func (s *service) GetFriendsLeaderboard(ctx context.Context, userID int32, board string, limit int, timeFrame TimeFrame) ([]*store.LeaderboardEntry, error) {
+	s.logger.Debug(ctx, "Service: GetFriendsLeaderboard", "userID", userID, "board", board, "limit", limit, "timeFrame", timeFrame)
+	if limit <= 0 || limit > 300 {
+		limit = 50 // Default/max limit
+	}
+
+	startDate, endDate := timeFrame.Dates(time.Now())
+
+	entries, err := s.leaderboardStore.GetFriendsAggregateLeaderboardByTypes(ctx, userID, boardExerciseTypes(board), boardScoringStandard(board), limit, startDate, endDate)
+	if err != nil {
+		s.logger.Error(ctx, "Failed to get friends leaderboard from store", "userID", userID, "board", board, "error", err)
+		return nil, fmt.Errorf("failed to retrieve friends leaderboard: %w", err)
+	}
+	assignRanks(entries)
+	s.logger.Info(ctx, "Friends leaderboard retrieved", "userID", userID, "board", board, "count", len(entries))
+	return entries, nil
+}
@@ -0,0 +1,227 @@
+package leaderboards
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"ptchampion/internal/grading"
+	"ptchampion/internal/store"
+)
+
+// Boards other than single exercise types
+const (
+	BoardOverall = "overall" // Sum of the best scores in OverallExerciseTypes
+	BoardACFT    = "acft"    // Sum of the best scores in ACFTExerciseTypes
+)
+
+// Unit ranking metrics
+const (
+	UnitMetricAverage = "average"
+	UnitMetricMedian  = "median"
+)
+
+const defaultMinParticipants = 3
+
+// OverallExerciseTypes are the exercise types summed by the overall leaderboards, matching
+// the global aggregate leaderboard.
+var OverallExerciseTypes = []string{"pushup", "situp", "pullup", "running"}
+
+// ErrPermissionDenied is returned when the user does not belong to the organization.
+var ErrPermissionDenied = errors.New("user does not have permission to view this organization's leaderboards")
+
+// ErrInvalidUnitMetric is returned for a unit ranking metric other than average or median.
+var ErrInvalidUnitMetric = errors.New("invalid unit ranking metric")
+
+// UnitRankingOptions controls how units are scored and which units are ranked.
+type UnitRankingOptions struct {
+	Metric               string  // UnitMetricAverage (default) or UnitMetricMedian
+	MinParticipants      int     // Units with fewer scored members are unranked; defaults to 3
+	MinParticipationRate float64 // Minimum percentage of members with a score; 0 for none
+}
+
+// boardExerciseTypes returns the exercise types whose best scores are summed for a board.
+func boardExerciseTypes(board string) []string {
+	switch board {
+	case BoardOverall, "aggregate":
+		return OverallExerciseTypes
+	case BoardACFT:
+		return ACFTExerciseTypes
+	}
+	return []string{board}
+}
+
+// boardScoringStandard returns the scoring standard whose grades a board ranks. Grades
+// under different standards are not comparable, so an exercise board ranks the standard
+// that scores the exercise by default.
+func boardScoringStandard(board string) string {
+	switch board {
+	case BoardOverall, "aggregate":
+		return grading.DefaultStandardID
+	case BoardACFT:
+		return ACFTStandardID
+	}
+	return grading.DefaultStandardIDForExercise(board)
+}
+
+// requireOrganizationAccess checks that the user belongs to the organization, one of its
+// parents or one of its subunits.
+func (s *service) requireOrganizationAccess(ctx context.Context, userID int32, orgID int32) (*store.Organization, error) {
+	org, err := s.orgStore.GetOrganizationByID(ctx, orgID)
+	if err != nil {
+		if err == store.ErrOrganizationNotFound {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to retrieve organization: %w", err)
+	}
+
+	roles, err := s.orgStore.GetUserOrganizationPathRoles(ctx, userID, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get organization roles: %w", err)
+	}
+	if len(roles) > 0 {
+		return org, nil
+	}
+	member, err := s.orgStore.IsOrganizationSubtreeMember(ctx, orgID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !member {
+		s.logger.Warn(ctx, "User is not in organization", "userID", userID, "orgID", orgID)
+		return nil, ErrPermissionDenied
+	}
+	return org, nil
+}
+
+// GetOrganizationLeaderboard retrieves the leaderboard of a unit's members, including those of its subunits.
+func (s *service) GetOrganizationLeaderboard(ctx context.Context, userID int32, orgID int32, board string, limit int, timeFrame TimeFrame) ([]*store.LeaderboardEntry, error) {
+	s.logger.Debug(ctx, "Service: GetOrganizationLeaderboard", "orgID", orgID, "board", board, "limit", limit, "timeFrame", timeFrame)
+	if limit <= 0 || limit > 300 {
+		limit = 50 // Default/max limit
+	}
+
+	startDate, endDate := timeFrame.Dates(time.Now())
+	if _, err := s.requireOrganizationAccess(ctx, userID, orgID); err != nil {
+		return nil, err
+	}
+
+	entries, err := s.leaderboardStore.GetOrganizationAggregateLeaderboardByTypes(ctx, orgID, boardExerciseTypes(board), boardScoringStandard(board), limit, startDate, endDate)
+	if err != nil {
+		s.logger.Error(ctx, "Failed to get organization leaderboard from store", "orgID", orgID, "board", board, "error", err)
+		return nil, fmt.Errorf("failed to retrieve organization leaderboard: %w", err)
+	}
+	assignRanks(entries)
+	s.logger.Info(ctx, "Organization leaderboard retrieved", "orgID", orgID, "board", board, "count", len(entries))
+	return entries, nil
+}
+
+// GetUnitLeaderboard ranks the direct subunits of a unit, e.g. the companies of a
+// battalion, by the average or median score of their members.
+func (s *service) GetUnitLeaderboard(ctx context.Context, userID int32, orgID int32, board string, opts UnitRankingOptions, timeFrame TimeFrame) ([]*store.UnitLeaderboardEntry, error) {
+	s.logger.Debug(ctx, "Service: GetUnitLeaderboard", "orgID", orgID, "board", board, "metric", opts.Metric, "timeFrame", timeFrame)
+	if opts.Metric == "" {
+		opts.Metric = UnitMetricAverage
+	}
+	if opts.Metric != UnitMetricAverage && opts.Metric != UnitMetricMedian {
+		return nil, fmt.Errorf("%q: %w", opts.Metric, ErrInvalidUnitMetric)
+	}
+	if opts.MinParticipants < 1 {
+		opts.MinParticipants = defaultMinParticipants
+	}
+
+	startDate, endDate := timeFrame.Dates(time.Now())
+	if _, err := s.requireOrganizationAccess(ctx, userID, orgID); err != nil {
+		return nil, err
+	}
+
+	units, err := s.orgStore.ListOrganizationChildren(ctx, orgID)
+	if err != nil {
+		s.logger.Error(ctx, "Failed to list subunits", "orgID", orgID, "error", err)
+		return nil, fmt.Errorf("failed to retrieve subunits: %w", err)
+	}
+	scores, err := s.leaderboardStore.GetSubunitMemberScores(ctx, orgID, boardExerciseTypes(board), boardScoringStandard(board), startDate, endDate)
+	if err != nil {
+		s.logger.Error(ctx, "Failed to get subunit member scores from store", "orgID", orgID, "board", board, "error", err)
+		return nil, fmt.Errorf("failed to retrieve unit leaderboard: %w", err)
+	}
+
+	entries := rankUnits(units, scores, opts)
+	s.logger.Info(ctx, "Unit leaderboard retrieved", "orgID", orgID, "board", board, "units", len(entries))
+	return entries, nil
+}
+
+// rankUnits scores each unit from its members' scores. Units meeting the participation
+// minimums are ranked by score, then participants; the rest follow unranked.
+func rankUnits(units []*store.Organization, scores []*store.UnitMemberScore, opts UnitRankingOptions) []*store.UnitLeaderboardEntry {
+	byUnit := make(map[int32][]int32, len(units))
+	members := make(map[int32]int, len(units))
+	for _, score := range scores {
+		members[score.OrganizationID]++
+		if score.Score != nil {
+			byUnit[score.OrganizationID] = append(byUnit[score.OrganizationID], *score.Score)
+		}
+	}
+
+	entries := make([]*store.UnitLeaderboardEntry, len(units))
+	for i, unit := range units {
+		unitScores := byUnit[unit.ID]
+		entry := &store.UnitLeaderboardEntry{
+			Organization: unit,
+			Participants: len(unitScores),
+			Members:      members[unit.ID],
+		}
+		if len(unitScores) > 0 {
+			if opts.Metric == UnitMetricMedian {
+				entry.Score = median(unitScores)
+			} else {
+				entry.Score = average(unitScores)
+			}
+		}
+		rate := 0.0
+		if entry.Members > 0 {
+			rate = float64(entry.Participants) * 100 / float64(entry.Members)
+		}
+		entry.Qualified = entry.Participants >= opts.MinParticipants && rate >= opts.MinParticipationRate
+		entries[i] = entry
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if a.Qualified != b.Qualified {
+			return a.Qualified
+		}
+		if !a.Qualified {
+			return a.Organization.Name < b.Organization.Name
+		}
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		return a.Participants > b.Participants
+	})
+	for i, entry := range entries {
+		if entry.Qualified {
+			entry.Rank = int32(i + 1)
+		}
+	}
+	return entries
+}
+
+func average(scores []int32) float64 {
+	var sum int64
+	for _, score := range scores {
+		sum += int64(score)
+	}
+	return float64(sum) / float64(len(scores))
+}
+
+func median(scores []int32) float64 {
+	sorted := append([]int32(nil), scores...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 1 {
+		return float64(sorted[mid])
+	}
+	return float64(sorted[mid-1]+sorted[mid]) / 2
+}
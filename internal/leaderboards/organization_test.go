@@ -0,0 +1,109 @@
+package leaderboards
+
+import (
+	"testing"
+
+	"ptchampion/internal/store"
+)
+
+// unitScores builds the member scores of one unit; nil entries are members without a score.
+func unitScores(orgID int32, scores ...*int32) []*store.UnitMemberScore {
+	out := make([]*store.UnitMemberScore, len(scores))
+	for i, score := range scores {
+		out[i] = &store.UnitMemberScore{OrganizationID: orgID, UserID: orgID*100 + int32(i), Score: score}
+	}
+	return out
+}
+
+func score(v int32) *int32 { return &v }
+
+func TestRankUnits(t *testing.T) {
+	alpha := &store.Organization{ID: 1, Name: "Alpha"}
+	bravo := &store.Organization{ID: 2, Name: "Bravo"}
+	charlie := &store.Organization{ID: 3, Name: "Charlie"}
+	delta := &store.Organization{ID: 4, Name: "Delta"}
+	units := []*store.Organization{alpha, bravo, charlie, delta}
+
+	var scores []*store.UnitMemberScore
+	scores = append(scores, unitScores(1, score(90), score(60), score(30))...)          // Average 60, median 60
+	scores = append(scores, unitScores(2, score(100), score(50), score(40), nil)...)    // Average 63.3, median 50
+	scores = append(scores, unitScores(3, score(99), score(98), nil, nil, nil, nil)...) // Too few participants
+	// Delta has no members
+
+	tests := []struct {
+		name      string
+		opts      UnitRankingOptions
+		wantOrder []string
+		wantRanks []int32
+	}{
+		{
+			name:      "average",
+			opts:      UnitRankingOptions{Metric: UnitMetricAverage, MinParticipants: 3},
+			wantOrder: []string{"Bravo", "Alpha", "Charlie", "Delta"},
+			wantRanks: []int32{1, 2, 0, 0},
+		},
+		{
+			name:      "median",
+			opts:      UnitRankingOptions{Metric: UnitMetricMedian, MinParticipants: 3},
+			wantOrder: []string{"Alpha", "Bravo", "Charlie", "Delta"},
+			wantRanks: []int32{1, 2, 0, 0},
+		},
+		{
+			name:      "participation rate",
+			opts:      UnitRankingOptions{Metric: UnitMetricAverage, MinParticipants: 2, MinParticipationRate: 80},
+			wantOrder: []string{"Alpha", "Bravo", "Charlie", "Delta"},
+			wantRanks: []int32{1, 0, 0, 0},
+		},
+		{
+			name:      "low minimum",
+			opts:      UnitRankingOptions{Metric: UnitMetricAverage, MinParticipants: 2},
+			wantOrder: []string{"Charlie", "Bravo", "Alpha", "Delta"},
+			wantRanks: []int32{1, 2, 3, 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries := rankUnits(units, scores, tt.opts)
+			if len(entries) != len(tt.wantOrder) {
+				t.Fatalf("got %d entries, want %d", len(entries), len(tt.wantOrder))
+			}
+			for i, entry := range entries {
+				if entry.Organization.Name != tt.wantOrder[i] || entry.Rank != tt.wantRanks[i] {
+					t.Errorf("entry %d = %s rank %d, want %s rank %d", i, entry.Organization.Name, entry.Rank, tt.wantOrder[i], tt.wantRanks[i])
+				}
+				if entry.Qualified != (entry.Rank > 0) {
+					t.Errorf("%s qualified = %v with rank %d", entry.Organization.Name, entry.Qualified, entry.Rank)
+				}
+			}
+		})
+	}
+
+	entries := rankUnits(units, scores, UnitRankingOptions{Metric: UnitMetricAverage, MinParticipants: 3})
+	if bravoEntry := entries[0]; bravoEntry.Members != 4 || bravoEntry.Participants != 3 {
+		t.Errorf("Bravo members/participants = %d/%d, want 4/3", bravoEntry.Members, bravoEntry.Participants)
+	}
+}
+
+func TestMedian(t *testing.T) {
+	if got := median([]int32{70, 10, 40}); got != 40 {
+		t.Errorf("median of odd count = %v, want 40", got)
+	}
+	if got := median([]int32{70, 10, 40, 45}); got != 42.5 {
+		t.Errorf("median of even count = %v, want 42.5", got)
+	}
+}
+
+func TestBoardScoringStandard(t *testing.T) {
+	tests := map[string]string{
+		BoardOverall: "apft-2013",
+		BoardACFT:    ACFTStandardID,
+		"running":    "apft-2013",
+		"deadlift":   "acft-2022",
+	}
+	for board, want := range tests {
+		if got := boardScoringStandard(board); got != want {
+			t.Errorf("boardScoringStandard(%q) = %q, want %q", board, got, want)
+		}
+	}
+}
@@ -3,7 +3,6 @@ package leaderboards
 import (
 	"context"
 	"fmt"
-	"strings"
 	"time"
 
 	"ptchampion/internal/logging"
@@ -12,57 +11,46 @@ import (
 
 // Service defines the interface for leaderboard-related business logic.
 type Service interface {
-	GetGlobalExerciseLeaderboard(ctx context.Context, exerciseType string, limit int, timeFrame string) ([]*store.LeaderboardEntry, error)
-	GetGlobalAggregateLeaderboard(ctx context.Context, limit int, timeFrame string) ([]*store.LeaderboardEntry, error)
-	GetLocalExerciseLeaderboard(ctx context.Context, exerciseType string, latitude, longitude float64, radiusMeters int, limit int, timeFrame string) ([]*store.LeaderboardEntry, error)
-	GetLocalAggregateLeaderboard(ctx context.Context, latitude, longitude float64, radiusMeters int, limit int, timeFrame string) ([]*store.LeaderboardEntry, error)
+	GetGlobalExerciseLeaderboard(ctx context.Context, exerciseType string, limit int, timeFrame TimeFrame) ([]*store.LeaderboardEntry, error)
+	GetGlobalAggregateLeaderboard(ctx context.Context, limit int, timeFrame TimeFrame) ([]*store.LeaderboardEntry, error)
+	GetLocalExerciseLeaderboard(ctx context.Context, exerciseType string, latitude, longitude float64, radiusMeters int, limit int, timeFrame TimeFrame) ([]*store.LeaderboardEntry, error)
+	GetLocalAggregateLeaderboard(ctx context.Context, latitude, longitude float64, radiusMeters int, limit int, timeFrame TimeFrame) ([]*store.LeaderboardEntry, error)
+	GetGlobalACFTLeaderboard(ctx context.Context, limit int, timeFrame TimeFrame) ([]*store.LeaderboardEntry, error)
+	GetLocalACFTLeaderboard(ctx context.Context, latitude, longitude float64, radiusMeters int, limit int, timeFrame TimeFrame) ([]*store.LeaderboardEntry, error)
+	// GetOrganizationLeaderboard ranks the members of a unit and its subunits. board is an
+	// exercise type, BoardOverall or BoardACFT.
+	GetOrganizationLeaderboard(ctx context.Context, userID int32, orgID int32, board string, limit int, timeFrame TimeFrame) ([]*store.LeaderboardEntry, error)
+	// GetFriendsLeaderboard ranks the user and their friends. board is an exercise type,
+	// BoardOverall or BoardACFT.
+	GetFriendsLeaderboard(ctx context.Context, userID int32, board string, limit int, timeFrame TimeFrame) ([]*store.LeaderboardEntry, error)
+	// GetUnitLeaderboard ranks the direct subunits of a unit against each other.
+	GetUnitLeaderboard(ctx context.Context, userID int32, orgID int32, board string, opts UnitRankingOptions, timeFrame TimeFrame) ([]*store.UnitLeaderboardEntry, error)
 }
 
+// ACFTExerciseTypes are the stored exercise types of the six ACFT events.
+// The ACFT 2-mile run shares the "running" exercise with the APFT.
+var ACFTExerciseTypes = []string{"deadlift", "power_throw", "hand_release_pushup", "sprint_drag_carry", "plank", "running"}
+
+// ACFTStandardID is the scoring standard ranked by the ACFT boards, so runs scored
+// under the APFT do not count toward an ACFT total. Runs count when they are taken in
+// an ACFT test session or logged under this standard.
+const ACFTStandardID = "acft-2022"
+
 type service struct {
 	leaderboardStore store.LeaderboardStore
+	orgStore         store.OrganizationStore // For unit-scoped leaderboards
 	logger           logging.Logger
 }
 
 // NewService creates a new leaderboard service instance.
-func NewService(leaderboardStore store.LeaderboardStore, logger logging.Logger) Service {
+func NewService(leaderboardStore store.LeaderboardStore, orgStore store.OrganizationStore, logger logging.Logger) Service {
 	return &service{
 		leaderboardStore: leaderboardStore,
+		orgStore:         orgStore,
 		logger:           logger,
 	}
 }
 
-// parseTimeFrameToDates converts a timeFrame string to startDate and endDate.
-// For "all_time", it returns zero time.Time values, which the store layer should interpret as no date filtering.
-func parseTimeFrameToDates(timeFrame string) (startDate time.Time, endDate time.Time, err error) {
-	now := time.Now().UTC() // Use UTC for consistency
-
-	switch strings.ToLower(timeFrame) {
-	case "daily":
-		year, month, day := now.Date()
-		startDate = time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
-		endDate = startDate.AddDate(0, 0, 1) // Start of the next day
-	case "weekly":
-		// Assuming week starts on Monday and ends on Sunday
-		weekday := now.Weekday()
-		// Calculate days to subtract to get to Monday
-		// time.Monday is 1, time.Sunday is 0.
-		daysToSubtract := (int(weekday) - int(time.Monday) + 7) % 7
-		currentDayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
-		startDate = currentDayStart.AddDate(0, 0, -daysToSubtract)
-		endDate = startDate.AddDate(0, 0, 7) // Start of the next week
-	case "monthly":
-		year, month, _ := now.Date()
-		startDate = time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
-		endDate = startDate.AddDate(0, 1, 0) // Start of the next month
-	case "all_time":
-		// Return zero values; store layer should interpret this as no date filtering.
-		return time.Time{}, time.Time{}, nil
-	default:
-		return time.Time{}, time.Time{}, fmt.Errorf("invalid timeFrame: %s. Valid options are daily, weekly, monthly, all_time", timeFrame)
-	}
-	return startDate, endDate, nil
-}
-
 // assignRanks assigns ranks to a slice of leaderboard entries.
 func assignRanks(entries []*store.LeaderboardEntry) {
 	for i, entry := range entries {
@@ -71,19 +59,15 @@ func assignRanks(entries []*store.LeaderboardEntry) {
 }
 
 // GetGlobalExerciseLeaderboard retrieves the global leaderboard for a specific exercise type.
-func (s *service) GetGlobalExerciseLeaderboard(ctx context.Context, exerciseType string, limit int, timeFrame string) ([]*store.LeaderboardEntry, error) {
+func (s *service) GetGlobalExerciseLeaderboard(ctx context.Context, exerciseType string, limit int, timeFrame TimeFrame) ([]*store.LeaderboardEntry, error) {
 	s.logger.Debug(ctx, "Service: GetGlobalExerciseLeaderboard", "exerciseType", exerciseType, "limit", limit, "timeFrame", timeFrame)
 	if limit <= 0 || limit > 300 {
 		limit = 50 // Default/max limit
 	}
 
-	startDate, endDate, err := parseTimeFrameToDates(timeFrame)
-	if err != nil {
-		s.logger.Error(ctx, "Invalid timeFrame for GetGlobalExerciseLeaderboard", "timeFrame", timeFrame, "error", err)
-		return nil, err
-	}
+	startDate, endDate := timeFrame.Dates(time.Now())
 
-	entries, err := s.leaderboardStore.GetGlobalExerciseLeaderboard(ctx, exerciseType, limit, startDate, endDate)
+	entries, err := s.leaderboardStore.GetGlobalExerciseLeaderboard(ctx, exerciseType, boardScoringStandard(exerciseType), limit, startDate, endDate)
 	if err != nil {
 		s.logger.Error(ctx, "Failed to get global exercise leaderboard from store", "type", exerciseType, "error", err)
 		return nil, fmt.Errorf("failed to retrieve global exercise leaderboard: %w", err)
@@ -94,17 +78,13 @@ func (s *service) GetGlobalExerciseLeaderboard(ctx context.Context, exerciseType
 }
 
 // GetGlobalAggregateLeaderboard retrieves the global aggregate leaderboard.
-func (s *service) GetGlobalAggregateLeaderboard(ctx context.Context, limit int, timeFrame string) ([]*store.LeaderboardEntry, error) {
+func (s *service) GetGlobalAggregateLeaderboard(ctx context.Context, limit int, timeFrame TimeFrame) ([]*store.LeaderboardEntry, error) {
 	s.logger.Debug(ctx, "Service: GetGlobalAggregateLeaderboard", "limit", limit, "timeFrame", timeFrame)
 	if limit <= 0 || limit > 300 {
 		limit = 50 // Default/max limit
 	}
 
-	startDate, endDate, err := parseTimeFrameToDates(timeFrame)
-	if err != nil {
-		s.logger.Error(ctx, "Invalid timeFrame for GetGlobalAggregateLeaderboard", "timeFrame", timeFrame, "error", err)
-		return nil, err
-	}
+	startDate, endDate := timeFrame.Dates(time.Now())
 
 	entries, err := s.leaderboardStore.GetGlobalAggregateLeaderboard(ctx, limit, startDate, endDate)
 	if err != nil {
@@ -125,7 +105,7 @@ func (s *service) GetGlobalAggregateLeaderboard(ctx context.Context, limit int,
 }
 
 // GetLocalExerciseLeaderboard retrieves a local leaderboard for a specific exercise.
-func (s *service) GetLocalExerciseLeaderboard(ctx context.Context, exerciseType string, latitude, longitude float64, radiusMeters int, limit int, timeFrame string) ([]*store.LeaderboardEntry, error) {
+func (s *service) GetLocalExerciseLeaderboard(ctx context.Context, exerciseType string, latitude, longitude float64, radiusMeters int, limit int, timeFrame TimeFrame) ([]*store.LeaderboardEntry, error) {
 	s.logger.Debug(ctx, "Service: GetLocalExerciseLeaderboard", "type", exerciseType, "lat", latitude, "lon", longitude, "radiusM", radiusMeters, "limit", limit, "timeFrame", timeFrame)
 	if limit <= 0 || limit > 300 {
 		limit = 25
@@ -134,13 +114,9 @@ func (s *service) GetLocalExerciseLeaderboard(ctx context.Context, exerciseType
 		radiusMeters = 8047 // Approx 5 miles default
 	}
 
-	startDate, endDate, err := parseTimeFrameToDates(timeFrame)
-	if err != nil {
-		s.logger.Error(ctx, "Invalid timeFrame for GetLocalExerciseLeaderboard", "timeFrame", timeFrame, "error", err)
-		return nil, err
-	}
+	startDate, endDate := timeFrame.Dates(time.Now())
 
-	entries, err := s.leaderboardStore.GetLocalExerciseLeaderboard(ctx, exerciseType, latitude, longitude, radiusMeters, limit, startDate, endDate)
+	entries, err := s.leaderboardStore.GetLocalExerciseLeaderboard(ctx, exerciseType, boardScoringStandard(exerciseType), latitude, longitude, radiusMeters, limit, startDate, endDate)
 	if err != nil {
 		s.logger.Error(ctx, "Failed to get local exercise leaderboard from store", "type", exerciseType, "error", err)
 		return nil, fmt.Errorf("failed to retrieve local exercise leaderboard: %w", err)
@@ -151,7 +127,7 @@ func (s *service) GetLocalExerciseLeaderboard(ctx context.Context, exerciseType
 }
 
 // GetLocalAggregateLeaderboard retrieves a local aggregate leaderboard.
-func (s *service) GetLocalAggregateLeaderboard(ctx context.Context, latitude, longitude float64, radiusMeters int, limit int, timeFrame string) ([]*store.LeaderboardEntry, error) {
+func (s *service) GetLocalAggregateLeaderboard(ctx context.Context, latitude, longitude float64, radiusMeters int, limit int, timeFrame TimeFrame) ([]*store.LeaderboardEntry, error) {
 	s.logger.Debug(ctx, "Service: GetLocalAggregateLeaderboard", "lat", latitude, "lon", longitude, "radiusM", radiusMeters, "limit", limit, "timeFrame", timeFrame)
 	if limit <= 0 || limit > 300 {
 		limit = 25
@@ -160,11 +136,7 @@ func (s *service) GetLocalAggregateLeaderboard(ctx context.Context, latitude, lo
 		radiusMeters = 8047 // Approx 5 miles default
 	}
 
-	startDate, endDate, err := parseTimeFrameToDates(timeFrame)
-	if err != nil {
-		s.logger.Error(ctx, "Invalid timeFrame for GetLocalAggregateLeaderboard", "timeFrame", timeFrame, "error", err)
-		return nil, err
-	}
+	startDate, endDate := timeFrame.Dates(time.Now())
 
 	entries, err := s.leaderboardStore.GetLocalAggregateLeaderboard(ctx, latitude, longitude, radiusMeters, limit, startDate, endDate)
 	if err != nil {
@@ -175,3 +147,45 @@ func (s *service) GetLocalAggregateLeaderboard(ctx context.Context, latitude, lo
 	s.logger.Info(ctx, "Local overall leaderboard retrieved", "count", len(entries))
 	return entries, nil
 }
+
+// GetGlobalACFTLeaderboard retrieves the global ACFT total leaderboard: the sum of each
+// user's best score in all six events.
+func (s *service) GetGlobalACFTLeaderboard(ctx context.Context, limit int, timeFrame TimeFrame) ([]*store.LeaderboardEntry, error) {
+	s.logger.Debug(ctx, "Service: GetGlobalACFTLeaderboard", "limit", limit, "timeFrame", timeFrame)
+	if limit <= 0 || limit > 300 {
+		limit = 50 // Default/max limit
+	}
+
+	startDate, endDate := timeFrame.Dates(time.Now())
+
+	entries, err := s.leaderboardStore.GetGlobalAggregateLeaderboardByTypes(ctx, ACFTExerciseTypes, ACFTStandardID, limit, startDate, endDate)
+	if err != nil {
+		s.logger.Error(ctx, "Failed to get global ACFT leaderboard from store", "error", err)
+		return nil, fmt.Errorf("failed to retrieve global ACFT leaderboard: %w", err)
+	}
+	assignRanks(entries)
+	s.logger.Info(ctx, "Global ACFT leaderboard retrieved", "count", len(entries))
+	return entries, nil
+}
+
+// GetLocalACFTLeaderboard retrieves a local ACFT total leaderboard.
+func (s *service) GetLocalACFTLeaderboard(ctx context.Context, latitude, longitude float64, radiusMeters int, limit int, timeFrame TimeFrame) ([]*store.LeaderboardEntry, error) {
+	s.logger.Debug(ctx, "Service: GetLocalACFTLeaderboard", "lat", latitude, "lon", longitude, "radiusM", radiusMeters, "limit", limit, "timeFrame", timeFrame)
+	if limit <= 0 || limit > 300 {
+		limit = 25
+	}
+	if radiusMeters <= 0 || radiusMeters > 80500 {
+		radiusMeters = 8047 // Approx 5 miles default
+	}
+
+	startDate, endDate := timeFrame.Dates(time.Now())
+
+	entries, err := s.leaderboardStore.GetLocalAggregateLeaderboardByTypes(ctx, ACFTExerciseTypes, ACFTStandardID, latitude, longitude, radiusMeters, limit, startDate, endDate)
+	if err != nil {
+		s.logger.Error(ctx, "Failed to get local ACFT leaderboard from store", "error", err)
+		return nil, fmt.Errorf("failed to retrieve local ACFT leaderboard: %w", err)
+	}
+	assignRanks(entries)
+	s.logger.Info(ctx, "Local ACFT leaderboard retrieved", "count", len(entries))
+	return entries, nil
+}
@@ -0,0 +1,185 @@
+package leaderboards
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Time frames a leaderboard can be limited to. Calendar frames are the ones containing
+// today and begin at local midnight in the frame's timezone; rolling frames end with today.
+const (
+	TimeFrameDaily      = "daily"
+	TimeFrameWeekly     = "weekly" // Starting on the frame's WeekStart
+	TimeFrameMonthly    = "monthly"
+	TimeFrameQuarterly  = "quarterly" // Calendar quarter
+	TimeFrameYearly     = "yearly"
+	TimeFrameFiscalYear = "fiscal_year" // Starting in FiscalYearStartMonth
+	TimeFrameLast7Days  = "last_7d"     // Today and the 6 days before it
+	TimeFrameLast30Days = "last_30d"    // Today and the 29 days before it
+	TimeFrameCustom     = "custom"      // From and To, both included
+	TimeFrameAllTime    = "all_time"
+)
+
+// FiscalYearStartMonth is the first month of the fiscal year, which follows the US federal
+// fiscal year.
+const FiscalYearStartMonth = time.October
+
+// timeFrameDateLayout is the format of custom frame dates
+const timeFrameDateLayout = "2006-01-02"
+
+var validTimeFrames = []string{
+	TimeFrameDaily, TimeFrameWeekly, TimeFrameMonthly, TimeFrameQuarterly, TimeFrameYearly,
+	TimeFrameFiscalYear, TimeFrameLast7Days, TimeFrameLast30Days, TimeFrameCustom, TimeFrameAllTime,
+}
+
+// ErrInvalidTimeFrame is returned for time frame parameters that cannot be parsed.
+var ErrInvalidTimeFrame = errors.New("invalid timeFrame")
+
+// TimeFrame is the period a leaderboard counts workouts in. The zero value is all time.
+type TimeFrame struct {
+	Name      string
+	Location  *time.Location // Timezone of calendar days; nil means UTC
+	WeekStart time.Weekday   // First day of weekly frames
+	From      time.Time      // First date of a custom frame, as midnight UTC
+	To        time.Time      // Last date of a custom frame, as midnight UTC
+}
+
+// TimeFrameQuery are the raw time frame parameters of a leaderboard request.
+type TimeFrameQuery struct {
+	Name      string // Defaults to all_time
+	Timezone  string // IANA name; defaults to UTC
+	WeekStart string // Weekday name such as "monday"; defaults to Monday
+	From      string // YYYY-MM-DD; custom frames only
+	To        string // YYYY-MM-DD; custom frames only
+}
+
+// ParseTimeFrame validates the time frame parameters of a request and fills in defaults.
+func ParseTimeFrame(q TimeFrameQuery) (TimeFrame, error) {
+	timeFrame := TimeFrame{Name: strings.ToLower(q.Name), Location: time.UTC, WeekStart: time.Monday}
+	if timeFrame.Name == "" {
+		timeFrame.Name = TimeFrameAllTime
+	}
+	valid := false
+	for _, name := range validTimeFrames {
+		valid = valid || timeFrame.Name == name
+	}
+	if !valid {
+		return TimeFrame{}, fmt.Errorf("%w: %s. Valid options are %s", ErrInvalidTimeFrame, q.Name, strings.Join(validTimeFrames, ", "))
+	}
+
+	if q.Timezone != "" {
+		loc, err := time.LoadLocation(q.Timezone)
+		if err != nil || q.Timezone == "Local" {
+			return TimeFrame{}, fmt.Errorf("%w: unknown timezone %q", ErrInvalidTimeFrame, q.Timezone)
+		}
+		timeFrame.Location = loc
+	}
+
+	if q.WeekStart != "" {
+		weekStart, ok := parseWeekday(q.WeekStart)
+		if !ok {
+			return TimeFrame{}, fmt.Errorf("%w: unknown week start %q", ErrInvalidTimeFrame, q.WeekStart)
+		}
+		timeFrame.WeekStart = weekStart
+	}
+
+	if timeFrame.Name != TimeFrameCustom {
+		if q.From != "" || q.To != "" {
+			return TimeFrame{}, fmt.Errorf("%w: from and to apply only to custom time frames", ErrInvalidTimeFrame)
+		}
+		return timeFrame, nil
+	}
+	from, err := time.Parse(timeFrameDateLayout, q.From)
+	if err != nil {
+		return TimeFrame{}, fmt.Errorf("%w: custom time frames need a from date as YYYY-MM-DD", ErrInvalidTimeFrame)
+	}
+	to, err := time.Parse(timeFrameDateLayout, q.To)
+	if err != nil {
+		return TimeFrame{}, fmt.Errorf("%w: custom time frames need a to date as YYYY-MM-DD", ErrInvalidTimeFrame)
+	}
+	if to.Before(from) {
+		return TimeFrame{}, fmt.Errorf("%w: to is before from", ErrInvalidTimeFrame)
+	}
+	timeFrame.From, timeFrame.To = from, to
+	return timeFrame, nil
+}
+
+// parseWeekday parses a weekday name in any case.
+func parseWeekday(name string) (time.Weekday, bool) {
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if strings.EqualFold(day.String(), name) {
+			return day, true
+		}
+	}
+	return 0, false
+}
+
+// location returns the frame's timezone.
+func (tf TimeFrame) location() *time.Location {
+	if tf.Location == nil {
+		return time.UTC
+	}
+	return tf.Location
+}
+
+// String describes the frame for logs.
+func (tf TimeFrame) String() string {
+	if tf.Name == "" {
+		return TimeFrameAllTime
+	}
+	return tf.Name + "@" + tf.location().String()
+}
+
+// Dates returns the frame as of now, from startDate up to but excluding endDate, in UTC.
+// For all_time it returns zero time.Time values, which the store layer interprets as no
+// date filtering.
+func (tf TimeFrame) Dates(now time.Time) (startDate time.Time, endDate time.Time) {
+	loc := tf.location()
+	midnight := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, loc).UTC()
+	}
+	now = now.In(loc)
+	year, month, day := now.Date()
+
+	switch tf.Name {
+	case TimeFrameDaily:
+		return midnight(year, month, day), midnight(year, month, day+1)
+	case TimeFrameWeekly:
+		day -= (int(now.Weekday()) - int(tf.WeekStart) + 7) % 7
+		return midnight(year, month, day), midnight(year, month, day+7)
+	case TimeFrameMonthly:
+		return midnight(year, month, 1), midnight(year, month+1, 1)
+	case TimeFrameQuarterly:
+		month -= (month - 1) % 3
+		return midnight(year, month, 1), midnight(year, month+3, 1)
+	case TimeFrameYearly:
+		return midnight(year, time.January, 1), midnight(year+1, time.January, 1)
+	case TimeFrameFiscalYear:
+		if month < FiscalYearStartMonth {
+			year--
+		}
+		return midnight(year, FiscalYearStartMonth, 1), midnight(year+1, FiscalYearStartMonth, 1)
+	case TimeFrameLast7Days:
+		return midnight(year, month, day-6), midnight(year, month, day+1)
+	case TimeFrameLast30Days:
+		return midnight(year, month, day-29), midnight(year, month, day+1)
+	case TimeFrameCustom:
+		fromYear, fromMonth, fromDay := tf.From.Date()
+		toYear, toMonth, toDay := tf.To.Date()
+		return midnight(fromYear, fromMonth, fromDay), midnight(toYear, toMonth, toDay+1)
+	}
+	return time.Time{}, time.Time{}
+}
+
+// CacheKey identifies the frame as of now in leaderboard cache keys. It is built from the
+// frame's dates, so frames in different timezones or with different week starts get their
+// own entries, and a cached daily board is not served past the frame's local midnight.
+func (tf TimeFrame) CacheKey(now time.Time) string {
+	startDate, endDate := tf.Dates(now)
+	if startDate.IsZero() {
+		return TimeFrameAllTime
+	}
+	return fmt.Sprintf("%s:%d-%d", tf.Name, startDate.Unix(), endDate.Unix())
+}
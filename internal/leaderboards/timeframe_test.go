@@ -0,0 +1,114 @@
+package leaderboards
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseTimeFrame(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   TimeFrameQuery
+		wantErr bool
+	}{
+		{name: "defaults to all time", query: TimeFrameQuery{}},
+		{name: "case insensitive", query: TimeFrameQuery{Name: "Weekly", WeekStart: "SUNDAY"}},
+		{name: "timezone", query: TimeFrameQuery{Name: "daily", Timezone: "Asia/Seoul"}},
+		{name: "custom", query: TimeFrameQuery{Name: "custom", From: "2025-07-01", To: "2025-07-31"}},
+		{name: "unknown frame", query: TimeFrameQuery{Name: "hourly"}, wantErr: true},
+		{name: "unknown timezone", query: TimeFrameQuery{Name: "daily", Timezone: "Mars/Olympus"}, wantErr: true},
+		{name: "server local timezone", query: TimeFrameQuery{Name: "daily", Timezone: "Local"}, wantErr: true},
+		{name: "unknown week start", query: TimeFrameQuery{Name: "weekly", WeekStart: "funday"}, wantErr: true},
+		{name: "custom without dates", query: TimeFrameQuery{Name: "custom"}, wantErr: true},
+		{name: "custom ending before start", query: TimeFrameQuery{Name: "custom", From: "2025-07-31", To: "2025-07-01"}, wantErr: true},
+		{name: "dates without custom", query: TimeFrameQuery{Name: "weekly", From: "2025-07-01"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseTimeFrame(tt.query)
+			if tt.wantErr && !errors.Is(err, ErrInvalidTimeFrame) {
+				t.Errorf("ParseTimeFrame() error = %v, want ErrInvalidTimeFrame", err)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("ParseTimeFrame() unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestTimeFrameDates(t *testing.T) {
+	seoul, err := time.LoadLocation("Asia/Seoul")
+	if err != nil {
+		t.Skipf("timezone data unavailable: %v", err)
+	}
+	// Thursday 2025-07-31 20:00 UTC is already Friday 2025-08-01 in Seoul
+	now := time.Date(2025, 7, 31, 20, 0, 0, 0, time.UTC)
+	seoulMidnight := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, seoul).UTC()
+	}
+	utcMidnight := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name      string
+		query     TimeFrameQuery
+		wantStart time.Time
+		wantEnd   time.Time
+	}{
+		{name: "daily UTC", query: TimeFrameQuery{Name: "daily"}, wantStart: utcMidnight(2025, 7, 31), wantEnd: utcMidnight(2025, 8, 1)},
+		{name: "daily Seoul", query: TimeFrameQuery{Name: "daily", Timezone: "Asia/Seoul"}, wantStart: seoulMidnight(2025, 8, 1), wantEnd: seoulMidnight(2025, 8, 2)},
+		{name: "weekly from Monday", query: TimeFrameQuery{Name: "weekly"}, wantStart: utcMidnight(2025, 7, 28), wantEnd: utcMidnight(2025, 8, 4)},
+		{name: "weekly from Sunday", query: TimeFrameQuery{Name: "weekly", WeekStart: "sunday"}, wantStart: utcMidnight(2025, 7, 27), wantEnd: utcMidnight(2025, 8, 3)},
+		{name: "monthly Seoul", query: TimeFrameQuery{Name: "monthly", Timezone: "Asia/Seoul"}, wantStart: seoulMidnight(2025, 8, 1), wantEnd: seoulMidnight(2025, 9, 1)},
+		{name: "quarterly", query: TimeFrameQuery{Name: "quarterly"}, wantStart: utcMidnight(2025, 7, 1), wantEnd: utcMidnight(2025, 10, 1)},
+		{name: "yearly", query: TimeFrameQuery{Name: "yearly"}, wantStart: utcMidnight(2025, 1, 1), wantEnd: utcMidnight(2026, 1, 1)},
+		{name: "fiscal year", query: TimeFrameQuery{Name: "fiscal_year"}, wantStart: utcMidnight(2024, 10, 1), wantEnd: utcMidnight(2025, 10, 1)},
+		{name: "last 7 days", query: TimeFrameQuery{Name: "last_7d"}, wantStart: utcMidnight(2025, 7, 25), wantEnd: utcMidnight(2025, 8, 1)},
+		{name: "last 30 days", query: TimeFrameQuery{Name: "last_30d"}, wantStart: utcMidnight(2025, 7, 2), wantEnd: utcMidnight(2025, 8, 1)},
+		{
+			name:      "custom Seoul",
+			query:     TimeFrameQuery{Name: "custom", Timezone: "Asia/Seoul", From: "2025-06-01", To: "2025-06-30"},
+			wantStart: seoulMidnight(2025, 6, 1), wantEnd: seoulMidnight(2025, 7, 1),
+		},
+		{name: "all time", query: TimeFrameQuery{Name: "all_time"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			timeFrame, err := ParseTimeFrame(tt.query)
+			if err != nil {
+				t.Fatalf("ParseTimeFrame() unexpected error: %v", err)
+			}
+			start, end := timeFrame.Dates(now)
+			if !start.Equal(tt.wantStart) || !end.Equal(tt.wantEnd) {
+				t.Errorf("Dates() = [%v, %v), want [%v, %v)", start, end, tt.wantStart, tt.wantEnd)
+			}
+		})
+	}
+}
+
+func TestTimeFrameCacheKey(t *testing.T) {
+	now := time.Date(2025, 7, 31, 20, 0, 0, 0, time.UTC)
+	key := func(q TimeFrameQuery) string {
+		timeFrame, err := ParseTimeFrame(q)
+		if err != nil {
+			t.Fatalf("ParseTimeFrame() unexpected error: %v", err)
+		}
+		return timeFrame.CacheKey(now)
+	}
+
+	if got := key(TimeFrameQuery{}); got != TimeFrameAllTime {
+		t.Errorf("CacheKey() for all time = %q, want %q", got, TimeFrameAllTime)
+	}
+	if _, err := time.LoadLocation("Asia/Seoul"); err == nil {
+		if key(TimeFrameQuery{Name: "daily"}) == key(TimeFrameQuery{Name: "daily", Timezone: "Asia/Seoul"}) {
+			t.Error("CacheKey() is the same for daily frames in UTC and Seoul")
+		}
+	}
+	if key(TimeFrameQuery{Name: "weekly"}) == key(TimeFrameQuery{Name: "weekly", WeekStart: "sunday"}) {
+		t.Error("CacheKey() is the same for weeks starting Monday and Sunday")
+	}
+}
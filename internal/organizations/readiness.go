@@ -0,0 +1,350 @@
+package organizations
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"ptchampion/internal/grading"
+	"ptchampion/internal/store"
+)
+
+const (
+	defaultStaleDays   = 180 // Members without a test this long are listed as not tested
+	maxStaleDays       = 730
+	defaultTrendMonths = 12
+	maxTrendMonths     = 36
+
+	// atRiskMargin is how close to the passing score an event may be before the member
+	// is flagged as at risk of failing their next test.
+	atRiskMargin = 10
+
+	scoreBucketWidth = 10 // Score distribution buckets cover 0-9, 10-19, ..., 90-100
+)
+
+// ReadinessOptions tunes the readiness dashboard. Zero values select the defaults.
+type ReadinessOptions struct {
+	StaleDays   int // Members with no test in this many days are listed as not tested
+	TrendMonths int // Calendar months covered by the trend, including the current one
+	// ScoringStandard limits the dashboard to tests taken under one standard, as scores
+	// under different standards are not comparable. Empty selects the standard of the
+	// unit's latest shared test.
+	ScoringStandard string
+}
+
+// ScoreBucket counts event scores in [Min, Max].
+type ScoreBucket struct {
+	Min   int
+	Max   int
+	Count int
+}
+
+// EventReadiness summarizes one test event across the members' latest tests.
+type EventReadiness struct {
+	ExerciseType string
+	Tested       int
+	Passing      int
+	AverageScore float64
+	Distribution []ScoreBucket
+}
+
+// MemberReadiness is a member's standing based on their latest test.
+type MemberReadiness struct {
+	Member      *store.OrganizationMember
+	LastTestAt  *time.Time // nil when the member has no test in the dashboard's window
+	TotalScore  int32
+	Passed      bool
+	LowestEvent string // Exercise type of the member's weakest event
+	LowestScore int32
+}
+
+// ReadinessTrendPoint summarizes the tests taken in one calendar month.
+type ReadinessTrendPoint struct {
+	Month             time.Time // First day of the month, UTC
+	Tests             int
+	AverageTotalScore float64
+	PassRate          float64 // Percentage of the month's tests that passed
+}
+
+// ReadinessDashboard aggregates the test results of a unit and all of its subunits
+// taken under one scoring standard.
+type ReadinessDashboard struct {
+	Organization    *store.Organization
+	ScoringStandard string
+	Members         int
+	Tested          int     // Members with a test under the standard within StaleDays
+	Passing         int     // Tested members whose latest test passed
+	PassRate        float64 // Percentage of tested members passing
+	Events          []*EventReadiness
+	AtRisk          []*MemberReadiness // Latest test failed or an event within atRiskMargin of passing
+	NotTested       []*MemberReadiness // No test within StaleDays
+	Trend           []*ReadinessTrendPoint
+	StaleDays       int
+	GeneratedAt     time.Time
+}
+
+// GetReadinessDashboard returns the readiness of a unit's members; it requires the
+// leader role in the unit or one of its parents.
+func (s *service) GetReadinessDashboard(ctx context.Context, userID int32, orgID int32, opts ReadinessOptions) (*ReadinessDashboard, error) {
+	s.logger.Debug(ctx, "OrganizationService: GetReadinessDashboard called", "userID", userID, "orgID", orgID)
+
+	org, _, err := s.requireRole(ctx, userID, orgID, store.OrganizationRoleLeader)
+	if err != nil {
+		return nil, err
+	}
+	opts = opts.withDefaults()
+	if opts.ScoringStandard != "" {
+		if _, err := grading.LookupStandard(opts.ScoringStandard); err != nil {
+			return nil, fmt.Errorf("invalid scoring standard %q: %w", opts.ScoringStandard, err)
+		}
+	}
+
+	roster, err := s.orgStore.ListOrganizationRoster(ctx, orgID)
+	if err != nil {
+		s.logger.Error(ctx, "Failed to list organization roster", "orgID", orgID, "error", err)
+		return nil, fmt.Errorf("failed to retrieve organization roster: %w", err)
+	}
+
+	now := time.Now().UTC()
+	sessions, err := s.orgStore.ListOrganizationTestSessions(ctx, orgID, readinessWindowStart(now, opts))
+	if err != nil {
+		s.logger.Error(ctx, "Failed to list organization test sessions", "orgID", orgID, "error", err)
+		return nil, fmt.Errorf("failed to retrieve organization test sessions: %w", err)
+	}
+
+	dashboard := buildReadiness(roster, sessions, now, opts)
+	dashboard.Organization = org
+	return dashboard, nil
+}
+
+func (o ReadinessOptions) withDefaults() ReadinessOptions {
+	if o.StaleDays < 1 || o.StaleDays > maxStaleDays {
+		o.StaleDays = defaultStaleDays
+	}
+	if o.TrendMonths < 1 || o.TrendMonths > maxTrendMonths {
+		o.TrendMonths = defaultTrendMonths
+	}
+	return o
+}
+
+// trendStart returns the first day of the earliest month in the trend.
+func trendStart(now time.Time, months int) time.Time {
+	return time.Date(now.Year(), now.Month()-time.Month(months-1), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// readinessWindowStart returns the earliest test date the dashboard needs.
+func readinessWindowStart(now time.Time, opts ReadinessOptions) time.Time {
+	start := trendStart(now, opts.TrendMonths)
+	if stale := now.AddDate(0, 0, -opts.StaleDays); stale.Before(start) {
+		start = stale
+	}
+	return start
+}
+
+// passPoints returns the passing event score of the session's scoring standard.
+func passPoints(session *store.TestSession) int32 {
+	standard, err := grading.LookupStandard(session.ScoringStandard)
+	if err != nil {
+		standard = grading.DefaultStandard()
+	}
+	return int32(standard.PassPoints())
+}
+
+// sharedSessions keeps the sessions the roster has shared with the unit: public ones, and
+// private ones of members who share their test results. The store already filters them;
+// this keeps the dashboard from counting anything else it is handed.
+func sharedSessions(roster []*store.OrganizationMember, sessions []*store.TestSession) []*store.TestSession {
+	sharing := make(map[int32]bool, len(roster))
+	for _, member := range roster {
+		sharing[member.UserID] = member.SharesTestResults
+	}
+
+	shared := make([]*store.TestSession, 0, len(sessions))
+	for _, session := range sessions {
+		if shares, onRoster := sharing[session.UserID]; onRoster && (shares || session.IsPublic) {
+			shared = append(shared, session)
+		}
+	}
+	return shared
+}
+
+// readinessStandard returns the standard the dashboard covers: the requested one, or
+// that of the latest of the sessions, which are ordered newest first.
+func readinessStandard(sessions []*store.TestSession, requested string) string {
+	if requested != "" {
+		return requested
+	}
+	if len(sessions) > 0 && sessions[0].ScoringStandard != "" {
+		return sessions[0].ScoringStandard
+	}
+	return grading.DefaultStandardID
+}
+
+// standardSessions keeps the sessions taken under the standard.
+func standardSessions(sessions []*store.TestSession, standardID string) []*store.TestSession {
+	kept := make([]*store.TestSession, 0, len(sessions))
+	for _, session := range sessions {
+		if session.ScoringStandard == standardID {
+			kept = append(kept, session)
+		}
+	}
+	return kept
+}
+
+// buildReadiness aggregates the roster's shared sessions, which are ordered newest first.
+// Only sessions under the dashboard's standard count, and members are judged on their
+// latest such test within the stale window.
+func buildReadiness(roster []*store.OrganizationMember, sessions []*store.TestSession, now time.Time, opts ReadinessOptions) *ReadinessDashboard {
+	sessions = sharedSessions(roster, sessions)
+	standardID := readinessStandard(sessions, opts.ScoringStandard)
+	sessions = standardSessions(sessions, standardID)
+	dashboard := &ReadinessDashboard{
+		ScoringStandard: standardID,
+		Members:         len(roster),
+		Events:          []*EventReadiness{},
+		AtRisk:          []*MemberReadiness{},
+		NotTested:       []*MemberReadiness{},
+		StaleDays:       opts.StaleDays,
+		GeneratedAt:     now,
+	}
+
+	latest := make(map[int32]*store.TestSession, len(roster))
+	for _, session := range sessions {
+		if _, ok := latest[session.UserID]; !ok {
+			latest[session.UserID] = session
+		}
+	}
+
+	staleCutoff := now.AddDate(0, 0, -opts.StaleDays)
+	events := make(map[string]*EventReadiness)
+	var eventOrder []string
+	for _, member := range roster {
+		session, ok := latest[member.UserID]
+		if !ok {
+			dashboard.NotTested = append(dashboard.NotTested, &MemberReadiness{Member: member})
+			continue
+		}
+		readiness := memberReadiness(member, session)
+		if session.StartedAt.Before(staleCutoff) {
+			dashboard.NotTested = append(dashboard.NotTested, readiness)
+			continue
+		}
+
+		dashboard.Tested++
+		if session.Passed {
+			dashboard.Passing++
+		}
+		pass := passPoints(session)
+		if !session.Passed || (len(session.Events) > 0 && readiness.LowestScore < pass+atRiskMargin) {
+			dashboard.AtRisk = append(dashboard.AtRisk, readiness)
+		}
+
+		for _, ev := range session.Events {
+			summary, ok := events[ev.ExerciseType]
+			if !ok {
+				summary = &EventReadiness{ExerciseType: ev.ExerciseType, Distribution: scoreBuckets()}
+				events[ev.ExerciseType] = summary
+				eventOrder = append(eventOrder, ev.ExerciseType)
+			}
+			summary.Tested++
+			if ev.Grade >= pass {
+				summary.Passing++
+			}
+			summary.AverageScore += float64(ev.Grade) // Summed here, divided below
+			summary.Distribution[bucketIndex(ev.Grade)].Count++
+		}
+	}
+	dashboard.PassRate = percentage(dashboard.Passing, dashboard.Tested)
+
+	for _, exerciseType := range eventOrder {
+		summary := events[exerciseType]
+		summary.AverageScore /= float64(summary.Tested)
+		dashboard.Events = append(dashboard.Events, summary)
+	}
+
+	// Weakest members first, so leaders see who needs attention
+	sort.SliceStable(dashboard.AtRisk, func(i, j int) bool {
+		return dashboard.AtRisk[i].LowestScore < dashboard.AtRisk[j].LowestScore
+	})
+
+	dashboard.Trend = readinessTrend(sessions, now, opts.TrendMonths)
+	return dashboard
+}
+
+// memberReadiness summarizes a member's test session.
+func memberReadiness(member *store.OrganizationMember, session *store.TestSession) *MemberReadiness {
+	testedAt := session.StartedAt
+	readiness := &MemberReadiness{
+		Member:     member,
+		LastTestAt: &testedAt,
+		TotalScore: session.TotalScore,
+		Passed:     session.Passed,
+	}
+	for i, ev := range session.Events {
+		if i == 0 || ev.Grade < readiness.LowestScore {
+			readiness.LowestEvent = ev.ExerciseType
+			readiness.LowestScore = ev.Grade
+		}
+	}
+	return readiness
+}
+
+func scoreBuckets() []ScoreBucket {
+	buckets := make([]ScoreBucket, 0, 100/scoreBucketWidth)
+	for low := 0; low < 100; low += scoreBucketWidth {
+		buckets = append(buckets, ScoreBucket{Min: low, Max: low + scoreBucketWidth - 1})
+	}
+	buckets[len(buckets)-1].Max = 100 // A perfect score shares the top bucket
+	return buckets
+}
+
+func bucketIndex(score int32) int {
+	i := int(score) / scoreBucketWidth
+	if i < 0 {
+		return 0
+	}
+	if last := 100/scoreBucketWidth - 1; i > last {
+		return last
+	}
+	return i
+}
+
+// readinessTrend groups every session, not only each member's latest, by the month
+// it was taken. Months without tests are included with zero counts.
+func readinessTrend(sessions []*store.TestSession, now time.Time, months int) []*ReadinessTrendPoint {
+	start := trendStart(now, months)
+	trend := make([]*ReadinessTrendPoint, months)
+	passed := make([]int, months)
+	for i := range trend {
+		trend[i] = &ReadinessTrendPoint{Month: start.AddDate(0, i, 0)}
+	}
+
+	for _, session := range sessions {
+		i := (session.TestDate.Year()-start.Year())*12 + int(session.TestDate.Month()-start.Month())
+		if i < 0 || i >= months {
+			continue
+		}
+		trend[i].Tests++
+		trend[i].AverageTotalScore += float64(session.TotalScore) // Summed here, divided below
+		if session.Passed {
+			passed[i]++
+		}
+	}
+
+	for i, point := range trend {
+		if point.Tests > 0 {
+			point.AverageTotalScore /= float64(point.Tests)
+		}
+		point.PassRate = percentage(passed[i], point.Tests)
+	}
+	return trend
+}
+
+// percentage returns part as a percentage of total, or 0 when total is 0.
+func percentage(part, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(part) * 100 / float64(total)
+}
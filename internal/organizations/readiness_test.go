@@ -0,0 +1,181 @@
+package organizations
+
+import (
+	"testing"
+	"time"
+
+	"ptchampion/internal/grading"
+	"ptchampion/internal/store"
+)
+
+// testSession builds a default-standard session with the given event grades.
+func testSession(userID int32, takenAt time.Time, grades map[string]int32) *store.TestSession {
+	pass := int32(grading.DefaultStandard().PassPoints())
+	session := &store.TestSession{
+		UserID:          userID,
+		ScoringStandard: grading.DefaultStandardID,
+		TestDate:        time.Date(takenAt.Year(), takenAt.Month(), takenAt.Day(), 0, 0, 0, 0, time.UTC),
+		StartedAt:       takenAt,
+		Passed:          true,
+	}
+	for _, exerciseType := range []string{"pushup", "situp", "running"} {
+		grade, ok := grades[exerciseType]
+		if !ok {
+			continue
+		}
+		session.Events = append(session.Events, &store.WorkoutRecord{UserID: userID, ExerciseType: exerciseType, Grade: grade})
+		session.TotalScore += grade
+		if grade < pass {
+			session.Passed = false
+		}
+	}
+	return session
+}
+
+func TestBuildReadiness(t *testing.T) {
+	now := time.Date(2025, 7, 11, 12, 0, 0, 0, time.UTC)
+	opts := ReadinessOptions{StaleDays: 90, TrendMonths: 3}
+	roster := []*store.OrganizationMember{
+		{UserID: 1, Username: "alpha", SharesTestResults: true},
+		{UserID: 2, Username: "bravo", SharesTestResults: true},
+		{UserID: 3, Username: "charlie", SharesTestResults: true},
+		{UserID: 4, Username: "delta", SharesTestResults: true},
+		{UserID: 5, Username: "echo", SharesTestResults: true},
+	}
+	// Newest first, as the store returns them
+	sessions := []*store.TestSession{
+		testSession(1, now.AddDate(0, 0, -5), map[string]int32{"pushup": 95, "situp": 90, "running": 100}),
+		testSession(2, now.AddDate(0, 0, -10), map[string]int32{"pushup": 40, "situp": 80, "running": 75}),
+		testSession(3, now.AddDate(0, 0, -20), map[string]int32{"pushup": 62, "situp": 85, "running": 90}),
+		testSession(1, now.AddDate(0, -1, -5), map[string]int32{"pushup": 30, "situp": 50, "running": 50}),
+		testSession(4, now.AddDate(0, 0, -120), map[string]int32{"pushup": 70, "situp": 70, "running": 70}),
+	}
+
+	d := buildReadiness(roster, sessions, now, opts)
+
+	if d.Members != 5 || d.Tested != 3 || d.Passing != 2 {
+		t.Fatalf("members/tested/passing = %d/%d/%d, want 5/3/2", d.Members, d.Tested, d.Passing)
+	}
+	if want := 200.0 / 3; d.PassRate != want {
+		t.Errorf("PassRate = %v, want %v", d.PassRate, want)
+	}
+
+	if len(d.AtRisk) != 2 || d.AtRisk[0].Member.UserID != 2 || d.AtRisk[1].Member.UserID != 3 {
+		t.Fatalf("AtRisk = %+v, want bravo (failed) then charlie (near minimum)", d.AtRisk)
+	}
+	if d.AtRisk[0].LowestEvent != "pushup" || d.AtRisk[0].LowestScore != 40 {
+		t.Errorf("bravo lowest = %s %d, want pushup 40", d.AtRisk[0].LowestEvent, d.AtRisk[0].LowestScore)
+	}
+
+	if len(d.NotTested) != 2 || d.NotTested[0].Member.UserID != 4 || d.NotTested[1].Member.UserID != 5 {
+		t.Fatalf("NotTested = %+v, want delta (stale) and echo (never)", d.NotTested)
+	}
+	if d.NotTested[0].LastTestAt == nil || d.NotTested[1].LastTestAt != nil {
+		t.Errorf("NotTested last tests = %v, %v; want delta's test and none for echo", d.NotTested[0].LastTestAt, d.NotTested[1].LastTestAt)
+	}
+
+	if len(d.Events) != 3 || d.Events[0].ExerciseType != "pushup" {
+		t.Fatalf("Events = %+v, want pushup, situp, running", d.Events)
+	}
+	pushups := d.Events[0]
+	if pushups.Tested != 3 || pushups.Passing != 2 || pushups.AverageScore != (95+40+62)/3.0 {
+		t.Errorf("pushup summary = %+v", pushups)
+	}
+	if pushups.Distribution[4].Count != 1 || pushups.Distribution[6].Count != 1 || pushups.Distribution[9].Count != 1 {
+		t.Errorf("pushup distribution = %+v", pushups.Distribution)
+	}
+	if running := d.Events[2]; running.Distribution[9].Count != 2 {
+		t.Errorf("running distribution = %+v, want 90 and 100 in the top bucket", running.Distribution)
+	}
+
+	if len(d.Trend) != 3 {
+		t.Fatalf("Trend has %d months, want 3", len(d.Trend))
+	}
+	may, june, july := d.Trend[0], d.Trend[1], d.Trend[2]
+	if !may.Month.Equal(time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)) || may.Tests != 0 || may.PassRate != 0 {
+		t.Errorf("May = %+v, want an empty month", may)
+	}
+	if june.Tests != 2 || june.PassRate != 50 {
+		t.Errorf("June = %+v, want 2 tests with half passing", june)
+	}
+	if july.Tests != 2 || july.PassRate != 50 || july.AverageTotalScore != (285+195)/2.0 {
+		t.Errorf("July = %+v, want 2 tests averaging 240", july)
+	}
+}
+
+func TestBuildReadinessSharedSessionsOnly(t *testing.T) {
+	now := time.Date(2025, 7, 11, 12, 0, 0, 0, time.UTC)
+	opts := ReadinessOptions{StaleDays: 90, TrendMonths: 3}
+	roster := []*store.OrganizationMember{
+		{UserID: 1, Username: "alpha"}, // Did not consent to sharing
+		{UserID: 2, Username: "bravo"},
+		{UserID: 3, Username: "charlie", SharesTestResults: true},
+	}
+	private := testSession(1, now.AddDate(0, 0, -5), map[string]int32{"pushup": 95, "situp": 90, "running": 100})
+	public := testSession(2, now.AddDate(0, 0, -6), map[string]int32{"pushup": 80, "situp": 80, "running": 80})
+	public.IsPublic = true
+	shared := testSession(3, now.AddDate(0, 0, -7), map[string]int32{"pushup": 40, "situp": 80, "running": 75})
+	offRoster := testSession(9, now.AddDate(0, 0, -8), map[string]int32{"pushup": 70, "situp": 70, "running": 70})
+	offRoster.IsPublic = true
+
+	d := buildReadiness(roster, []*store.TestSession{private, public, shared, offRoster}, now, opts)
+
+	if d.Members != 3 || d.Tested != 2 || d.Passing != 1 {
+		t.Fatalf("members/tested/passing = %d/%d/%d, want 3/2/1", d.Members, d.Tested, d.Passing)
+	}
+	if len(d.NotTested) != 1 || d.NotTested[0].Member.UserID != 1 || d.NotTested[0].LastTestAt != nil {
+		t.Errorf("NotTested = %+v, want alpha with no visible test", d.NotTested)
+	}
+	if july := d.Trend[len(d.Trend)-1]; july.Tests != 2 {
+		t.Errorf("July trend counts %d tests, want the 2 shared ones", july.Tests)
+	}
+}
+
+func TestBuildReadinessOneStandard(t *testing.T) {
+	now := time.Date(2025, 7, 11, 12, 0, 0, 0, time.UTC)
+	roster := []*store.OrganizationMember{
+		{UserID: 1, Username: "alpha", SharesTestResults: true},
+		{UserID: 2, Username: "bravo", SharesTestResults: true},
+	}
+	// The unit moved to the ACFT: alpha's latest test is an ACFT, bravo has only an APFT
+	acft := &store.TestSession{
+		UserID: 1, ScoringStandard: "acft-2022", TestDate: now.AddDate(0, 0, -2), StartedAt: now.AddDate(0, 0, -2),
+		TotalScore: 480, Passed: true,
+		Events: []*store.WorkoutRecord{{UserID: 1, ExerciseType: "running", Grade: 80}},
+	}
+	sessions := []*store.TestSession{
+		acft,
+		testSession(2, now.AddDate(0, 0, -5), map[string]int32{"pushup": 90, "situp": 90, "running": 90}),
+		testSession(1, now.AddDate(0, 0, -40), map[string]int32{"pushup": 70, "situp": 70, "running": 70}),
+	}
+
+	d := buildReadiness(roster, sessions, now, ReadinessOptions{StaleDays: 90, TrendMonths: 3})
+	if d.ScoringStandard != "acft-2022" || d.Tested != 1 || len(d.NotTested) != 1 || d.NotTested[0].Member.UserID != 2 {
+		t.Fatalf("standard %s, tested %d, not tested %+v; want the latest test's standard with bravo untested",
+			d.ScoringStandard, d.Tested, d.NotTested)
+	}
+	if len(d.Events) != 1 || d.Events[0].AverageScore != 80 {
+		t.Errorf("Events = %+v, want only the ACFT run", d.Events)
+	}
+	for _, point := range d.Trend {
+		if point.Tests > 0 && point.AverageTotalScore != 480 {
+			t.Errorf("trend %v averages %v, want APFT totals left out", point.Month, point.AverageTotalScore)
+		}
+	}
+
+	d = buildReadiness(roster, sessions, now, ReadinessOptions{StaleDays: 90, TrendMonths: 3, ScoringStandard: grading.DefaultStandardID})
+	if d.Tested != 2 || len(d.Events) != 3 || d.Events[2].AverageScore != 80 {
+		t.Errorf("APFT dashboard tested %d with events %+v, want both members and the APFT runs only", d.Tested, d.Events)
+	}
+}
+
+func TestReadinessWindowStart(t *testing.T) {
+	now := time.Date(2025, 7, 11, 12, 0, 0, 0, time.UTC)
+
+	if got, want := readinessWindowStart(now, ReadinessOptions{StaleDays: 30, TrendMonths: 12}), time.Date(2024, 8, 1, 0, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("window start = %v, want trend start %v", got, want)
+	}
+	if got, want := readinessWindowStart(now, ReadinessOptions{StaleDays: 365, TrendMonths: 3}), now.AddDate(0, 0, -365); !got.Equal(want) {
+		t.Errorf("window start = %v, want stale cutoff %v", got, want)
+	}
+}
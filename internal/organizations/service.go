@@ -0,0 +1,517 @@
+package organizations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"ptchampion/internal/logging"
+	"ptchampion/internal/store"
+)
+
+// ErrPermissionDenied is returned when the user's role in an organization does not allow the action.
+var ErrPermissionDenied = errors.New("user does not have permission for this organization")
+
+// ErrInvalidHierarchy is returned when a unit's type does not fit under its parent.
+var ErrInvalidHierarchy = errors.New("invalid organization hierarchy")
+
+// ErrInvalidRole is returned for a role that is not one of the store.OrganizationRole* constants.
+var ErrInvalidRole = errors.New("invalid organization role")
+
+// ErrInvalidStatus is returned for a membership status that is not one of the
+// store.OrganizationMemberStatus* constants.
+var ErrInvalidStatus = errors.New("invalid organization membership status")
+
+// ErrLastAdmin is returned when removing or demoting the last admin of a top-level unit,
+// which would leave nobody able to manage it.
+var ErrLastAdmin = errors.New("top-level organization must keep an admin")
+
+// CreateOrganizationData defines a new unit at the service layer.
+type CreateOrganizationData struct {
+	Name     string
+	Type     string // One of the store.OrganizationType* constants
+	ParentID *int32 // nil for a top-level unit
+}
+
+// AddMemberData identifies the user to invite to a unit, by ID or username.
+type AddMemberData struct {
+	UserID   int32
+	Username string
+	Role     string
+}
+
+// OrganizationDetails is a unit with its subunits and the caller's role in it.
+type OrganizationDetails struct {
+	Organization *store.Organization
+	Children     []*store.Organization
+	Role         string // Caller's effective role, including roles held in parent units
+}
+
+// Service defines the interface for organization-related business logic.
+type Service interface {
+	CreateOrganization(ctx context.Context, userID int32, data *CreateOrganizationData) (*store.Organization, error)
+	GetOrganization(ctx context.Context, userID int32, orgID int32) (*OrganizationDetails, error)
+	RenameOrganization(ctx context.Context, userID int32, orgID int32, name string) (*store.Organization, error)
+	DeleteOrganization(ctx context.Context, userID int32, orgID int32) error
+	ListUserOrganizations(ctx context.Context, userID int32) ([]*store.OrganizationMembership, error)
+	ListUserInvitations(ctx context.Context, userID int32) ([]*store.OrganizationMembership, error)
+	AcceptInvitation(ctx context.Context, userID int32, orgID int32) (*store.OrganizationMember, error)
+	// SetTestResultSharing records whether the user lets the unit's leaders see their
+	// private test results.
+	SetTestResultSharing(ctx context.Context, userID int32, orgID int32, share bool) (*store.OrganizationMember, error)
+	ListMembers(ctx context.Context, userID int32, orgID int32, includeDescendants bool, status string, page, pageSize int) (*store.PaginatedOrganizationMembers, error)
+	AddMember(ctx context.Context, userID int32, orgID int32, data *AddMemberData) (*store.OrganizationMember, error)
+	UpdateMemberRole(ctx context.Context, userID int32, orgID int32, memberID int32, role string) (*store.OrganizationMember, error)
+	// RemoveMember removes a member or withdraws an invitation; users may leave a unit
+	// or decline an invitation by removing themselves.
+	RemoveMember(ctx context.Context, userID int32, orgID int32, memberID int32) error
+	GetReadinessDashboard(ctx context.Context, userID int32, orgID int32, opts ReadinessOptions) (*ReadinessDashboard, error)
+	// EffectiveRole returns the highest role the user holds in the unit or any parent
+	// unit, or "" when they hold none.
+	EffectiveRole(ctx context.Context, userID int32, orgID int32) (string, error)
+}
+
+type service struct {
+	orgStore  store.OrganizationStore
+	userStore store.UserStore // To look up users added by username
+	logger    logging.Logger
+}
+
+// NewService creates a new organization service instance.
+func NewService(orgStore store.OrganizationStore, userStore store.UserStore, logger logging.Logger) Service {
+	return &service{
+		orgStore:  orgStore,
+		userStore: userStore,
+		logger:    logger,
+	}
+}
+
+// roleRank orders roles by authority; unknown roles rank below member.
+func roleRank(role string) int {
+	switch role {
+	case store.OrganizationRoleMember:
+		return 1
+	case store.OrganizationRoleLeader:
+		return 2
+	case store.OrganizationRoleAdmin:
+		return 3
+	}
+	return 0
+}
+
+// highestRole returns the role with the most authority, or "" for none.
+func highestRole(roles []string) string {
+	best := ""
+	for _, role := range roles {
+		if roleRank(role) > roleRank(best) {
+			best = role
+		}
+	}
+	return best
+}
+
+// validateHierarchy checks that a unit of childType may be created under a parent of
+// parentType ("" for a top-level unit). Top-level units may be of any type; a subunit
+// must be exactly one level below its parent.
+func validateHierarchy(parentType, childType string) error {
+	level := typeLevel(childType)
+	if level < 0 {
+		return fmt.Errorf("unknown organization type %q: %w", childType, ErrInvalidHierarchy)
+	}
+	if parentType == "" {
+		return nil
+	}
+	if typeLevel(parentType) != level-1 {
+		return fmt.Errorf("a %s cannot be created under a %s: %w", childType, parentType, ErrInvalidHierarchy)
+	}
+	return nil
+}
+
+// typeLevel returns the depth of an organization type in the hierarchy, or -1 if unknown.
+func typeLevel(orgType string) int {
+	for i, t := range store.OrganizationTypes {
+		if t == orgType {
+			return i
+		}
+	}
+	return -1
+}
+
+// EffectiveRole implements Service.
+func (s *service) EffectiveRole(ctx context.Context, userID int32, orgID int32) (string, error) {
+	roles, err := s.orgStore.GetUserOrganizationPathRoles(ctx, userID, orgID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get organization roles: %w", err)
+	}
+	return highestRole(roles), nil
+}
+
+// getOrganization loads a unit without checking the user's role in it.
+func (s *service) getOrganization(ctx context.Context, orgID int32) (*store.Organization, error) {
+	org, err := s.orgStore.GetOrganizationByID(ctx, orgID)
+	if err != nil {
+		if err == store.ErrOrganizationNotFound {
+			return nil, err
+		}
+		s.logger.Error(ctx, "Failed to get organization", "orgID", orgID, "error", err)
+		return nil, fmt.Errorf("failed to retrieve organization: %w", err)
+	}
+	return org, nil
+}
+
+// requireRole loads the unit and checks the user holds at least the given role in it.
+// It returns the unit and the user's effective role.
+func (s *service) requireRole(ctx context.Context, userID int32, orgID int32, minRole string) (*store.Organization, string, error) {
+	org, err := s.getOrganization(ctx, orgID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	role, err := s.EffectiveRole(ctx, userID, orgID)
+	if err != nil {
+		s.logger.Error(ctx, "Failed to get organization role", "userID", userID, "orgID", orgID, "error", err)
+		return nil, "", err
+	}
+	if roleRank(role) < roleRank(minRole) {
+		s.logger.Warn(ctx, "User lacks organization role", "userID", userID, "orgID", orgID, "role", role, "required", minRole)
+		return nil, "", ErrPermissionDenied
+	}
+	return org, role, nil
+}
+
+// CreateOrganization creates a unit with the user as its admin. Subunits require the
+// admin role in the parent unit.
+func (s *service) CreateOrganization(ctx context.Context, userID int32, data *CreateOrganizationData) (*store.Organization, error) {
+	s.logger.Debug(ctx, "OrganizationService: CreateOrganization called", "userID", userID, "type", data.Type)
+
+	parentType := ""
+	if data.ParentID != nil {
+		parent, _, err := s.requireRole(ctx, userID, *data.ParentID, store.OrganizationRoleAdmin)
+		if err != nil {
+			return nil, err
+		}
+		parentType = parent.Type
+	}
+	if err := validateHierarchy(parentType, data.Type); err != nil {
+		return nil, err
+	}
+
+	org, err := s.orgStore.CreateOrganization(ctx, &store.Organization{
+		ParentID: data.ParentID,
+		Name:     strings.TrimSpace(data.Name),
+		Type:     data.Type,
+	}, userID)
+	if err != nil {
+		s.logger.Error(ctx, "Failed to create organization in store", "userID", userID, "error", err)
+		return nil, fmt.Errorf("failed to create organization: %w", err)
+	}
+
+	s.logger.Info(ctx, "Organization created", "userID", userID, "orgID", org.ID, "type", org.Type)
+	return org, nil
+}
+
+// GetOrganization returns a unit the user belongs to, directly or through a parent unit.
+func (s *service) GetOrganization(ctx context.Context, userID int32, orgID int32) (*OrganizationDetails, error) {
+	org, role, err := s.requireRole(ctx, userID, orgID, store.OrganizationRoleMember)
+	if err != nil {
+		return nil, err
+	}
+
+	children, err := s.orgStore.ListOrganizationChildren(ctx, orgID)
+	if err != nil {
+		s.logger.Error(ctx, "Failed to list subunits", "orgID", orgID, "error", err)
+		return nil, fmt.Errorf("failed to retrieve subunits: %w", err)
+	}
+	return &OrganizationDetails{Organization: org, Children: children, Role: role}, nil
+}
+
+// RenameOrganization changes a unit's name; it requires the admin role.
+func (s *service) RenameOrganization(ctx context.Context, userID int32, orgID int32, name string) (*store.Organization, error) {
+	org, _, err := s.requireRole(ctx, userID, orgID, store.OrganizationRoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+
+	org.Name = strings.TrimSpace(name)
+	updated, err := s.orgStore.UpdateOrganization(ctx, org)
+	if err != nil {
+		if err == store.ErrOrganizationNotFound {
+			return nil, err
+		}
+		s.logger.Error(ctx, "Failed to update organization in store", "orgID", orgID, "error", err)
+		return nil, fmt.Errorf("failed to update organization: %w", err)
+	}
+
+	s.logger.Info(ctx, "Organization renamed", "userID", userID, "orgID", orgID)
+	return updated, nil
+}
+
+// DeleteOrganization deletes a unit without subunits; it requires the admin role.
+func (s *service) DeleteOrganization(ctx context.Context, userID int32, orgID int32) error {
+	if _, _, err := s.requireRole(ctx, userID, orgID, store.OrganizationRoleAdmin); err != nil {
+		return err
+	}
+
+	if err := s.orgStore.DeleteOrganization(ctx, orgID); err != nil {
+		if err == store.ErrOrganizationNotFound || err == store.ErrOrganizationHasChildren {
+			return err
+		}
+		s.logger.Error(ctx, "Failed to delete organization in store", "orgID", orgID, "error", err)
+		return fmt.Errorf("failed to delete organization: %w", err)
+	}
+
+	s.logger.Info(ctx, "Organization deleted", "userID", userID, "orgID", orgID)
+	return nil
+}
+
+// ListUserOrganizations returns the units the user is a direct member of.
+func (s *service) ListUserOrganizations(ctx context.Context, userID int32) ([]*store.OrganizationMembership, error) {
+	memberships, err := s.orgStore.ListUserOrganizations(ctx, userID, store.OrganizationMemberStatusAccepted)
+	if err != nil {
+		s.logger.Error(ctx, "Failed to list user organizations", "userID", userID, "error", err)
+		return nil, fmt.Errorf("failed to retrieve organizations: %w", err)
+	}
+	return memberships, nil
+}
+
+// ListUserInvitations returns the units the user has been invited to and not yet joined.
+func (s *service) ListUserInvitations(ctx context.Context, userID int32) ([]*store.OrganizationMembership, error) {
+	invitations, err := s.orgStore.ListUserOrganizations(ctx, userID, store.OrganizationMemberStatusPending)
+	if err != nil {
+		s.logger.Error(ctx, "Failed to list organization invitations", "userID", userID, "error", err)
+		return nil, fmt.Errorf("failed to retrieve organization invitations: %w", err)
+	}
+	return invitations, nil
+}
+
+// AcceptInvitation makes the user a member of a unit they were invited to, with the
+// invited role.
+func (s *service) AcceptInvitation(ctx context.Context, userID int32, orgID int32) (*store.OrganizationMember, error) {
+	member, err := s.orgStore.AcceptOrganizationInvitation(ctx, orgID, userID)
+	if err != nil {
+		if err == store.ErrMembershipNotFound {
+			return nil, err
+		}
+		s.logger.Error(ctx, "Failed to accept organization invitation", "userID", userID, "orgID", orgID, "error", err)
+		return nil, fmt.Errorf("failed to accept organization invitation: %w", err)
+	}
+
+	s.logger.Info(ctx, "Organization invitation accepted", "userID", userID, "orgID", orgID, "role", member.Role)
+	return member, nil
+}
+
+// SetTestResultSharing implements Service. Only the member themselves may change it.
+func (s *service) SetTestResultSharing(ctx context.Context, userID int32, orgID int32, share bool) (*store.OrganizationMember, error) {
+	member, err := s.orgStore.SetOrganizationMemberSharing(ctx, orgID, userID, share)
+	if err != nil {
+		if err == store.ErrMembershipNotFound {
+			return nil, err
+		}
+		s.logger.Error(ctx, "Failed to set organization test result sharing", "userID", userID, "orgID", orgID, "error", err)
+		return nil, fmt.Errorf("failed to set test result sharing: %w", err)
+	}
+
+	s.logger.Info(ctx, "Organization test result sharing set", "userID", userID, "orgID", orgID, "share", share)
+	return member, nil
+}
+
+// ListMembers returns a page of a unit's members, optionally including the members of
+// its subunits. Any member of the unit may list its members; pending invitations are
+// listed to leaders only.
+func (s *service) ListMembers(ctx context.Context, userID int32, orgID int32, includeDescendants bool, status string, page, pageSize int) (*store.PaginatedOrganizationMembers, error) {
+	minRole := store.OrganizationRoleMember
+	switch status {
+	case "", store.OrganizationMemberStatusAccepted:
+		status = store.OrganizationMemberStatusAccepted
+	case store.OrganizationMemberStatusPending:
+		minRole = store.OrganizationRoleLeader
+	default:
+		return nil, fmt.Errorf("%q: %w", status, ErrInvalidStatus)
+	}
+	if _, _, err := s.requireRole(ctx, userID, orgID, minRole); err != nil {
+		return nil, err
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 { // Max page size constraint
+		pageSize = 20 // Default page size
+	}
+	limit := int32(pageSize)
+	offset := int32((page - 1) * pageSize)
+
+	members, err := s.orgStore.ListOrganizationMembers(ctx, orgID, includeDescendants, status, limit, offset)
+	if err != nil {
+		s.logger.Error(ctx, "Failed to list organization members", "orgID", orgID, "error", err)
+		return nil, fmt.Errorf("failed to retrieve organization members: %w", err)
+	}
+	return members, nil
+}
+
+// AddMember invites a user to a unit, or changes their role there if they already
+// belong to it or are invited. Leaders may invite members; granting leader or admin, or
+// changing an existing leader or admin, requires admin. Invited users join only once
+// they accept.
+func (s *service) AddMember(ctx context.Context, userID int32, orgID int32, data *AddMemberData) (*store.OrganizationMember, error) {
+	memberID := data.UserID
+	if data.Username != "" {
+		user, err := s.userStore.GetUserByUsername(ctx, data.Username)
+		if err != nil {
+			if err == store.ErrUserNotFound {
+				return nil, err
+			}
+			return nil, fmt.Errorf("failed to look up user: %w", err)
+		}
+		id, err := strconv.Atoi(user.ID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid user ID %q: %w", user.ID, err)
+		}
+		memberID = int32(id)
+	} else if _, err := s.userStore.GetUserByID(ctx, strconv.Itoa(int(memberID))); err != nil {
+		if err == store.ErrUserNotFound {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to look up user: %w", err)
+	}
+
+	return s.UpdateMemberRole(ctx, userID, orgID, memberID, data.Role)
+}
+
+// UpdateMemberRole sets a user's role in a unit, inviting them if they are neither a
+// member nor invited.
+func (s *service) UpdateMemberRole(ctx context.Context, userID int32, orgID int32, memberID int32, role string) (*store.OrganizationMember, error) {
+	if roleRank(role) == 0 {
+		return nil, fmt.Errorf("%q: %w", role, ErrInvalidRole)
+	}
+
+	org, actorRole, err := s.requireRole(ctx, userID, orgID, store.OrganizationRoleLeader)
+	if err != nil {
+		return nil, err
+	}
+	current, err := s.currentMember(ctx, orgID, memberID)
+	if err != nil {
+		return nil, err
+	}
+	if !canAssignRole(actorRole, memberRole(current), role) {
+		s.logger.Warn(ctx, "User cannot assign organization role", "userID", userID, "orgID", orgID, "memberID", memberID, "from", memberRole(current), "to", role)
+		return nil, ErrPermissionDenied
+	}
+	if isAcceptedAdmin(current) && role != store.OrganizationRoleAdmin {
+		if err := s.checkKeepsAdmin(ctx, org); err != nil {
+			return nil, err
+		}
+	}
+
+	member, err := s.orgStore.SetOrganizationMember(ctx, orgID, memberID, role)
+	if err != nil {
+		s.logger.Error(ctx, "Failed to set organization member", "orgID", orgID, "memberID", memberID, "error", err)
+		return nil, fmt.Errorf("failed to set organization member: %w", err)
+	}
+
+	s.logger.Info(ctx, "Organization member set", "userID", userID, "orgID", orgID, "memberID", memberID, "role", role, "status", member.Status)
+	return member, nil
+}
+
+// RemoveMember removes a user from a unit or withdraws their invitation. Users may
+// always leave or decline; leaders may remove members and admins may remove anyone.
+func (s *service) RemoveMember(ctx context.Context, userID int32, orgID int32, memberID int32) error {
+	var org *store.Organization
+	var actorRole string
+	var err error
+	if memberID == userID {
+		org, err = s.getOrganization(ctx, orgID)
+	} else {
+		org, actorRole, err = s.requireRole(ctx, userID, orgID, store.OrganizationRoleLeader)
+	}
+	if err != nil {
+		return err
+	}
+	current, err := s.currentMember(ctx, orgID, memberID)
+	if err != nil {
+		return err
+	}
+	if current == nil {
+		return store.ErrMembershipNotFound
+	}
+	if memberID != userID && !canAssignRole(actorRole, current.Role, "") {
+		s.logger.Warn(ctx, "User cannot remove organization member", "userID", userID, "orgID", orgID, "memberID", memberID, "role", current.Role)
+		return ErrPermissionDenied
+	}
+	if isAcceptedAdmin(current) {
+		if err := s.checkKeepsAdmin(ctx, org); err != nil {
+			return err
+		}
+	}
+
+	if err := s.orgStore.RemoveOrganizationMember(ctx, orgID, memberID); err != nil {
+		if err == store.ErrMembershipNotFound {
+			return err
+		}
+		s.logger.Error(ctx, "Failed to remove organization member", "orgID", orgID, "memberID", memberID, "error", err)
+		return fmt.Errorf("failed to remove organization member: %w", err)
+	}
+
+	s.logger.Info(ctx, "Organization member removed", "userID", userID, "orgID", orgID, "memberID", memberID)
+	return nil
+}
+
+// canAssignRole reports whether a user with actorRole may change a membership from
+// current to next, where "" means no membership. Leaders manage plain members only.
+func canAssignRole(actorRole, current, next string) bool {
+	if actorRole == store.OrganizationRoleAdmin {
+		return true
+	}
+	if actorRole != store.OrganizationRoleLeader {
+		return false
+	}
+	return roleRank(current) <= roleRank(store.OrganizationRoleMember) &&
+		roleRank(next) <= roleRank(store.OrganizationRoleMember)
+}
+
+// currentMember returns the user's direct membership or invitation in the unit, or nil
+// if they have neither.
+func (s *service) currentMember(ctx context.Context, orgID int32, memberID int32) (*store.OrganizationMember, error) {
+	member, err := s.orgStore.GetOrganizationMember(ctx, orgID, memberID)
+	if err == store.ErrMembershipNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		s.logger.Error(ctx, "Failed to get organization member", "orgID", orgID, "memberID", memberID, "error", err)
+		return nil, fmt.Errorf("failed to retrieve organization member: %w", err)
+	}
+	return member, nil
+}
+
+// memberRole returns the role a membership holds or an invitation offers, or "" for
+// neither. Leaders may not change a leader or admin invitation any more than the role.
+func memberRole(member *store.OrganizationMember) string {
+	if member == nil {
+		return ""
+	}
+	return member.Role
+}
+
+// isAcceptedAdmin reports whether the membership makes the user one of the unit's
+// admins; pending admin invitations do not keep a top-level unit managed.
+func isAcceptedAdmin(member *store.OrganizationMember) bool {
+	return member != nil && member.Status == store.OrganizationMemberStatusAccepted && member.Role == store.OrganizationRoleAdmin
+}
+
+// checkKeepsAdmin rejects losing one of a top-level unit's admins when it is the last.
+// Subunits can always be managed by the admins of their parents.
+func (s *service) checkKeepsAdmin(ctx context.Context, org *store.Organization) error {
+	if org.ParentID != nil {
+		return nil
+	}
+	admins, err := s.orgStore.CountOrganizationMembersByRole(ctx, org.ID, store.OrganizationRoleAdmin)
+	if err != nil {
+		return fmt.Errorf("failed to count organization admins: %w", err)
+	}
+	if admins <= 1 {
+		return ErrLastAdmin
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package organizations
+
+import (
+	"errors"
+	"testing"
+
+	"ptchampion/internal/store"
+)
+
+func TestHighestRole(t *testing.T) {
+	tests := []struct {
+		roles []string
+		want  string
+	}{
+		{roles: nil, want: ""},
+		{roles: []string{store.OrganizationRoleMember}, want: store.OrganizationRoleMember},
+		{roles: []string{store.OrganizationRoleMember, store.OrganizationRoleAdmin, store.OrganizationRoleLeader}, want: store.OrganizationRoleAdmin},
+		{roles: []string{"owner", store.OrganizationRoleLeader}, want: store.OrganizationRoleLeader},
+	}
+
+	for _, tt := range tests {
+		if got := highestRole(tt.roles); got != tt.want {
+			t.Errorf("highestRole(%v) = %q, want %q", tt.roles, got, tt.want)
+		}
+	}
+}
+
+func TestValidateHierarchy(t *testing.T) {
+	tests := []struct {
+		name       string
+		parentType string
+		childType  string
+		wantErr    bool
+	}{
+		{name: "top-level battalion", childType: store.OrganizationTypeBattalion},
+		{name: "top-level platoon", childType: store.OrganizationTypePlatoon},
+		{name: "company in battalion", parentType: store.OrganizationTypeBattalion, childType: store.OrganizationTypeCompany},
+		{name: "squad in platoon", parentType: store.OrganizationTypePlatoon, childType: store.OrganizationTypeSquad},
+		{name: "squad in company", parentType: store.OrganizationTypeCompany, childType: store.OrganizationTypeSquad, wantErr: true},
+		{name: "battalion in company", parentType: store.OrganizationTypeCompany, childType: store.OrganizationTypeBattalion, wantErr: true},
+		{name: "subunit of squad", parentType: store.OrganizationTypeSquad, childType: store.OrganizationTypeSquad, wantErr: true},
+		{name: "unknown type", childType: "brigade", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateHierarchy(tt.parentType, tt.childType)
+			if tt.wantErr != errors.Is(err, ErrInvalidHierarchy) {
+				t.Errorf("validateHierarchy(%q, %q) = %v, wantErr %v", tt.parentType, tt.childType, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCanAssignRole(t *testing.T) {
+	member, leader, admin := store.OrganizationRoleMember, store.OrganizationRoleLeader, store.OrganizationRoleAdmin
+
+	tests := []struct {
+		name      string
+		actorRole string
+		current   string
+		next      string
+		want      bool
+	}{
+		{name: "leader adds member", actorRole: leader, next: member, want: true},
+		{name: "leader removes member", actorRole: leader, current: member, want: true},
+		{name: "leader promotes member", actorRole: leader, current: member, next: leader},
+		{name: "leader removes leader", actorRole: leader, current: leader},
+		{name: "leader demotes admin", actorRole: leader, current: admin, next: member},
+		{name: "member adds member", actorRole: member, next: member},
+		{name: "admin promotes leader", actorRole: admin, current: leader, next: admin, want: true},
+		{name: "admin removes admin", actorRole: admin, current: admin, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := canAssignRole(tt.actorRole, tt.current, tt.next); got != tt.want {
+				t.Errorf("canAssignRole(%q, %q, %q) = %v, want %v", tt.actorRole, tt.current, tt.next, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsAcceptedAdmin(t *testing.T) {
+	tests := []struct {
+		name   string
+		member *store.OrganizationMember
+		want   bool
+	}{
+		{name: "no membership"},
+		{name: "accepted admin", member: &store.OrganizationMember{Role: store.OrganizationRoleAdmin, Status: store.OrganizationMemberStatusAccepted}, want: true},
+		{name: "pending admin invitation", member: &store.OrganizationMember{Role: store.OrganizationRoleAdmin, Status: store.OrganizationMemberStatusPending}},
+		{name: "accepted leader", member: &store.OrganizationMember{Role: store.OrganizationRoleLeader, Status: store.OrganizationMemberStatusAccepted}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isAcceptedAdmin(tt.member); got != tt.want {
+				t.Errorf("isAcceptedAdmin() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
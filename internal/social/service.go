@@ -0,0 +1,276 @@
+package social
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"ptchampion/internal/logging"
+	"ptchampion/internal/store"
+)
+
+// ErrInvalidFriendRequest is returned when a user sends a friend request to themselves.
+var ErrInvalidFriendRequest = errors.New("cannot send a friend request to yourself")
+
+// ErrAlreadyFriends is returned for a friend request between users who are already friends.
+var ErrAlreadyFriends = errors.New("users are already friends")
+
+// ErrFriendRequestExists is returned when the user already has a pending request to the other user.
+var ErrFriendRequestExists = errors.New("friend request already sent")
+
+// ErrUserBlocked is returned for a friend request to a user the caller has blocked.
+var ErrUserBlocked = errors.New("user is blocked")
+
+// FriendRequestData identifies the user to send a friend request to, by ID or username.
+type FriendRequestData struct {
+	UserID   int32
+	Username string
+}
+
+// Service defines the interface for friendship-related business logic.
+type Service interface {
+	// SendFriendRequest asks another user to be friends. A request to a user who has
+	// already asked the caller accepts theirs.
+	SendFriendRequest(ctx context.Context, userID int32, data *FriendRequestData) (*store.Friendship, error)
+	AcceptFriendRequest(ctx context.Context, userID int32, requesterID int32) (*store.Friendship, error)
+	// RemoveFriend unfriends another user, or cancels or declines a pending request between them.
+	RemoveFriend(ctx context.Context, userID int32, otherUserID int32) error
+	BlockUser(ctx context.Context, userID int32, otherUserID int32) error
+	UnblockUser(ctx context.Context, userID int32, otherUserID int32) error
+	// ListFriendships lists the user's friends, requests or blocks. Only the blocks the
+	// user made are listed.
+	ListFriendships(ctx context.Context, userID int32, filters store.FriendshipFilters, page, pageSize int) (*store.PaginatedFriends, error)
+}
+
+type service struct {
+	socialStore store.SocialStore
+	userStore   store.UserStore // To look up users by ID or username
+	logger      logging.Logger
+}
+
+// NewService creates a new social service instance.
+func NewService(socialStore store.SocialStore, userStore store.UserStore, logger logging.Logger) Service {
+	return &service{
+		socialStore: socialStore,
+		userStore:   userStore,
+		logger:      logger,
+	}
+}
+
+// requestOutcome decides what a friend request from userID does to their existing
+// relationship with the other user: it either accepts the other user's pending request
+// or fails. A user who blocked the caller is reported as not found.
+func requestOutcome(existing *store.Friendship, userID int32) error {
+	switch existing.Status {
+	case store.FriendshipStatusAccepted:
+		return ErrAlreadyFriends
+	case store.FriendshipStatusPending:
+		if existing.RequesterID == userID {
+			return ErrFriendRequestExists
+		}
+		return nil
+	case store.FriendshipStatusBlocked:
+		if existing.RequesterID == userID {
+			return ErrUserBlocked
+		}
+		return store.ErrUserNotFound
+	}
+	return fmt.Errorf("unknown friendship status %q", existing.Status)
+}
+
+// SendFriendRequest implements Service.
+func (s *service) SendFriendRequest(ctx context.Context, userID int32, data *FriendRequestData) (*store.Friendship, error) {
+	otherUserID, err := s.resolveUser(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+	if otherUserID == userID {
+		return nil, ErrInvalidFriendRequest
+	}
+
+	existing, err := s.getFriendship(ctx, userID, otherUserID)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		created, err := s.socialStore.CreateFriendship(ctx, &store.Friendship{
+			RequesterID: userID,
+			AddresseeID: otherUserID,
+			Status:      store.FriendshipStatusPending,
+		})
+		if err != nil {
+			s.logger.Error(ctx, "Failed to create friend request", "userID", userID, "otherUserID", otherUserID, "error", err)
+			return nil, fmt.Errorf("failed to send friend request: %w", err)
+		}
+		s.logger.Info(ctx, "Friend request sent", "userID", userID, "otherUserID", otherUserID)
+		return created, nil
+	}
+
+	if err := requestOutcome(existing, userID); err != nil {
+		return nil, err
+	}
+	return s.accept(ctx, userID, existing)
+}
+
+// AcceptFriendRequest implements Service.
+func (s *service) AcceptFriendRequest(ctx context.Context, userID int32, requesterID int32) (*store.Friendship, error) {
+	existing, err := s.getFriendship(ctx, userID, requesterID)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil || existing.Status != store.FriendshipStatusPending || existing.AddresseeID != userID {
+		return nil, store.ErrFriendshipNotFound
+	}
+	return s.accept(ctx, userID, existing)
+}
+
+func (s *service) accept(ctx context.Context, userID int32, friendship *store.Friendship) (*store.Friendship, error) {
+	friendship.Status = store.FriendshipStatusAccepted
+	accepted, err := s.socialStore.UpdateFriendship(ctx, friendship)
+	if err != nil {
+		if err == store.ErrFriendshipNotFound {
+			return nil, err
+		}
+		s.logger.Error(ctx, "Failed to accept friend request", "userID", userID, "friendshipID", friendship.ID, "error", err)
+		return nil, fmt.Errorf("failed to accept friend request: %w", err)
+	}
+	s.logger.Info(ctx, "Friend request accepted", "userID", userID, "requesterID", friendship.RequesterID)
+	return accepted, nil
+}
+
+// RemoveFriend implements Service. Blocks are lifted with UnblockUser.
+func (s *service) RemoveFriend(ctx context.Context, userID int32, otherUserID int32) error {
+	existing, err := s.getFriendship(ctx, userID, otherUserID)
+	if err != nil {
+		return err
+	}
+	if existing == nil || existing.Status == store.FriendshipStatusBlocked {
+		return store.ErrFriendshipNotFound
+	}
+	return s.delete(ctx, userID, existing)
+}
+
+// BlockUser implements Service. Blocking replaces any friendship or request between
+// the users. A user the other user has already blocked stays blocked by them.
+func (s *service) BlockUser(ctx context.Context, userID int32, otherUserID int32) error {
+	if otherUserID == userID {
+		return ErrInvalidFriendRequest
+	}
+	if _, err := s.resolveUser(ctx, &FriendRequestData{UserID: otherUserID}); err != nil {
+		return err
+	}
+
+	existing, err := s.getFriendship(ctx, userID, otherUserID)
+	if err != nil {
+		return err
+	}
+	blocked := &store.Friendship{
+		RequesterID: userID,
+		AddresseeID: otherUserID,
+		Status:      store.FriendshipStatusBlocked,
+	}
+	switch {
+	case existing == nil:
+		_, err = s.socialStore.CreateFriendship(ctx, blocked)
+	case existing.Status == store.FriendshipStatusBlocked:
+		return nil
+	default:
+		blocked.ID = existing.ID
+		_, err = s.socialStore.UpdateFriendship(ctx, blocked)
+	}
+	if err != nil {
+		s.logger.Error(ctx, "Failed to block user", "userID", userID, "otherUserID", otherUserID, "error", err)
+		return fmt.Errorf("failed to block user: %w", err)
+	}
+	s.logger.Info(ctx, "User blocked", "userID", userID, "otherUserID", otherUserID)
+	return nil
+}
+
+// UnblockUser implements Service. Only the user who made a block can lift it.
+func (s *service) UnblockUser(ctx context.Context, userID int32, otherUserID int32) error {
+	existing, err := s.getFriendship(ctx, userID, otherUserID)
+	if err != nil {
+		return err
+	}
+	if existing == nil || existing.Status != store.FriendshipStatusBlocked || existing.RequesterID != userID {
+		return store.ErrFriendshipNotFound
+	}
+	return s.delete(ctx, userID, existing)
+}
+
+// ListFriendships implements Service.
+func (s *service) ListFriendships(ctx context.Context, userID int32, filters store.FriendshipFilters, page, pageSize int) (*store.PaginatedFriends, error) {
+	if filters.Status == "" {
+		filters.Status = store.FriendshipStatusAccepted
+	}
+	if filters.Status == store.FriendshipStatusBlocked {
+		filters.Direction = store.FriendshipDirectionOutgoing
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 { // Max page size constraint
+		pageSize = 20 // Default page size
+	}
+	limit := int32(pageSize)
+	offset := int32((page - 1) * pageSize)
+
+	friends, err := s.socialStore.ListFriendships(ctx, userID, filters, limit, offset)
+	if err != nil {
+		s.logger.Error(ctx, "Failed to list friendships", "userID", userID, "status", filters.Status, "error", err)
+		return nil, fmt.Errorf("failed to retrieve friendships: %w", err)
+	}
+	return friends, nil
+}
+
+// getFriendship returns the relationship between the users, or nil when they have none.
+func (s *service) getFriendship(ctx context.Context, userID int32, otherUserID int32) (*store.Friendship, error) {
+	friendship, err := s.socialStore.GetFriendship(ctx, userID, otherUserID)
+	if err != nil {
+		if err == store.ErrFriendshipNotFound {
+			return nil, nil
+		}
+		s.logger.Error(ctx, "Failed to get friendship", "userID", userID, "otherUserID", otherUserID, "error", err)
+		return nil, fmt.Errorf("failed to retrieve friendship: %w", err)
+	}
+	return friendship, nil
+}
+
+func (s *service) delete(ctx context.Context, userID int32, friendship *store.Friendship) error {
+	if err := s.socialStore.DeleteFriendship(ctx, friendship.ID); err != nil {
+		if err == store.ErrFriendshipNotFound {
+			return err
+		}
+		s.logger.Error(ctx, "Failed to delete friendship", "userID", userID, "friendshipID", friendship.ID, "error", err)
+		return fmt.Errorf("failed to delete friendship: %w", err)
+	}
+	s.logger.Info(ctx, "Friendship removed", "userID", userID, "otherUserID", friendship.OtherUserID(userID), "status", friendship.Status)
+	return nil
+}
+
+// resolveUser returns the ID of the user identified by ID or username.
+func (s *service) resolveUser(ctx context.Context, data *FriendRequestData) (int32, error) {
+	if data.Username != "" {
+		user, err := s.userStore.GetUserByUsername(ctx, data.Username)
+		if err != nil {
+			if err == store.ErrUserNotFound {
+				return 0, err
+			}
+			return 0, fmt.Errorf("failed to look up user: %w", err)
+		}
+		id, err := strconv.Atoi(user.ID)
+		if err != nil {
+			return 0, fmt.Errorf("invalid user ID %q: %w", user.ID, err)
+		}
+		return int32(id), nil
+	}
+	if _, err := s.userStore.GetUserByID(ctx, strconv.Itoa(int(data.UserID))); err != nil {
+		if err == store.ErrUserNotFound {
+			return 0, err
+		}
+		return 0, fmt.Errorf("failed to look up user: %w", err)
+	}
+	return data.UserID, nil
+}
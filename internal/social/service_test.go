@@ -0,0 +1,38 @@
+package social
+
+import (
+	"errors"
+	"testing"
+
+	"ptchampion/internal/store"
+)
+
+func TestRequestOutcome(t *testing.T) {
+	const me, them = 1, 2
+
+	tests := []struct {
+		name      string
+		requester int32
+		status    string
+		want      error
+	}{
+		{name: "already friends", requester: them, status: store.FriendshipStatusAccepted, want: ErrAlreadyFriends},
+		{name: "own pending request", requester: me, status: store.FriendshipStatusPending, want: ErrFriendRequestExists},
+		{name: "their pending request is accepted", requester: them, status: store.FriendshipStatusPending},
+		{name: "blocked by me", requester: me, status: store.FriendshipStatusBlocked, want: ErrUserBlocked},
+		{name: "blocked by them looks like no user", requester: them, status: store.FriendshipStatusBlocked, want: store.ErrUserNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			addressee := int32(them)
+			if tt.requester == them {
+				addressee = me
+			}
+			existing := &store.Friendship{RequesterID: tt.requester, AddresseeID: addressee, Status: tt.status}
+			if got := requestOutcome(existing, me); !errors.Is(got, tt.want) {
+				t.Errorf("requestOutcome() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
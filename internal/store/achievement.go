@@ -0,0 +1,35 @@
+package store
+
+import "time"
+
+// Achievement is a badge a user has earned. Badges are declared by the workouts service;
+// the store only records which ones were awarded and when.
+type Achievement struct {
+	ID        int32
+	UserID    int32
+	BadgeID   string
+	WorkoutID *int32 // Logged workout that earned the badge; nil when awarded by a backfill
+	EarnedAt  time.Time
+}
+
+// AchievementAward is a badge to award with AwardAchievements.
+type AchievementAward struct {
+	BadgeID   string
+	BadgeName string // Shown in the activity feed
+}
+
+// ExerciseAchievementStats are a user's lifetime results in one exercise type.
+type ExerciseAchievementStats struct {
+	Workouts               int
+	TotalReps              int64
+	BestGrade              int32  // Server-computed where available, as leaderboards rank it; mismatched grades do not count
+	FastestDurationSeconds *int32 // nil without a timed workout
+}
+
+// AchievementStats are the lifetime results badges are evaluated against. Deleted
+// workouts do not count.
+type AchievementStats struct {
+	TotalWorkouts int
+	Exercises     map[string]*ExerciseAchievementStats // By exercise type
+	ActiveDays    []time.Time                          // Calendar dates in the user's timezone with a workout, oldest first
+}
@@ -0,0 +1,26 @@
+package store
+
+import (
+	"context"
+	"time"
+)
+
+// WorkoutSeriesBucket aggregates a user's workouts of one exercise type in one time bucket.
+// Buckets without workouts are included so series have no gaps.
+type WorkoutSeriesBucket struct {
+	ExerciseType        string
+	Start               time.Time // Start of the day, week (Monday) or month, in UTC
+	Workouts            int
+	MaxReps             *int32   // nil without rep-counted workouts
+	Volume              int64    // Total repetitions
+	MeanGrade           *float64 // nil without workouts
+	MeanDurationSeconds *float64 // nil without timed workouts
+}
+
+// AnalyticsStore defines methods for workout analytics data access
+type AnalyticsStore interface {
+	// GetWorkoutTimeSeries buckets the user's workouts by interval ("day", "week" or "month")
+	// from their first workout to now, optionally limited to one exercise type. Buckets are
+	// ordered by exercise type, then time.
+	GetWorkoutTimeSeries(ctx context.Context, userID int32, interval string, exerciseType string) ([]*WorkoutSeriesBucket, error)
+}
@@ -0,0 +1,104 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrChallengeNotFound is returned when a challenge is not found.
+var ErrChallengeNotFound = errors.New("challenge not found")
+
+// ErrChallengeParticipantNotFound is returned when a user or unit has not joined a challenge.
+var ErrChallengeParticipantNotFound = errors.New("challenge participant not found")
+
+// ErrChallengeParticipantExists is returned when a user or unit joins a challenge twice.
+var ErrChallengeParticipantExists = errors.New("already participating in challenge")
+
+// Challenge scoring rules
+const (
+	ChallengeRuleMaxReps     = "max_reps"     // Most repetitions in one workout
+	ChallengeRuleTotalVolume = "total_volume" // Most repetitions across all workouts in the window
+	ChallengeRuleBestTime    = "best_time"    // Fastest time in one workout
+)
+
+// Challenge participant types
+const (
+	ChallengeParticipantIndividual = "individual" // Users join themselves
+	ChallengeParticipantTeam       = "team"       // Leaders enter their units
+)
+
+// Challenge is a competition on one exercise within a time window.
+type Challenge struct {
+	ID              int32
+	CreatorID       int32
+	Name            string
+	Description     *string
+	ExerciseID      int32
+	ExerciseType    string // Denormalized from exercises
+	ScoringRule     string // One of the ChallengeRule* constants
+	ParticipantType string // One of the ChallengeParticipant* constants
+	StartsAt        time.Time
+	EndsAt          time.Time
+	FinalizedAt     *time.Time // Set once the results are frozen
+	Participants    int
+	CreatedAt       time.Time
+}
+
+// PaginatedChallenges holds a page of challenges and total count.
+type PaginatedChallenges struct {
+	Challenges []*Challenge
+	TotalCount int64
+}
+
+// ChallengeFilters selects challenges by their window relative to a point in time.
+type ChallengeFilters struct {
+	Status string    // "active", "upcoming", "closed" or empty for all
+	At     time.Time // Reference time for Status
+}
+
+// ChallengeParticipant is a user or unit entered in a challenge.
+type ChallengeParticipant struct {
+	ID             int32
+	ChallengeID    int32
+	UserID         *int32 // Set for individual participants
+	OrganizationID *int32 // Set for teams
+	Name           string // Username or unit name
+	JoinedAt       time.Time
+}
+
+// ChallengeMemberResult is one user's result for a participant: the participant itself
+// for individuals, or one of the unit's members for teams.
+type ChallengeMemberResult struct {
+	ParticipantID int32
+	UserID        int32
+	Value         float64 // Repetitions or seconds, per the scoring rule
+}
+
+// ChallengeStanding is a participant's place in a challenge.
+type ChallengeStanding struct {
+	Participant  *ChallengeParticipant
+	Score        *float64 // nil when no qualifying workout was logged
+	Contributors int      // Users with a result; 1 or 0 for individuals
+	Rank         int32    // 0 for participants without a score
+}
+
+// ChallengeStore defines methods for challenge data access
+type ChallengeStore interface {
+	CreateChallenge(ctx context.Context, challenge *Challenge) (*Challenge, error)
+	GetChallengeByID(ctx context.Context, id int32) (*Challenge, error)
+	ListChallenges(ctx context.Context, filters ChallengeFilters, limit int32, offset int32) (*PaginatedChallenges, error)
+	// AddChallengeParticipant enters a user or, when orgID is set, a unit
+	AddChallengeParticipant(ctx context.Context, challengeID int32, userID *int32, orgID *int32) (*ChallengeParticipant, error)
+	GetChallengeParticipant(ctx context.Context, challengeID int32, userID *int32, orgID *int32) (*ChallengeParticipant, error)
+	RemoveChallengeParticipant(ctx context.Context, challengeID int32, participantID int32) error
+	ListChallengeParticipants(ctx context.Context, challengeID int32) ([]*ChallengeParticipant, error)
+	// GetChallengeMemberResults applies the challenge's scoring rule to each participating
+	// user's workouts within the window, including every member of participating units
+	GetChallengeMemberResults(ctx context.Context, challenge *Challenge) ([]*ChallengeMemberResult, error)
+	// FinalizeChallenge freezes the standings and publishes individual results to the activity
+	// feed; it returns false if the challenge was already finalized
+	FinalizeChallenge(ctx context.Context, challengeID int32, standings []*ChallengeStanding) (bool, error)
+	// GetChallengeResults returns the frozen standings of a finalized challenge
+	GetChallengeResults(ctx context.Context, challengeID int32) ([]*ChallengeStanding, error)
+}
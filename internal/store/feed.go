@@ -0,0 +1,119 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrActivityEventNotFound is returned when an activity event does not exist or is not
+// visible to the user.
+var ErrActivityEventNotFound = errors.New("activity event not found")
+
+// ErrActivityCommentNotFound is returned when a comment does not exist on the event.
+var ErrActivityCommentNotFound = errors.New("activity comment not found")
+
+// Activity event types
+const (
+	ActivityTypeWorkout         = "workout"          // The actor logged a workout
+	ActivityTypePersonalRecord  = "personal_record"  // A logged workout set a personal record
+	ActivityTypeChallengeResult = "challenge_result" // A challenge the actor entered was finalized
+	ActivityTypeAchievement     = "achievement"      // A logged workout earned the actor a badge
+)
+
+// Reactions to activity events
+const (
+	ReactionLike   = "like"
+	ReactionFire   = "fire"
+	ReactionStrong = "strong"
+	ReactionSalute = "salute"
+)
+
+// ActivityPayload is the snapshot of an event shown in feeds; fields not used by the
+// event type are omitted.
+type ActivityPayload struct {
+	ExerciseType    string   `json:"exercise_type,omitempty"`
+	ExerciseName    string   `json:"exercise_name,omitempty"`
+	Grade           *int32   `json:"grade,omitempty"`
+	Reps            *int32   `json:"reps,omitempty"`
+	DurationSeconds *int32   `json:"duration_seconds,omitempty"`
+	WeightLbs       *int32   `json:"weight_lbs,omitempty"`
+	DistanceMeters  *float64 `json:"distance_meters,omitempty"`
+
+	// Personal records
+	Metric        string   `json:"metric,omitempty"`
+	Value         *float64 `json:"value,omitempty"`
+	PreviousValue *float64 `json:"previous_value,omitempty"`
+
+	// Challenge results
+	ChallengeID   *int32   `json:"challenge_id,omitempty"`
+	ChallengeName string   `json:"challenge_name,omitempty"`
+	Rank          *int32   `json:"rank,omitempty"`
+	Score         *float64 `json:"score,omitempty"`
+	Participants  *int     `json:"participants,omitempty"`
+
+	// Achievements
+	BadgeID   string `json:"badge_id,omitempty"`
+	BadgeName string `json:"badge_name,omitempty"`
+}
+
+// ActivityEvent is something a user did, fanned out to the feeds of the user and their
+// friends when it happens. Events tied to a workout follow its current visibility.
+type ActivityEvent struct {
+	ID            int32
+	ActorUserID   int32
+	ActorUsername string
+	Type          string // One of the ActivityType* constants
+	WorkoutID     *int32 // Workout, personal record and achievement events
+	Payload       *ActivityPayload
+	CreatedAt     time.Time
+
+	// Engagement, as seen by the reading user
+	Reactions    map[string]int // Count per reaction
+	Comments     int
+	UserReaction string // The reading user's reaction; empty for none
+}
+
+// FeedPage is a page of a user's feed, newest first.
+type FeedPage struct {
+	Events     []*ActivityEvent
+	NextCursor int32 // ID to read the next page before; 0 on the last page
+}
+
+// ActivityComment is a comment on an activity event.
+type ActivityComment struct {
+	ID        int32
+	EventID   int32
+	UserID    int32
+	Username  string
+	Body      string
+	CreatedAt time.Time
+}
+
+// PaginatedActivityComments holds a page of comments and total count.
+type PaginatedActivityComments struct {
+	Comments   []*ActivityComment
+	TotalCount int64
+}
+
+// FeedStore defines methods for activity feed data access. Events are published by the
+// stores that record what happened, in the same transaction: logged workouts and the
+// personal records they set by CreateWorkoutRecordWithPersonalRecords, badges they earn by
+// AwardAchievements, and individual challenge results by FinalizeChallenge.
+type FeedStore interface {
+	// GetFeed returns the events fanned out to the user before the cursor (0 for the newest).
+	// Events of former friends and blocked users, deleted workouts and workouts since made
+	// private are left out.
+	GetFeed(ctx context.Context, userID int32, before int32, limit int32) (*FeedPage, error)
+	// GetActivityEvent returns an event if it is visible to the user
+	GetActivityEvent(ctx context.Context, userID int32, eventID int32) (*ActivityEvent, error)
+	// SetActivityReaction sets the user's reaction to an event, replacing any earlier one
+	SetActivityReaction(ctx context.Context, eventID int32, userID int32, reaction string) error
+	DeleteActivityReaction(ctx context.Context, eventID int32, userID int32) error
+	CreateActivityComment(ctx context.Context, comment *ActivityComment) (*ActivityComment, error)
+	GetActivityComment(ctx context.Context, eventID int32, commentID int32) (*ActivityComment, error)
+	DeleteActivityComment(ctx context.Context, commentID int32) error
+	// ListActivityComments returns an event's comments, oldest first, leaving out those of
+	// users blocked by or blocking the reading user
+	ListActivityComments(ctx context.Context, userID int32, eventID int32, limit int32, offset int32) (*PaginatedActivityComments, error)
+}
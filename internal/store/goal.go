@@ -0,0 +1,27 @@
+package store
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrGoalNotFound is returned when a goal is not found.
+var ErrGoalNotFound = errors.New("goal not found")
+
+// Goal types
+const (
+	GoalTypeExercise   = "exercise"    // A measurement in one exercise, e.g. 70 push-ups
+	GoalTypeTotalScore = "total_score" // A total score in a full test, e.g. 270 on the APFT
+)
+
+// Goal is a target a user sets for a date.
+type Goal struct {
+	ID              int32
+	UserID          int32
+	Type            string    // One of the GoalType* constants
+	ExerciseType    string    // Exercise goals only
+	ScoringStandard string    // Standard the goal is scored against
+	TargetValue     float64   // Measurement for exercise goals (reps, seconds, ...), points for total score goals
+	TargetDate      time.Time // Calendar date
+	CreatedAt       time.Time
+}
@@ -0,0 +1,124 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrOrganizationNotFound is returned when an organization is not found.
+var ErrOrganizationNotFound = errors.New("organization not found")
+
+// ErrOrganizationHasChildren is returned when deleting an organization that still has subunits.
+var ErrOrganizationHasChildren = errors.New("organization has subunits")
+
+// ErrMembershipNotFound is returned when a user is not a member of an organization.
+var ErrMembershipNotFound = errors.New("organization membership not found")
+
+// Organization types, from the top of the hierarchy down
+const (
+	OrganizationTypeBattalion = "battalion"
+	OrganizationTypeCompany   = "company"
+	OrganizationTypePlatoon   = "platoon"
+	OrganizationTypeSquad     = "squad"
+)
+
+// OrganizationTypes lists the organization types in hierarchy order; a subunit's type
+// is the one after its parent's.
+var OrganizationTypes = []string{
+	OrganizationTypeBattalion,
+	OrganizationTypeCompany,
+	OrganizationTypePlatoon,
+	OrganizationTypeSquad,
+}
+
+// Organization membership roles, in increasing order of authority. A role held in an
+// organization also applies to all of its subunits.
+const (
+	OrganizationRoleMember = "member" // Appears in the unit's rosters and leaderboards
+	OrganizationRoleLeader = "leader" // Manages the unit's members and sees their results
+	OrganizationRoleAdmin  = "admin"  // Manages the unit itself, its subunits and its leaders
+)
+
+// Organization membership statuses. Only accepted memberships grant a role; a pending
+// membership is an invitation the user has not yet accepted.
+const (
+	OrganizationMemberStatusPending  = "pending"  // A leader invited the user
+	OrganizationMemberStatusAccepted = "accepted" // The user accepted and is a member
+)
+
+// Organization is a unit in the organization tree.
+type Organization struct {
+	ID        int32
+	ParentID  *int32 // nil for a top-level unit
+	Name      string
+	Type      string // One of the OrganizationType* constants
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// OrganizationMember is a user's membership in one organization.
+type OrganizationMember struct {
+	OrganizationID int32
+	UserID         int32
+	Username       string // Denormalized from users
+	FirstName      *string
+	LastName       *string
+	Role           string    // One of the OrganizationRole* constants
+	Status         string    // One of the OrganizationMemberStatus* constants
+	JoinedAt       time.Time // When the user accepted, or was invited while pending
+	// SharesTestResults is set when the user lets the unit's leaders see their private
+	// test sessions; public sessions are visible regardless
+	SharesTestResults bool
+}
+
+// OrganizationMembership is an organization a user belongs to, with their role in it.
+type OrganizationMembership struct {
+	Organization *Organization
+	Role         string
+	Status       string // One of the OrganizationMemberStatus* constants
+	JoinedAt     time.Time
+}
+
+// PaginatedOrganizationMembers holds a page of organization members and total count.
+type PaginatedOrganizationMembers struct {
+	Members    []*OrganizationMember
+	TotalCount int64
+}
+
+// OrganizationStore defines methods for organization and membership data access
+type OrganizationStore interface {
+	// CreateOrganization creates the organization and makes adminUserID its admin in one transaction
+	CreateOrganization(ctx context.Context, org *Organization, adminUserID int32) (*Organization, error)
+	GetOrganizationByID(ctx context.Context, id int32) (*Organization, error)
+	UpdateOrganization(ctx context.Context, org *Organization) (*Organization, error)
+	DeleteOrganization(ctx context.Context, id int32) error
+	ListOrganizationChildren(ctx context.Context, parentID int32) ([]*Organization, error)
+	// ListUserOrganizations lists the organizations the user is a direct member of, or invited to, with the given status
+	ListUserOrganizations(ctx context.Context, userID int32, status string) ([]*OrganizationMembership, error)
+	// GetUserOrganizationPathRoles returns the accepted roles the user holds in the organization and in each of its ancestors
+	GetUserOrganizationPathRoles(ctx context.Context, userID int32, orgID int32) ([]string, error)
+	// GetOrganizationMember returns the user's membership or pending invitation
+	GetOrganizationMember(ctx context.Context, orgID int32, userID int32) (*OrganizationMember, error)
+	// IsOrganizationSubtreeMember reports whether the user is an accepted member of the organization or any of its subunits
+	IsOrganizationSubtreeMember(ctx context.Context, orgID int32, userID int32) (bool, error)
+	// ListOrganizationMembers lists direct members with the given status, or those of every unit in the subtree when includeDescendants is set
+	ListOrganizationMembers(ctx context.Context, orgID int32, includeDescendants bool, status string, limit int32, offset int32) (*PaginatedOrganizationMembers, error)
+	// CountOrganizationMembersByRole counts the organization's accepted direct members with the role
+	CountOrganizationMembersByRole(ctx context.Context, orgID int32, role string) (int64, error)
+	// SetOrganizationMember invites the user to the organization with the role, or changes the role
+	// of their existing membership or invitation without changing its status
+	SetOrganizationMember(ctx context.Context, orgID int32, userID int32, role string) (*OrganizationMember, error)
+	// AcceptOrganizationInvitation turns the user's pending invitation into a membership
+	AcceptOrganizationInvitation(ctx context.Context, orgID int32, userID int32) (*OrganizationMember, error)
+	// SetOrganizationMemberSharing records whether the member shares their private test results with the organization
+	SetOrganizationMemberSharing(ctx context.Context, orgID int32, userID int32, share bool) (*OrganizationMember, error)
+	// RemoveOrganizationMember removes a membership or withdraws an invitation
+	RemoveOrganizationMember(ctx context.Context, orgID int32, userID int32) error
+	// ListOrganizationRoster lists every accepted member of the organization's subtree once, with their highest role
+	// there; SharesTestResults is set if any of their memberships there shares test results
+	ListOrganizationRoster(ctx context.Context, orgID int32) ([]*OrganizationMember, error)
+	// ListOrganizationTestSessions lists the roster's test sessions taken on or after the given date, newest first.
+	// Private sessions are included only for members who share their test results with the subtree
+	ListOrganizationTestSessions(ctx context.Context, orgID int32, since time.Time) ([]*TestSession, error)
+}
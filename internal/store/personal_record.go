@@ -0,0 +1,101 @@
+package store
+
+import (
+	"context"
+	"time"
+)
+
+// PersonalRecordMetricGrade tracks the best grade in an exercise type. The other personal
+// record metrics are the grading.Metric an exercise is measured by, e.g. "reps".
+const PersonalRecordMetricGrade = "grade"
+
+// PersonalRecord is a best a user set in one metric of an exercise type. A new record is
+// stored each time the best improves, so the latest record per metric is the current best
+// and earlier ones are its history.
+type PersonalRecord struct {
+	ID            int32
+	UserID        int32
+	ExerciseType  string
+	Metric        string // A grading.Metric or PersonalRecordMetricGrade
+	Value         float64
+	PreviousValue *float64 // Best this record beat, nil for the first record
+	WorkoutID     int32    // Workout that set the record
+	AchievedAt    time.Time
+	CreatedAt     time.Time
+}
+
+// PaginatedPersonalRecords holds a page of personal records and total count.
+type PaginatedPersonalRecords struct {
+	Records    []*PersonalRecord
+	TotalCount int64
+}
+
+// PersonalRecordCandidate is a workout result that sets a personal record if it beats
+// the user's current best in the metric.
+type PersonalRecordCandidate struct {
+	Metric        string
+	Value         float64
+	LowerIsBetter bool // e.g. run times
+}
+
+// Beats reports whether the candidate improves on a best value in its metric.
+func (c PersonalRecordCandidate) Beats(best float64) bool {
+	if c.LowerIsBetter {
+		return c.Value < best
+	}
+	return c.Value > best
+}
+
+// PersonalRecordCandidatesFunc returns the results of a stored workout that could set a
+// personal record. Stores use it to re-evaluate workouts after edits and deletes.
+type PersonalRecordCandidatesFunc func(workout *WorkoutRecord) []PersonalRecordCandidate
+
+// ReplayPersonalRecords returns the records a user's workouts of one exercise type set,
+// given the workouts oldest first: each candidate that beats the best before it in its
+// metric. Records are returned in the order they were set.
+func ReplayPersonalRecords(workouts []*WorkoutRecord, candidatesFor PersonalRecordCandidatesFunc) []*PersonalRecord {
+	best := make(map[string]float64)
+	var records []*PersonalRecord
+	for _, workout := range workouts {
+		for _, candidate := range candidatesFor(workout) {
+			previous, ok := best[candidate.Metric]
+			if ok && !candidate.Beats(previous) {
+				continue
+			}
+			record := &PersonalRecord{
+				UserID:       workout.UserID,
+				ExerciseType: workout.ExerciseType,
+				Metric:       candidate.Metric,
+				Value:        candidate.Value,
+				WorkoutID:    workout.ID,
+				AchievedAt:   workout.CompletedAt,
+			}
+			if ok {
+				record.PreviousValue = &previous
+			}
+			best[candidate.Metric] = candidate.Value
+			records = append(records, record)
+		}
+	}
+	return records
+}
+
+// PersonalRecordStore defines methods for personal record data access
+type PersonalRecordStore interface {
+	// CreateWorkoutRecordWithPersonalRecords creates the workout and, in the same transaction,
+	// stores a personal record for each candidate that beats the user's current best for the
+	// exercise type, publishing the workout and records to the activity feed. It returns the
+	// records the workout set.
+	CreateWorkoutRecordWithPersonalRecords(ctx context.Context, record *WorkoutRecord, candidates []PersonalRecordCandidate) (*WorkoutRecord, []*PersonalRecord, error)
+	// UpdateWorkoutRecordWithPersonalRecords edits the workout like WorkoutStore.UpdateWorkoutRecord and,
+	// in the same transaction, rebuilds the user's personal records for its exercise type
+	UpdateWorkoutRecordWithPersonalRecords(ctx context.Context, actorID int32, record *WorkoutRecord, candidatesFor PersonalRecordCandidatesFunc) (*WorkoutRecord, error)
+	// DeleteWorkoutRecordWithPersonalRecords deletes the workout like WorkoutStore.DeleteWorkoutRecord and,
+	// in the same transaction, rebuilds the user's personal records for its exercise type
+	DeleteWorkoutRecordWithPersonalRecords(ctx context.Context, actorID int32, id int32, candidatesFor PersonalRecordCandidatesFunc) error
+	// GetUserPersonalRecords returns the user's current best per exercise type and metric
+	GetUserPersonalRecords(ctx context.Context, userID int32) ([]*PersonalRecord, error)
+	// GetPersonalRecordHistory returns every record the user set, newest first, optionally
+	// limited to one exercise type
+	GetPersonalRecordHistory(ctx context.Context, userID int32, exerciseType string, limit int32, offset int32) (*PaginatedPersonalRecords, error)
+}
@@ -0,0 +1,166 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"ptchampion/internal/store"
+)
+
+// achievementColumns selects the fields read by scanAchievementRow
+const achievementColumns = `id, user_id, badge_id, workout_id, earned_at`
+
+func scanAchievementRow(row rowScanner) (*store.Achievement, error) {
+	var a store.Achievement
+	var workoutID sql.NullInt32
+	if err := row.Scan(&a.ID, &a.UserID, &a.BadgeID, &workoutID, &a.EarnedAt); err != nil {
+		return nil, err
+	}
+	a.WorkoutID = nullInt32ToInt32Ptr(workoutID)
+	return &a, nil
+}
+
+// GetAchievementStats implements store.WorkoutStore
+func (s *Store) GetAchievementStats(ctx context.Context, userID int32) (*store.AchievementStats, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT e.type,
+		       COUNT(*),
+		       COALESCE(SUM(w.repetitions), 0),
+		       COALESCE(MAX(COALESCE(w.expected_grade, w.grade)) FILTER (WHERE w.verification_status <> 'mismatched'), 0),
+		       MIN(w.duration_seconds) FILTER (WHERE w.duration_seconds > 0)
+		FROM workouts w
+		JOIN exercises e ON e.id = w.exercise_id
+		WHERE w.user_id = $1 AND w.deleted_at IS NULL
+		GROUP BY e.type`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get achievement stats: %w", err)
+	}
+	defer rows.Close()
+
+	stats := &store.AchievementStats{Exercises: map[string]*store.ExerciseAchievementStats{}}
+	for rows.Next() {
+		var exerciseType string
+		var e store.ExerciseAchievementStats
+		var fastest sql.NullInt32
+		if err := rows.Scan(&exerciseType, &e.Workouts, &e.TotalReps, &e.BestGrade, &fastest); err != nil {
+			return nil, fmt.Errorf("failed to scan achievement stats row: %w", err)
+		}
+		e.FastestDurationSeconds = nullInt32ToInt32Ptr(fastest)
+		stats.Exercises[exerciseType] = &e
+		stats.TotalWorkouts += e.Workouts
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating achievement stats rows: %w", err)
+	}
+
+	if stats.ActiveDays, err = queryActiveDays(ctx, s.db, userID); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// ListUserAchievements implements store.WorkoutStore, earliest first
+func (s *Store) ListUserAchievements(ctx context.Context, userID int32) ([]*store.Achievement, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT `+achievementColumns+`
+		FROM user_achievements
+		WHERE user_id = $1
+		ORDER BY earned_at, id`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list achievements: %w", err)
+	}
+	defer rows.Close()
+
+	achievements := []*store.Achievement{}
+	for rows.Next() {
+		achievement, err := scanAchievementRow(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan achievement row: %w", err)
+		}
+		achievements = append(achievements, achievement)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating achievement rows: %w", err)
+	}
+	return achievements, nil
+}
+
+// AwardAchievements implements store.WorkoutStore. Awards are unique per user and badge,
+// so awarding a badge again leaves the first award as it is.
+func (s *Store) AwardAchievements(ctx context.Context, userID int32, workout *store.WorkoutRecord, awards []store.AchievementAward) ([]*store.Achievement, error) {
+	var workoutID *int32
+	if workout != nil {
+		workoutID = &workout.ID
+	}
+
+	achievements := []*store.Achievement{}
+	err := s.ExecTx(ctx, func(q *Queries) error {
+		for _, award := range awards {
+			achievement, err := scanAchievementRow(q.DB().QueryRowContext(ctx, `
+				INSERT INTO user_achievements (user_id, badge_id, workout_id)
+				VALUES ($1, $2, $3)
+				ON CONFLICT (user_id, badge_id) DO NOTHING
+				RETURNING `+achievementColumns,
+				userID, award.BadgeID, workoutID))
+			if err != nil {
+				if err == sql.ErrNoRows {
+					continue // Already awarded
+				}
+				return fmt.Errorf("failed to award achievement: %w", err)
+			}
+			achievements = append(achievements, achievement)
+
+			if workout == nil {
+				continue
+			}
+			err = publishActivity(ctx, q.DB(), &store.ActivityEvent{
+				ActorUserID: userID,
+				Type:        store.ActivityTypeAchievement,
+				WorkoutID:   workoutID,
+				Payload: &store.ActivityPayload{
+					ExerciseType: workout.ExerciseType,
+					ExerciseName: workout.ExerciseName,
+					BadgeID:      award.BadgeID,
+					BadgeName:    award.BadgeName,
+				},
+			}, achievement.ID, workout.Visibility)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return achievements, nil
+}
+
+// ListUserIDsWithWorkouts implements store.WorkoutStore
+func (s *Store) ListUserIDsWithWorkouts(ctx context.Context, afterID int32, limit int32) ([]int32, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT u.id
+		FROM users u
+		WHERE u.id > $1
+		  AND EXISTS (SELECT 1 FROM workouts w WHERE w.user_id = u.id AND w.deleted_at IS NULL)
+		ORDER BY u.id
+		LIMIT $2`, afterID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users with workouts: %w", err)
+	}
+	defer rows.Close()
+
+	ids := []int32{}
+	for rows.Next() {
+		var id int32
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan user ID row: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating user ID rows: %w", err)
+	}
+	return ids, nil
+}
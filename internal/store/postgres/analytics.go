@@ -0,0 +1,74 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"ptchampion/internal/store"
+)
+
+// GetWorkoutTimeSeries implements store.AnalyticsStore. Every exercise type the user has
+// logged gets a bucket for each interval from their first workout to now.
+func (s *Store) GetWorkoutTimeSeries(ctx context.Context, userID int32, interval string, exerciseType string) ([]*store.WorkoutSeriesBucket, error) {
+	query := `
+		WITH w AS (
+			SELECT exercise_type, date_trunc($2::text, completed_at AT TIME ZONE 'UTC') AS bucket,
+				repetitions, duration_seconds, grade
+			FROM workouts
+			WHERE user_id = $1 AND deleted_at IS NULL AND ($3 = '' OR exercise_type = $3)
+		),
+		span AS (
+			SELECT MIN(bucket) AS first_bucket,
+				GREATEST(MAX(bucket), date_trunc($2::text, NOW() AT TIME ZONE 'UTC')) AS last_bucket
+			FROM w
+		),
+		buckets AS (
+			SELECT generate_series(first_bucket, last_bucket, ('1 ' || $2::text)::interval) AS bucket
+			FROM span
+			WHERE first_bucket IS NOT NULL
+		),
+		agg AS (
+			SELECT exercise_type, bucket,
+				COUNT(*) AS workouts,
+				MAX(repetitions) AS max_reps,
+				COALESCE(SUM(repetitions), 0) AS volume,
+				AVG(grade)::float8 AS mean_grade,
+				AVG(duration_seconds)::float8 AS mean_duration
+			FROM w
+			GROUP BY exercise_type, bucket
+		)
+		SELECT t.exercise_type, b.bucket, COALESCE(a.workouts, 0), a.max_reps, COALESCE(a.volume, 0), a.mean_grade, a.mean_duration
+		FROM (SELECT DISTINCT exercise_type FROM w) t
+		CROSS JOIN buckets b
+		LEFT JOIN agg a ON a.exercise_type = t.exercise_type AND a.bucket = b.bucket
+		ORDER BY t.exercise_type, b.bucket`
+
+	rows, err := s.db.QueryContext(ctx, query, userID, interval, exerciseType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get workout time series: %w", err)
+	}
+	defer rows.Close()
+
+	buckets := []*store.WorkoutSeriesBucket{}
+	for rows.Next() {
+		var b store.WorkoutSeriesBucket
+		var maxReps sql.NullInt32
+		var meanGrade, meanDuration sql.NullFloat64
+		if err := rows.Scan(&b.ExerciseType, &b.Start, &b.Workouts, &maxReps, &b.Volume, &meanGrade, &meanDuration); err != nil {
+			return nil, fmt.Errorf("failed to scan workout time series: %w", err)
+		}
+		b.MaxReps = nullInt32ToInt32Ptr(maxReps)
+		if meanGrade.Valid {
+			b.MeanGrade = &meanGrade.Float64
+		}
+		if meanDuration.Valid {
+			b.MeanDurationSeconds = &meanDuration.Float64
+		}
+		buckets = append(buckets, &b)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate workout time series: %w", err)
+	}
+	return buckets, nil
+}
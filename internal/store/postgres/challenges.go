@@ -0,0 +1,397 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+
+	"ptchampion/internal/store"
+)
+
+// challengeColumns selects the fields read by scanChallengeRow from challenges c joined with exercises e
+const challengeColumns = `
+			c.id,
+			c.creator_id,
+			c.name,
+			c.description,
+			c.exercise_id,
+			e.type,
+			c.scoring_rule,
+			c.participant_type,
+			c.starts_at,
+			c.ends_at,
+			c.finalized_at,
+			(SELECT COUNT(*) FROM challenge_participants p WHERE p.challenge_id = c.id),
+			c.created_at`
+
+// challengeParticipantColumns selects the fields read by scanChallengeParticipantRow from
+// challenge_participants p, with the participant's name from users u or organizations o
+const challengeParticipantColumns = `p.id, p.challenge_id, p.user_id, p.organization_id, COALESCE(u.username, o.name), p.joined_at`
+
+const challengeParticipantFrom = `
+		FROM challenge_participants p
+		LEFT JOIN users u ON p.user_id = u.id
+		LEFT JOIN organizations o ON p.organization_id = o.id`
+
+// challengeRuleAggregates maps each scoring rule to the aggregate of a user's workouts
+var challengeRuleAggregates = map[string]string{
+	store.ChallengeRuleMaxReps:     "MAX(w.repetitions)",
+	store.ChallengeRuleTotalVolume: "SUM(w.repetitions)",
+	store.ChallengeRuleBestTime:    "MIN(w.duration_seconds)",
+}
+
+// CreateChallenge implements store.ChallengeStore
+func (s *Store) CreateChallenge(ctx context.Context, challenge *store.Challenge) (*store.Challenge, error) {
+	var id int32
+	err := s.db.QueryRowContext(ctx, `
+		INSERT INTO challenges (creator_id, name, description, exercise_id, scoring_rule, participant_type, starts_at, ends_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id`,
+		challenge.CreatorID, challenge.Name, stringPtrToNullString(challenge.Description), challenge.ExerciseID,
+		challenge.ScoringRule, challenge.ParticipantType, challenge.StartsAt, challenge.EndsAt,
+	).Scan(&id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create challenge: %w", err)
+	}
+	return s.GetChallengeByID(ctx, id)
+}
+
+// GetChallengeByID implements store.ChallengeStore
+func (s *Store) GetChallengeByID(ctx context.Context, id int32) (*store.Challenge, error) {
+	query := `
+		SELECT ` + challengeColumns + `
+		FROM challenges c
+		JOIN exercises e ON c.exercise_id = e.id
+		WHERE c.id = $1`
+
+	challenge, err := scanChallengeRow(s.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, store.ErrChallengeNotFound
+		}
+		return nil, fmt.Errorf("failed to get challenge: %w", err)
+	}
+	return challenge, nil
+}
+
+// ListChallenges implements store.ChallengeStore, ordering challenges by end time with
+// the soonest to close first
+func (s *Store) ListChallenges(ctx context.Context, filters store.ChallengeFilters, limit int32, offset int32) (*store.PaginatedChallenges, error) {
+	where := ""
+	order := "c.ends_at ASC, c.id"
+	var args []interface{}
+	switch filters.Status {
+	case "active":
+		where = "WHERE c.starts_at <= $1 AND c.ends_at > $1"
+		args = append(args, filters.At)
+	case "upcoming":
+		where = "WHERE c.starts_at > $1"
+		order = "c.starts_at ASC, c.id"
+		args = append(args, filters.At)
+	case "closed":
+		where = "WHERE c.ends_at <= $1"
+		order = "c.ends_at DESC, c.id" // Most recently closed first
+		args = append(args, filters.At)
+	}
+
+	var count int64
+	if err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM challenges c `+where, args...).Scan(&count); err != nil {
+		return nil, fmt.Errorf("failed to count challenges: %w", err)
+	}
+	if count == 0 {
+		return &store.PaginatedChallenges{
+			Challenges: []*store.Challenge{},
+			TotalCount: 0,
+		}, nil
+	}
+
+	query := fmt.Sprintf(`
+		SELECT `+challengeColumns+`
+		FROM challenges c
+		JOIN exercises e ON c.exercise_id = e.id
+		%s
+		ORDER BY %s
+		LIMIT $%d OFFSET $%d`, where, order, len(args)+1, len(args)+2)
+
+	rows, err := s.db.QueryContext(ctx, query, append(args, limit, offset)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list challenges: %w", err)
+	}
+	defer rows.Close()
+
+	challenges := make([]*store.Challenge, 0)
+	for rows.Next() {
+		challenge, err := scanChallengeRow(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan challenge row: %w", err)
+		}
+		challenges = append(challenges, challenge)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating challenge rows: %w", err)
+	}
+
+	return &store.PaginatedChallenges{
+		Challenges: challenges,
+		TotalCount: count,
+	}, nil
+}
+
+// AddChallengeParticipant implements store.ChallengeStore
+func (s *Store) AddChallengeParticipant(ctx context.Context, challengeID int32, userID *int32, orgID *int32) (*store.ChallengeParticipant, error) {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO challenge_participants (challenge_id, user_id, organization_id)
+		VALUES ($1, $2, $3)`, challengeID, int32PtrToNullInt32(userID), int32PtrToNullInt32(orgID))
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" { // unique_violation
+			return nil, store.ErrChallengeParticipantExists
+		}
+		return nil, fmt.Errorf("failed to add challenge participant: %w", err)
+	}
+	return s.GetChallengeParticipant(ctx, challengeID, userID, orgID)
+}
+
+// GetChallengeParticipant implements store.ChallengeStore
+func (s *Store) GetChallengeParticipant(ctx context.Context, challengeID int32, userID *int32, orgID *int32) (*store.ChallengeParticipant, error) {
+	query := `SELECT ` + challengeParticipantColumns + challengeParticipantFrom + `
+		WHERE p.challenge_id = $1 AND p.user_id IS NOT DISTINCT FROM $2 AND p.organization_id IS NOT DISTINCT FROM $3`
+
+	participant, err := scanChallengeParticipantRow(s.db.QueryRowContext(ctx, query, challengeID,
+		int32PtrToNullInt32(userID), int32PtrToNullInt32(orgID)))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, store.ErrChallengeParticipantNotFound
+		}
+		return nil, fmt.Errorf("failed to get challenge participant: %w", err)
+	}
+	return participant, nil
+}
+
+// RemoveChallengeParticipant implements store.ChallengeStore
+func (s *Store) RemoveChallengeParticipant(ctx context.Context, challengeID int32, participantID int32) error {
+	result, err := s.db.ExecContext(ctx, `
+		DELETE FROM challenge_participants WHERE challenge_id = $1 AND id = $2`, challengeID, participantID)
+	if err != nil {
+		return fmt.Errorf("failed to remove challenge participant: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return store.ErrChallengeParticipantNotFound
+	}
+	return nil
+}
+
+// ListChallengeParticipants implements store.ChallengeStore, in joining order
+func (s *Store) ListChallengeParticipants(ctx context.Context, challengeID int32) ([]*store.ChallengeParticipant, error) {
+	query := `SELECT ` + challengeParticipantColumns + challengeParticipantFrom + `
+		WHERE p.challenge_id = $1
+		ORDER BY p.joined_at, p.id`
+
+	rows, err := s.db.QueryContext(ctx, query, challengeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list challenge participants: %w", err)
+	}
+	defer rows.Close()
+
+	participants := make([]*store.ChallengeParticipant, 0)
+	for rows.Next() {
+		participant, err := scanChallengeParticipantRow(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan challenge participant row: %w", err)
+		}
+		participants = append(participants, participant)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating challenge participant rows: %w", err)
+	}
+	return participants, nil
+}
+
+// GetChallengeMemberResults implements store.ChallengeStore. Units count the members of
+// all their subunits; workouts flagged as mismatched by verification do not count.
+func (s *Store) GetChallengeMemberResults(ctx context.Context, challenge *store.Challenge) ([]*store.ChallengeMemberResult, error) {
+	aggregate, ok := challengeRuleAggregates[challenge.ScoringRule]
+	if !ok {
+		return nil, fmt.Errorf("unknown scoring rule %q", challenge.ScoringRule)
+	}
+
+	query := `
+		WITH RECURSIVE team_units AS (
+			SELECT p.id AS participant_id, p.organization_id AS org_id
+			FROM challenge_participants p
+			WHERE p.challenge_id = $1 AND p.organization_id IS NOT NULL
+			UNION ALL
+			SELECT t.participant_id, o.id FROM organizations o JOIN team_units t ON o.parent_id = t.org_id
+		),
+		participant_users AS (
+			SELECT p.id AS participant_id, p.user_id
+			FROM challenge_participants p
+			WHERE p.challenge_id = $1 AND p.user_id IS NOT NULL
+			UNION
+			SELECT t.participant_id, m.user_id
+			FROM team_units t
+			JOIN organization_memberships m ON m.organization_id = t.org_id AND m.status = 'accepted'
+		)
+		SELECT pu.participant_id, pu.user_id, ` + aggregate + `::float8 AS value
+		FROM participant_users pu
+		JOIN workouts w ON w.user_id = pu.user_id
+		WHERE w.exercise_id = $2
+		  AND w.deleted_at IS NULL
+		  AND w.verification_status <> 'mismatched'
+		  AND w.completed_at >= $3
+		  AND w.completed_at < $4
+		GROUP BY pu.participant_id, pu.user_id
+		HAVING ` + aggregate + ` IS NOT NULL
+		ORDER BY pu.participant_id, pu.user_id`
+
+	rows, err := s.db.QueryContext(ctx, query, challenge.ID, challenge.ExerciseID, challenge.StartsAt, challenge.EndsAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get challenge results: %w", err)
+	}
+	defer rows.Close()
+
+	results := make([]*store.ChallengeMemberResult, 0)
+	for rows.Next() {
+		var result store.ChallengeMemberResult
+		if err := rows.Scan(&result.ParticipantID, &result.UserID, &result.Value); err != nil {
+			return nil, fmt.Errorf("failed to scan challenge result row: %w", err)
+		}
+		results = append(results, &result)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating challenge result rows: %w", err)
+	}
+	return results, nil
+}
+
+// FinalizeChallenge implements store.ChallengeStore, marking the challenge finalized,
+// storing every standing and publishing individual results in one transaction
+func (s *Store) FinalizeChallenge(ctx context.Context, challengeID int32, standings []*store.ChallengeStanding) (bool, error) {
+	finalized := false
+	err := s.ExecTx(ctx, func(q *Queries) error {
+		result, err := q.DB().ExecContext(ctx, `
+			UPDATE challenges SET finalized_at = NOW()
+			WHERE id = $1 AND finalized_at IS NULL AND ends_at <= NOW()`, challengeID)
+		if err != nil {
+			return fmt.Errorf("failed to finalize challenge: %w", err)
+		}
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		if rowsAffected == 0 {
+			return nil // Finalized concurrently, or not yet closed
+		}
+
+		for _, standing := range standings {
+			var rank sql.NullInt32
+			var score sql.NullFloat64
+			if standing.Score != nil {
+				rank = sql.NullInt32{Int32: standing.Rank, Valid: true}
+				score = sql.NullFloat64{Float64: *standing.Score, Valid: true}
+			}
+			_, err := q.DB().ExecContext(ctx, `
+				UPDATE challenge_participants
+				SET final_rank = $3, final_score = $4, final_contributors = $5
+				WHERE challenge_id = $1 AND id = $2`,
+				challengeID, standing.Participant.ID, rank, score, standing.Contributors)
+			if err != nil {
+				return fmt.Errorf("failed to store challenge result: %w", err)
+			}
+		}
+		if err := publishChallengeResults(ctx, q.DB(), challengeID, standings); err != nil {
+			return err
+		}
+		finalized = true
+		return nil
+	})
+	return finalized, err
+}
+
+// GetChallengeResults implements store.ChallengeStore, ordering ranked participants first
+func (s *Store) GetChallengeResults(ctx context.Context, challengeID int32) ([]*store.ChallengeStanding, error) {
+	query := `SELECT ` + challengeParticipantColumns + `, p.final_rank, p.final_score, p.final_contributors` + challengeParticipantFrom + `
+		WHERE p.challenge_id = $1
+		ORDER BY p.final_rank ASC NULLS LAST, p.joined_at, p.id`
+
+	rows, err := s.db.QueryContext(ctx, query, challengeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get challenge results: %w", err)
+	}
+	defer rows.Close()
+
+	standings := make([]*store.ChallengeStanding, 0)
+	for rows.Next() {
+		var rank sql.NullInt32
+		var score sql.NullFloat64
+		var contributors sql.NullInt32
+		participant, err := scanChallengeParticipantRow(rows, &rank, &score, &contributors)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan challenge result row: %w", err)
+		}
+		standing := &store.ChallengeStanding{
+			Participant:  participant,
+			Rank:         rank.Int32,
+			Contributors: int(contributors.Int32),
+		}
+		if score.Valid {
+			standing.Score = &score.Float64
+		}
+		standings = append(standings, standing)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating challenge result rows: %w", err)
+	}
+	return standings, nil
+}
+
+// scanChallengeRow reads a row selected with challengeColumns
+func scanChallengeRow(row rowScanner) (*store.Challenge, error) {
+	var c store.Challenge
+	var description sql.NullString
+	var finalizedAt sql.NullTime
+	err := row.Scan(
+		&c.ID,
+		&c.CreatorID,
+		&c.Name,
+		&description,
+		&c.ExerciseID,
+		&c.ExerciseType,
+		&c.ScoringRule,
+		&c.ParticipantType,
+		&c.StartsAt,
+		&c.EndsAt,
+		&finalizedAt,
+		&c.Participants,
+		&c.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	c.Description = nullStringToStringPtr(description)
+	if finalizedAt.Valid {
+		c.FinalizedAt = &finalizedAt.Time
+	}
+	return &c, nil
+}
+
+// scanChallengeParticipantRow reads a row selected with challengeParticipantColumns,
+// followed by any trailing columns
+func scanChallengeParticipantRow(row rowScanner, trailing ...interface{}) (*store.ChallengeParticipant, error) {
+	var p store.ChallengeParticipant
+	var userID, orgID sql.NullInt32
+	dest := append([]interface{}{&p.ID, &p.ChallengeID, &userID, &orgID, &p.Name, &p.JoinedAt}, trailing...)
+	if err := row.Scan(dest...); err != nil {
+		return nil, err
+	}
+	p.UserID = nullInt32ToInt32Ptr(userID)
+	p.OrganizationID = nullInt32ToInt32Ptr(orgID)
+	return &p, nil
+}
@@ -81,6 +81,7 @@ WHERE
     e.type = $1
     AND w.grade IS NOT NULL
     AND w.is_public = true
+    AND w.deleted_at IS NULL
 GROUP BY 
     u.id, u.username, u.first_name, u.last_name
 ORDER BY 
@@ -129,7 +130,7 @@ const getUserWorkoutsByType = `-- name: GetUserWorkoutsByType :many
 SELECT w.id, w.user_id, w.exercise_id, w.exercise_type, w.repetitions, w.duration_seconds, w.form_score, w.grade, w.is_public, w.completed_at, w.created_at, w.device_id, w.metadata, w.notes, e.name as exercise_name, e.type as exercise_type
 FROM workouts w
 JOIN exercises e ON e.id = w.exercise_id
-WHERE w.user_id = $1 AND e.type = $2
+WHERE w.user_id = $1 AND e.type = $2 AND w.deleted_at IS NULL
 ORDER BY w.created_at DESC
 `
 
@@ -216,6 +217,7 @@ JOIN
     exercises e ON w.exercise_id = e.id
 WHERE
     w.user_id = $1
+    AND w.deleted_at IS NULL
 ORDER BY
     w.created_at DESC
 LIMIT $2
@@ -0,0 +1,407 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"ptchampion/internal/store"
+)
+
+// activityEventColumns selects the fields read by scanActivityEventRow from activity_events a
+// joined with users u, including the engagement seen by the reading user $1
+const activityEventColumns = `a.id, a.actor_user_id, u.username, a.event_type, a.workout_id, a.payload, a.created_at,
+	COALESCE((
+		SELECT jsonb_object_agg(rc.reaction, rc.n)
+		FROM (SELECT r.reaction, COUNT(*) AS n FROM activity_reactions r WHERE r.event_id = a.id GROUP BY r.reaction) rc
+	), '{}'::jsonb),
+	(SELECT COUNT(*) FROM activity_comments c WHERE c.event_id = a.id),
+	COALESCE((SELECT r.reaction FROM activity_reactions r WHERE r.event_id = a.id AND r.user_id = $1), '')`
+
+const activityEventFrom = `
+	JOIN users u ON u.id = a.actor_user_id
+	LEFT JOIN workouts w ON w.id = a.workout_id`
+
+// activityVisible limits activity_events a to the events the user $1 may see: their own,
+// and their current friends' unless tied to a workout that is deleted or now private.
+const activityVisible = `
+	(a.workout_id IS NULL OR w.deleted_at IS NULL)
+	AND (a.actor_user_id = $1 OR (
+		(a.workout_id IS NULL OR w.visibility <> 'private')
+		AND EXISTS (
+			SELECT 1 FROM friendships fr
+			WHERE fr.status = 'accepted'
+			  AND ((fr.requester_id = $1 AND fr.addressee_id = a.actor_user_id)
+			    OR (fr.requester_id = a.actor_user_id AND fr.addressee_id = $1))
+		)
+	))`
+
+// notBlocked limits rows written by user_id column col to users the user $1 has not
+// blocked and is not blocked by
+func notBlocked(col string) string {
+	return `NOT EXISTS (
+		SELECT 1 FROM friendships fb
+		WHERE fb.status = 'blocked'
+		  AND ((fb.requester_id = $1 AND fb.addressee_id = ` + col + `)
+		    OR (fb.requester_id = ` + col + ` AND fb.addressee_id = $1))
+	)`
+}
+
+// publishActivity stores an event and fans it out to the feed of the actor and, unless the
+// event is private, their friends. An event already published for the same source is left
+// as it is.
+func publishActivity(ctx context.Context, db DBTX, event *store.ActivityEvent, sourceID int32, visibility string) error {
+	payload, err := json.Marshal(event.Payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode activity payload: %w", err)
+	}
+
+	var eventID int32
+	err = db.QueryRowContext(ctx, `
+		INSERT INTO activity_events (actor_user_id, event_type, source_id, workout_id, payload)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (event_type, source_id, actor_user_id) DO NOTHING
+		RETURNING id`,
+		event.ActorUserID, event.Type, sourceID, event.WorkoutID, payload,
+	).Scan(&eventID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil // Already published
+		}
+		return fmt.Errorf("failed to create activity event: %w", err)
+	}
+
+	if visibility == store.WorkoutVisibilityPrivate {
+		_, err = db.ExecContext(ctx, `INSERT INTO feed_entries (user_id, event_id) VALUES ($1, $2)`, event.ActorUserID, eventID)
+	} else {
+		_, err = db.ExecContext(ctx, friendsOf+`
+			INSERT INTO feed_entries (user_id, event_id)
+			SELECT user_id, $2::int FROM friends`, event.ActorUserID, eventID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to fan out activity event: %w", err)
+	}
+	return nil
+}
+
+// publishWorkoutActivity publishes a logged workout and the personal records it set
+func publishWorkoutActivity(ctx context.Context, db DBTX, workout *store.WorkoutRecord, records []*store.PersonalRecord) error {
+	workoutID := workout.ID
+	err := publishActivity(ctx, db, &store.ActivityEvent{
+		ActorUserID: workout.UserID,
+		Type:        store.ActivityTypeWorkout,
+		WorkoutID:   &workoutID,
+		Payload:     workoutActivityPayload(workout),
+	}, workout.ID, workout.Visibility)
+	if err != nil {
+		return err
+	}
+
+	for _, pr := range records {
+		err := publishActivity(ctx, db, &store.ActivityEvent{
+			ActorUserID: workout.UserID,
+			Type:        store.ActivityTypePersonalRecord,
+			WorkoutID:   &workoutID,
+			Payload:     personalRecordActivityPayload(workout, pr),
+		}, pr.ID, workout.Visibility)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// workoutActivityPayload describes a workout in its feed event
+func workoutActivityPayload(workout *store.WorkoutRecord) *store.ActivityPayload {
+	grade := workout.Grade
+	return &store.ActivityPayload{
+		ExerciseType:    workout.ExerciseType,
+		ExerciseName:    workout.ExerciseName,
+		Grade:           &grade,
+		Reps:            workout.Reps,
+		DurationSeconds: workout.DurationSeconds,
+		WeightLbs:       workout.WeightLbs,
+		DistanceMeters:  workout.DistanceMeters,
+	}
+}
+
+// personalRecordActivityPayload describes a personal record set by workout in its feed event
+func personalRecordActivityPayload(workout *store.WorkoutRecord, pr *store.PersonalRecord) *store.ActivityPayload {
+	value := pr.Value
+	return &store.ActivityPayload{
+		ExerciseType:  pr.ExerciseType,
+		ExerciseName:  workout.ExerciseName,
+		Metric:        pr.Metric,
+		Value:         &value,
+		PreviousValue: pr.PreviousValue,
+	}
+}
+
+// refreshWorkoutActivity brings the feed after a change to a workout in line with it. The
+// workout event is rewritten from the stored record, and unless the workout is private or
+// deleted its events are fanned out to the actor's friends that do not have them yet,
+// such as when it was logged private. Events of workouts made private or deleted are
+// hidden when feeds are read.
+func refreshWorkoutActivity(ctx context.Context, db DBTX, workout *store.WorkoutRecord) error {
+	payload, err := json.Marshal(workoutActivityPayload(workout))
+	if err != nil {
+		return fmt.Errorf("failed to encode activity payload: %w", err)
+	}
+	_, err = db.ExecContext(ctx, `
+		UPDATE activity_events SET payload = $4
+		WHERE event_type = $1 AND source_id = $2 AND actor_user_id = $3`,
+		store.ActivityTypeWorkout, workout.ID, workout.UserID, payload)
+	if err != nil {
+		return fmt.Errorf("failed to refresh workout activity: %w", err)
+	}
+
+	if workout.Visibility == store.WorkoutVisibilityPrivate || workout.DeletedAt != nil {
+		return nil
+	}
+	_, err = db.ExecContext(ctx, friendsOf+`
+		INSERT INTO feed_entries (user_id, event_id)
+		SELECT f.user_id, a.id FROM friends f CROSS JOIN activity_events a
+		WHERE a.workout_id = $2
+		ON CONFLICT DO NOTHING`, workout.UserID, workout.ID)
+	if err != nil {
+		return fmt.Errorf("failed to fan out workout activity: %w", err)
+	}
+	return nil
+}
+
+// publishChallengeResults publishes the result of each individual participant in a
+// finalized challenge. Team results belong to units and are not published.
+func publishChallengeResults(ctx context.Context, db DBTX, challengeID int32, standings []*store.ChallengeStanding) error {
+	var name, exerciseType string
+	err := db.QueryRowContext(ctx, `
+		SELECT c.name, e.type FROM challenges c JOIN exercises e ON e.id = c.exercise_id
+		WHERE c.id = $1`, challengeID).Scan(&name, &exerciseType)
+	if err != nil {
+		return fmt.Errorf("failed to get challenge for activity: %w", err)
+	}
+
+	participants := len(standings)
+	for _, standing := range standings {
+		if standing.Participant.UserID == nil || standing.Score == nil {
+			continue
+		}
+		id, rank, score := challengeID, standing.Rank, *standing.Score
+		err := publishActivity(ctx, db, &store.ActivityEvent{
+			ActorUserID: *standing.Participant.UserID,
+			Type:        store.ActivityTypeChallengeResult,
+			Payload: &store.ActivityPayload{
+				ExerciseType:  exerciseType,
+				ChallengeID:   &id,
+				ChallengeName: name,
+				Rank:          &rank,
+				Score:         &score,
+				Participants:  &participants,
+			},
+		}, challengeID, store.WorkoutVisibilityFriends)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetFeed implements store.FeedStore
+func (s *Store) GetFeed(ctx context.Context, userID int32, before int32, limit int32) (*store.FeedPage, error) {
+	query := `
+		SELECT ` + activityEventColumns + `
+		FROM feed_entries f
+		JOIN activity_events a ON a.id = f.event_id` + activityEventFrom + `
+		WHERE f.user_id = $1
+		  AND ($2::int = 0 OR f.event_id < $2::int)
+		  AND ` + activityVisible + `
+		ORDER BY f.event_id DESC
+		LIMIT $3`
+
+	// Read one extra event to tell whether there is a next page
+	rows, err := s.db.QueryContext(ctx, query, userID, before, limit+1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get feed: %w", err)
+	}
+	defer rows.Close()
+
+	page := &store.FeedPage{Events: []*store.ActivityEvent{}}
+	for rows.Next() {
+		event, err := scanActivityEventRow(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan activity event row: %w", err)
+		}
+		page.Events = append(page.Events, event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating activity event rows: %w", err)
+	}
+
+	if int32(len(page.Events)) > limit {
+		page.Events = page.Events[:limit]
+		page.NextCursor = page.Events[limit-1].ID
+	}
+	return page, nil
+}
+
+// GetActivityEvent implements store.FeedStore
+func (s *Store) GetActivityEvent(ctx context.Context, userID int32, eventID int32) (*store.ActivityEvent, error) {
+	query := `
+		SELECT ` + activityEventColumns + `
+		FROM activity_events a` + activityEventFrom + `
+		WHERE a.id = $2 AND ` + activityVisible
+
+	event, err := scanActivityEventRow(s.db.QueryRowContext(ctx, query, userID, eventID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, store.ErrActivityEventNotFound
+		}
+		return nil, fmt.Errorf("failed to get activity event: %w", err)
+	}
+	return event, nil
+}
+
+// SetActivityReaction implements store.FeedStore
+func (s *Store) SetActivityReaction(ctx context.Context, eventID int32, userID int32, reaction string) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO activity_reactions (event_id, user_id, reaction)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (event_id, user_id) DO UPDATE SET reaction = EXCLUDED.reaction, created_at = NOW()`,
+		eventID, userID, reaction)
+	if err != nil {
+		return fmt.Errorf("failed to set activity reaction: %w", err)
+	}
+	return nil
+}
+
+// DeleteActivityReaction implements store.FeedStore
+func (s *Store) DeleteActivityReaction(ctx context.Context, eventID int32, userID int32) error {
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM activity_reactions WHERE event_id = $1 AND user_id = $2`, eventID, userID); err != nil {
+		return fmt.Errorf("failed to delete activity reaction: %w", err)
+	}
+	return nil
+}
+
+// activityCommentColumns selects the fields read by scanActivityCommentRow from
+// activity_comments c joined with users u
+const activityCommentColumns = `c.id, c.event_id, c.user_id, u.username, c.body, c.created_at`
+
+// CreateActivityComment implements store.FeedStore
+func (s *Store) CreateActivityComment(ctx context.Context, comment *store.ActivityComment) (*store.ActivityComment, error) {
+	query := `
+		WITH c AS (
+			INSERT INTO activity_comments (event_id, user_id, body)
+			VALUES ($1, $2, $3)
+			RETURNING id, event_id, user_id, body, created_at
+		)
+		SELECT ` + activityCommentColumns + ` FROM c JOIN users u ON u.id = c.user_id`
+
+	created, err := scanActivityCommentRow(s.db.QueryRowContext(ctx, query, comment.EventID, comment.UserID, comment.Body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create activity comment: %w", err)
+	}
+	return created, nil
+}
+
+// GetActivityComment implements store.FeedStore
+func (s *Store) GetActivityComment(ctx context.Context, eventID int32, commentID int32) (*store.ActivityComment, error) {
+	query := `SELECT ` + activityCommentColumns + `
+		FROM activity_comments c JOIN users u ON u.id = c.user_id
+		WHERE c.event_id = $1 AND c.id = $2`
+
+	comment, err := scanActivityCommentRow(s.db.QueryRowContext(ctx, query, eventID, commentID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, store.ErrActivityCommentNotFound
+		}
+		return nil, fmt.Errorf("failed to get activity comment: %w", err)
+	}
+	return comment, nil
+}
+
+// DeleteActivityComment implements store.FeedStore
+func (s *Store) DeleteActivityComment(ctx context.Context, commentID int32) error {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM activity_comments WHERE id = $1`, commentID)
+	if err != nil {
+		return fmt.Errorf("failed to delete activity comment: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check deleted activity comment: %w", err)
+	}
+	if affected == 0 {
+		return store.ErrActivityCommentNotFound
+	}
+	return nil
+}
+
+// ListActivityComments implements store.FeedStore
+func (s *Store) ListActivityComments(ctx context.Context, userID int32, eventID int32, limit int32, offset int32) (*store.PaginatedActivityComments, error) {
+	from := `
+		FROM activity_comments c
+		JOIN users u ON u.id = c.user_id
+		WHERE c.event_id = $2 AND ` + notBlocked("c.user_id")
+
+	var count int64
+	if err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) `+from, userID, eventID).Scan(&count); err != nil {
+		return nil, fmt.Errorf("failed to count activity comments: %w", err)
+	}
+	if count == 0 {
+		return &store.PaginatedActivityComments{
+			Comments:   []*store.ActivityComment{},
+			TotalCount: 0,
+		}, nil
+	}
+
+	rows, err := s.db.QueryContext(ctx, `SELECT `+activityCommentColumns+from+`
+		ORDER BY c.id
+		LIMIT $3 OFFSET $4`, userID, eventID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list activity comments: %w", err)
+	}
+	defer rows.Close()
+
+	comments := make([]*store.ActivityComment, 0)
+	for rows.Next() {
+		comment, err := scanActivityCommentRow(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan activity comment row: %w", err)
+		}
+		comments = append(comments, comment)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating activity comment rows: %w", err)
+	}
+	return &store.PaginatedActivityComments{
+		Comments:   comments,
+		TotalCount: count,
+	}, nil
+}
+
+// scanActivityEventRow reads a row selected with activityEventColumns
+func scanActivityEventRow(row rowScanner) (*store.ActivityEvent, error) {
+	var e store.ActivityEvent
+	var workoutID sql.NullInt32
+	var payload, reactions []byte
+	if err := row.Scan(&e.ID, &e.ActorUserID, &e.ActorUsername, &e.Type, &workoutID, &payload, &e.CreatedAt,
+		&reactions, &e.Comments, &e.UserReaction); err != nil {
+		return nil, err
+	}
+	e.WorkoutID = nullInt32ToInt32Ptr(workoutID)
+	if err := json.Unmarshal(payload, &e.Payload); err != nil {
+		return nil, fmt.Errorf("failed to decode activity payload: %w", err)
+	}
+	if err := json.Unmarshal(reactions, &e.Reactions); err != nil {
+		return nil, fmt.Errorf("failed to decode activity reactions: %w", err)
+	}
+	return &e, nil
+}
+
+// scanActivityCommentRow reads a row selected with activityCommentColumns
+func scanActivityCommentRow(row rowScanner) (*store.ActivityComment, error) {
+	var c store.ActivityComment
+	if err := row.Scan(&c.ID, &c.EventID, &c.UserID, &c.Username, &c.Body, &c.CreatedAt); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
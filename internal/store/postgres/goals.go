@@ -0,0 +1,105 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+
+	"ptchampion/internal/store"
+)
+
+// goalColumns selects the fields read by scanGoalRow
+const goalColumns = `id, user_id, goal_type, exercise_type, scoring_standard, target_value, target_date, created_at`
+
+func scanGoalRow(row rowScanner) (*store.Goal, error) {
+	var g store.Goal
+	var exerciseType sql.NullString
+	if err := row.Scan(&g.ID, &g.UserID, &g.Type, &exerciseType, &g.ScoringStandard, &g.TargetValue, &g.TargetDate, &g.CreatedAt); err != nil {
+		return nil, err
+	}
+	g.ExerciseType = exerciseType.String
+	return &g, nil
+}
+
+// ListUserWorkoutRecordsSince implements store.WorkoutStore
+func (s *Store) ListUserWorkoutRecordsSince(ctx context.Context, userID int32, exerciseTypes []string, since time.Time) ([]*store.WorkoutRecord, error) {
+	return queryWorkoutRecords(ctx, s.db, `
+		WHERE w.user_id = $1 AND e.type = ANY($2) AND w.completed_at >= $3 AND w.deleted_at IS NULL
+		ORDER BY w.completed_at, w.id`,
+		userID, pq.Array(exerciseTypes), since)
+}
+
+// CreateGoal implements store.WorkoutStore
+func (s *Store) CreateGoal(ctx context.Context, goal *store.Goal) (*store.Goal, error) {
+	var exerciseType sql.NullString
+	if goal.ExerciseType != "" {
+		exerciseType = sql.NullString{String: goal.ExerciseType, Valid: true}
+	}
+	created, err := scanGoalRow(s.db.QueryRowContext(ctx, `
+		INSERT INTO goals (user_id, goal_type, exercise_type, scoring_standard, target_value, target_date)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING `+goalColumns,
+		goal.UserID, goal.Type, exerciseType, goal.ScoringStandard, goal.TargetValue, goal.TargetDate.Format("2006-01-02"),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create goal: %w", err)
+	}
+	return created, nil
+}
+
+// GetGoalByID implements store.WorkoutStore
+func (s *Store) GetGoalByID(ctx context.Context, id int32) (*store.Goal, error) {
+	goal, err := scanGoalRow(s.db.QueryRowContext(ctx, `SELECT `+goalColumns+` FROM goals WHERE id = $1`, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, store.ErrGoalNotFound
+		}
+		return nil, fmt.Errorf("failed to get goal: %w", err)
+	}
+	return goal, nil
+}
+
+// ListUserGoals implements store.WorkoutStore, soonest target date first
+func (s *Store) ListUserGoals(ctx context.Context, userID int32) ([]*store.Goal, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT `+goalColumns+`
+		FROM goals
+		WHERE user_id = $1
+		ORDER BY target_date, id`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list goals: %w", err)
+	}
+	defer rows.Close()
+
+	goals := []*store.Goal{}
+	for rows.Next() {
+		goal, err := scanGoalRow(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan goal: %w", err)
+		}
+		goals = append(goals, goal)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate goals: %w", err)
+	}
+	return goals, nil
+}
+
+// DeleteGoal implements store.WorkoutStore
+func (s *Store) DeleteGoal(ctx context.Context, id int32) error {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM goals WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete goal: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to delete goal: %w", err)
+	}
+	if affected == 0 {
+		return store.ErrGoalNotFound
+	}
+	return nil
+}
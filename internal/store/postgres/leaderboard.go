@@ -34,9 +34,10 @@ type LocalLeaderboardParams struct {
 	Latitude     float64 // Center point latitude
 	Longitude    float64 // Center point longitude
 	RadiusMeters float64 // Search radius in meters
-	ExerciseType string  // Type of exercise (push_up, pull_up, etc.)
-	Limit        int     // Maximum number of results to return
-	TimeFrame    string  // Time period filter (daily, weekly, monthly, all_time)
+	ExerciseType    string  // Type of exercise (push_up, pull_up, etc.)
+	ScoringStandard string  // Only grades under this standard are ranked
+	Limit           int     // Maximum number of results to return
+	TimeFrame       string  // Time period filter (daily, weekly, monthly, all_time)
 }
 
 // GlobalLeaderboardParams contains parameters for global leaderboard queries
@@ -55,12 +56,15 @@ func (r *LeaderboardRepository) GetLocalLeaderboardWithParams(ctx context.Contex
 			SELECT 
 				w.user_id,
 				w.exercise_type,
-				MAX(w.grade) AS best_score,
-				ROW_NUMBER() OVER (ORDER BY MAX(w.grade) DESC) AS rank
+				MAX(COALESCE(w.expected_grade, w.grade)) AS best_score,
+				ROW_NUMBER() OVER (ORDER BY MAX(COALESCE(w.expected_grade, w.grade)) DESC) AS rank
 			FROM workouts w
 			JOIN users u ON w.user_id = u.id
 			WHERE w.exercise_type = $1
+			AND w.scoring_standard = $6
 			AND u.is_public = true
+			AND w.verification_status <> 'mismatched'
+			AND w.deleted_at IS NULL
 			GROUP BY w.user_id, w.exercise_type
 		)
 		SELECT 
@@ -75,7 +79,7 @@ func (r *LeaderboardRepository) GetLocalLeaderboardWithParams(ctx context.Contex
 			MAX(w.completed_at) AS last_updated
 		FROM ranked_workouts rw
 		JOIN users u ON rw.user_id = u.id
-		JOIN workouts w ON rw.user_id = w.user_id AND rw.exercise_type = w.exercise_type
+		JOIN workouts w ON rw.user_id = w.user_id AND rw.exercise_type = w.exercise_type AND w.deleted_at IS NULL
 		WHERE u.last_location IS NOT NULL
 		AND u.is_public = true
 		AND ST_DWithin(u.last_location::geography, ST_MakePoint($2, $3)::geography, $4)
@@ -89,7 +93,8 @@ func (r *LeaderboardRepository) GetLocalLeaderboardWithParams(ctx context.Contex
 		params.Longitude, // Using ST_MakePoint(long, lat)
 		params.Latitude,
 		params.RadiusMeters,
-		params.Limit)
+		params.Limit,
+		params.ScoringStandard)
 
 	if err != nil {
 		return nil, fmt.Errorf("error querying local leaderboard: %w", err)
@@ -123,23 +128,26 @@ func (r *LeaderboardRepository) GetLocalLeaderboardWithParams(ctx context.Contex
 }
 
 // GetGlobalLeaderboard returns a global leaderboard for a specific exercise type with time filtering
-func (r *LeaderboardRepository) GetGlobalLeaderboard(ctx context.Context, exerciseType string, startDate, endDate *time.Time, limit, offset int) ([]LeaderboardEntry, error) {
+func (r *LeaderboardRepository) GetGlobalLeaderboard(ctx context.Context, exerciseType string, scoringStandard string, startDate, endDate *time.Time, limit, offset int) ([]LeaderboardEntry, error) {
 	query := `
 		WITH ranked_workouts AS (
 			SELECT 
 				w.user_id,
 				w.exercise_type,
-				MAX(w.grade) AS best_score,
+				MAX(COALESCE(w.expected_grade, w.grade)) AS best_score,
 				ROW_NUMBER() OVER (
 					ORDER BY 
-						MAX(w.grade) DESC,          -- Primary: highest score
+						MAX(COALESCE(w.expected_grade, w.grade)) DESC,          -- Primary: highest score
 						MAX(w.completed_at) DESC,   -- Tie-breaker 1: most recent
 						w.user_id ASC               -- Tie-breaker 2: consistent ordering
 				) AS rank
 			FROM workouts w
 			JOIN users u ON w.user_id = u.id
 			WHERE w.exercise_type = $1
+			AND w.scoring_standard = $6
 			AND u.is_public = true
+			AND w.verification_status <> 'mismatched'
+			AND w.deleted_at IS NULL
 			AND ($4::timestamp IS NULL OR w.completed_at >= $4)  -- Start date
 			AND ($5::timestamp IS NULL OR w.completed_at < $5)   -- End date
 			GROUP BY w.user_id, w.exercise_type
@@ -155,7 +163,7 @@ func (r *LeaderboardRepository) GetGlobalLeaderboard(ctx context.Context, exerci
 			MAX(w.completed_at) AS last_updated
 		FROM ranked_workouts rw
 		JOIN users u ON rw.user_id = u.id
-		JOIN workouts w ON rw.user_id = w.user_id AND rw.exercise_type = w.exercise_type
+		JOIN workouts w ON rw.user_id = w.user_id AND rw.exercise_type = w.exercise_type AND w.deleted_at IS NULL
 		WHERE u.is_public = true
 		GROUP BY u.id, u.username, u.display_name, u.profile_picture_url, rw.best_score, rw.rank, rw.exercise_type
 		ORDER BY rw.rank ASC
@@ -171,7 +179,7 @@ func (r *LeaderboardRepository) GetGlobalLeaderboard(ctx context.Context, exerci
 		endTime = *endDate
 	}
 
-	rows, err := r.db.QueryContext(ctx, query, exerciseType, limit, offset, startTime, endTime)
+	rows, err := r.db.QueryContext(ctx, query, exerciseType, limit, offset, startTime, endTime, scoringStandard)
 	if err != nil {
 		return nil, fmt.Errorf("error querying global leaderboard with time filter: %w", err)
 	}
@@ -203,23 +211,26 @@ func (r *LeaderboardRepository) GetGlobalLeaderboard(ctx context.Context, exerci
 }
 
 // GetLocalLeaderboard returns a local leaderboard for a specific exercise type with time filtering
-func (r *LeaderboardRepository) GetLocalLeaderboard(ctx context.Context, exerciseType string, lat, lng, radiusMeters float64, startDate, endDate *time.Time, limit, offset int) ([]LeaderboardEntry, error) {
+func (r *LeaderboardRepository) GetLocalLeaderboard(ctx context.Context, exerciseType string, scoringStandard string, lat, lng, radiusMeters float64, startDate, endDate *time.Time, limit, offset int) ([]LeaderboardEntry, error) {
 	query := `
 		WITH ranked_workouts AS (
 			SELECT 
 				w.user_id,
 				w.exercise_type,
-				MAX(w.grade) AS best_score,
+				MAX(COALESCE(w.expected_grade, w.grade)) AS best_score,
 				ROW_NUMBER() OVER (
 					ORDER BY 
-						MAX(w.grade) DESC,          -- Primary: highest score
+						MAX(COALESCE(w.expected_grade, w.grade)) DESC,          -- Primary: highest score
 						MAX(w.completed_at) DESC,   -- Tie-breaker 1: most recent
 						w.user_id ASC               -- Tie-breaker 2: consistent ordering
 				) AS rank
 			FROM workouts w
 			JOIN users u ON w.user_id = u.id
 			WHERE w.exercise_type = $1
+			AND w.scoring_standard = $9
 			AND u.is_public = true
+			AND w.verification_status <> 'mismatched'
+			AND w.deleted_at IS NULL
 			AND ($6::timestamp IS NULL OR w.completed_at >= $6)  -- Start date
 			AND ($7::timestamp IS NULL OR w.completed_at < $7)   -- End date
 			GROUP BY w.user_id, w.exercise_type
@@ -236,7 +247,7 @@ func (r *LeaderboardRepository) GetLocalLeaderboard(ctx context.Context, exercis
 			MAX(w.completed_at) AS last_updated
 		FROM ranked_workouts rw
 		JOIN users u ON rw.user_id = u.id
-		JOIN workouts w ON rw.user_id = w.user_id AND rw.exercise_type = w.exercise_type
+		JOIN workouts w ON rw.user_id = w.user_id AND rw.exercise_type = w.exercise_type AND w.deleted_at IS NULL
 		WHERE u.last_location IS NOT NULL
 		AND u.is_public = true
 		AND ST_DWithin(u.last_location::geography, ST_MakePoint($2, $3)::geography, $4)
@@ -263,7 +274,8 @@ func (r *LeaderboardRepository) GetLocalLeaderboard(ctx context.Context, exercis
 		limit,
 		startTime,
 		endTime,
-		offset)
+		offset,
+		scoringStandard)
 
 	if err != nil {
 		return nil, fmt.Errorf("error querying local leaderboard with time filter: %w", err)
@@ -15,12 +15,15 @@ WITH user_best_scores AS (
     SELECT 
         u.id as user_id,
         e.type as exercise_type,
-        MAX(w.grade) as best_score
+        MAX(COALESCE(w.expected_grade, w.grade)) as best_score
     FROM workouts w
     JOIN users u ON w.user_id = u.id
     JOIN exercises e ON w.exercise_id = e.id
     WHERE w.is_public = true
+      AND w.verification_status <> 'mismatched'
+      AND w.deleted_at IS NULL
       AND e.type IN ('pushup','situp','pullup','running')              -- NEW: limit to 4 core types
+      AND w.scoring_standard = 'apft-2013'                             -- Grades under other standards are not comparable
       AND ($2::timestamptz IS NULL OR w.completed_at >= $2::timestamptz)
       AND ($3::timestamptz IS NULL OR w.completed_at < $3::timestamptz)
     GROUP BY u.id, e.type
@@ -85,24 +88,28 @@ SELECT
     u.id as user_id,
     u.username,
     CONCAT(u.first_name, ' ', u.last_name) as display_name,
-    MAX(w.grade) as score
+    MAX(COALESCE(w.expected_grade, w.grade)) as score
 FROM workouts w
 JOIN users u ON w.user_id = u.id
 JOIN exercises e ON w.exercise_id = e.id
 WHERE e.type = $1
+  AND w.scoring_standard = $2 -- Grades under other standards are not comparable
   AND w.is_public = true
-  AND ($2::timestamptz IS NULL OR w.completed_at >= $2::timestamptz)
-  AND ($3::timestamptz IS NULL OR w.completed_at < $3::timestamptz)
+  AND w.verification_status <> 'mismatched'
+  AND w.deleted_at IS NULL
+  AND ($3::timestamptz IS NULL OR w.completed_at >= $3::timestamptz)
+  AND ($4::timestamptz IS NULL OR w.completed_at < $4::timestamptz)
 GROUP BY u.id, u.username, u.first_name, u.last_name
 ORDER BY score DESC
-LIMIT $4
+LIMIT $5
 `
 
 type GetGlobalExerciseLeaderboardParams struct {
-	Type      string       `json:"type"`
-	StartDate sql.NullTime `json:"start_date"`
-	EndDate   sql.NullTime `json:"end_date"`
-	Limit     int32        `json:"limit"`
+	Type            string       `json:"type"`
+	ScoringStandard string       `json:"scoring_standard"`
+	StartDate       sql.NullTime `json:"start_date"`
+	EndDate         sql.NullTime `json:"end_date"`
+	Limit           int32        `json:"limit"`
 }
 
 type GetGlobalExerciseLeaderboardRow struct {
@@ -116,6 +123,7 @@ type GetGlobalExerciseLeaderboardRow struct {
 func (q *Queries) GetGlobalExerciseLeaderboard(ctx context.Context, arg GetGlobalExerciseLeaderboardParams) ([]GetGlobalExerciseLeaderboardRow, error) {
 	rows, err := q.db.QueryContext(ctx, getGlobalExerciseLeaderboard,
 		arg.Type,
+		arg.ScoringStandard,
 		arg.StartDate,
 		arg.EndDate,
 		arg.Limit,
@@ -151,21 +159,25 @@ SELECT
     u.username,
     CONCAT(u.first_name, ' ', u.last_name) as display_name,
     e.type as exercise_type,
-    MAX(w.grade) as best_grade -- Get the best grade for this exercise type per user
+    MAX(COALESCE(w.expected_grade, w.grade)) as best_grade -- Get the best grade for this exercise type per user
 FROM workouts w
 JOIN users u ON w.user_id = u.id
 JOIN exercises e ON w.exercise_id = e.id
 WHERE e.type = $1
+AND w.scoring_standard = $2 -- Grades under other standards are not comparable
 AND w.grade IS NOT NULL
 AND u.is_public = true
+AND w.verification_status <> 'mismatched'
+AND w.deleted_at IS NULL
 GROUP BY u.id, e.type -- Group by user to find their best score for this exercise
 ORDER BY best_grade DESC
-LIMIT $2
+LIMIT $3
 `
 
 type GetLeaderboardByExerciseTypeParams struct {
-	Type  string `json:"type"`
-	Limit int32  `json:"limit"`
+	Type            string `json:"type"`
+	ScoringStandard string `json:"scoring_standard"`
+	Limit           int32  `json:"limit"`
 }
 
 type GetLeaderboardByExerciseTypeRow struct {
@@ -176,7 +188,7 @@ type GetLeaderboardByExerciseTypeRow struct {
 }
 
 func (q *Queries) GetLeaderboardByExerciseType(ctx context.Context, arg GetLeaderboardByExerciseTypeParams) ([]GetLeaderboardByExerciseTypeRow, error) {
-	rows, err := q.db.QueryContext(ctx, getLeaderboardByExerciseType, arg.Type, arg.Limit)
+	rows, err := q.db.QueryContext(ctx, getLeaderboardByExerciseType, arg.Type, arg.ScoringStandard, arg.Limit)
 	if err != nil {
 		return nil, err
 	}
@@ -208,13 +220,16 @@ WITH user_best_scores AS (
     SELECT 
         u.id as user_id,
         e.type as exercise_type,
-        MAX(w.grade) as best_score
+        MAX(COALESCE(w.expected_grade, w.grade)) as best_score
     FROM workouts w
     JOIN users u ON w.user_id = u.id
     JOIN exercises e ON w.exercise_id = e.id
     WHERE 
         w.is_public = true
+        AND w.verification_status <> 'mismatched'
+        AND w.deleted_at IS NULL
         AND e.type IN ('pushup','situp','pullup','running')              -- NEW: limit to 4 core types
+        AND w.scoring_standard = 'apft-2013'                             -- Grades under other standards are not comparable
         AND ST_DWithin(
             u.last_location::geography,
             ST_MakePoint($1, $2)::geography, -- longitude, then latitude for ST_MakePoint
@@ -296,34 +311,38 @@ SELECT
     u.id as user_id,
     u.username,
     CONCAT(u.first_name, ' ', u.last_name) as display_name,
-    MAX(w.grade) as score,
+    MAX(COALESCE(w.expected_grade, w.grade)) as score,
     ST_Distance(u.last_location::geography, ST_MakePoint($1, $2)::geography) as distance_meters
 FROM workouts w
 JOIN users u ON w.user_id = u.id
 JOIN exercises e ON w.exercise_id = e.id
 WHERE 
     e.type = $3 
+    AND w.scoring_standard = $4 -- Grades under other standards are not comparable
     AND w.is_public = true
+    AND w.verification_status <> 'mismatched'
+    AND w.deleted_at IS NULL
     AND ST_DWithin(
         u.last_location::geography,
         ST_MakePoint($1, $2)::geography, -- longitude, then latitude for ST_MakePoint
-        $4 
+        $5 
     )
-    AND ($5::timestamptz IS NULL OR w.completed_at >= $5::timestamptz)
-    AND ($6::timestamptz IS NULL OR w.completed_at < $6::timestamptz)
+    AND ($6::timestamptz IS NULL OR w.completed_at >= $6::timestamptz)
+    AND ($7::timestamptz IS NULL OR w.completed_at < $7::timestamptz)
 GROUP BY u.id, u.username, u.first_name, u.last_name, u.last_location
 ORDER BY score DESC
-LIMIT $7
+LIMIT $8
 `
 
 type GetLocalExerciseLeaderboardParams struct {
-	Longitude    interface{}  `json:"longitude"`
-	Latitude     interface{}  `json:"latitude"`
-	Type         string       `json:"type"`
-	RadiusMeters interface{}  `json:"radius_meters"`
-	StartDate    sql.NullTime `json:"start_date"`
-	EndDate      sql.NullTime `json:"end_date"`
-	Limit        int32        `json:"limit"`
+	Longitude       interface{}  `json:"longitude"`
+	Latitude        interface{}  `json:"latitude"`
+	Type            string       `json:"type"`
+	ScoringStandard string       `json:"scoring_standard"`
+	RadiusMeters    interface{}  `json:"radius_meters"`
+	StartDate       sql.NullTime `json:"start_date"`
+	EndDate         sql.NullTime `json:"end_date"`
+	Limit           int32        `json:"limit"`
 }
 
 type GetLocalExerciseLeaderboardRow struct {
@@ -339,6 +358,7 @@ func (q *Queries) GetLocalExerciseLeaderboard(ctx context.Context, arg GetLocalE
 		arg.Longitude,
 		arg.Latitude,
 		arg.Type,
+		arg.ScoringStandard,
 		arg.RadiusMeters,
 		arg.StartDate,
 		arg.EndDate,
@@ -398,6 +418,8 @@ WHERE
         $4
     )
     AND w.is_public = true
+    AND w.verification_status <> 'mismatched'
+    AND w.deleted_at IS NULL
     AND u.is_public = true
 GROUP BY u.id, u.username, u.first_name, u.last_name, w.exercise_id
 ORDER BY score DESC
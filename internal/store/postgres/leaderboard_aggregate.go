@@ -0,0 +1,137 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+
+	"ptchampion/internal/store"
+)
+
+// GetGlobalAggregateLeaderboardByTypes implements store.LeaderboardStore, summing each user's
+// best grade under the scoring standard across the given exercise types. Only users with a
+// public, non-mismatched result in every type are ranked.
+func (s *Store) GetGlobalAggregateLeaderboardByTypes(ctx context.Context, exerciseTypes []string, scoringStandard string, limit int, startDate time.Time, endDate time.Time) ([]*store.LeaderboardEntry, error) {
+	s.logger.Debug(ctx, "Store: GetGlobalAggregateLeaderboardByTypes called", "types", exerciseTypes, "standard", scoringStandard, "limit", limit, "startDate", startDate, "endDate", endDate)
+
+	query := `
+		WITH user_best_scores AS (
+			SELECT
+				w.user_id,
+				e.type AS exercise_type,
+				MAX(COALESCE(w.expected_grade, w.grade)) AS best_score
+			FROM workouts w
+			JOIN exercises e ON w.exercise_id = e.id
+			WHERE w.is_public = true
+			  AND w.verification_status <> 'mismatched'
+			  AND w.deleted_at IS NULL
+			  AND e.type = ANY($1)
+			  AND w.scoring_standard = $6
+			  AND ($3::timestamptz IS NULL OR w.completed_at >= $3::timestamptz)
+			  AND ($4::timestamptz IS NULL OR w.completed_at < $4::timestamptz)
+			GROUP BY w.user_id, e.type
+		)
+		SELECT
+			u.id AS user_id,
+			u.username,
+			CONCAT(u.first_name, ' ', u.last_name) AS display_name,
+			SUM(ubs.best_score) AS score
+		FROM users u
+		JOIN user_best_scores ubs ON u.id = ubs.user_id
+		GROUP BY u.id, u.username, u.first_name, u.last_name
+		HAVING COUNT(DISTINCT ubs.exercise_type) = $2
+		ORDER BY score DESC
+		LIMIT $5`
+
+	rows, err := s.db.QueryContext(ctx, query, pq.Array(exerciseTypes), len(exerciseTypes),
+		nullTimeFromZero(startDate), nullTimeFromZero(endDate), limit, scoringStandard)
+	if err != nil {
+		s.logger.Error(ctx, "Failed to get global aggregate leaderboard by types from DB", "error", err)
+		return nil, fmt.Errorf("failed to get global aggregate leaderboard by types from DB: %w", err)
+	}
+	defer rows.Close()
+
+	return scanAggregateLeaderboardRows(rows)
+}
+
+// GetLocalAggregateLeaderboardByTypes implements store.LeaderboardStore, the local variant of
+// GetGlobalAggregateLeaderboardByTypes limited to users within radiusMeters of the point.
+func (s *Store) GetLocalAggregateLeaderboardByTypes(ctx context.Context, exerciseTypes []string, scoringStandard string, latitude, longitude float64, radiusMeters int, limit int, startDate time.Time, endDate time.Time) ([]*store.LeaderboardEntry, error) {
+	s.logger.Debug(ctx, "Store: GetLocalAggregateLeaderboardByTypes called", "types", exerciseTypes, "standard", scoringStandard, "lat", latitude, "lon", longitude, "radius", radiusMeters, "limit", limit)
+
+	query := `
+		WITH user_best_scores AS (
+			SELECT
+				w.user_id,
+				e.type AS exercise_type,
+				MAX(COALESCE(w.expected_grade, w.grade)) AS best_score
+			FROM workouts w
+			JOIN users u ON w.user_id = u.id
+			JOIN exercises e ON w.exercise_id = e.id
+			WHERE w.is_public = true
+			  AND w.verification_status <> 'mismatched'
+			  AND w.deleted_at IS NULL
+			  AND e.type = ANY($1)
+			  AND w.scoring_standard = $9
+			  AND ST_DWithin(
+				u.last_location::geography,
+				ST_MakePoint($3, $4)::geography, -- longitude, then latitude for ST_MakePoint
+				$5
+			  )
+			  AND ($6::timestamptz IS NULL OR w.completed_at >= $6::timestamptz)
+			  AND ($7::timestamptz IS NULL OR w.completed_at < $7::timestamptz)
+			GROUP BY w.user_id, e.type
+		)
+		SELECT
+			u.id AS user_id,
+			u.username,
+			CONCAT(u.first_name, ' ', u.last_name) AS display_name,
+			SUM(ubs.best_score) AS score
+		FROM users u
+		JOIN user_best_scores ubs ON u.id = ubs.user_id
+		GROUP BY u.id, u.username, u.first_name, u.last_name
+		HAVING COUNT(DISTINCT ubs.exercise_type) = $2
+		ORDER BY score DESC
+		LIMIT $8`
+
+	rows, err := s.db.QueryContext(ctx, query, pq.Array(exerciseTypes), len(exerciseTypes),
+		longitude, latitude, float64(radiusMeters),
+		nullTimeFromZero(startDate), nullTimeFromZero(endDate), limit, scoringStandard)
+	if err != nil {
+		s.logger.Error(ctx, "Failed to get local aggregate leaderboard by types from DB", "error", err)
+		return nil, fmt.Errorf("failed to get local aggregate leaderboard by types from DB: %w", err)
+	}
+	defer rows.Close()
+
+	return scanAggregateLeaderboardRows(rows)
+}
+
+// scanAggregateLeaderboardRows maps (user_id, username, display_name, score) rows to entries
+func scanAggregateLeaderboardRows(rows *sql.Rows) ([]*store.LeaderboardEntry, error) {
+	entries := make([]*store.LeaderboardEntry, 0)
+	for rows.Next() {
+		var userID int32
+		var username string
+		var displayName string
+		var score int64
+		if err := rows.Scan(&userID, &username, &displayName, &score); err != nil {
+			return nil, fmt.Errorf("failed to scan aggregate leaderboard row: %w", err)
+		}
+		entries = append(entries, mapSqlcRowToLeaderboardEntry(userID, username, displayName, int32(score)))
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating aggregate leaderboard rows: %w", err)
+	}
+	return entries, nil
+}
+
+// nullTimeFromZero converts a zero time (no bound) to a NULL parameter
+func nullTimeFromZero(t time.Time) sql.NullTime {
+	if t.IsZero() {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: t, Valid: true}
+}
@@ -0,0 +1,132 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+
+	"ptchampion/internal/store"
+)
+
+// GetOrganizationAggregateLeaderboardByTypes implements store.LeaderboardStore, the variant of
+// GetGlobalAggregateLeaderboardByTypes limited to the members of the organization's subtree.
+func (s *Store) GetOrganizationAggregateLeaderboardByTypes(ctx context.Context, orgID int32, exerciseTypes []string, scoringStandard string, limit int, startDate time.Time, endDate time.Time) ([]*store.LeaderboardEntry, error) {
+	s.logger.Debug(ctx, "Store: GetOrganizationAggregateLeaderboardByTypes called", "orgID", orgID, "types", exerciseTypes, "standard", scoringStandard, "limit", limit, "startDate", startDate, "endDate", endDate)
+
+	query := organizationSubtree + `,
+		user_best_scores AS (
+			SELECT
+				w.user_id,
+				e.type AS exercise_type,
+				MAX(COALESCE(w.expected_grade, w.grade)) AS best_score
+			FROM workouts w
+			JOIN exercises e ON w.exercise_id = e.id
+			WHERE w.is_public = true
+			  AND w.verification_status <> 'mismatched'
+			  AND w.deleted_at IS NULL
+			  AND e.type = ANY($2)
+			  AND w.scoring_standard = $7
+			  AND w.user_id IN (
+				SELECT m.user_id FROM organization_memberships m
+				WHERE m.organization_id IN (SELECT id FROM subtree) AND m.status = 'accepted'
+			  )
+			  AND ($4::timestamptz IS NULL OR w.completed_at >= $4::timestamptz)
+			  AND ($5::timestamptz IS NULL OR w.completed_at < $5::timestamptz)
+			GROUP BY w.user_id, e.type
+		)
+		SELECT
+			u.id AS user_id,
+			u.username,
+			CONCAT(u.first_name, ' ', u.last_name) AS display_name,
+			SUM(ubs.best_score) AS score
+		FROM users u
+		JOIN user_best_scores ubs ON u.id = ubs.user_id
+		GROUP BY u.id, u.username, u.first_name, u.last_name
+		HAVING COUNT(DISTINCT ubs.exercise_type) = $3
+		ORDER BY score DESC
+		LIMIT $6`
+
+	rows, err := s.db.QueryContext(ctx, query, orgID, pq.Array(exerciseTypes), len(exerciseTypes),
+		nullTimeFromZero(startDate), nullTimeFromZero(endDate), limit, scoringStandard)
+	if err != nil {
+		s.logger.Error(ctx, "Failed to get organization leaderboard from DB", "orgID", orgID, "error", err)
+		return nil, fmt.Errorf("failed to get organization leaderboard from DB: %w", err)
+	}
+	defer rows.Close()
+
+	return scanAggregateLeaderboardRows(rows)
+}
+
+// GetSubunitMemberScores implements store.LeaderboardStore. A member of several units in
+// one subunit's subtree is counted once for it.
+func (s *Store) GetSubunitMemberScores(ctx context.Context, orgID int32, exerciseTypes []string, scoringStandard string, startDate time.Time, endDate time.Time) ([]*store.UnitMemberScore, error) {
+	s.logger.Debug(ctx, "Store: GetSubunitMemberScores called", "orgID", orgID, "types", exerciseTypes, "standard", scoringStandard, "startDate", startDate, "endDate", endDate)
+
+	query := `
+		WITH RECURSIVE units AS (
+			SELECT id AS unit_id, id FROM organizations WHERE parent_id = $1
+			UNION ALL
+			SELECT u.unit_id, o.id FROM organizations o JOIN units u ON o.parent_id = u.id
+		),
+		unit_members AS (
+			SELECT DISTINCT u.unit_id, m.user_id
+			FROM units u
+			JOIN organization_memberships m ON m.organization_id = u.id AND m.status = 'accepted'
+		),
+		user_best_scores AS (
+			SELECT
+				w.user_id,
+				e.type AS exercise_type,
+				MAX(COALESCE(w.expected_grade, w.grade)) AS best_score
+			FROM workouts w
+			JOIN exercises e ON w.exercise_id = e.id
+			WHERE w.is_public = true
+			  AND w.verification_status <> 'mismatched'
+			  AND w.deleted_at IS NULL
+			  AND e.type = ANY($2)
+			  AND w.scoring_standard = $6
+			  AND w.user_id IN (SELECT user_id FROM unit_members)
+			  AND ($4::timestamptz IS NULL OR w.completed_at >= $4::timestamptz)
+			  AND ($5::timestamptz IS NULL OR w.completed_at < $5::timestamptz)
+			GROUP BY w.user_id, e.type
+		),
+		user_totals AS (
+			SELECT user_id, SUM(best_score) AS score
+			FROM user_best_scores
+			GROUP BY user_id
+			HAVING COUNT(DISTINCT exercise_type) = $3
+		)
+		SELECT um.unit_id, um.user_id, ut.score
+		FROM unit_members um
+		LEFT JOIN user_totals ut ON ut.user_id = um.user_id
+		ORDER BY um.unit_id, um.user_id`
+
+	rows, err := s.db.QueryContext(ctx, query, orgID, pq.Array(exerciseTypes), len(exerciseTypes),
+		nullTimeFromZero(startDate), nullTimeFromZero(endDate), scoringStandard)
+	if err != nil {
+		s.logger.Error(ctx, "Failed to get subunit member scores from DB", "orgID", orgID, "error", err)
+		return nil, fmt.Errorf("failed to get subunit member scores from DB: %w", err)
+	}
+	defer rows.Close()
+
+	scores := make([]*store.UnitMemberScore, 0)
+	for rows.Next() {
+		var score store.UnitMemberScore
+		var total sql.NullInt64
+		if err := rows.Scan(&score.OrganizationID, &score.UserID, &total); err != nil {
+			return nil, fmt.Errorf("failed to scan subunit member score row: %w", err)
+		}
+		if total.Valid {
+			v := int32(total.Int64)
+			score.Score = &v
+		}
+		scores = append(scores, &score)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating subunit member score rows: %w", err)
+	}
+	return scores, nil
+}
@@ -31,6 +31,7 @@ type User struct {
 	LastLocation        interface{}    `json:"last_location"`
 	Gender              sql.NullString `json:"gender"`
 	DateOfBirth         sql.NullTime   `json:"date_of_birth"`
+	Timezone            string         `json:"timezone"`
 	TokensInvalidatedAt sql.NullTime   `json:"tokens_invalidated_at"`
 	LastSyncedAt        sql.NullTime   `json:"last_synced_at"`
 	CreatedAt           sql.NullTime   `json:"created_at"`
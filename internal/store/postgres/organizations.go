@@ -0,0 +1,449 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"ptchampion/internal/store"
+)
+
+// organizationColumns selects the fields read by scanOrganizationRow from organizations o
+const organizationColumns = `o.id, o.parent_id, o.name, o.type, o.created_at, o.updated_at`
+
+// organizationMemberColumns selects the fields read by scanOrganizationMemberRows from
+// organization_memberships m joined with users u
+const organizationMemberColumns = `m.organization_id, m.user_id, u.username, u.first_name, u.last_name, m.role, m.status, m.created_at, m.share_test_results`
+
+// organizationSubtree is a recursive CTE naming the organization $1 and all of its descendants
+const organizationSubtree = `
+	WITH RECURSIVE subtree AS (
+		SELECT id FROM organizations WHERE id = $1
+		UNION ALL
+		SELECT o.id FROM organizations o JOIN subtree s ON o.parent_id = s.id
+	)`
+
+// CreateOrganization implements store.OrganizationStore
+func (s *Store) CreateOrganization(ctx context.Context, org *store.Organization, adminUserID int32) (*store.Organization, error) {
+	var created *store.Organization
+	err := s.ExecTx(ctx, func(q *Queries) error {
+		query := `
+			INSERT INTO organizations AS o (parent_id, name, type)
+			VALUES ($1, $2, $3)
+			RETURNING ` + organizationColumns
+
+		var err error
+		created, err = scanOrganizationRow(q.DB().QueryRowContext(ctx, query, int32PtrToNullInt32(org.ParentID), org.Name, org.Type))
+		if err != nil {
+			return fmt.Errorf("failed to create organization: %w", err)
+		}
+
+		_, err = q.DB().ExecContext(ctx, `
+			INSERT INTO organization_memberships (organization_id, user_id, role, status)
+			VALUES ($1, $2, $3, $4)`, created.ID, adminUserID, store.OrganizationRoleAdmin, store.OrganizationMemberStatusAccepted)
+		if err != nil {
+			return fmt.Errorf("failed to add organization admin: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// GetOrganizationByID implements store.OrganizationStore
+func (s *Store) GetOrganizationByID(ctx context.Context, id int32) (*store.Organization, error) {
+	query := `SELECT ` + organizationColumns + ` FROM organizations o WHERE o.id = $1`
+
+	org, err := scanOrganizationRow(s.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, store.ErrOrganizationNotFound
+		}
+		return nil, fmt.Errorf("failed to get organization: %w", err)
+	}
+	return org, nil
+}
+
+// UpdateOrganization implements store.OrganizationStore. Only the name can change;
+// a unit's type and place in the tree are fixed when it is created.
+func (s *Store) UpdateOrganization(ctx context.Context, org *store.Organization) (*store.Organization, error) {
+	query := `
+		UPDATE organizations AS o
+		SET name = $2, updated_at = NOW()
+		WHERE o.id = $1
+		RETURNING ` + organizationColumns
+
+	updated, err := scanOrganizationRow(s.db.QueryRowContext(ctx, query, org.ID, org.Name))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, store.ErrOrganizationNotFound
+		}
+		return nil, fmt.Errorf("failed to update organization: %w", err)
+	}
+	return updated, nil
+}
+
+// DeleteOrganization implements store.OrganizationStore. Units with subunits are not
+// deleted; memberships are removed with the unit.
+func (s *Store) DeleteOrganization(ctx context.Context, id int32) error {
+	return s.ExecTx(ctx, func(q *Queries) error {
+		// Lock the unit so a subunit cannot be created under it concurrently
+		var locked int32
+		err := q.DB().QueryRowContext(ctx, `SELECT id FROM organizations WHERE id = $1 FOR UPDATE`, id).Scan(&locked)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return store.ErrOrganizationNotFound
+			}
+			return fmt.Errorf("failed to lock organization: %w", err)
+		}
+
+		var children int64
+		if err := q.DB().QueryRowContext(ctx, `SELECT COUNT(*) FROM organizations WHERE parent_id = $1`, id).Scan(&children); err != nil {
+			return fmt.Errorf("failed to count subunits: %w", err)
+		}
+		if children > 0 {
+			return store.ErrOrganizationHasChildren
+		}
+
+		if _, err := q.DB().ExecContext(ctx, `DELETE FROM organizations WHERE id = $1`, id); err != nil {
+			return fmt.Errorf("failed to delete organization: %w", err)
+		}
+		return nil
+	})
+}
+
+// ListOrganizationChildren implements store.OrganizationStore, returning subunits by name
+func (s *Store) ListOrganizationChildren(ctx context.Context, parentID int32) ([]*store.Organization, error) {
+	query := `SELECT ` + organizationColumns + ` FROM organizations o WHERE o.parent_id = $1 ORDER BY o.name, o.id`
+
+	rows, err := s.db.QueryContext(ctx, query, parentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list subunits: %w", err)
+	}
+	defer rows.Close()
+
+	orgs := make([]*store.Organization, 0)
+	for rows.Next() {
+		org, err := scanOrganizationRow(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan organization row: %w", err)
+		}
+		orgs = append(orgs, org)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating organization rows: %w", err)
+	}
+	return orgs, nil
+}
+
+// ListUserOrganizations implements store.OrganizationStore, returning the organizations
+// the user is a direct member of, or invited to, by name
+func (s *Store) ListUserOrganizations(ctx context.Context, userID int32, status string) ([]*store.OrganizationMembership, error) {
+	query := `
+		SELECT ` + organizationColumns + `, m.role, m.status, m.created_at
+		FROM organization_memberships m
+		JOIN organizations o ON m.organization_id = o.id
+		WHERE m.user_id = $1 AND m.status = $2
+		ORDER BY o.name, o.id`
+
+	rows, err := s.db.QueryContext(ctx, query, userID, status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list user organizations: %w", err)
+	}
+	defer rows.Close()
+
+	memberships := make([]*store.OrganizationMembership, 0)
+	for rows.Next() {
+		var m store.OrganizationMembership
+		org, err := scanOrganizationRow(rows, &m.Role, &m.Status, &m.JoinedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan organization membership row: %w", err)
+		}
+		m.Organization = org
+		memberships = append(memberships, &m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating organization membership rows: %w", err)
+	}
+	return memberships, nil
+}
+
+// GetUserOrganizationPathRoles implements store.OrganizationStore
+func (s *Store) GetUserOrganizationPathRoles(ctx context.Context, userID int32, orgID int32) ([]string, error) {
+	query := `
+		WITH RECURSIVE path AS (
+			SELECT id, parent_id FROM organizations WHERE id = $2
+			UNION ALL
+			SELECT o.id, o.parent_id FROM organizations o JOIN path p ON o.id = p.parent_id
+		)
+		SELECT m.role
+		FROM organization_memberships m
+		JOIN path p ON m.organization_id = p.id
+		WHERE m.user_id = $1 AND m.status = 'accepted'`
+
+	rows, err := s.db.QueryContext(ctx, query, userID, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get organization roles: %w", err)
+	}
+	defer rows.Close()
+
+	roles := make([]string, 0)
+	for rows.Next() {
+		var role string
+		if err := rows.Scan(&role); err != nil {
+			return nil, fmt.Errorf("failed to scan organization role: %w", err)
+		}
+		roles = append(roles, role)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating organization roles: %w", err)
+	}
+	return roles, nil
+}
+
+// GetOrganizationMember implements store.OrganizationStore
+func (s *Store) GetOrganizationMember(ctx context.Context, orgID int32, userID int32) (*store.OrganizationMember, error) {
+	query := `
+		SELECT ` + organizationMemberColumns + `
+		FROM organization_memberships m
+		JOIN users u ON m.user_id = u.id
+		WHERE m.organization_id = $1 AND m.user_id = $2`
+
+	rows, err := s.db.QueryContext(ctx, query, orgID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get organization member: %w", err)
+	}
+	defer rows.Close()
+
+	members, err := scanOrganizationMemberRows(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(members) == 0 {
+		return nil, store.ErrMembershipNotFound
+	}
+	return members[0], nil
+}
+
+// IsOrganizationSubtreeMember implements store.OrganizationStore
+func (s *Store) IsOrganizationSubtreeMember(ctx context.Context, orgID int32, userID int32) (bool, error) {
+	query := organizationSubtree + `
+		SELECT EXISTS (
+			SELECT 1 FROM organization_memberships m
+			WHERE m.organization_id IN (SELECT id FROM subtree) AND m.user_id = $2 AND m.status = 'accepted'
+		)`
+
+	var member bool
+	if err := s.db.QueryRowContext(ctx, query, orgID, userID).Scan(&member); err != nil {
+		return false, fmt.Errorf("failed to check organization membership: %w", err)
+	}
+	return member, nil
+}
+
+// ListOrganizationMembers implements store.OrganizationStore, ordering members by
+// role (admins first) and then username
+func (s *Store) ListOrganizationMembers(ctx context.Context, orgID int32, includeDescendants bool, status string, limit int32, offset int32) (*store.PaginatedOrganizationMembers, error) {
+	prefix := ""
+	from := `
+		FROM organization_memberships m
+		JOIN users u ON m.user_id = u.id
+		WHERE m.organization_id = $1 AND m.status = $2`
+	if includeDescendants {
+		prefix = organizationSubtree
+		from = `
+		FROM organization_memberships m
+		JOIN users u ON m.user_id = u.id
+		WHERE m.organization_id IN (SELECT id FROM subtree) AND m.status = $2`
+	}
+
+	var count int64
+	if err := s.db.QueryRowContext(ctx, prefix+` SELECT COUNT(*) `+from, orgID, status).Scan(&count); err != nil {
+		return nil, fmt.Errorf("failed to count organization members: %w", err)
+	}
+	if count == 0 {
+		return &store.PaginatedOrganizationMembers{
+			Members:    []*store.OrganizationMember{},
+			TotalCount: 0,
+		}, nil
+	}
+
+	query := prefix + ` SELECT ` + organizationMemberColumns + from + `
+		ORDER BY CASE m.role WHEN 'admin' THEN 0 WHEN 'leader' THEN 1 ELSE 2 END, u.username, m.organization_id
+		LIMIT $3 OFFSET $4`
+
+	rows, err := s.db.QueryContext(ctx, query, orgID, status, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list organization members: %w", err)
+	}
+	defer rows.Close()
+
+	members, err := scanOrganizationMemberRows(rows)
+	if err != nil {
+		return nil, err
+	}
+	return &store.PaginatedOrganizationMembers{
+		Members:    members,
+		TotalCount: count,
+	}, nil
+}
+
+// ListOrganizationRoster implements store.OrganizationStore, ordering users by username
+func (s *Store) ListOrganizationRoster(ctx context.Context, orgID int32) ([]*store.OrganizationMember, error) {
+	query := organizationSubtree + `
+		SELECT * FROM (
+			SELECT DISTINCT ON (m.user_id)
+				m.organization_id, m.user_id, u.username, u.first_name, u.last_name, m.role, m.status, m.created_at,
+				BOOL_OR(m.share_test_results) OVER (PARTITION BY m.user_id) AS share_test_results
+			FROM organization_memberships m
+			JOIN users u ON m.user_id = u.id
+			WHERE m.organization_id IN (SELECT id FROM subtree) AND m.status = 'accepted'
+			ORDER BY m.user_id, CASE m.role WHEN 'admin' THEN 0 WHEN 'leader' THEN 1 ELSE 2 END
+		) roster
+		ORDER BY roster.username`
+
+	rows, err := s.db.QueryContext(ctx, query, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list organization roster: %w", err)
+	}
+	defer rows.Close()
+
+	return scanOrganizationMemberRows(rows)
+}
+
+// ListOrganizationTestSessions implements store.OrganizationStore
+func (s *Store) ListOrganizationTestSessions(ctx context.Context, orgID int32, since time.Time) ([]*store.TestSession, error) {
+	query := organizationSubtree + `
+		SELECT ` + testSessionColumns + `
+		FROM test_sessions
+		WHERE user_id IN (
+			SELECT m.user_id FROM organization_memberships m
+			WHERE m.organization_id IN (SELECT id FROM subtree) AND m.status = 'accepted'
+			  AND (m.share_test_results OR test_sessions.is_public)
+		) AND test_date >= $2::date
+		ORDER BY started_at DESC`
+
+	rows, err := s.db.QueryContext(ctx, query, orgID, since.Format("2006-01-02"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list organization test sessions: %w", err)
+	}
+	defer rows.Close()
+
+	sessions, err := scanTestSessionRows(rows)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.attachTestSessionEvents(ctx, sessions); err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+// CountOrganizationMembersByRole implements store.OrganizationStore, counting direct members only
+func (s *Store) CountOrganizationMembersByRole(ctx context.Context, orgID int32, role string) (int64, error) {
+	var count int64
+	err := s.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM organization_memberships
+		WHERE organization_id = $1 AND role = $2 AND status = 'accepted'`, orgID, role).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count organization members: %w", err)
+	}
+	return count, nil
+}
+
+// SetOrganizationMember implements store.OrganizationStore. New rows are pending
+// invitations; an existing row keeps its status.
+func (s *Store) SetOrganizationMember(ctx context.Context, orgID int32, userID int32, role string) (*store.OrganizationMember, error) {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO organization_memberships (organization_id, user_id, role, status)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (organization_id, user_id) DO UPDATE SET role = EXCLUDED.role`, orgID, userID, role, store.OrganizationMemberStatusPending)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set organization member: %w", err)
+	}
+	return s.GetOrganizationMember(ctx, orgID, userID)
+}
+
+// AcceptOrganizationInvitation implements store.OrganizationStore. The membership's
+// joined time restarts at acceptance.
+func (s *Store) AcceptOrganizationInvitation(ctx context.Context, orgID int32, userID int32) (*store.OrganizationMember, error) {
+	result, err := s.db.ExecContext(ctx, `
+		UPDATE organization_memberships
+		SET status = $3, created_at = NOW()
+		WHERE organization_id = $1 AND user_id = $2 AND status = $4`,
+		orgID, userID, store.OrganizationMemberStatusAccepted, store.OrganizationMemberStatusPending)
+	if err != nil {
+		return nil, fmt.Errorf("failed to accept organization invitation: %w", err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return nil, store.ErrMembershipNotFound
+	}
+	return s.GetOrganizationMember(ctx, orgID, userID)
+}
+
+// SetOrganizationMemberSharing implements store.OrganizationStore
+func (s *Store) SetOrganizationMemberSharing(ctx context.Context, orgID int32, userID int32, share bool) (*store.OrganizationMember, error) {
+	result, err := s.db.ExecContext(ctx, `
+		UPDATE organization_memberships
+		SET share_test_results = $3
+		WHERE organization_id = $1 AND user_id = $2`, orgID, userID, share)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set organization member sharing: %w", err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return nil, store.ErrMembershipNotFound
+	}
+	return s.GetOrganizationMember(ctx, orgID, userID)
+}
+
+// RemoveOrganizationMember implements store.OrganizationStore
+func (s *Store) RemoveOrganizationMember(ctx context.Context, orgID int32, userID int32) error {
+	result, err := s.db.ExecContext(ctx, `
+		DELETE FROM organization_memberships
+		WHERE organization_id = $1 AND user_id = $2`, orgID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to remove organization member: %w", err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return store.ErrMembershipNotFound
+	}
+	return nil
+}
+
+// rowScanner is satisfied by *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanOrganizationRow reads organizationColumns, followed by any trailing columns
+func scanOrganizationRow(row rowScanner, trailing ...interface{}) (*store.Organization, error) {
+	var org store.Organization
+	var parentID sql.NullInt32
+	dest := append([]interface{}{&org.ID, &parentID, &org.Name, &org.Type, &org.CreatedAt, &org.UpdatedAt}, trailing...)
+	if err := row.Scan(dest...); err != nil {
+		return nil, err
+	}
+	org.ParentID = nullInt32ToInt32Ptr(parentID)
+	return &org, nil
+}
+
+// scanOrganizationMemberRows reads rows selected with organizationMemberColumns
+func scanOrganizationMemberRows(rows *sql.Rows) ([]*store.OrganizationMember, error) {
+	members := make([]*store.OrganizationMember, 0)
+	for rows.Next() {
+		var m store.OrganizationMember
+		var firstName, lastName sql.NullString
+		if err := rows.Scan(&m.OrganizationID, &m.UserID, &m.Username, &firstName, &lastName, &m.Role, &m.Status, &m.JoinedAt, &m.SharesTestResults); err != nil {
+			return nil, fmt.Errorf("failed to scan organization member row: %w", err)
+		}
+		m.FirstName = nullStringToStringPtr(firstName)
+		m.LastName = nullStringToStringPtr(lastName)
+		members = append(members, &m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating organization member rows: %w", err)
+	}
+	return members, nil
+}
@@ -0,0 +1,296 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"ptchampion/internal/store"
+)
+
+// personalRecordColumns selects the fields read by scanPersonalRecordRow
+const personalRecordColumns = `id, user_id, exercise_type, metric, value, previous_value, workout_id, achieved_at, created_at`
+
+func scanPersonalRecordRow(row rowScanner) (*store.PersonalRecord, error) {
+	var r store.PersonalRecord
+	var previous sql.NullFloat64
+	if err := row.Scan(&r.ID, &r.UserID, &r.ExerciseType, &r.Metric, &r.Value, &previous, &r.WorkoutID, &r.AchievedAt, &r.CreatedAt); err != nil {
+		return nil, err
+	}
+	if previous.Valid {
+		r.PreviousValue = &previous.Float64
+	}
+	return &r, nil
+}
+
+// CreateWorkoutRecordWithPersonalRecords implements store.PersonalRecordStore. The user's
+// row is locked for the transaction so concurrent logs compare against each other's records.
+// The workout and its records are published to the activity feed in the same transaction.
+func (s *Store) CreateWorkoutRecordWithPersonalRecords(ctx context.Context, record *store.WorkoutRecord, candidates []store.PersonalRecordCandidate) (*store.WorkoutRecord, []*store.PersonalRecord, error) {
+	var newRecord *store.WorkoutRecord
+	var records []*store.PersonalRecord
+	err := s.ExecTx(ctx, func(q *Queries) error {
+		var err error
+		newRecord, err = insertWorkoutRecord(ctx, q, record)
+		if err != nil {
+			return err
+		}
+
+		if len(candidates) > 0 {
+			if _, err := q.DB().ExecContext(ctx, `SELECT 1 FROM users WHERE id = $1 FOR UPDATE`, record.UserID); err != nil {
+				return fmt.Errorf("failed to lock user: %w", err)
+			}
+		}
+		for _, candidate := range candidates {
+			pr, err := setPersonalRecord(ctx, q.DB(), newRecord, candidate)
+			if err != nil {
+				return err
+			}
+			if pr != nil {
+				records = append(records, pr)
+			}
+		}
+		return publishWorkoutActivity(ctx, q.DB(), newRecord, records)
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create workout record in DB: %w", err)
+	}
+	return newRecord, records, nil
+}
+
+// setPersonalRecord stores the candidate as a new record if it beats the user's current
+// best, returning nil otherwise. It must run inside a transaction holding the user lock.
+func setPersonalRecord(ctx context.Context, db DBTX, workout *store.WorkoutRecord, candidate store.PersonalRecordCandidate) (*store.PersonalRecord, error) {
+	var best sql.NullFloat64
+	err := db.QueryRowContext(ctx, `
+		SELECT value FROM personal_records
+		WHERE user_id = $1 AND exercise_type = $2 AND metric = $3
+		ORDER BY id DESC
+		LIMIT 1`,
+		workout.UserID, workout.ExerciseType, candidate.Metric,
+	).Scan(&best)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, fmt.Errorf("failed to get current personal record: %w", err)
+	}
+	if best.Valid && !candidate.Beats(best.Float64) {
+		return nil, nil
+	}
+
+	pr, err := scanPersonalRecordRow(db.QueryRowContext(ctx, `
+		INSERT INTO personal_records (user_id, exercise_type, metric, value, previous_value, workout_id, achieved_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING `+personalRecordColumns,
+		workout.UserID, workout.ExerciseType, candidate.Metric, candidate.Value, best, workout.ID, workout.CompletedAt,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create personal record: %w", err)
+	}
+	return pr, nil
+}
+
+// UpdateWorkoutRecordWithPersonalRecords implements store.PersonalRecordStore
+func (s *Store) UpdateWorkoutRecordWithPersonalRecords(ctx context.Context, actorID int32, record *store.WorkoutRecord, candidatesFor store.PersonalRecordCandidatesFunc) (*store.WorkoutRecord, error) {
+	return s.updateWorkoutRecord(ctx, actorID, record, candidatesFor)
+}
+
+// DeleteWorkoutRecordWithPersonalRecords implements store.PersonalRecordStore
+func (s *Store) DeleteWorkoutRecordWithPersonalRecords(ctx context.Context, actorID int32, id int32, candidatesFor store.PersonalRecordCandidatesFunc) error {
+	return s.deleteWorkoutRecord(ctx, actorID, id, candidatesFor)
+}
+
+// rebuildPersonalRecords replays the user's live workouts of the exercise type, oldest
+// first, and brings their stored records in line with the result. Records are rewritten
+// from the first one that changed so the latest record per metric stays the current best.
+// A rewritten record for the same workout keeps its feed event; the events of records
+// that no longer stand are deleted. It must run inside a transaction.
+func rebuildPersonalRecords(ctx context.Context, db DBTX, userID int32, exerciseType string, candidatesFor store.PersonalRecordCandidatesFunc) error {
+	if _, err := db.ExecContext(ctx, `SELECT 1 FROM users WHERE id = $1 FOR UPDATE`, userID); err != nil {
+		return fmt.Errorf("failed to lock user: %w", err)
+	}
+
+	// Test session events do not set personal records when they are logged, so they are not replayed
+	workouts, err := queryWorkoutRecords(ctx, db, `
+		WHERE w.user_id = $1 AND e.type = $2 AND w.deleted_at IS NULL AND w.test_session_id IS NULL
+		ORDER BY w.completed_at, w.id`, userID, exerciseType)
+	if err != nil {
+		return err
+	}
+	want := groupPersonalRecords(store.ReplayPersonalRecords(workouts, candidatesFor))
+	workoutsByID := make(map[int32]*store.WorkoutRecord, len(workouts))
+	for _, workout := range workouts {
+		workoutsByID[workout.ID] = workout
+	}
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT `+personalRecordColumns+`
+		FROM personal_records
+		WHERE user_id = $1 AND exercise_type = $2
+		ORDER BY id`, userID, exerciseType)
+	if err != nil {
+		return fmt.Errorf("failed to get personal records: %w", err)
+	}
+	var stored []*store.PersonalRecord
+	for rows.Next() {
+		pr, err := scanPersonalRecordRow(rows)
+		if err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan personal record: %w", err)
+		}
+		stored = append(stored, pr)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to iterate personal records: %w", err)
+	}
+	have := groupPersonalRecords(stored)
+
+	metrics := make(map[string]bool)
+	for metric := range want {
+		metrics[metric] = true
+	}
+	for metric := range have {
+		metrics[metric] = true
+	}
+	for metric := range metrics {
+		wanted, existing := want[metric], have[metric]
+		kept := 0
+		for kept < len(wanted) && kept < len(existing) && samePersonalRecord(wanted[kept], existing[kept]) {
+			kept++
+		}
+
+		// Feed events of replaced records move to the record set by the same workout and
+		// describe it from then on
+		events := make(map[int32]int32, len(existing)-kept)
+		for _, old := range existing[kept:] {
+			events[old.WorkoutID] = old.ID
+			if _, err := db.ExecContext(ctx, `DELETE FROM personal_records WHERE id = $1`, old.ID); err != nil {
+				return fmt.Errorf("failed to delete personal record: %w", err)
+			}
+		}
+		for _, pr := range wanted[kept:] {
+			var previous sql.NullFloat64
+			if pr.PreviousValue != nil {
+				previous = sql.NullFloat64{Float64: *pr.PreviousValue, Valid: true}
+			}
+			var id int32
+			err := db.QueryRowContext(ctx, `
+				INSERT INTO personal_records (user_id, exercise_type, metric, value, previous_value, workout_id, achieved_at)
+				VALUES ($1, $2, $3, $4, $5, $6, $7)
+				RETURNING id`,
+				pr.UserID, pr.ExerciseType, pr.Metric, pr.Value, previous, pr.WorkoutID, pr.AchievedAt,
+			).Scan(&id)
+			if err != nil {
+				return fmt.Errorf("failed to create personal record: %w", err)
+			}
+			if oldID, ok := events[pr.WorkoutID]; ok {
+				payload, err := json.Marshal(personalRecordActivityPayload(workoutsByID[pr.WorkoutID], pr))
+				if err != nil {
+					return fmt.Errorf("failed to encode activity payload: %w", err)
+				}
+				_, err = db.ExecContext(ctx, `
+					UPDATE activity_events SET source_id = $3, payload = $4
+					WHERE event_type = $1 AND source_id = $2`, store.ActivityTypePersonalRecord, oldID, id, payload)
+				if err != nil {
+					return fmt.Errorf("failed to move personal record activity: %w", err)
+				}
+				delete(events, pr.WorkoutID)
+			}
+		}
+		for _, oldID := range events {
+			_, err := db.ExecContext(ctx, `
+				DELETE FROM activity_events WHERE event_type = $1 AND source_id = $2`, store.ActivityTypePersonalRecord, oldID)
+			if err != nil {
+				return fmt.Errorf("failed to delete personal record activity: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// groupPersonalRecords splits records by metric, keeping their order
+func groupPersonalRecords(records []*store.PersonalRecord) map[string][]*store.PersonalRecord {
+	byMetric := make(map[string][]*store.PersonalRecord)
+	for _, pr := range records {
+		byMetric[pr.Metric] = append(byMetric[pr.Metric], pr)
+	}
+	return byMetric
+}
+
+// samePersonalRecord reports whether a replayed record matches a stored one
+func samePersonalRecord(a, b *store.PersonalRecord) bool {
+	if a.WorkoutID != b.WorkoutID || a.Value != b.Value || !a.AchievedAt.Equal(b.AchievedAt) {
+		return false
+	}
+	if a.PreviousValue == nil || b.PreviousValue == nil {
+		return a.PreviousValue == nil && b.PreviousValue == nil
+	}
+	return *a.PreviousValue == *b.PreviousValue
+}
+
+// GetUserPersonalRecords implements store.PersonalRecordStore
+func (s *Store) GetUserPersonalRecords(ctx context.Context, userID int32) ([]*store.PersonalRecord, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT DISTINCT ON (exercise_type, metric) `+personalRecordColumns+`
+		FROM personal_records
+		WHERE user_id = $1
+		ORDER BY exercise_type, metric, id DESC`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get personal records: %w", err)
+	}
+	defer rows.Close()
+
+	records := []*store.PersonalRecord{}
+	for rows.Next() {
+		pr, err := scanPersonalRecordRow(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan personal record: %w", err)
+		}
+		records = append(records, pr)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate personal records: %w", err)
+	}
+	return records, nil
+}
+
+// GetPersonalRecordHistory implements store.PersonalRecordStore
+func (s *Store) GetPersonalRecordHistory(ctx context.Context, userID int32, exerciseType string, limit int32, offset int32) (*store.PaginatedPersonalRecords, error) {
+	var count int64
+	err := s.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM personal_records
+		WHERE user_id = $1 AND ($2 = '' OR exercise_type = $2)`,
+		userID, exerciseType,
+	).Scan(&count)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count personal records: %w", err)
+	}
+	if count == 0 {
+		return &store.PaginatedPersonalRecords{Records: []*store.PersonalRecord{}, TotalCount: 0}, nil
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT `+personalRecordColumns+`
+		FROM personal_records
+		WHERE user_id = $1 AND ($2 = '' OR exercise_type = $2)
+		ORDER BY id DESC
+		LIMIT $3 OFFSET $4`,
+		userID, exerciseType, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get personal record history: %w", err)
+	}
+	defer rows.Close()
+
+	records := []*store.PersonalRecord{}
+	for rows.Next() {
+		pr, err := scanPersonalRecordRow(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan personal record: %w", err)
+		}
+		records = append(records, pr)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate personal records: %w", err)
+	}
+	return &store.PaginatedPersonalRecords{Records: records, TotalCount: count}, nil
+}
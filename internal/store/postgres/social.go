@@ -0,0 +1,204 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+
+	"ptchampion/internal/store"
+)
+
+// friendshipColumns selects the fields read by scanFriendshipRow from friendships f
+const friendshipColumns = `f.id, f.requester_id, f.addressee_id, f.status, f.created_at, f.updated_at`
+
+// friendsOf is a CTE naming the user $1 and the users they are friends with
+const friendsOf = `
+	WITH friends AS (
+		SELECT $1::int AS user_id
+		UNION
+		SELECT CASE WHEN f.requester_id = $1 THEN f.addressee_id ELSE f.requester_id END
+		FROM friendships f
+		WHERE f.status = 'accepted' AND $1 IN (f.requester_id, f.addressee_id)
+	)`
+
+// GetFriendship implements store.SocialStore
+func (s *Store) GetFriendship(ctx context.Context, userID int32, otherUserID int32) (*store.Friendship, error) {
+	query := `
+		SELECT ` + friendshipColumns + `
+		FROM friendships f
+		WHERE (f.requester_id = $1 AND f.addressee_id = $2)
+		   OR (f.requester_id = $2 AND f.addressee_id = $1)`
+
+	friendship, err := scanFriendshipRow(s.db.QueryRowContext(ctx, query, userID, otherUserID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, store.ErrFriendshipNotFound
+		}
+		return nil, fmt.Errorf("failed to get friendship: %w", err)
+	}
+	return friendship, nil
+}
+
+// CreateFriendship implements store.SocialStore
+func (s *Store) CreateFriendship(ctx context.Context, friendship *store.Friendship) (*store.Friendship, error) {
+	query := `
+		INSERT INTO friendships AS f (requester_id, addressee_id, status)
+		VALUES ($1, $2, $3)
+		RETURNING ` + friendshipColumns
+
+	created, err := scanFriendshipRow(s.db.QueryRowContext(ctx, query, friendship.RequesterID, friendship.AddresseeID, friendship.Status))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create friendship: %w", err)
+	}
+	return created, nil
+}
+
+// UpdateFriendship implements store.SocialStore
+func (s *Store) UpdateFriendship(ctx context.Context, friendship *store.Friendship) (*store.Friendship, error) {
+	query := `
+		UPDATE friendships AS f
+		SET requester_id = $2, addressee_id = $3, status = $4, updated_at = NOW()
+		WHERE f.id = $1
+		RETURNING ` + friendshipColumns
+
+	updated, err := scanFriendshipRow(s.db.QueryRowContext(ctx, query,
+		friendship.ID, friendship.RequesterID, friendship.AddresseeID, friendship.Status))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, store.ErrFriendshipNotFound
+		}
+		return nil, fmt.Errorf("failed to update friendship: %w", err)
+	}
+	return updated, nil
+}
+
+// DeleteFriendship implements store.SocialStore
+func (s *Store) DeleteFriendship(ctx context.Context, id int32) error {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM friendships WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete friendship: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check deleted friendship: %w", err)
+	}
+	if affected == 0 {
+		return store.ErrFriendshipNotFound
+	}
+	return nil
+}
+
+// ListFriendships implements store.SocialStore
+func (s *Store) ListFriendships(ctx context.Context, userID int32, filters store.FriendshipFilters, limit int32, offset int32) (*store.PaginatedFriends, error) {
+	from := `
+		FROM friendships f
+		JOIN users u ON u.id = CASE WHEN f.requester_id = $1 THEN f.addressee_id ELSE f.requester_id END
+		WHERE f.status = $2
+		  AND CASE $3
+			WHEN 'incoming' THEN f.addressee_id = $1
+			WHEN 'outgoing' THEN f.requester_id = $1
+			ELSE $1 IN (f.requester_id, f.addressee_id)
+		  END`
+
+	var count int64
+	if err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) `+from, userID, filters.Status, filters.Direction).Scan(&count); err != nil {
+		return nil, fmt.Errorf("failed to count friendships: %w", err)
+	}
+	if count == 0 {
+		return &store.PaginatedFriends{
+			Friends:    []*store.Friend{},
+			TotalCount: 0,
+		}, nil
+	}
+
+	query := `SELECT ` + friendshipColumns + `, u.id, u.username, u.first_name, u.last_name` + from + `
+		ORDER BY f.updated_at DESC, f.id DESC
+		LIMIT $4 OFFSET $5`
+
+	rows, err := s.db.QueryContext(ctx, query, userID, filters.Status, filters.Direction, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list friendships: %w", err)
+	}
+	defer rows.Close()
+
+	friends := make([]*store.Friend, 0)
+	for rows.Next() {
+		var friend store.Friend
+		var firstName, lastName sql.NullString
+		friendship, err := scanFriendshipRow(rows, &friend.UserID, &friend.Username, &firstName, &lastName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan friendship row: %w", err)
+		}
+		friend.Friendship = friendship
+		friend.FirstName = nullStringToStringPtr(firstName)
+		friend.LastName = nullStringToStringPtr(lastName)
+		friends = append(friends, &friend)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating friendship rows: %w", err)
+	}
+	return &store.PaginatedFriends{
+		Friends:    friends,
+		TotalCount: count,
+	}, nil
+}
+
+// GetFriendsAggregateLeaderboardByTypes implements store.LeaderboardStore, the variant of
+// GetGlobalAggregateLeaderboardByTypes limited to the user and their friends. Friends-only
+// workouts count alongside public ones.
+func (s *Store) GetFriendsAggregateLeaderboardByTypes(ctx context.Context, userID int32, exerciseTypes []string, scoringStandard string, limit int, startDate time.Time, endDate time.Time) ([]*store.LeaderboardEntry, error) {
+	s.logger.Debug(ctx, "Store: GetFriendsAggregateLeaderboardByTypes called", "userID", userID, "types", exerciseTypes, "standard", scoringStandard, "limit", limit, "startDate", startDate, "endDate", endDate)
+
+	query := friendsOf + `,
+		user_best_scores AS (
+			SELECT
+				w.user_id,
+				e.type AS exercise_type,
+				MAX(COALESCE(w.expected_grade, w.grade)) AS best_score
+			FROM workouts w
+			JOIN exercises e ON w.exercise_id = e.id
+			WHERE w.visibility IN ('public', 'friends')
+			  AND w.verification_status <> 'mismatched'
+			  AND w.deleted_at IS NULL
+			  AND e.type = ANY($2)
+			  AND w.scoring_standard = $7
+			  AND w.user_id IN (SELECT user_id FROM friends)
+			  AND ($4::timestamptz IS NULL OR w.completed_at >= $4::timestamptz)
+			  AND ($5::timestamptz IS NULL OR w.completed_at < $5::timestamptz)
+			GROUP BY w.user_id, e.type
+		)
+		SELECT
+			u.id AS user_id,
+			u.username,
+			CONCAT(u.first_name, ' ', u.last_name) AS display_name,
+			SUM(ubs.best_score) AS score
+		FROM users u
+		JOIN user_best_scores ubs ON u.id = ubs.user_id
+		GROUP BY u.id, u.username, u.first_name, u.last_name
+		HAVING COUNT(DISTINCT ubs.exercise_type) = $3
+		ORDER BY score DESC
+		LIMIT $6`
+
+	rows, err := s.db.QueryContext(ctx, query, userID, pq.Array(exerciseTypes), len(exerciseTypes),
+		nullTimeFromZero(startDate), nullTimeFromZero(endDate), limit, scoringStandard)
+	if err != nil {
+		s.logger.Error(ctx, "Failed to get friends leaderboard from DB", "userID", userID, "error", err)
+		return nil, fmt.Errorf("failed to get friends leaderboard from DB: %w", err)
+	}
+	defer rows.Close()
+
+	return scanAggregateLeaderboardRows(rows)
+}
+
+// scanFriendshipRow reads a row selected with friendshipColumns, followed by any trailing columns
+func scanFriendshipRow(row rowScanner, trailing ...interface{}) (*store.Friendship, error) {
+	var f store.Friendship
+	dest := append([]interface{}{&f.ID, &f.RequesterID, &f.AddresseeID, &f.Status, &f.CreatedAt, &f.UpdatedAt}, trailing...)
+	if err := row.Scan(dest...); err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
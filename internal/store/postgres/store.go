@@ -146,6 +146,7 @@ func toStoreUser(dbUser User) *store.User {
 		LastName:     lastName,
 		Gender:       gender,
 		DateOfBirth:  dateOfBirth,
+		Timezone:     dbUser.Timezone,
 		CreatedAt:    createdAt,
 		UpdatedAt:    updatedAt,
 	}
@@ -202,9 +203,14 @@ func (s *Store) GetUserByEmail(ctx context.Context, email string) (*store.User,
 
 // GetUserByUsername implements store.UserStore
 func (s *Store) GetUserByUsername(ctx context.Context, username string) (*store.User, error) {
-	// TODO: Implement once the actual SQL query is defined
-	s.logger.Debug(ctx, "GetUserByUsername: Not implemented yet, returning ErrUserNotFound", "username", username)
-	return nil, store.ErrUserNotFound
+	dbUser, err := s.Queries.GetUserByUsername(ctx, username)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, store.ErrUserNotFound
+		}
+		return nil, fmt.Errorf("failed to get user by username from DB: %w", err)
+	}
+	return toStoreUser(dbUser), nil
 }
 
 // GetUserByProviderID implements store.UserStore
@@ -243,6 +249,7 @@ func (s *Store) UpdateUser(ctx context.Context, user *store.User) (*store.User,
 		Longitude:   currentUserRecord.Longitude,
 		Gender:      currentUserRecord.Gender,
 		DateOfBirth: currentUserRecord.DateOfBirth,
+		Timezone:    currentUserRecord.Timezone,
 	}
 
 	// Override with values from the update request if provided
@@ -270,6 +277,10 @@ func (s *Store) UpdateUser(ctx context.Context, user *store.User) (*store.User,
 		params.DateOfBirth = sql.NullTime{Time: user.DateOfBirth, Valid: true}
 	}
 
+	if user.Timezone != "" {
+		params.Timezone = user.Timezone
+	}
+
 	// Perform the update
 	updatedDbUser, err := s.Queries.UpdateUser(ctx, params)
 	if err != nil {
@@ -552,7 +563,7 @@ func mapSqlcRowToLeaderboardEntry(userID int32, username string, displayName int
 }
 
 // GetGlobalExerciseLeaderboard implements store.LeaderboardStore
-func (s *Store) GetGlobalExerciseLeaderboard(ctx context.Context, exerciseType string, limit int, startDate time.Time, endDate time.Time) ([]*store.LeaderboardEntry, error) {
+func (s *Store) GetGlobalExerciseLeaderboard(ctx context.Context, exerciseType string, scoringStandard string, limit int, startDate time.Time, endDate time.Time) ([]*store.LeaderboardEntry, error) {
 	s.logger.Debug(ctx, "Store: GetGlobalExerciseLeaderboard called", "type", exerciseType, "limit", limit, "startDate", startDate, "endDate", endDate)
 
 	var sqlStartDate sql.NullTime
@@ -565,10 +576,11 @@ func (s *Store) GetGlobalExerciseLeaderboard(ctx context.Context, exerciseType s
 	}
 
 	params := GetGlobalExerciseLeaderboardParams{
-		Type:      exerciseType,
-		Limit:     int32(limit),
-		StartDate: sqlStartDate, // Assumes sqlc generated `StartDate sql.NullTime`
-		EndDate:   sqlEndDate,   // Assumes sqlc generated `EndDate sql.NullTime`
+		Type:            exerciseType,
+		ScoringStandard: scoringStandard,
+		Limit:           int32(limit),
+		StartDate:       sqlStartDate, // Assumes sqlc generated `StartDate sql.NullTime`
+		EndDate:         sqlEndDate,   // Assumes sqlc generated `EndDate sql.NullTime`
 	}
 	dbRows, err := s.Queries.GetGlobalExerciseLeaderboard(ctx, params)
 	if err != nil {
@@ -643,7 +655,7 @@ func (s *Store) GetGlobalAggregateLeaderboard(ctx context.Context, limit int, st
 }
 
 // GetLocalExerciseLeaderboard implements store.LeaderboardStore
-func (s *Store) GetLocalExerciseLeaderboard(ctx context.Context, exerciseType string, latitude, longitude float64, radiusMeters int, limit int, startDate time.Time, endDate time.Time) ([]*store.LeaderboardEntry, error) {
+func (s *Store) GetLocalExerciseLeaderboard(ctx context.Context, exerciseType string, scoringStandard string, latitude, longitude float64, radiusMeters int, limit int, startDate time.Time, endDate time.Time) ([]*store.LeaderboardEntry, error) {
 	s.logger.Debug(ctx, "Store: GetLocalExerciseLeaderboard called", "type", exerciseType, "lat", latitude, "lon", longitude, "radius", radiusMeters, "limit", limit, "startDate", startDate, "endDate", endDate)
 
 	var sqlStartDate sql.NullTime
@@ -656,13 +668,14 @@ func (s *Store) GetLocalExerciseLeaderboard(ctx context.Context, exerciseType st
 	}
 
 	params := GetLocalExerciseLeaderboardParams{
-		Type:         exerciseType,
-		Longitude:    longitude,             // SQLC will expect `Longitude` based on @longitude
-		Latitude:     latitude,              // SQLC will expect `Latitude` based on @latitude
-		RadiusMeters: float64(radiusMeters), // SQLC will expect `RadiusMeters`
-		Limit:        int32(limit),
-		StartDate:    sqlStartDate, // Assumes sqlc generated `StartDate sql.NullTime`
-		EndDate:      sqlEndDate,   // Assumes sqlc generated `EndDate sql.NullTime`
+		Type:            exerciseType,
+		ScoringStandard: scoringStandard,
+		Longitude:       longitude,             // SQLC will expect `Longitude` based on @longitude
+		Latitude:        latitude,              // SQLC will expect `Latitude` based on @latitude
+		RadiusMeters:    float64(radiusMeters), // SQLC will expect `RadiusMeters`
+		Limit:           int32(limit),
+		StartDate:       sqlStartDate, // Assumes sqlc generated `StartDate sql.NullTime`
+		EndDate:         sqlEndDate,   // Assumes sqlc generated `EndDate sql.NullTime`
 	}
 	dbRows, err := s.Queries.GetLocalExerciseLeaderboard(ctx, params)
 	if err != nil {
@@ -728,12 +741,12 @@ func (s *Store) GetLocalAggregateLeaderboard(ctx context.Context, latitude, long
 }
 
 // GetGlobalLeaderboard implements store.LeaderboardStore with time filtering and offset support
-func (s *Store) GetGlobalLeaderboard(ctx context.Context, exerciseType string, startDate, endDate *time.Time, limit, offset int) ([]*store.LeaderboardEntry, error) {
+func (s *Store) GetGlobalLeaderboard(ctx context.Context, exerciseType string, scoringStandard string, startDate, endDate *time.Time, limit, offset int) ([]*store.LeaderboardEntry, error) {
 	s.logger.Debug(ctx, "Store: GetGlobalLeaderboard called", "type", exerciseType, "limit", limit, "offset", offset, "startDate", startDate, "endDate", endDate)
 
 	// Create the repository and call the method
 	repo := NewLeaderboardRepository(s.db)
-	entries, err := repo.GetGlobalLeaderboard(ctx, exerciseType, startDate, endDate, limit, offset)
+	entries, err := repo.GetGlobalLeaderboard(ctx, exerciseType, scoringStandard, startDate, endDate, limit, offset)
 	if err != nil {
 		s.logger.Error(ctx, "Failed to get global leaderboard with time filter from DB", "type", exerciseType, "error", err)
 		return nil, fmt.Errorf("failed to get global leaderboard with time filter from DB: %w", err)
@@ -770,12 +783,12 @@ func (s *Store) GetGlobalLeaderboard(ctx context.Context, exerciseType string, s
 }
 
 // GetLocalLeaderboard implements store.LeaderboardStore with time filtering and offset support
-func (s *Store) GetLocalLeaderboard(ctx context.Context, exerciseType string, lat, lng, radiusMeters float64, startDate, endDate *time.Time, limit, offset int) ([]*store.LeaderboardEntry, error) {
+func (s *Store) GetLocalLeaderboard(ctx context.Context, exerciseType string, scoringStandard string, lat, lng, radiusMeters float64, startDate, endDate *time.Time, limit, offset int) ([]*store.LeaderboardEntry, error) {
 	s.logger.Debug(ctx, "Store: GetLocalLeaderboard called", "type", exerciseType, "lat", lat, "lng", lng, "radius", radiusMeters, "limit", limit, "offset", offset, "startDate", startDate, "endDate", endDate)
 
 	// Create the repository and call the method
 	repo := NewLeaderboardRepository(s.db)
-	entries, err := repo.GetLocalLeaderboard(ctx, exerciseType, lat, lng, radiusMeters, startDate, endDate, limit, offset)
+	entries, err := repo.GetLocalLeaderboard(ctx, exerciseType, scoringStandard, lat, lng, radiusMeters, startDate, endDate, limit, offset)
 	if err != nil {
 		s.logger.Error(ctx, "Failed to get local leaderboard with time filter from DB", "type", exerciseType, "error", err)
 		return nil, fmt.Errorf("failed to get local leaderboard with time filter from DB: %w", err)
@@ -827,6 +840,7 @@ func toStoreWorkoutRecord(dbRec interface{}) *store.WorkoutRecord {
 			FormScore:       nullInt32ToInt32Ptr(v.FormScore),
 			Grade:           v.Grade,
 			IsPublic:        v.IsPublic,
+			Visibility:      visibilityFromPublic(v.IsPublic),
 			CompletedAt:     v.CompletedAt,
 			CreatedAt:       v.CreatedAt,
 		}
@@ -842,6 +856,7 @@ func toStoreWorkoutRecord(dbRec interface{}) *store.WorkoutRecord {
 			FormScore:       nullInt32ToInt32Ptr(v.FormScore),
 			Grade:           v.Grade,
 			IsPublic:     v.IsPublic,
+			Visibility:   visibilityFromPublic(v.IsPublic),
 			CompletedAt:     v.CompletedAt,
 			CreatedAt:       v.CreatedAt,
 		}
@@ -852,6 +867,21 @@ func toStoreWorkoutRecord(dbRec interface{}) *store.WorkoutRecord {
 
 // CreateWorkoutRecord implements store.WorkoutStore
 func (s *Store) CreateWorkoutRecord(ctx context.Context, record *store.WorkoutRecord) (*store.WorkoutRecord, error) {
+	var newRecord *store.WorkoutRecord
+	err := s.ExecTx(ctx, func(q *Queries) error {
+		var err error
+		newRecord, err = insertWorkoutRecord(ctx, q, record)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create workout record in DB: %w", err)
+	}
+	return newRecord, nil
+}
+
+// insertWorkoutRecord creates a workout and writes the columns the generated
+// CreateWorkout query predates. It must run inside a transaction.
+func insertWorkoutRecord(ctx context.Context, q *Queries, record *store.WorkoutRecord) (*store.WorkoutRecord, error) {
 	params := CreateWorkoutParams{
 		UserID:          record.UserID,
 		ExerciseID:      record.ExerciseID,
@@ -868,11 +898,41 @@ func (s *Store) CreateWorkoutRecord(ctx context.Context, record *store.WorkoutRe
 		// For now, it will be whatever the DB defaults it to or what the RETURNING clause provides if it's set by trigger/default.
 		// The db.Workout model does have FormScore, so it is read back.
 	}
-	dbWorkout, err := s.Queries.CreateWorkout(ctx, params)
+	dbWorkout, err := q.CreateWorkout(ctx, params)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create workout record in DB: %w", err)
+		return nil, err
 	}
+	if record.WeightLbs != nil || record.DistanceMeters != nil {
+		if err := setWorkoutMeasurements(ctx, q.DB(), dbWorkout.ID, record.WeightLbs, record.DistanceMeters); err != nil {
+			return nil, err
+		}
+	}
+	if record.Visibility == store.WorkoutVisibilityFriends {
+		// The generated query only sets is_public, from which the trigger derives public or private
+		if err := setWorkoutVisibility(ctx, q.DB(), dbWorkout.ID, record.Visibility); err != nil {
+			return nil, err
+		}
+	}
+	if record.VerificationStatus != "" {
+		// Verification columns are written in the same transaction as the insert
+		if err := setWorkoutVerification(ctx, q.DB(), dbWorkout.ID, record); err != nil {
+			return nil, err
+		}
+	}
+
 	newRecord := toStoreWorkoutRecord(dbWorkout)
+	if newRecord != nil {
+		newRecord.VerificationStatus = record.VerificationStatus
+		newRecord.ExpectedGrade = record.ExpectedGrade
+		newRecord.GradeDiscrepancy = record.GradeDiscrepancy
+		newRecord.ScoringStandard = record.ScoringStandard
+		newRecord.ScoringVersion = record.ScoringVersion
+		newRecord.WeightLbs = record.WeightLbs
+		newRecord.DistanceMeters = record.DistanceMeters
+		if record.Visibility == store.WorkoutVisibilityFriends {
+			newRecord.Visibility = record.Visibility
+		}
+	}
 	// If ExerciseName was part of the input store.WorkoutRecord (e.g. already known by caller),
 	// we can copy it over, as db.Workout from CreateWorkout doesn't have it directly.
 	if newRecord != nil && record.ExerciseName != "" {
@@ -911,6 +971,12 @@ func (s *Store) GetUserWorkoutRecords(ctx context.Context, userID int32, limit i
 	for i, dbRow := range dbWorkoutRows {
 		records[i] = toStoreWorkoutRecord(dbRow)
 	}
+	if err := attachWorkoutMeasurements(ctx, s.db, records); err != nil {
+		return nil, err
+	}
+	if err := attachWorkoutVisibility(ctx, s.db, records); err != nil {
+		return nil, err
+	}
 
 	return &store.PaginatedWorkoutRecords{
 		Records:    records,
@@ -920,42 +986,37 @@ func (s *Store) GetUserWorkoutRecords(ctx context.Context, userID int32, limit i
 
 // GetWorkoutRecordByID implements store.WorkoutStore
 func (s *Store) GetWorkoutRecordByID(ctx context.Context, id int32) (*store.WorkoutRecord, error) {
-	dbWorkout, err := s.Queries.GetWorkoutRecordByID(ctx, id)
+	// Joins the exercise and reads the verification and sync columns the generated query predates
+	records, err := queryWorkoutRecords(ctx, s.db, `WHERE w.id = $1 AND w.deleted_at IS NULL`, id)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, store.ErrWorkoutRecordNotFound
-		}
 		return nil, fmt.Errorf("failed to get workout record by ID from DB: %w", err)
 	}
-	// The db.Workout might not have ExerciseName directly, so we need to fetch it.
-	// This conversion needs to be smarter or the query needs to join.
-	// For now, toStoreWorkoutRecord will handle what it can.
-	workout := toStoreWorkoutRecord(dbWorkout)
-	if workout != nil && workout.ExerciseName == "" { // If name is missing, try to fetch exercise details
-		exDef, err := s.GetExerciseDefinition(ctx, workout.ExerciseID)
-		if err == nil && exDef != nil {
-			workout.ExerciseName = exDef.Name
-			workout.ExerciseType = exDef.Type // Ensure type is also set
-		}
+	if len(records) == 0 {
+		return nil, store.ErrWorkoutRecordNotFound
 	}
-	return workout, nil
+	return records[0], nil
 }
 
-// UpdateWorkoutVisibility implements store.WorkoutStore
-func (s *Store) UpdateWorkoutVisibility(ctx context.Context, userID int32, workoutID int32, isPublic bool) error {
-	params := UpdateWorkoutVisibilityParams{
-		IsPublic: isPublic,
-		ID:       workoutID,
-		UserID:   userID, // Ensure user owns the workout
-	}
-	err := s.Queries.UpdateWorkoutVisibility(ctx, params)
-	if err != nil {
-		// TODO: Check if the error is because the row was not found (e.g., user_id didn't match or workout_id didn't exist)
-		// sqlc exec result doesn't directly tell us rows affected easily without more complex handling.
-		// The service layer should verify ownership before calling this.
-		return fmt.Errorf("failed to update workout visibility in DB: %w", err)
-	}
-	return nil
+// UpdateWorkoutVisibility implements store.WorkoutStore. The workouts trigger keeps
+// is_public in step with the visibility, and the workout's feed events reach friends
+// once it is no longer private.
+func (s *Store) UpdateWorkoutVisibility(ctx context.Context, userID int32, workoutID int32, visibility string) error {
+	return s.ExecTx(ctx, func(q *Queries) error {
+		_, err := q.DB().ExecContext(ctx, `
+			UPDATE workouts SET visibility = $1
+			WHERE id = $2 AND user_id = $3 AND deleted_at IS NULL`, // Ensure user owns the workout
+			visibility, workoutID, userID)
+		if err != nil {
+			// The service layer verifies ownership before calling this, so no rows affected is not reported.
+			return fmt.Errorf("failed to update workout visibility in DB: %w", err)
+		}
+
+		records, err := queryWorkoutRecords(ctx, q.DB(), `WHERE w.id = $1 AND w.user_id = $2 AND w.deleted_at IS NULL`, workoutID, userID)
+		if err != nil || len(records) == 0 {
+			return err
+		}
+		return refreshWorkoutActivity(ctx, q.DB(), records[0])
+	})
 }
 
 // GetUserWorkoutRecordsWithFilters implements store.WorkoutStore with filtering support
@@ -976,7 +1037,7 @@ func (s *Store) GetUserWorkoutRecordsWithFilters(ctx context.Context, userID int
 			w.completed_at
 		FROM workouts w
 		JOIN exercises e ON w.exercise_id = e.id
-		WHERE w.user_id = $1`
+		WHERE w.user_id = $1 AND w.deleted_at IS NULL`
 	
 	args := []interface{}{userID}
 	argCount := 1
@@ -1009,7 +1070,7 @@ func (s *Store) GetUserWorkoutRecordsWithFilters(ctx context.Context, userID int
 		SELECT COUNT(*)
 		FROM workouts w
 		JOIN exercises e ON w.exercise_id = e.id
-		WHERE w.user_id = $1`
+		WHERE w.user_id = $1 AND w.deleted_at IS NULL`
 	
 	countArgs := []interface{}{userID}
 	countArgCount := 1
@@ -1088,6 +1149,12 @@ func (s *Store) GetUserWorkoutRecordsWithFilters(ctx context.Context, userID int
 	if err := rows.Err(); err != nil {
 		return nil, fmt.Errorf("error iterating workout rows: %w", err)
 	}
+	if err := attachWorkoutMeasurements(ctx, s.db, records); err != nil {
+		return nil, err
+	}
+	if err := attachWorkoutVisibility(ctx, s.db, records); err != nil {
+		return nil, err
+	}
 	
 	return &store.PaginatedWorkoutRecords{
 		Records:    records,
@@ -0,0 +1,40 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// ListUserActiveDays implements store.WorkoutStore
+func (s *Store) ListUserActiveDays(ctx context.Context, userID int32) ([]time.Time, error) {
+	return queryActiveDays(ctx, s.db, userID)
+}
+
+// queryActiveDays returns the distinct calendar dates, in the user's timezone, on which the
+// user completed a workout that is not deleted, oldest first
+func queryActiveDays(ctx context.Context, db DBTX, userID int32) ([]time.Time, error) {
+	rows, err := db.QueryContext(ctx, `
+		SELECT DISTINCT (w.completed_at AT TIME ZONE u.timezone)::date AS day
+		FROM workouts w
+		JOIN users u ON u.id = w.user_id
+		WHERE w.user_id = $1 AND w.deleted_at IS NULL
+		ORDER BY day`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get active days: %w", err)
+	}
+	defer rows.Close()
+
+	days := []time.Time{}
+	for rows.Next() {
+		var day time.Time
+		if err := rows.Scan(&day); err != nil {
+			return nil, fmt.Errorf("failed to scan active day row: %w", err)
+		}
+		days = append(days, day)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating active day rows: %w", err)
+	}
+	return days, nil
+}
@@ -0,0 +1,207 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+
+	"ptchampion/internal/store"
+)
+
+const testSessionColumns = `
+			id,
+			user_id,
+			scoring_standard,
+			scoring_version,
+			test_date,
+			started_at,
+			completed_at,
+			total_score,
+			passed,
+			is_public,
+			created_at`
+
+// CreateTestSession implements store.WorkoutStore, storing the session and one workout
+// per event in a single transaction
+func (s *Store) CreateTestSession(ctx context.Context, session *store.TestSession) (*store.TestSession, error) {
+	query := `
+		INSERT INTO test_sessions (
+			user_id, scoring_standard, scoring_version, test_date,
+			started_at, completed_at, total_score, passed, is_public
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id, created_at`
+
+	created := *session
+	created.Events = make([]*store.WorkoutRecord, 0, len(session.Events))
+	err := s.ExecTx(ctx, func(q *Queries) error {
+		err := q.DB().QueryRowContext(ctx, query,
+			session.UserID, session.ScoringStandard, session.ScoringVersion, session.TestDate,
+			session.StartedAt, session.CompletedAt, session.TotalScore, session.Passed, session.IsPublic,
+		).Scan(&created.ID, &created.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("failed to insert test session: %w", err)
+		}
+
+		for i, event := range session.Events {
+			record, err := insertWorkoutRecord(ctx, q, event)
+			if err != nil {
+				return err
+			}
+			_, err = q.DB().ExecContext(ctx,
+				`UPDATE workouts SET test_session_id = $2, test_event_order = $3 WHERE id = $1`,
+				record.ID, created.ID, i+1)
+			if err != nil {
+				return fmt.Errorf("failed to link workout to test session: %w", err)
+			}
+			created.Events = append(created.Events, record)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create test session in DB: %w", err)
+	}
+	return &created, nil
+}
+
+// GetTestSessionByID implements store.WorkoutStore
+func (s *Store) GetTestSessionByID(ctx context.Context, id int32) (*store.TestSession, error) {
+	query := `SELECT ` + testSessionColumns + ` FROM test_sessions WHERE id = $1`
+
+	rows, err := s.db.QueryContext(ctx, query, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get test session: %w", err)
+	}
+	defer rows.Close()
+
+	sessions, err := scanTestSessionRows(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(sessions) == 0 {
+		return nil, store.ErrTestSessionNotFound
+	}
+	if err := s.attachTestSessionEvents(ctx, sessions); err != nil {
+		return nil, err
+	}
+	return sessions[0], nil
+}
+
+// GetUserTestSessions implements store.WorkoutStore, returning a user's sessions newest first
+func (s *Store) GetUserTestSessions(ctx context.Context, userID int32, limit int32, offset int32) (*store.PaginatedTestSessions, error) {
+	var count int64
+	err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM test_sessions WHERE user_id = $1`, userID).Scan(&count)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get test session count: %w", err)
+	}
+
+	if count == 0 {
+		return &store.PaginatedTestSessions{
+			Sessions:   []*store.TestSession{},
+			TotalCount: 0,
+		}, nil
+	}
+
+	query := `
+		SELECT ` + testSessionColumns + `
+		FROM test_sessions
+		WHERE user_id = $1
+		ORDER BY started_at DESC
+		LIMIT $2 OFFSET $3`
+
+	rows, err := s.db.QueryContext(ctx, query, userID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user test sessions: %w", err)
+	}
+	defer rows.Close()
+
+	sessions, err := scanTestSessionRows(rows)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.attachTestSessionEvents(ctx, sessions); err != nil {
+		return nil, err
+	}
+
+	return &store.PaginatedTestSessions{
+		Sessions:   sessions,
+		TotalCount: count,
+	}, nil
+}
+
+// scanTestSessionRows reads rows selected with testSessionColumns
+func scanTestSessionRows(rows *sql.Rows) ([]*store.TestSession, error) {
+	sessions := make([]*store.TestSession, 0)
+	for rows.Next() {
+		var session store.TestSession
+		err := rows.Scan(
+			&session.ID,
+			&session.UserID,
+			&session.ScoringStandard,
+			&session.ScoringVersion,
+			&session.TestDate,
+			&session.StartedAt,
+			&session.CompletedAt,
+			&session.TotalScore,
+			&session.Passed,
+			&session.IsPublic,
+			&session.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan test session row: %w", err)
+		}
+		session.Events = []*store.WorkoutRecord{}
+		sessions = append(sessions, &session)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating test session rows: %w", err)
+	}
+	return sessions, nil
+}
+
+// attachTestSessionEvents loads the event workouts of each session in test order
+func (s *Store) attachTestSessionEvents(ctx context.Context, sessions []*store.TestSession) error {
+	if len(sessions) == 0 {
+		return nil
+	}
+
+	byID := make(map[int32]*store.TestSession, len(sessions))
+	ids := make([]int64, 0, len(sessions))
+	for _, session := range sessions {
+		byID[session.ID] = session
+		ids = append(ids, int64(session.ID))
+	}
+
+	query := `
+		SELECT w.test_session_id, ` + workoutRecordColumns + `
+		FROM workouts w
+		JOIN exercises e ON w.exercise_id = e.id
+		WHERE w.test_session_id = ANY($1) AND w.deleted_at IS NULL
+		ORDER BY w.test_session_id, w.test_event_order`
+
+	rows, err := s.db.QueryContext(ctx, query, pq.Array(ids))
+	if err != nil {
+		return fmt.Errorf("failed to get test session events: %w", err)
+	}
+	defer rows.Close()
+
+	// The session ID precedes the workout columns, so scan it separately per row
+	var all []*store.WorkoutRecord
+	for rows.Next() {
+		var sessionID int32
+		rec, err := scanWorkoutRecordRow(rows, &sessionID)
+		if err != nil {
+			return err
+		}
+		byID[sessionID].Events = append(byID[sessionID].Events, rec)
+		all = append(all, rec)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating test session events: %w", err)
+	}
+
+	return attachWorkoutMeasurements(ctx, s.db, all)
+}
@@ -0,0 +1,109 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"ptchampion/internal/store"
+)
+
+// CreateTrainingPlan implements store.WorkoutStore. The user's active plan is superseded
+// in the same transaction, so a user never has two.
+func (s *Store) CreateTrainingPlan(ctx context.Context, plan *store.TrainingPlan) (*store.TrainingPlan, error) {
+	events, err := json.Marshal(plan.Events)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode training plan events: %w", err)
+	}
+
+	var created *store.TrainingPlan
+	err = s.ExecTx(ctx, func(q *Queries) error {
+		db := q.DB()
+		if _, err := db.ExecContext(ctx, `
+			UPDATE training_plans SET superseded_at = NOW()
+			WHERE user_id = $1 AND superseded_at IS NULL`, plan.UserID); err != nil {
+			return fmt.Errorf("failed to supersede training plan: %w", err)
+		}
+
+		p := *plan
+		p.Sessions = make([]*store.TrainingSession, 0, len(plan.Sessions))
+		if err := db.QueryRowContext(ctx, `
+			INSERT INTO training_plans (user_id, scoring_standard, test_session_id, start_date, weeks, events)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			RETURNING id, created_at`,
+			plan.UserID, plan.ScoringStandard, plan.TestSessionID, plan.StartDate.Format("2006-01-02"), plan.Weeks, events,
+		).Scan(&p.ID, &p.CreatedAt); err != nil {
+			return fmt.Errorf("failed to create training plan: %w", err)
+		}
+
+		for _, session := range plan.Sessions {
+			sess := *session
+			sess.PlanID = p.ID
+			if err := db.QueryRowContext(ctx, `
+				INSERT INTO training_sessions (plan_id, scheduled_date, week, event, focus, sets, target_value)
+				VALUES ($1, $2, $3, $4, $5, $6, $7)
+				RETURNING id`,
+				p.ID, session.ScheduledDate.Format("2006-01-02"), session.Week, session.Event, session.Focus, session.Sets, session.TargetValue,
+			).Scan(&sess.ID); err != nil {
+				return fmt.Errorf("failed to create training session: %w", err)
+			}
+			p.Sessions = append(p.Sessions, &sess)
+		}
+		created = &p
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// GetActiveTrainingPlan implements store.WorkoutStore
+func (s *Store) GetActiveTrainingPlan(ctx context.Context, userID int32) (*store.TrainingPlan, error) {
+	var p store.TrainingPlan
+	var testSessionID sql.NullInt32
+	var events []byte
+	err := s.db.QueryRowContext(ctx, `
+		SELECT id, user_id, scoring_standard, test_session_id, start_date, weeks, events, created_at
+		FROM training_plans
+		WHERE user_id = $1 AND superseded_at IS NULL`, userID,
+	).Scan(&p.ID, &p.UserID, &p.ScoringStandard, &testSessionID, &p.StartDate, &p.Weeks, &events, &p.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, store.ErrTrainingPlanNotFound
+		}
+		return nil, fmt.Errorf("failed to get training plan: %w", err)
+	}
+	p.TestSessionID = nullInt32ToInt32Ptr(testSessionID)
+	if err := json.Unmarshal(events, &p.Events); err != nil {
+		return nil, fmt.Errorf("failed to decode training plan events: %w", err)
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, plan_id, scheduled_date, week, event, focus, sets, target_value
+		FROM training_sessions
+		WHERE plan_id = $1
+		ORDER BY scheduled_date, id`, p.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get training sessions: %w", err)
+	}
+	defer rows.Close()
+
+	p.Sessions = []*store.TrainingSession{}
+	for rows.Next() {
+		var sess store.TrainingSession
+		var target sql.NullFloat64
+		if err := rows.Scan(&sess.ID, &sess.PlanID, &sess.ScheduledDate, &sess.Week, &sess.Event, &sess.Focus, &sess.Sets, &target); err != nil {
+			return nil, fmt.Errorf("failed to scan training session: %w", err)
+		}
+		if target.Valid {
+			sess.TargetValue = &target.Float64
+		}
+		p.Sessions = append(p.Sessions, &sess)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate training sessions: %w", err)
+	}
+	return &p, nil
+}
@@ -32,7 +32,7 @@ INSERT INTO users (
   last_name
 )
 VALUES ($1, $2, $3, $4, $5)
-RETURNING id, username, email, password_hash, first_name, last_name, location, latitude, longitude, last_location, gender, date_of_birth, tokens_invalidated_at, last_synced_at, created_at, updated_at
+RETURNING id, username, email, password_hash, first_name, last_name, location, latitude, longitude, last_location, gender, date_of_birth, timezone, tokens_invalidated_at, last_synced_at, created_at, updated_at
 `
 
 type CreateUserParams struct {
@@ -65,6 +65,7 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.LastLocation,
 		&i.Gender,
 		&i.DateOfBirth,
+		&i.Timezone,
 		&i.TokensInvalidatedAt,
 		&i.LastSyncedAt,
 		&i.CreatedAt,
@@ -74,7 +75,7 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 }
 
 const getUser = `-- name: GetUser :one
-SELECT id, username, email, password_hash, first_name, last_name, location, latitude, longitude, last_location, gender, date_of_birth, tokens_invalidated_at, last_synced_at, created_at, updated_at FROM users
+SELECT id, username, email, password_hash, first_name, last_name, location, latitude, longitude, last_location, gender, date_of_birth, timezone, tokens_invalidated_at, last_synced_at, created_at, updated_at FROM users
 WHERE id = $1 LIMIT 1
 `
 
@@ -94,6 +95,7 @@ func (q *Queries) GetUser(ctx context.Context, id int32) (User, error) {
 		&i.LastLocation,
 		&i.Gender,
 		&i.DateOfBirth,
+		&i.Timezone,
 		&i.TokensInvalidatedAt,
 		&i.LastSyncedAt,
 		&i.CreatedAt,
@@ -103,7 +105,7 @@ func (q *Queries) GetUser(ctx context.Context, id int32) (User, error) {
 }
 
 const getUserByEmail = `-- name: GetUserByEmail :one
-SELECT id, username, email, password_hash, first_name, last_name, location, latitude, longitude, last_location, gender, date_of_birth, tokens_invalidated_at, last_synced_at, created_at, updated_at FROM users
+SELECT id, username, email, password_hash, first_name, last_name, location, latitude, longitude, last_location, gender, date_of_birth, timezone, tokens_invalidated_at, last_synced_at, created_at, updated_at FROM users
 WHERE email = $1 LIMIT 1
 `
 
@@ -123,6 +125,7 @@ func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error
 		&i.LastLocation,
 		&i.Gender,
 		&i.DateOfBirth,
+		&i.Timezone,
 		&i.TokensInvalidatedAt,
 		&i.LastSyncedAt,
 		&i.CreatedAt,
@@ -132,7 +135,7 @@ func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error
 }
 
 const getUserByUsername = `-- name: GetUserByUsername :one
-SELECT id, username, email, password_hash, first_name, last_name, location, latitude, longitude, last_location, gender, date_of_birth, tokens_invalidated_at, last_synced_at, created_at, updated_at FROM users
+SELECT id, username, email, password_hash, first_name, last_name, location, latitude, longitude, last_location, gender, date_of_birth, timezone, tokens_invalidated_at, last_synced_at, created_at, updated_at FROM users
 WHERE username = $1 LIMIT 1
 `
 
@@ -152,6 +155,7 @@ func (q *Queries) GetUserByUsername(ctx context.Context, username string) (User,
 		&i.LastLocation,
 		&i.Gender,
 		&i.DateOfBirth,
+		&i.Timezone,
 		&i.TokensInvalidatedAt,
 		&i.LastSyncedAt,
 		&i.CreatedAt,
@@ -172,9 +176,10 @@ SET
   longitude = $8,
   gender = $9,
   date_of_birth = $10,
+  timezone = $11,
   updated_at = now()
 WHERE id = $1
-RETURNING id, username, email, password_hash, first_name, last_name, location, latitude, longitude, last_location, gender, date_of_birth, tokens_invalidated_at, last_synced_at, created_at, updated_at
+RETURNING id, username, email, password_hash, first_name, last_name, location, latitude, longitude, last_location, gender, date_of_birth, timezone, tokens_invalidated_at, last_synced_at, created_at, updated_at
 `
 
 type UpdateUserParams struct {
@@ -188,6 +193,7 @@ type UpdateUserParams struct {
 	Longitude   sql.NullString `json:"longitude"`
 	Gender      sql.NullString `json:"gender"`
 	DateOfBirth sql.NullTime   `json:"date_of_birth"`
+	Timezone    string         `json:"timezone"`
 }
 
 func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error) {
@@ -202,6 +208,7 @@ func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, e
 		arg.Longitude,
 		arg.Gender,
 		arg.DateOfBirth,
+		arg.Timezone,
 	)
 	var i User
 	err := row.Scan(
@@ -217,6 +224,7 @@ func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, e
 		&i.LastLocation,
 		&i.Gender,
 		&i.DateOfBirth,
+		&i.Timezone,
 		&i.TokensInvalidatedAt,
 		&i.LastSyncedAt,
 		&i.CreatedAt,
@@ -210,6 +210,7 @@ func TestUpdateUser(t *testing.T) {
 		Location:  sql.NullString{Valid: false},
 		Latitude:  sql.NullString{Valid: false},
 		Longitude: sql.NullString{Valid: false},
+		Timezone:  createdUser.Timezone,
 	}
 
 	updatedUser, err := queries.UpdateUser(ctx, updateParams)
@@ -88,7 +88,7 @@ SELECT
     w.completed_at
 FROM workouts w
 JOIN exercises e ON w.exercise_id = e.id
-WHERE w.user_id = $1
+WHERE w.user_id = $1 AND w.deleted_at IS NULL
 ORDER BY w.completed_at DESC
 LIMIT $2 OFFSET $3
 `
@@ -149,7 +149,7 @@ func (q *Queries) GetUserWorkouts(ctx context.Context, arg GetUserWorkoutsParams
 }
 
 const getUserWorkoutsCount = `-- name: GetUserWorkoutsCount :one
-SELECT COUNT(*) FROM workouts WHERE user_id = $1
+SELECT COUNT(*) FROM workouts WHERE user_id = $1 AND deleted_at IS NULL
 `
 
 func (q *Queries) GetUserWorkoutsCount(ctx context.Context, userID int32) (int64, error) {
@@ -160,7 +160,7 @@ func (q *Queries) GetUserWorkoutsCount(ctx context.Context, userID int32) (int64
 }
 
 const getWorkoutRecordByID = `-- name: GetWorkoutRecordByID :one
-SELECT id, user_id, exercise_id, exercise_type, repetitions, duration_seconds, form_score, grade, is_public, completed_at, created_at, device_id, metadata, notes FROM workouts WHERE id = $1 LIMIT 1
+SELECT id, user_id, exercise_id, exercise_type, repetitions, duration_seconds, form_score, grade, is_public, completed_at, created_at, device_id, metadata, notes FROM workouts WHERE id = $1 AND deleted_at IS NULL LIMIT 1
 `
 
 func (q *Queries) GetWorkoutRecordByID(ctx context.Context, id int32) (Workout, error) {
@@ -188,7 +188,7 @@ func (q *Queries) GetWorkoutRecordByID(ctx context.Context, id int32) (Workout,
 const updateWorkoutVisibility = `-- name: UpdateWorkoutVisibility :exec
 UPDATE workouts
 SET is_public = $1
-WHERE id = $2 AND user_id = $3
+WHERE id = $2 AND user_id = $3 AND deleted_at IS NULL
 `
 
 type UpdateWorkoutVisibilityParams struct {
@@ -0,0 +1,152 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"ptchampion/internal/store"
+)
+
+// UpdateWorkoutRecord implements store.WorkoutStore. The workout's editable columns are
+// overwritten from record and the changed fields are written to the audit log in the
+// same transaction.
+func (s *Store) UpdateWorkoutRecord(ctx context.Context, actorID int32, record *store.WorkoutRecord) (*store.WorkoutRecord, error) {
+	return s.updateWorkoutRecord(ctx, actorID, record, nil)
+}
+
+// DeleteWorkoutRecord implements store.WorkoutStore. The workout is soft-deleted so
+// sync clients pull a tombstone, and the deletion is written to the audit log.
+func (s *Store) DeleteWorkoutRecord(ctx context.Context, actorID int32, id int32) error {
+	return s.deleteWorkoutRecord(ctx, actorID, id, nil)
+}
+
+// updateWorkoutRecord edits and audits a workout and refreshes its feed events,
+// rebuilding the user's personal records for its old and new exercise types when
+// candidatesFor is set
+func (s *Store) updateWorkoutRecord(ctx context.Context, actorID int32, record *store.WorkoutRecord, candidatesFor store.PersonalRecordCandidatesFunc) (*store.WorkoutRecord, error) {
+	var updated *store.WorkoutRecord
+	err := s.ExecTx(ctx, func(q *Queries) error {
+		before, err := lockWorkoutRecord(ctx, q.DB(), record.ID)
+		if err != nil {
+			return err
+		}
+		if err := updateWorkoutRecordColumns(ctx, q.DB(), record.ID, record); err != nil {
+			return err
+		}
+		updated, err = auditWorkoutChange(ctx, q.DB(), actorID, store.WorkoutAuditActionUpdate, before)
+		if err != nil {
+			return err
+		}
+		if err := refreshWorkoutActivity(ctx, q.DB(), updated); err != nil || candidatesFor == nil {
+			return err
+		}
+		if updated.ExerciseType != before.ExerciseType {
+			if err := rebuildPersonalRecords(ctx, q.DB(), before.UserID, before.ExerciseType, candidatesFor); err != nil {
+				return err
+			}
+		}
+		return rebuildPersonalRecords(ctx, q.DB(), updated.UserID, updated.ExerciseType, candidatesFor)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// deleteWorkoutRecord soft-deletes and audits a workout, rebuilding the user's personal
+// records for its exercise type when candidatesFor is set
+func (s *Store) deleteWorkoutRecord(ctx context.Context, actorID int32, id int32, candidatesFor store.PersonalRecordCandidatesFunc) error {
+	return s.ExecTx(ctx, func(q *Queries) error {
+		before, err := lockWorkoutRecord(ctx, q.DB(), id)
+		if err != nil {
+			return err
+		}
+		if _, err := q.DB().ExecContext(ctx, `UPDATE workouts SET deleted_at = NOW() WHERE id = $1`, id); err != nil {
+			return fmt.Errorf("failed to delete workout: %w", err)
+		}
+		if _, err = auditWorkoutChange(ctx, q.DB(), actorID, store.WorkoutAuditActionDelete, before); err != nil || candidatesFor == nil {
+			return err
+		}
+		return rebuildPersonalRecords(ctx, q.DB(), before.UserID, before.ExerciseType, candidatesFor)
+	})
+}
+
+// GetWorkoutAuditLog implements store.WorkoutStore, returning a workout's audit entries oldest first
+func (s *Store) GetWorkoutAuditLog(ctx context.Context, workoutID int32) ([]*store.WorkoutAuditEntry, error) {
+	query := `
+		SELECT id, workout_id, actor_user_id, action, workout_version, changes, created_at
+		FROM workout_audit_log
+		WHERE workout_id = $1
+		ORDER BY id`
+
+	rows, err := s.db.QueryContext(ctx, query, workoutID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get workout audit log: %w", err)
+	}
+	defer rows.Close()
+
+	entries := make([]*store.WorkoutAuditEntry, 0)
+	for rows.Next() {
+		var entry store.WorkoutAuditEntry
+		var changes []byte
+		if err := rows.Scan(&entry.ID, &entry.WorkoutID, &entry.ActorUserID, &entry.Action,
+			&entry.Version, &changes, &entry.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan workout audit entry: %w", err)
+		}
+		if err := json.Unmarshal(changes, &entry.Changes); err != nil {
+			return nil, fmt.Errorf("failed to decode workout audit changes: %w", err)
+		}
+		entries = append(entries, &entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating workout audit rows: %w", err)
+	}
+	return entries, nil
+}
+
+// lockWorkoutRecord reads a live workout and locks its row for the rest of the
+// transaction. Test session events are reported with store.ErrWorkoutInTestSession.
+func lockWorkoutRecord(ctx context.Context, db DBTX, id int32) (*store.WorkoutRecord, error) {
+	records, err := queryWorkoutRecords(ctx, db, `WHERE w.id = $1 AND w.deleted_at IS NULL FOR UPDATE OF w`, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, store.ErrWorkoutRecordNotFound
+	}
+	if records[0].TestSessionID != nil {
+		return nil, store.ErrWorkoutInTestSession
+	}
+	return records[0], nil
+}
+
+// auditWorkoutChange reads a workout back after a change and records the fields that
+// differ from before in the audit log. It returns the workout as changed.
+func auditWorkoutChange(ctx context.Context, db DBTX, actorID int32, action string, before *store.WorkoutRecord) (*store.WorkoutRecord, error) {
+	records, err := queryWorkoutRecords(ctx, db, `WHERE w.id = $1`, before.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, store.ErrWorkoutRecordNotFound
+	}
+	after := records[0]
+
+	changes := store.WorkoutChanges(before, after)
+	if len(changes) == 0 {
+		return after, nil
+	}
+	data, err := json.Marshal(changes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode workout audit changes: %w", err)
+	}
+
+	query := `
+		INSERT INTO workout_audit_log (workout_id, actor_user_id, action, workout_version, changes)
+		VALUES ($1, $2, $3, $4, $5)`
+	if _, err := db.ExecContext(ctx, query, after.ID, actorID, action, after.Version, data); err != nil {
+		return nil, fmt.Errorf("failed to write workout audit entry: %w", err)
+	}
+	return after, nil
+}
@@ -0,0 +1,81 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+
+	"ptchampion/internal/store"
+)
+
+// setWorkoutMeasurements stores the weight and distance measurements of a workout
+func setWorkoutMeasurements(ctx context.Context, db DBTX, workoutID int32, weightLbs *int32, distanceMeters *float64) error {
+	query := `
+		UPDATE workouts
+		SET weight_lbs = $2,
+			distance_meters = $3
+		WHERE id = $1`
+
+	distance := sql.NullFloat64{}
+	if distanceMeters != nil {
+		distance = sql.NullFloat64{Float64: *distanceMeters, Valid: true}
+	}
+
+	_, err := db.ExecContext(ctx, query, workoutID, int32PtrToNullInt32(weightLbs), distance)
+	if err != nil {
+		return fmt.Errorf("failed to set workout measurements: %w", err)
+	}
+	return nil
+}
+
+// attachWorkoutMeasurements loads the weight and distance measurements for records read
+// through the generated queries, which predate those columns
+func attachWorkoutMeasurements(ctx context.Context, db DBTX, records []*store.WorkoutRecord) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	byID := make(map[int32]*store.WorkoutRecord, len(records))
+	ids := make([]int64, 0, len(records))
+	for _, rec := range records {
+		if rec == nil {
+			continue
+		}
+		byID[rec.ID] = rec
+		ids = append(ids, int64(rec.ID))
+	}
+
+	query := `
+		SELECT id, weight_lbs, distance_meters
+		FROM workouts
+		WHERE id = ANY($1)
+		AND (weight_lbs IS NOT NULL OR distance_meters IS NOT NULL)`
+
+	rows, err := db.QueryContext(ctx, query, pq.Array(ids))
+	if err != nil {
+		return fmt.Errorf("failed to get workout measurements: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int32
+		var weight sql.NullInt32
+		var distance sql.NullFloat64
+		if err := rows.Scan(&id, &weight, &distance); err != nil {
+			return fmt.Errorf("failed to scan workout measurements: %w", err)
+		}
+		rec := byID[id]
+		rec.WeightLbs = nullInt32ToInt32Ptr(weight)
+		if distance.Valid {
+			d := distance.Float64
+			rec.DistanceMeters = &d
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating workout measurements: %w", err)
+	}
+	return nil
+}
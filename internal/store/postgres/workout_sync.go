@@ -0,0 +1,229 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/lib/pq"
+
+	"ptchampion/internal/store"
+)
+
+// SyncWorkoutRecords implements store.WorkoutStore. The batch runs in one transaction:
+// each push is resolved against the stored record with store.ResolveWorkoutSync, the
+// records changed after the cursor are pulled and the user's last_synced_at is advanced.
+// Created workouts and the personal records they set are published to the activity feed.
+func (s *Store) SyncWorkoutRecords(ctx context.Context, batch *store.WorkoutSyncBatch) (*store.WorkoutSyncResult, error) {
+	result := &store.WorkoutSyncResult{
+		Outcomes: make([]*store.WorkoutSyncOutcome, 0, len(batch.Pushes)),
+	}
+
+	err := s.ExecTx(ctx, func(q *Queries) error {
+		db := q.DB()
+
+		// Serialize a user's syncs so a retried batch cannot race its original past the version checks
+		if _, err := db.ExecContext(ctx, `SELECT pg_advisory_xact_lock($1)`, int64(batch.UserID)); err != nil {
+			return fmt.Errorf("failed to lock user for sync: %w", err)
+		}
+		// NOW() is the transaction start time and becomes the client's next cursor
+		if err := db.QueryRowContext(ctx, `SELECT NOW()`).Scan(&result.SyncedAt); err != nil {
+			return fmt.Errorf("failed to read sync time: %w", err)
+		}
+
+		pushedIDs := make([]int64, 0, len(batch.Pushes))
+		for _, push := range batch.Pushes {
+			outcome, err := pushWorkoutRecord(ctx, q, batch, push)
+			if err != nil {
+				return err
+			}
+			result.Outcomes = append(result.Outcomes, outcome)
+			if outcome.Record != nil {
+				pushedIDs = append(pushedIDs, int64(outcome.Record.ID))
+			}
+		}
+
+		clauses := `WHERE w.user_id = $1 AND NOT (w.id = ANY($2))`
+		args := []interface{}{batch.UserID, pq.Array(pushedIDs)}
+		if batch.Since != nil {
+			clauses += ` AND w.updated_at > $3`
+			args = append(args, *batch.Since)
+		} else {
+			// A first sync has nothing to delete, so it gets no tombstones
+			clauses += ` AND w.deleted_at IS NULL`
+		}
+		changes, err := queryWorkoutRecords(ctx, db, clauses+` ORDER BY w.updated_at, w.id`, args...)
+		if err != nil {
+			return err
+		}
+		result.Changes = changes
+
+		_, err = db.ExecContext(ctx, `UPDATE users SET last_synced_at = $2 WHERE id = $1`, batch.UserID, result.SyncedAt)
+		if err != nil {
+			return fmt.Errorf("failed to update last synced time: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to sync workout records in DB: %w", err)
+	}
+	return result, nil
+}
+
+// pushWorkoutRecord resolves one push against the stored record and writes it if the
+// policy accepts it
+func pushWorkoutRecord(ctx context.Context, q *Queries, batch *store.WorkoutSyncBatch, push *store.WorkoutSyncPush) (*store.WorkoutSyncOutcome, error) {
+	existing, err := findSyncedWorkoutRecord(ctx, q.DB(), batch, push)
+	if err != nil {
+		return nil, err
+	}
+
+	outcome := &store.WorkoutSyncOutcome{ClientID: push.ClientID, Record: existing}
+	var apply bool
+	outcome.Status, apply, outcome.Reason = store.ResolveWorkoutSync(push, existing)
+	if !apply {
+		return outcome, nil
+	}
+
+	// Edits and deletes are audited like those made through the workouts API
+	switch {
+	case existing == nil:
+		created, err := insertWorkoutRecord(ctx, q, push.Record)
+		if err != nil {
+			return nil, err
+		}
+		_, err = q.DB().ExecContext(ctx, `UPDATE workouts SET client_id = $2, device_id = $3 WHERE id = $1`,
+			created.ID, push.ClientID, batch.DeviceID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to set workout client ID: %w", err)
+		}
+
+		// Read the record back for the columns set by the database
+		stored, err := queryWorkoutRecords(ctx, q.DB(), `WHERE w.id = $1`, created.ID)
+		if err != nil {
+			return nil, err
+		}
+		if len(stored) == 0 {
+			return nil, store.ErrWorkoutRecordNotFound
+		}
+		outcome.Record = stored[0]
+	case push.Deleted:
+		if _, err := q.DB().ExecContext(ctx, `UPDATE workouts SET deleted_at = NOW() WHERE id = $1`, existing.ID); err != nil {
+			return nil, fmt.Errorf("failed to delete workout: %w", err)
+		}
+		if outcome.Record, err = auditWorkoutChange(ctx, q.DB(), batch.UserID, store.WorkoutAuditActionDelete, existing); err != nil {
+			return nil, err
+		}
+	default:
+		if push.KeepVisibility {
+			push.Record.IsPublic, push.Record.Visibility = existing.IsPublic, existing.Visibility
+		}
+		if err := updateWorkoutRecordColumns(ctx, q.DB(), existing.ID, push.Record); err != nil {
+			return nil, err
+		}
+		if outcome.Record, err = auditWorkoutChange(ctx, q.DB(), batch.UserID, store.WorkoutAuditActionUpdate, existing); err != nil {
+			return nil, err
+		}
+		if err := refreshWorkoutActivity(ctx, q.DB(), outcome.Record); err != nil {
+			return nil, err
+		}
+	}
+	records, err := syncPersonalRecords(ctx, q.DB(), batch.PersonalRecordCandidates, existing, outcome.Record)
+	if err != nil {
+		return nil, err
+	}
+
+	// New workouts reach the feed like logged ones; edits refreshed their events above
+	if existing == nil {
+		if err := publishWorkoutActivity(ctx, q.DB(), outcome.Record, records); err != nil {
+			return nil, err
+		}
+	}
+	return outcome, nil
+}
+
+// syncPersonalRecords applies a pushed workout to the user's personal records. A new
+// workout is compared against the current bests like a logged one; edits and deletes
+// rebuild the records of the exercise types they touched. It returns the records a new
+// workout set.
+func syncPersonalRecords(ctx context.Context, db DBTX, candidatesFor store.PersonalRecordCandidatesFunc, existing, record *store.WorkoutRecord) ([]*store.PersonalRecord, error) {
+	if candidatesFor == nil {
+		return nil, nil
+	}
+	if existing == nil {
+		candidates := candidatesFor(record)
+		if len(candidates) == 0 {
+			return nil, nil
+		}
+		if _, err := db.ExecContext(ctx, `SELECT 1 FROM users WHERE id = $1 FOR UPDATE`, record.UserID); err != nil {
+			return nil, fmt.Errorf("failed to lock user: %w", err)
+		}
+		var records []*store.PersonalRecord
+		for _, candidate := range candidates {
+			pr, err := setPersonalRecord(ctx, db, record, candidate)
+			if err != nil {
+				return nil, err
+			}
+			if pr != nil {
+				records = append(records, pr)
+			}
+		}
+		return records, nil
+	}
+	if record.ExerciseType != existing.ExerciseType {
+		if err := rebuildPersonalRecords(ctx, db, existing.UserID, existing.ExerciseType, candidatesFor); err != nil {
+			return nil, err
+		}
+	}
+	return nil, rebuildPersonalRecords(ctx, db, record.UserID, record.ExerciseType, candidatesFor)
+}
+
+// findSyncedWorkoutRecord returns the stored record a push addresses, tombstones
+// included, or nil, and locks it against concurrent edits. Test session events are not
+// editable through sync.
+func findSyncedWorkoutRecord(ctx context.Context, db DBTX, batch *store.WorkoutSyncBatch, push *store.WorkoutSyncPush) (*store.WorkoutRecord, error) {
+	var records []*store.WorkoutRecord
+	var err error
+	if push.WorkoutID != 0 {
+		records, err = queryWorkoutRecords(ctx, db, `WHERE w.id = $1 AND w.user_id = $2 AND w.test_session_id IS NULL FOR UPDATE OF w`,
+			push.WorkoutID, batch.UserID)
+	} else {
+		records, err = queryWorkoutRecords(ctx, db, `WHERE w.user_id = $1 AND w.device_id = $2 AND w.client_id = $3 FOR UPDATE OF w`,
+			batch.UserID, batch.DeviceID, push.ClientID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+	return records[0], nil
+}
+
+// updateWorkoutRecordColumns overwrites a workout's editable columns with an edit; the
+// version and updated_at are advanced by the workouts trigger. A record without a
+// visibility keeps the stored one unless is_public changes.
+func updateWorkoutRecordColumns(ctx context.Context, db DBTX, workoutID int32, record *store.WorkoutRecord) error {
+	query := `
+		UPDATE workouts
+		SET exercise_id = $2,
+			exercise_type = $3,
+			repetitions = $4,
+			duration_seconds = $5,
+			form_score = $6,
+			grade = $7,
+			is_public = $8,
+			completed_at = $9,
+			visibility = COALESCE(NULLIF($10, ''), visibility)
+		WHERE id = $1`
+
+	_, err := db.ExecContext(ctx, query, workoutID, record.ExerciseID, record.ExerciseType,
+		int32PtrToNullInt32(record.Reps), int32PtrToNullInt32(record.DurationSeconds),
+		int32PtrToNullInt32(record.FormScore), record.Grade, record.IsPublic, record.CompletedAt, record.Visibility)
+	if err != nil {
+		return fmt.Errorf("failed to update workout: %w", err)
+	}
+	if err := setWorkoutMeasurements(ctx, db, workoutID, record.WeightLbs, record.DistanceMeters); err != nil {
+		return err
+	}
+	return setWorkoutVerification(ctx, db, workoutID, record)
+}
@@ -0,0 +1,214 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"ptchampion/internal/store"
+)
+
+// setWorkoutVerification records the outcome of server-side grade verification for a workout,
+// along with the scoring standard used to compute the expected grade
+func setWorkoutVerification(ctx context.Context, db DBTX, workoutID int32, record *store.WorkoutRecord) error {
+	query := `
+		UPDATE workouts
+		SET verification_status = $2,
+			expected_grade = $3,
+			grade_discrepancy = $4,
+			scoring_standard = $5,
+			scoring_version = $6
+		WHERE id = $1`
+
+	_, err := db.ExecContext(ctx, query, workoutID, record.VerificationStatus,
+		int32PtrToNullInt32(record.ExpectedGrade), int32PtrToNullInt32(record.GradeDiscrepancy),
+		sql.NullString{String: record.ScoringStandard, Valid: record.ScoringStandard != ""},
+		sql.NullString{String: record.ScoringVersion, Valid: record.ScoringVersion != ""})
+	if err != nil {
+		return fmt.Errorf("failed to set workout verification: %w", err)
+	}
+	return nil
+}
+
+// workoutRecordColumns selects the workout fields read by scanWorkoutRecordRows
+// from workouts w joined with exercises e
+const workoutRecordColumns = `
+			w.id,
+			w.user_id,
+			w.exercise_id,
+			e.name AS exercise_name,
+			e.type AS exercise_type,
+			w.repetitions,
+			w.duration_seconds,
+			w.form_score,
+			w.grade,
+			w.is_public,
+			w.visibility,
+			w.completed_at,
+			w.created_at,
+			w.verification_status,
+			w.expected_grade,
+			w.grade_discrepancy,
+			w.scoring_standard,
+			w.scoring_version,
+			w.client_id,
+			w.device_id,
+			w.updated_at,
+			w.version,
+			w.deleted_at,
+			w.test_session_id`
+
+// queryWorkoutRecords selects workout records with their measurements; clauses holds the
+// WHERE and ORDER BY clauses over workouts w and exercises e
+func queryWorkoutRecords(ctx context.Context, db DBTX, clauses string, args ...interface{}) ([]*store.WorkoutRecord, error) {
+	query := `
+		SELECT ` + workoutRecordColumns + `
+		FROM workouts w
+		JOIN exercises e ON w.exercise_id = e.id
+		` + clauses
+
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query workout records: %w", err)
+	}
+	defer rows.Close()
+
+	records, err := scanWorkoutRecordRows(rows)
+	if err != nil {
+		return nil, err
+	}
+	if err := attachWorkoutMeasurements(ctx, db, records); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// scanWorkoutRecordRows reads rows selected with workoutRecordColumns
+func scanWorkoutRecordRows(rows *sql.Rows) ([]*store.WorkoutRecord, error) {
+	records := make([]*store.WorkoutRecord, 0)
+	for rows.Next() {
+		rec, err := scanWorkoutRecordRow(rows)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, rec)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating workout rows: %w", err)
+	}
+	return records, nil
+}
+
+// scanWorkoutRecordRow reads the current row, whose workoutRecordColumns may be
+// preceded by extra columns scanned into leading
+func scanWorkoutRecordRow(rows *sql.Rows, leading ...interface{}) (*store.WorkoutRecord, error) {
+	var rec store.WorkoutRecord
+	var reps, duration, formScore, expected, discrepancy, testSessionID sql.NullInt32
+	var standard, version, clientID, deviceID sql.NullString
+	var deletedAt sql.NullTime
+	dest := append(leading,
+		&rec.ID,
+		&rec.UserID,
+		&rec.ExerciseID,
+		&rec.ExerciseName,
+		&rec.ExerciseType,
+		&reps,
+		&duration,
+		&formScore,
+		&rec.Grade,
+		&rec.IsPublic,
+		&rec.Visibility,
+		&rec.CompletedAt,
+		&rec.CreatedAt,
+		&rec.VerificationStatus,
+		&expected,
+		&discrepancy,
+		&standard,
+		&version,
+		&clientID,
+		&deviceID,
+		&rec.UpdatedAt,
+		&rec.Version,
+		&deletedAt,
+		&testSessionID,
+	)
+	if err := rows.Scan(dest...); err != nil {
+		return nil, fmt.Errorf("failed to scan workout row: %w", err)
+	}
+	rec.Reps = nullInt32ToInt32Ptr(reps)
+	rec.DurationSeconds = nullInt32ToInt32Ptr(duration)
+	rec.FormScore = nullInt32ToInt32Ptr(formScore)
+	rec.ExpectedGrade = nullInt32ToInt32Ptr(expected)
+	rec.GradeDiscrepancy = nullInt32ToInt32Ptr(discrepancy)
+	rec.ScoringStandard = standard.String
+	rec.ScoringVersion = version.String
+	rec.ClientID = clientID.String
+	rec.DeviceID = deviceID.String
+	rec.TestSessionID = nullInt32ToInt32Ptr(testSessionID)
+	if deletedAt.Valid {
+		rec.DeletedAt = &deletedAt.Time
+	}
+	return &rec, nil
+}
+
+// ListMismatchedWorkoutRecords implements store.WorkoutStore, returning workouts whose
+// client-submitted grade disagreed with the server-computed grade, newest first
+func (s *Store) ListMismatchedWorkoutRecords(ctx context.Context, limit int32, offset int32) (*store.PaginatedWorkoutRecords, error) {
+	var count int64
+	err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM workouts WHERE verification_status = $1 AND deleted_at IS NULL`, store.VerificationStatusMismatched).Scan(&count)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get mismatched workout count: %w", err)
+	}
+
+	if count == 0 {
+		return &store.PaginatedWorkoutRecords{
+			Records:    []*store.WorkoutRecord{},
+			TotalCount: 0,
+		}, nil
+	}
+
+	records, err := queryWorkoutRecords(ctx, s.db, `
+		WHERE w.verification_status = $1 AND w.deleted_at IS NULL
+		ORDER BY w.created_at DESC
+		LIMIT $2 OFFSET $3`, store.VerificationStatusMismatched, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get mismatched workout records: %w", err)
+	}
+
+	return &store.PaginatedWorkoutRecords{
+		Records:    records,
+		TotalCount: count,
+	}, nil
+}
+
+// SetWorkoutReplayResult implements store.WorkoutStore, storing the rep count and form score
+// obtained by replaying a workout's uploaded pose frames
+func (s *Store) SetWorkoutReplayResult(ctx context.Context, workoutID int32, reps int32, formScore int32) (*store.WorkoutRecord, error) {
+	query := `
+		UPDATE workouts
+		SET replay_reps = $2,
+			replay_form_score = $3,
+			replayed_at = NOW()
+		WHERE id = $1
+		RETURNING replayed_at`
+
+	var replayedAt time.Time
+	err := s.db.QueryRowContext(ctx, query, workoutID, reps, formScore).Scan(&replayedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, store.ErrWorkoutRecordNotFound
+		}
+		return nil, fmt.Errorf("failed to set workout replay result: %w", err)
+	}
+
+	record, err := s.GetWorkoutRecordByID(ctx, workoutID)
+	if err != nil {
+		return nil, err
+	}
+	record.ReplayReps = &reps
+	record.ReplayFormScore = &formScore
+	record.ReplayedAt = &replayedAt
+	return record, nil
+}
@@ -0,0 +1,64 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/lib/pq"
+
+	"ptchampion/internal/store"
+)
+
+// visibilityFromPublic returns the visibility the workouts trigger derives from is_public
+func visibilityFromPublic(isPublic bool) string {
+	if isPublic {
+		return store.WorkoutVisibilityPublic
+	}
+	return store.WorkoutVisibilityPrivate
+}
+
+// setWorkoutVisibility stores a workout's visibility; the workouts trigger updates is_public to match
+func setWorkoutVisibility(ctx context.Context, db DBTX, workoutID int32, visibility string) error {
+	if _, err := db.ExecContext(ctx, `UPDATE workouts SET visibility = $2 WHERE id = $1`, workoutID, visibility); err != nil {
+		return fmt.Errorf("failed to set workout visibility: %w", err)
+	}
+	return nil
+}
+
+// attachWorkoutVisibility marks the friends-only workouts among records read through the
+// generated queries, which predate the visibility column and map is_public to public or private
+func attachWorkoutVisibility(ctx context.Context, db DBTX, records []*store.WorkoutRecord) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	byID := make(map[int32]*store.WorkoutRecord, len(records))
+	ids := make([]int64, 0, len(records))
+	for _, rec := range records {
+		if rec == nil {
+			continue
+		}
+		byID[rec.ID] = rec
+		ids = append(ids, int64(rec.ID))
+	}
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT id FROM workouts
+		WHERE id = ANY($1) AND visibility = 'friends'`, pq.Array(ids))
+	if err != nil {
+		return fmt.Errorf("failed to get workout visibility: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int32
+		if err := rows.Scan(&id); err != nil {
+			return fmt.Errorf("failed to scan workout visibility: %w", err)
+		}
+		byID[id].Visibility = store.WorkoutVisibilityFriends
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating workout visibility: %w", err)
+	}
+	return nil
+}
@@ -38,7 +38,8 @@ func (c *LeaderboardCache) WithTTL(ttl time.Duration) *LeaderboardCache {
 // lat and lon are the coordinates for the center point
 // radius is the search radius in meters
 // exerciseType filters by type of exercise
-// timeFrame is the time period (daily, weekly, monthly, all_time)
+// timeFrame is the time frame's cache key (leaderboards.TimeFrame.CacheKey), which covers
+// its timezone, week start and dates
 func LocalLeaderboardKey(lat, lon float64, radius float64, exerciseType string, limit int, timeFrame string) string {
 	if timeFrame == "" {
 		timeFrame = "all_time" // Default if not provided
@@ -47,7 +48,8 @@ func LocalLeaderboardKey(lat, lon float64, radius float64, exerciseType string,
 }
 
 // GlobalLeaderboardKey generates a cache key for a global leaderboard
-// timeFrame is the time period (daily, weekly, monthly, all_time)
+// timeFrame is the time frame's cache key (leaderboards.TimeFrame.CacheKey), which covers
+// its timezone, week start and dates
 func GlobalLeaderboardKey(exerciseType string, limit int, timeFrame string) string {
 	if timeFrame == "" {
 		timeFrame = "all_time" // Default if not provided
@@ -0,0 +1,76 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrFriendshipNotFound is returned when two users have no relationship, or not the one required.
+var ErrFriendshipNotFound = errors.New("friendship not found")
+
+// Friendship statuses
+const (
+	FriendshipStatusPending  = "pending"  // RequesterID asked AddresseeID to be friends
+	FriendshipStatusAccepted = "accepted" // The users are friends
+	FriendshipStatusBlocked  = "blocked"  // RequesterID blocked AddresseeID
+)
+
+// Friendship directions, from one user's point of view
+const (
+	FriendshipDirectionIncoming = "incoming" // The user is the addressee
+	FriendshipDirectionOutgoing = "outgoing" // The user is the requester
+)
+
+// Friendship is the relationship between two users. A pair of users has at most one,
+// whichever of them started it.
+type Friendship struct {
+	ID          int32
+	RequesterID int32 // Sent the request, or blocked the addressee
+	AddresseeID int32
+	Status      string // One of the FriendshipStatus* constants
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// OtherUserID returns the user on the other side of the friendship from userID.
+func (f *Friendship) OtherUserID(userID int32) int32 {
+	if f.RequesterID == userID {
+		return f.AddresseeID
+	}
+	return f.RequesterID
+}
+
+// Friend is a friendship from one user's point of view, with the other user's details.
+type Friend struct {
+	Friendship *Friendship
+	UserID     int32 // The other user
+	Username   string
+	FirstName  *string
+	LastName   *string
+}
+
+// PaginatedFriends holds a page of friends and total count.
+type PaginatedFriends struct {
+	Friends    []*Friend
+	TotalCount int64
+}
+
+// FriendshipFilters selects a user's friendships by status and, for requests and
+// blocks, by which side the user is on.
+type FriendshipFilters struct {
+	Status    string // One of the FriendshipStatus* constants
+	Direction string // One of the FriendshipDirection* constants; empty for both
+}
+
+// SocialStore defines methods for friendship data access
+type SocialStore interface {
+	// GetFriendship returns the relationship between two users, whichever started it
+	GetFriendship(ctx context.Context, userID int32, otherUserID int32) (*Friendship, error)
+	CreateFriendship(ctx context.Context, friendship *Friendship) (*Friendship, error)
+	// UpdateFriendship rewrites a relationship, e.g. to record who blocked whom
+	UpdateFriendship(ctx context.Context, friendship *Friendship) (*Friendship, error)
+	DeleteFriendship(ctx context.Context, id int32) error
+	// ListFriendships lists the user's relationships matching the filters, most recently changed first
+	ListFriendships(ctx context.Context, userID int32, filters FriendshipFilters, limit int32, offset int32) (*PaginatedFriends, error)
+}
@@ -3,6 +3,7 @@ package store
 import (
 	"context"
 	"errors"
+	"fmt"
 	"time"
 )
 
@@ -21,6 +22,13 @@ var ErrLeaderboardUnavailable = errors.New("leaderboard unavailable")
 // ErrWorkoutRecordNotFound is returned when a workout record is not found.
 var ErrWorkoutRecordNotFound = errors.New("workout record not found")
 
+// ErrTestSessionNotFound is returned when a test session is not found.
+var ErrTestSessionNotFound = errors.New("test session not found")
+
+// ErrWorkoutInTestSession is returned when editing or deleting a workout that is an event
+// of a test session, whose score depends on it.
+var ErrWorkoutInTestSession = errors.New("workout is part of a test session")
+
 // ErrEmailTaken is returned when an email address is already in use by another user.
 var ErrEmailTaken = errors.New("email address is already in use")
 
@@ -68,6 +76,24 @@ type LeaderboardEntry struct {
 	// Potentially add LastSubmittedAt time.Time if relevant
 }
 
+// UnitMemberScore is one member's score in a unit-vs-unit leaderboard. Members without
+// a qualifying result in the time frame have a nil Score.
+type UnitMemberScore struct {
+	OrganizationID int32 // The competing unit; the member may belong to one of its subunits
+	UserID         int32
+	Score          *int32
+}
+
+// UnitLeaderboardEntry ranks one unit against its sibling units.
+type UnitLeaderboardEntry struct {
+	Organization *Organization
+	Score        float64 // Average or median of the participants' scores
+	Participants int     // Members with a qualifying result
+	Members      int
+	Qualified    bool  // Met the minimum participation; unqualified units are listed unranked
+	Rank         int32 // 0 for unqualified units
+}
+
 // WorkoutRecord defines the structure for a logged workout instance in the domain.
 // This is based on the existing db.Workout table, which seems to represent a single exercise performance.
 type WorkoutRecord struct {
@@ -80,23 +106,238 @@ type WorkoutRecord struct {
 	DurationSeconds *int32 // Nullable
 	FormScore       *int32 // Nullable
 	Grade           int32
-	IsPublic        bool // For leaderboard visibility
+	IsPublic        bool   // For leaderboard visibility; set exactly when Visibility is public
+	Visibility      string // One of the WorkoutVisibility* constants; empty on write derives it from IsPublic
 	CompletedAt     time.Time
 	CreatedAt       time.Time
+
+	// Measurements for weight- and distance-scored events (ACFT deadlift, power throw)
+	WeightLbs      *int32   // Nullable
+	DistanceMeters *float64 // Nullable
+
+	// Server-side grade verification
+	VerificationStatus string // One of the VerificationStatus* constants
+	ExpectedGrade      *int32 // Grade recomputed by the server, nil if not verified
+	GradeDiscrepancy   *int32 // Client grade minus expected grade, nil if not verified
+	ScoringStandard    string // ID of the scoring standard that graded the workout (e.g. "apft-2013")
+	ScoringVersion     string // Version of that standard
+
+	// Server-side replay of uploaded pose frames
+	ReplayReps      *int32     // Reps counted by replaying the frames, nil if never uploaded
+	ReplayFormScore *int32     // Mean form score from the replay (0-100)
+	ReplayedAt      *time.Time // When the frames were replayed
+
+	TestSessionID *int32 // Test session the workout is an event of, nil for standalone workouts
+
+	// Offline sync
+	ClientID  string     // Client-generated ID of a record pushed through sync
+	DeviceID  string     // Device that pushed the record
+	UpdatedAt time.Time  // Last change to the record; sync pulls changes after a cursor
+	Version   int32      // Incremented by every change; sync pushes name the version they edit
+	DeletedAt *time.Time // Set on tombstones, which only sync pulls return
 }
 
+// Workout visibility levels
+const (
+	WorkoutVisibilityPrivate = "private" // The owner only
+	WorkoutVisibilityFriends = "friends" // The owner and their friends, including friends-only leaderboards
+	WorkoutVisibilityPublic  = "public"  // Everyone, including global and local leaderboards
+)
+
+// Workout verification statuses
+const (
+	VerificationStatusUnverified = "unverified" // Logged before server-side verification existed
+	VerificationStatusVerified   = "verified"   // Client grade matches the server-computed grade
+	VerificationStatusMismatched = "mismatched" // Client grade disagrees; excluded from leaderboards
+)
+
 // PaginatedWorkoutRecords holds a page of workout records and total count.
 type PaginatedWorkoutRecords struct {
 	Records    []*WorkoutRecord
 	TotalCount int64
 }
 
+// Workout audit actions
+const (
+	WorkoutAuditActionUpdate = "update"
+	WorkoutAuditActionDelete = "delete"
+)
+
+// WorkoutAuditEntry is an immutable record of one change to a workout.
+type WorkoutAuditEntry struct {
+	ID          int32
+	WorkoutID   int32
+	ActorUserID int32  // User who made the change
+	Action      string // One of the WorkoutAuditAction* constants
+	Version     int32  // Workout version after the change
+	Changes     map[string]WorkoutFieldChange
+	CreatedAt   time.Time
+}
+
+// WorkoutFieldChange is the value of one workout field before and after a change.
+type WorkoutFieldChange struct {
+	Old interface{} `json:"old"`
+	New interface{} `json:"new"`
+}
+
+// WorkoutChanges returns the fields that differ between two versions of a workout,
+// keyed by column name. Timestamps are compared as instants.
+func WorkoutChanges(before, after *WorkoutRecord) map[string]WorkoutFieldChange {
+	changes := make(map[string]WorkoutFieldChange)
+	add := func(field string, old, new interface{}) {
+		if old != new {
+			changes[field] = WorkoutFieldChange{Old: old, New: new}
+		}
+	}
+
+	add("exercise_id", before.ExerciseID, after.ExerciseID)
+	add("repetitions", optionalInt32(before.Reps), optionalInt32(after.Reps))
+	add("duration_seconds", optionalInt32(before.DurationSeconds), optionalInt32(after.DurationSeconds))
+	add("weight_lbs", optionalInt32(before.WeightLbs), optionalInt32(after.WeightLbs))
+	add("distance_meters", optionalFloat64(before.DistanceMeters), optionalFloat64(after.DistanceMeters))
+	add("form_score", optionalInt32(before.FormScore), optionalInt32(after.FormScore))
+	add("grade", before.Grade, after.Grade)
+	add("is_public", before.IsPublic, after.IsPublic)
+	add("visibility", before.Visibility, after.Visibility)
+	add("scoring_standard", before.ScoringStandard, after.ScoringStandard)
+	if !before.CompletedAt.Equal(after.CompletedAt) {
+		changes["completed_at"] = WorkoutFieldChange{Old: before.CompletedAt.UTC(), New: after.CompletedAt.UTC()}
+	}
+	if (before.DeletedAt == nil) != (after.DeletedAt == nil) {
+		changes["deleted_at"] = WorkoutFieldChange{Old: optionalTime(before.DeletedAt), New: optionalTime(after.DeletedAt)}
+	}
+	return changes
+}
+
+func optionalInt32(v *int32) interface{} {
+	if v == nil {
+		return nil
+	}
+	return *v
+}
+
+func optionalFloat64(v *float64) interface{} {
+	if v == nil {
+		return nil
+	}
+	return *v
+}
+
+func optionalTime(v *time.Time) interface{} {
+	if v == nil {
+		return nil
+	}
+	return v.UTC()
+}
+
+// Per-record outcomes of a sync push
+const (
+	SyncStatusCreated  = "created"  // Record created, or a create the device had already pushed
+	SyncStatusUpdated  = "updated"  // Edit or delete applied to the current version
+	SyncStatusConflict = "conflict" // Pushed against a stale version or a deleted record; the server copy wins
+	SyncStatusRejected = "rejected" // Record invalid or not found; nothing was stored
+)
+
+// WorkoutSyncPush is one record an offline client pushes: a create, an edit or a delete.
+type WorkoutSyncPush struct {
+	WorkoutID      int32          // Server ID of a pulled record; 0 addresses the record by the device's client ID
+	ClientID       string         // Client-generated ID, echoed in the outcome
+	BaseVersion    int32          // Server version the client edited; 0 for a record created on the client
+	Deleted        bool           // Push a tombstone
+	KeepVisibility bool           // An edit keeps the stored visibility instead of the record's
+	Record         *WorkoutRecord // Graded record to store; nil for deletes
+}
+
+// WorkoutSyncOutcome reports what happened to one pushed record.
+type WorkoutSyncOutcome struct {
+	ClientID string
+	Status   string         // One of the SyncStatus* constants
+	Record   *WorkoutRecord // Server copy after the push; nil when rejected
+	Reason   string         // Why the push conflicted or was rejected
+}
+
+// ResolveWorkoutSync decides the outcome of a push against the server's copy of the
+// record, nil if there is none, and whether the push should be written. The policy is
+// optimistic concurrency with the server winning conflicts: edits and deletes apply
+// only to the version the client last saw, a deleted record stays deleted, and a
+// create the device already pushed returns the stored record. Grades are never taken
+// from the client, so they are not part of the comparison.
+func ResolveWorkoutSync(push *WorkoutSyncPush, existing *WorkoutRecord) (status string, apply bool, reason string) {
+	if existing == nil {
+		if push.WorkoutID != 0 || push.BaseVersion != 0 || push.Deleted {
+			return SyncStatusRejected, false, "workout not found"
+		}
+		return SyncStatusCreated, true, ""
+	}
+	if existing.DeletedAt != nil {
+		if push.Deleted {
+			return SyncStatusUpdated, false, "" // Retried delete
+		}
+		return SyncStatusConflict, false, "workout was deleted"
+	}
+	if push.WorkoutID == 0 && push.BaseVersion == 0 && !push.Deleted {
+		return SyncStatusCreated, false, "" // Retried create
+	}
+	if push.BaseVersion != existing.Version {
+		return SyncStatusConflict, false, fmt.Sprintf("workout is at version %d, push was based on version %d", existing.Version, push.BaseVersion)
+	}
+	return SyncStatusUpdated, true, ""
+}
+
+// WorkoutSyncBatch is one sync exchange with an offline client: the records it pushes
+// and the cursor it pulls changes after.
+type WorkoutSyncBatch struct {
+	UserID   int32
+	DeviceID string
+	Since    *time.Time         // Pull changes after this time; nil pulls every live record
+	Pushes   []*WorkoutSyncPush // Valid pushes; records the service rejected are not sent to the store
+	// PersonalRecordCandidates evaluates created workouts for personal records and rebuilds
+	// them after edits and deletes; nil leaves personal records untouched
+	PersonalRecordCandidates PersonalRecordCandidatesFunc
+}
+
+// WorkoutSyncResult is the outcome of a sync exchange.
+type WorkoutSyncResult struct {
+	Outcomes []*WorkoutSyncOutcome // One per pushed record, in push order
+	Changes  []*WorkoutRecord      // Records changed after the cursor, including tombstones, excluding those pushed
+	SyncedAt time.Time             // Cursor for the client's next sync
+}
+
+// TestSession is one full PT test: every event of a scoring standard taken in
+// order on one day. Each event is stored as a workout record.
+type TestSession struct {
+	ID              int32
+	UserID          int32
+	ScoringStandard string
+	ScoringVersion  string
+	TestDate        time.Time // Calendar date the test was taken, in the athlete's local time
+	StartedAt       time.Time // Start of the first event
+	CompletedAt     time.Time // End of the last event
+	TotalScore      int32
+	Passed          bool // Every event met the standard's passing score
+	IsPublic        bool
+	CreatedAt       time.Time
+	Events          []*WorkoutRecord // In the order they were taken
+}
+
+// PaginatedTestSessions holds a page of test sessions and total count.
+type PaginatedTestSessions struct {
+	Sessions   []*TestSession
+	TotalCount int64
+}
+
 // Store defines the interface for data access operations
 type Store interface {
 	UserStore
 	ExerciseStore
 	LeaderboardStore
 	WorkoutStore // Add WorkoutStore
+	OrganizationStore
+	ChallengeStore
+	PersonalRecordStore
+	AnalyticsStore
+	SocialStore
+	FeedStore
 	// Add other store interfaces as needed
 
 	Ping(ctx context.Context) error // For health checks
@@ -124,15 +365,28 @@ type ExerciseStore interface {
 
 // LeaderboardStore defines methods for leaderboard data access
 type LeaderboardStore interface {
-	GetGlobalLeaderboard(ctx context.Context, exerciseType string,
+	// Exercise leaderboards rank the grades of one scoring standard, as grades under
+	// different standards are not comparable
+	GetGlobalLeaderboard(ctx context.Context, exerciseType string, scoringStandard string,
 		startDate, endDate *time.Time, limit, offset int) ([]*LeaderboardEntry, error)
-	GetLocalLeaderboard(ctx context.Context, exerciseType string, lat, lng, radiusMeters float64,
+	GetLocalLeaderboard(ctx context.Context, exerciseType string, scoringStandard string, lat, lng, radiusMeters float64,
 		startDate, endDate *time.Time, limit, offset int) ([]*LeaderboardEntry, error)
 	// Keep existing methods for backward compatibility
-	GetGlobalExerciseLeaderboard(ctx context.Context, exerciseType string, limit int, startDate time.Time, endDate time.Time) ([]*LeaderboardEntry, error)
+	GetGlobalExerciseLeaderboard(ctx context.Context, exerciseType string, scoringStandard string, limit int, startDate time.Time, endDate time.Time) ([]*LeaderboardEntry, error)
 	GetGlobalAggregateLeaderboard(ctx context.Context, limit int, startDate time.Time, endDate time.Time) ([]*LeaderboardEntry, error)
-	GetLocalExerciseLeaderboard(ctx context.Context, exerciseType string, latitude, longitude float64, radiusMeters int, limit int, startDate time.Time, endDate time.Time) ([]*LeaderboardEntry, error)
+	GetLocalExerciseLeaderboard(ctx context.Context, exerciseType string, scoringStandard string, latitude, longitude float64, radiusMeters int, limit int, startDate time.Time, endDate time.Time) ([]*LeaderboardEntry, error)
 	GetLocalAggregateLeaderboard(ctx context.Context, latitude, longitude float64, radiusMeters int, limit int, startDate time.Time, endDate time.Time) ([]*LeaderboardEntry, error)
+	// Aggregates over an explicit set of exercise types scored by one standard, e.g. the six ACFT events
+	GetGlobalAggregateLeaderboardByTypes(ctx context.Context, exerciseTypes []string, scoringStandard string, limit int, startDate time.Time, endDate time.Time) ([]*LeaderboardEntry, error)
+	GetLocalAggregateLeaderboardByTypes(ctx context.Context, exerciseTypes []string, scoringStandard string, latitude, longitude float64, radiusMeters int, limit int, startDate time.Time, endDate time.Time) ([]*LeaderboardEntry, error)
+	// Limited to the members of an organization and its subunits; one type gives an exercise leaderboard
+	GetOrganizationAggregateLeaderboardByTypes(ctx context.Context, orgID int32, exerciseTypes []string, scoringStandard string, limit int, startDate time.Time, endDate time.Time) ([]*LeaderboardEntry, error)
+	// GetSubunitMemberScores scores every member of each direct subunit of the organization,
+	// summing their best grade under the standard across the exercise types; members missing
+	// any type have no score
+	GetSubunitMemberScores(ctx context.Context, orgID int32, exerciseTypes []string, scoringStandard string, startDate time.Time, endDate time.Time) ([]*UnitMemberScore, error)
+	// Limited to the user and their friends, counting workouts visible to friends
+	GetFriendsAggregateLeaderboardByTypes(ctx context.Context, userID int32, exerciseTypes []string, scoringStandard string, limit int, startDate time.Time, endDate time.Time) ([]*LeaderboardEntry, error)
 }
 
 // WorkoutStore defines methods for workout data access
@@ -140,11 +394,43 @@ type WorkoutStore interface {
 	CreateWorkoutRecord(ctx context.Context, record *WorkoutRecord) (*WorkoutRecord, error)
 	GetUserWorkoutRecords(ctx context.Context, userID int32, limit int32, offset int32) (*PaginatedWorkoutRecords, error)
 	GetUserWorkoutRecordsWithFilters(ctx context.Context, userID int32, limit int32, offset int32, filters WorkoutFilters) (*PaginatedWorkoutRecords, error)
-	UpdateWorkoutVisibility(ctx context.Context, userID int32, workoutID int32, isPublic bool) error
+	UpdateWorkoutVisibility(ctx context.Context, userID int32, workoutID int32, visibility string) error
 	GetWorkoutRecordByID(ctx context.Context, id int32) (*WorkoutRecord, error)
 	GetDashboardStats(ctx context.Context, userID int32) (*DashboardStats, error)
-	// UpdateWorkoutRecord(ctx context.Context, record *WorkoutRecord) (*WorkoutRecord, error) // Optional: if needed
-	// DeleteWorkoutRecord(ctx context.Context, id int32) error // Optional: if needed
+	ListMismatchedWorkoutRecords(ctx context.Context, limit int32, offset int32) (*PaginatedWorkoutRecords, error)
+	SetWorkoutReplayResult(ctx context.Context, workoutID int32, reps int32, formScore int32) (*WorkoutRecord, error)
+	// SyncWorkoutRecords applies a sync batch in one transaction, resolving each push with ResolveWorkoutSync.
+	// Creates are idempotent on (device, client ID).
+	SyncWorkoutRecords(ctx context.Context, batch *WorkoutSyncBatch) (*WorkoutSyncResult, error)
+	// Test sessions are stored as workouts grouped under a session row
+	CreateTestSession(ctx context.Context, session *TestSession) (*TestSession, error)
+	GetTestSessionByID(ctx context.Context, id int32) (*TestSession, error)
+	GetUserTestSessions(ctx context.Context, userID int32, limit int32, offset int32) (*PaginatedTestSessions, error)
+	// Edits and deletes record an audit entry for the acting user in the same transaction.
+	// Deletes leave a tombstone that sync clients pull.
+	UpdateWorkoutRecord(ctx context.Context, actorID int32, record *WorkoutRecord) (*WorkoutRecord, error)
+	DeleteWorkoutRecord(ctx context.Context, actorID int32, id int32) error
+	GetWorkoutAuditLog(ctx context.Context, workoutID int32) ([]*WorkoutAuditEntry, error)
+	// ListUserWorkoutRecordsSince returns the user's workouts of the exercise types completed
+	// since the given time, oldest first, for measuring progress toward goals
+	ListUserWorkoutRecordsSince(ctx context.Context, userID int32, exerciseTypes []string, since time.Time) ([]*WorkoutRecord, error)
+	CreateGoal(ctx context.Context, goal *Goal) (*Goal, error)
+	GetGoalByID(ctx context.Context, id int32) (*Goal, error)
+	ListUserGoals(ctx context.Context, userID int32) ([]*Goal, error)
+	DeleteGoal(ctx context.Context, id int32) error
+	// CreateTrainingPlan stores a plan with its sessions, superseding the user's active plan
+	CreateTrainingPlan(ctx context.Context, plan *TrainingPlan) (*TrainingPlan, error)
+	GetActiveTrainingPlan(ctx context.Context, userID int32) (*TrainingPlan, error)
+	// ListUserActiveDays returns the calendar dates, in the user's timezone, with a workout, oldest first
+	ListUserActiveDays(ctx context.Context, userID int32) ([]time.Time, error)
+	GetAchievementStats(ctx context.Context, userID int32) (*AchievementStats, error)
+	ListUserAchievements(ctx context.Context, userID int32) ([]*Achievement, error)
+	// AwardAchievements stores the badges the user has not been awarded yet and returns
+	// them. Badges earned by a logged workout are published to the activity feed with the
+	// workout's visibility; those awarded without one (workout nil) are not published.
+	AwardAchievements(ctx context.Context, userID int32, workout *WorkoutRecord, awards []AchievementAward) ([]*Achievement, error)
+	// ListUserIDsWithWorkouts returns, in ID order, up to limit users after afterID who have logged a workout
+	ListUserIDsWithWorkouts(ctx context.Context, afterID int32, limit int32) ([]int32, error)
 }
 
 // DashboardStats represents aggregated workout statistics for the dashboard
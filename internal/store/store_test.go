@@ -0,0 +1,183 @@
+package store
+
+import (
+	"testing"
+	"time"
+)
+
+func TestResolveWorkoutSync(t *testing.T) {
+	deletedAt := time.Date(2025, 7, 8, 12, 0, 0, 0, time.UTC)
+	live := &WorkoutRecord{ID: 7, ClientID: "c1", Version: 3}
+	tombstone := &WorkoutRecord{ID: 7, ClientID: "c1", Version: 4, DeletedAt: &deletedAt}
+
+	tests := []struct {
+		name       string
+		push       WorkoutSyncPush
+		existing   *WorkoutRecord
+		wantStatus string
+		wantApply  bool
+	}{
+		{
+			name:       "new record",
+			push:       WorkoutSyncPush{ClientID: "c1"},
+			wantStatus: SyncStatusCreated,
+			wantApply:  true,
+		},
+		{
+			name:       "retried create",
+			push:       WorkoutSyncPush{ClientID: "c1"},
+			existing:   live,
+			wantStatus: SyncStatusCreated,
+		},
+		{
+			name:       "edit of current version",
+			push:       WorkoutSyncPush{WorkoutID: 7, ClientID: "c1", BaseVersion: 3},
+			existing:   live,
+			wantStatus: SyncStatusUpdated,
+			wantApply:  true,
+		},
+		{
+			name:       "edit of stale version",
+			push:       WorkoutSyncPush{WorkoutID: 7, ClientID: "c1", BaseVersion: 2},
+			existing:   live,
+			wantStatus: SyncStatusConflict,
+		},
+		{
+			name:       "delete of current version",
+			push:       WorkoutSyncPush{WorkoutID: 7, ClientID: "c1", BaseVersion: 3, Deleted: true},
+			existing:   live,
+			wantStatus: SyncStatusUpdated,
+			wantApply:  true,
+		},
+		{
+			name:       "delete of stale version",
+			push:       WorkoutSyncPush{WorkoutID: 7, ClientID: "c1", BaseVersion: 1, Deleted: true},
+			existing:   live,
+			wantStatus: SyncStatusConflict,
+		},
+		{
+			name:       "edit of deleted record",
+			push:       WorkoutSyncPush{WorkoutID: 7, ClientID: "c1", BaseVersion: 4},
+			existing:   tombstone,
+			wantStatus: SyncStatusConflict,
+		},
+		{
+			name:       "retried create of deleted record",
+			push:       WorkoutSyncPush{ClientID: "c1"},
+			existing:   tombstone,
+			wantStatus: SyncStatusConflict,
+		},
+		{
+			name:       "retried delete",
+			push:       WorkoutSyncPush{WorkoutID: 7, ClientID: "c1", BaseVersion: 3, Deleted: true},
+			existing:   tombstone,
+			wantStatus: SyncStatusUpdated,
+		},
+		{
+			name:       "edit of unknown record",
+			push:       WorkoutSyncPush{WorkoutID: 9, ClientID: "c2", BaseVersion: 1},
+			wantStatus: SyncStatusRejected,
+		},
+		{
+			name:       "delete of unknown record",
+			push:       WorkoutSyncPush{ClientID: "c2", Deleted: true},
+			wantStatus: SyncStatusRejected,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, apply, reason := ResolveWorkoutSync(&tt.push, tt.existing)
+			if status != tt.wantStatus || apply != tt.wantApply {
+				t.Errorf("ResolveWorkoutSync = (%q, %v), want (%q, %v)", status, apply, tt.wantStatus, tt.wantApply)
+			}
+			needsReason := status == SyncStatusConflict || status == SyncStatusRejected
+			if needsReason != (reason != "") {
+				t.Errorf("reason = %q for status %q", reason, status)
+			}
+		})
+	}
+}
+
+func TestWorkoutChanges(t *testing.T) {
+	reps := int32(40)
+	fixedReps := int32(45)
+	completed := time.Date(2025, 7, 9, 6, 0, 0, 0, time.UTC)
+	deletedAt := completed.Add(time.Hour)
+
+	before := &WorkoutRecord{ID: 1, ExerciseID: 2, Reps: &reps, Grade: 60, IsPublic: true, CompletedAt: completed}
+	edited := *before
+	edited.Reps = &fixedReps
+	edited.Grade = 66
+	edited.CompletedAt = completed.In(time.FixedZone("EDT", -4*60*60)) // Same instant
+
+	changes := WorkoutChanges(before, &edited)
+	if len(changes) != 2 {
+		t.Fatalf("changes = %v, want repetitions and grade", changes)
+	}
+	if got := changes["repetitions"]; got.Old != int32(40) || got.New != int32(45) {
+		t.Errorf("repetitions change = %+v, want 40 -> 45", got)
+	}
+	if got := changes["grade"]; got.Old != int32(60) || got.New != int32(66) {
+		t.Errorf("grade change = %+v, want 60 -> 66", got)
+	}
+
+	deleted := *before
+	deleted.DeletedAt = &deletedAt
+	changes = WorkoutChanges(before, &deleted)
+	if got, ok := changes["deleted_at"]; !ok || got.Old != nil || got.New != deletedAt {
+		t.Errorf("deleted_at change = %+v, want nil -> %v", got, deletedAt)
+	}
+
+	if changes := WorkoutChanges(before, before); len(changes) != 0 {
+		t.Errorf("changes = %v for an unchanged workout", changes)
+	}
+}
+
+func TestReplayPersonalRecords(t *testing.T) {
+	completed := time.Date(2025, 7, 9, 6, 0, 0, 0, time.UTC)
+	workout := func(id int32, reps int32, seconds int32) *WorkoutRecord {
+		return &WorkoutRecord{ID: id, UserID: 3, ExerciseType: "pushup", Reps: &reps, DurationSeconds: &seconds,
+			CompletedAt: completed.Add(time.Duration(id) * time.Hour)}
+	}
+	candidatesFor := func(w *WorkoutRecord) []PersonalRecordCandidate {
+		return []PersonalRecordCandidate{
+			{Metric: "reps", Value: float64(*w.Reps)},
+			{Metric: "seconds", Value: float64(*w.DurationSeconds), LowerIsBetter: true},
+		}
+	}
+
+	records := ReplayPersonalRecords([]*WorkoutRecord{
+		workout(1, 40, 120),
+		workout(2, 38, 110),
+		workout(3, 45, 110), // Ties the time record
+	}, candidatesFor)
+
+	type setRecord struct {
+		metric    string
+		workoutID int32
+		value     float64
+		previous  float64 // 0 for the first record
+	}
+	want := []setRecord{
+		{"reps", 1, 40, 0},
+		{"seconds", 1, 120, 0},
+		{"seconds", 2, 110, 120},
+		{"reps", 3, 45, 40},
+	}
+	if len(records) != len(want) {
+		t.Fatalf("got %d records, want %d", len(records), len(want))
+	}
+	for i, pr := range records {
+		got := setRecord{metric: pr.Metric, workoutID: pr.WorkoutID, value: pr.Value}
+		if pr.PreviousValue != nil {
+			got.previous = *pr.PreviousValue
+		}
+		if got != want[i] {
+			t.Errorf("record %d = %+v, want %+v", i, got, want[i])
+		}
+		if pr.UserID != 3 || pr.ExerciseType != "pushup" || !pr.AchievedAt.Equal(completed.Add(time.Duration(pr.WorkoutID)*time.Hour)) {
+			t.Errorf("record %d = %+v, want it attributed to workout %d", i, pr, pr.WorkoutID)
+		}
+	}
+}
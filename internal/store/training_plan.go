@@ -0,0 +1,52 @@
+package store
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrTrainingPlanNotFound is returned when a user has no active training plan.
+var ErrTrainingPlanNotFound = errors.New("training plan not found")
+
+// Training focuses
+const (
+	TrainingFocusImprove  = "improve"  // Weak event: trained most often, with the steepest progression
+	TrainingFocusMaintain = "maintain" // Event near the maximum score: trained to hold it
+	TrainingFocusBaseline = "baseline" // Event without a recent result: a max-effort attempt to set one
+)
+
+// TrainingPlan is a multi-week schedule of sessions generated from a user's latest
+// grades in the events of a scoring standard. A user has at most one active plan;
+// generating a new one supersedes it.
+type TrainingPlan struct {
+	ID              int32
+	UserID          int32
+	ScoringStandard string
+	TestSessionID   *int32    // Latest test session when the plan was generated; a newer test regenerates the plan
+	StartDate       time.Time // Calendar date of the first day
+	Weeks           int
+	CreatedAt       time.Time
+	Events          []*TrainingPlanEvent // One per test slot, weakest first
+	Sessions        []*TrainingSession   // By date, then event priority
+}
+
+// TrainingPlanEvent is the baseline of one event a plan trains.
+type TrainingPlanEvent struct {
+	Event string   `json:"event"`           // Scoring standard event
+	Score *int32   `json:"score,omitempty"` // Latest score; nil without a recent result
+	Value *float64 `json:"value,omitempty"` // Measurement of the latest result, in the event's metric
+	Gap   int32    `json:"gap"`             // Points short of the maximum
+	Focus string   `json:"focus"`           // One of the TrainingFocus* constants
+}
+
+// TrainingSession is one scheduled session of a plan.
+type TrainingSession struct {
+	ID            int32
+	PlanID        int32
+	ScheduledDate time.Time // Calendar date
+	Week          int       // 1-based
+	Event         string    // Scoring standard event
+	Focus         string    // One of the TrainingFocus* constants
+	Sets          int
+	TargetValue   *float64 // Per set, in the event's metric; nil when there is no baseline to scale
+}
@@ -20,6 +20,7 @@ type User struct {
 	// Added for USMC PFT scoring
 	Gender      string    `json:"gender"`        // 'male' or 'female'
 	DateOfBirth time.Time `json:"date_of_birth"` // For age calculation
+	Timezone    string    `json:"timezone"`      // IANA name, e.g. Europe/Berlin; local calendar days are in this zone
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
 }
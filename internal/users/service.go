@@ -2,6 +2,7 @@ package users
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"regexp" // Added for email validation
 	"time"
@@ -22,9 +23,13 @@ type UpdateUserProfileRequest struct {
 	LastName    *string
 	Gender      *string // 'male' or 'female' for USMC PFT scoring
 	DateOfBirth *string // Format: YYYY-MM-DD
+	Timezone    *string // IANA name, e.g. Asia/Seoul
 	// Password updates should be handled by a separate dedicated method/service if needed
 }
 
+// ErrInvalidTimezone is returned for a timezone that is not an IANA timezone name.
+var ErrInvalidTimezone = errors.New("invalid timezone: expected an IANA name such as Europe/Berlin")
+
 // Service defines the interface for user-related business logic.
 type Service interface {
 	GetUserProfile(ctx context.Context, userID string) (*store.User, error)
@@ -150,6 +155,15 @@ func (s *service) UpdateUserProfile(ctx context.Context, userID string, req *Upd
 			s.logger.Debug(ctx, "Updating user date of birth", "userID", userID)
 		}
 	}
+	if req.Timezone != nil && *req.Timezone != currentUser.Timezone {
+		if !validTimezone(*req.Timezone) {
+			s.logger.Warn(ctx, "Invalid timezone provided for update", "userID", userID, "timezone", *req.Timezone)
+			return nil, ErrInvalidTimezone
+		}
+		currentUser.Timezone = *req.Timezone
+		updated = true
+		s.logger.Debug(ctx, "Updating user timezone", "userID", userID)
+	}
 
 	if !updated {
 		s.logger.Info(ctx, "No changes detected for user profile update", "userID", userID)
@@ -184,6 +198,16 @@ func isValidEmailFormat(email string) bool {
 	return emailRegex.MatchString(email)
 }
 
+// validTimezone reports whether name is an IANA timezone name. The empty name and
+// "Local", which time.LoadLocation also accepts, are not.
+func validTimezone(name string) bool {
+	if name == "" || name == "Local" {
+		return false
+	}
+	_, err := time.LoadLocation(name)
+	return err == nil
+}
+
 // calculateAge calculates age from date of birth
 func calculateAge(dob time.Time) int {
 	now := time.Now()
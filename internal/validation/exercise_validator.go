@@ -21,6 +21,8 @@ func (v *ExerciseValidator) ValidateWorkoutData(
 	exerciseID int32,
 	reps *int32,
 	duration *int32,
+	weightLbs *int32,
+	distanceMeters *float64,
 	formScore *int32,
 ) error {
 	exercise, err := v.exerciseStore.GetExerciseDefinition(ctx, exerciseID)
@@ -37,10 +39,29 @@ func (v *ExerciseValidator) ValidateWorkoutData(
 		if duration != nil && *duration > 300 { // 5 minute max
 			return errors.New("duration exceeds maximum allowed")
 		}
-	case "run", "running":
+	case "run", "running", "run_3mile":
 		if duration == nil || *duration < 60 { // 1 minute minimum
 			return errors.New("valid duration required for running")
 		}
+	case "hand_release_pushup":
+		if reps == nil || *reps < 0 {
+			return errors.New("reps required for this exercise type")
+		}
+		if duration != nil && *duration > 120 { // 2 minute event
+			return errors.New("duration exceeds maximum allowed")
+		}
+	case "sprint_drag_carry", "plank":
+		if duration == nil || *duration <= 0 {
+			return errors.New("valid duration required for this exercise type")
+		}
+	case "deadlift":
+		if weightLbs == nil || *weightLbs <= 0 {
+			return errors.New("weight required for deadlift")
+		}
+	case "power_throw":
+		if distanceMeters == nil || *distanceMeters <= 0 {
+			return errors.New("distance required for power throw")
+		}
 	default:
 		return fmt.Errorf("unsupported exercise type: %s", exercise.Type)
 	}
@@ -59,6 +80,8 @@ func (v *ExerciseValidator) ValidateExercisePerformance(
 	exerciseID int32,
 	reps *int32,
 	duration *int32,
+	weightLbs *int32,
+	distanceMeters *float64,
 ) error {
 	exercise, err := v.exerciseStore.GetExerciseDefinition(ctx, exerciseID)
 	if err != nil {
@@ -78,13 +101,36 @@ func (v *ExerciseValidator) ValidateExercisePerformance(
 		if reps != nil && *reps > 100 { // Reasonable max for pullups
 			return errors.New("pullup count exceeds reasonable maximum")
 		}
-	case "run", "running":
+	case "run", "running", "run_3mile":
 		if duration != nil && *duration > 7200 { // 2 hour max
 			return errors.New("run duration exceeds reasonable maximum")
 		}
 		if duration != nil && *duration < 30 { // 30 second minimum
 			return errors.New("run duration below reasonable minimum")
 		}
+	case "hand_release_pushup":
+		if reps != nil && *reps > 150 { // Two-minute event
+			return errors.New("hand-release push-up count exceeds reasonable maximum")
+		}
+	case "sprint_drag_carry":
+		if duration != nil && *duration < 60 { // Five 50 m shuttles
+			return errors.New("sprint-drag-carry time below reasonable minimum")
+		}
+		if duration != nil && *duration > 900 {
+			return errors.New("sprint-drag-carry time exceeds reasonable maximum")
+		}
+	case "plank":
+		if duration != nil && *duration > 3600 { // 1 hour max
+			return errors.New("plank duration exceeds reasonable maximum")
+		}
+	case "deadlift":
+		if weightLbs != nil && (*weightLbs < 60 || *weightLbs > 700) { // Empty hex bar to well past the max standard
+			return errors.New("deadlift weight outside reasonable range")
+		}
+	case "power_throw":
+		if distanceMeters != nil && *distanceMeters > 20 {
+			return errors.New("power throw distance exceeds reasonable maximum")
+		}
 	}
 
 	return nil
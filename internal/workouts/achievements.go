@@ -0,0 +1,255 @@
+package workouts
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"ptchampion/internal/grading"
+	"ptchampion/internal/store"
+)
+
+// achievementBackfillBatch is how many users a backfill evaluates per store read.
+const achievementBackfillBatch = 100
+
+// Badge rule kinds
+const (
+	BadgeKindWorkouts  = "workouts"   // Log Target workouts of any exercise
+	BadgeKindTotalReps = "total_reps" // Complete Target reps across the badge's exercises
+	BadgeKindStreak    = "streak"     // Reach a daily streak of Target days; streak freezes apply
+	BadgeKindMaxScore  = "max_score"  // Score Target points in one of the badge's exercises
+	BadgeKindTime      = "time"       // Finish one of the badge's exercises in Target seconds or less
+)
+
+// Badge is an achievement declared as a rule over a user's lifetime results.
+type Badge struct {
+	ID            string
+	Name          string
+	Description   string
+	Kind          string   // One of the BadgeKind* constants
+	ExerciseTypes []string // Stored exercise types the rule counts; empty for all
+	Target        float64
+}
+
+// badges is the badge catalog, in the order badges are listed. Badge IDs are stored with
+// awards and must not change.
+var badges = []*Badge{
+	{ID: "first_workout", Name: "First Rep", Description: "Log your first workout", Kind: BadgeKindWorkouts, Target: 1},
+	{ID: "workouts_50", Name: "Regular", Description: "Log 50 workouts", Kind: BadgeKindWorkouts, Target: 50},
+	{ID: "pushups_100", Name: "Push-up Century", Description: "Complete 100 total push-ups", Kind: BadgeKindTotalReps,
+		ExerciseTypes: []string{grading.ExerciseTypePushup, grading.ExerciseTypeHandReleasePushup}, Target: 100},
+	{ID: "pushups_1000", Name: "Push-up Thousand", Description: "Complete 1,000 total push-ups", Kind: BadgeKindTotalReps,
+		ExerciseTypes: []string{grading.ExerciseTypePushup, grading.ExerciseTypeHandReleasePushup}, Target: 1000},
+	{ID: "situps_1000", Name: "Iron Core", Description: "Complete 1,000 total sit-ups", Kind: BadgeKindTotalReps,
+		ExerciseTypes: []string{grading.ExerciseTypeSitup}, Target: 1000},
+	{ID: "pullups_500", Name: "Bar Hanger", Description: "Complete 500 total pull-ups", Kind: BadgeKindTotalReps,
+		ExerciseTypes: []string{grading.ExerciseTypePullup}, Target: 500},
+	{ID: "streak_7", Name: "Week Warrior", Description: "Work out 7 days in a row", Kind: BadgeKindStreak, Target: 7},
+	{ID: "streak_30", Name: "Unbroken", Description: "Work out 30 days in a row", Kind: BadgeKindStreak, Target: 30},
+	{ID: "pushup_max_score", Name: "Push-up Max", Description: "Score 100 points on push-ups", Kind: BadgeKindMaxScore,
+		ExerciseTypes: []string{grading.ExerciseTypePushup, grading.ExerciseTypeHandReleasePushup}, Target: grading.MaxPoints},
+	{ID: "situp_max_score", Name: "Sit-up Max", Description: "Score 100 points on sit-ups", Kind: BadgeKindMaxScore,
+		ExerciseTypes: []string{grading.ExerciseTypeSitup}, Target: grading.MaxPoints},
+	{ID: "run_sub_13", Name: "Sub-13", Description: "Run the two-mile in under 13:00", Kind: BadgeKindTime,
+		ExerciseTypes: []string{storedExerciseType(grading.ExerciseTypeRun)}, Target: 13 * 60},
+}
+
+// BadgeProgress is a badge with the user's progress toward it.
+type BadgeProgress struct {
+	Badge    *Badge
+	Earned   bool
+	EarnedAt *time.Time // nil until earned
+	Current  float64    // Measured the way Target is; 0 without a qualifying workout
+	Percent  float64    // Progress toward the target, 0-100
+}
+
+// AchievementBackfillResult summarizes a backfill over all users with workouts.
+type AchievementBackfillResult struct {
+	Users   int // Users evaluated
+	Awarded int // Badges newly awarded
+	Failed  int // Users whose evaluation failed; see the logs
+}
+
+// ListAchievements returns every badge in the catalog with the user's progress, earned
+// or locked.
+func (s *service) ListAchievements(ctx context.Context, userID int32) ([]*BadgeProgress, error) {
+	stats, err := s.workoutStore.GetAchievementStats(ctx, userID)
+	if err != nil {
+		s.logger.Error(ctx, "Failed to get achievement stats", "userID", userID, "error", err)
+		return nil, fmt.Errorf("failed to retrieve achievement stats: %w", err)
+	}
+	awarded, err := s.workoutStore.ListUserAchievements(ctx, userID)
+	if err != nil {
+		s.logger.Error(ctx, "Failed to list achievements", "userID", userID, "error", err)
+		return nil, fmt.Errorf("failed to retrieve achievements: %w", err)
+	}
+
+	earnedAt := make(map[string]time.Time, len(awarded))
+	for _, a := range awarded {
+		earnedAt[a.BadgeID] = a.EarnedAt
+	}
+
+	progress := make([]*BadgeProgress, len(badges))
+	for i, badge := range badges {
+		progress[i] = badgeProgress(badge, stats)
+		if at, ok := earnedAt[badge.ID]; ok {
+			// An award stands even if the results behind it were since deleted
+			progress[i].Earned = true
+			progress[i].EarnedAt = &at
+			progress[i].Percent = 100
+		}
+	}
+	return progress, nil
+}
+
+// BackfillAchievements evaluates the badges of every user with workouts against their
+// full history and awards those they have earned. Backfilled awards are not published to
+// the activity feed. Running it again awards only badges earned since.
+func (s *service) BackfillAchievements(ctx context.Context) (*AchievementBackfillResult, error) {
+	result := &AchievementBackfillResult{}
+	var afterID int32
+	for {
+		userIDs, err := s.workoutStore.ListUserIDsWithWorkouts(ctx, afterID, achievementBackfillBatch)
+		if err != nil {
+			s.logger.Error(ctx, "Failed to list users for achievement backfill", "afterID", afterID, "error", err)
+			return nil, fmt.Errorf("failed to list users: %w", err)
+		}
+		if len(userIDs) == 0 {
+			break
+		}
+
+		for _, userID := range userIDs {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			awarded, err := s.evaluateAchievements(ctx, userID, nil)
+			if err != nil {
+				s.logger.Warn(ctx, "Failed to backfill achievements for user", "userID", userID, "error", err)
+				result.Failed++
+				continue
+			}
+			result.Users++
+			result.Awarded += len(awarded)
+		}
+		afterID = userIDs[len(userIDs)-1]
+	}
+
+	s.logger.Info(ctx, "Achievement backfill finished", "users", result.Users, "awarded", result.Awarded, "failed", result.Failed)
+	return result, nil
+}
+
+// evaluateAchievements awards the badges the user has earned and not yet been awarded, and
+// returns them. workout is the logged workout being evaluated, or nil for a backfill.
+func (s *service) evaluateAchievements(ctx context.Context, userID int32, workout *store.WorkoutRecord) ([]*Badge, error) {
+	stats, err := s.workoutStore.GetAchievementStats(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get achievement stats: %w", err)
+	}
+	awarded, err := s.workoutStore.ListUserAchievements(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list achievements: %w", err)
+	}
+
+	earned := earnedBadges(stats, awarded)
+	if len(earned) == 0 {
+		return nil, nil
+	}
+	awards := make([]store.AchievementAward, len(earned))
+	for i, badge := range earned {
+		awards[i] = store.AchievementAward{BadgeID: badge.ID, BadgeName: badge.Name}
+	}
+	created, err := s.workoutStore.AwardAchievements(ctx, userID, workout, awards)
+	if err != nil {
+		return nil, fmt.Errorf("failed to award achievements: %w", err)
+	}
+
+	// Report only the awards this call stored; a concurrent evaluation may have stored the rest
+	stored := make(map[string]bool, len(created))
+	for _, a := range created {
+		stored[a.BadgeID] = true
+	}
+	newBadges := make([]*Badge, 0, len(created))
+	for _, badge := range earned {
+		if stored[badge.ID] {
+			newBadges = append(newBadges, badge)
+		}
+	}
+	if len(newBadges) > 0 {
+		s.logger.Info(ctx, "Achievements awarded", "userID", userID, "count", len(newBadges))
+	}
+	return newBadges, nil
+}
+
+// earnedBadges returns the badges the stats meet that have not been awarded yet.
+func earnedBadges(stats *store.AchievementStats, awarded []*store.Achievement) []*Badge {
+	have := make(map[string]bool, len(awarded))
+	for _, a := range awarded {
+		have[a.BadgeID] = true
+	}
+	var earned []*Badge
+	for _, badge := range badges {
+		if !have[badge.ID] && badgeProgress(badge, stats).Earned {
+			earned = append(earned, badge)
+		}
+	}
+	return earned
+}
+
+// badgeProgress measures the user's results against a badge's rule.
+func badgeProgress(badge *Badge, stats *store.AchievementStats) *BadgeProgress {
+	progress := &BadgeProgress{Badge: badge}
+	switch badge.Kind {
+	case BadgeKindWorkouts:
+		progress.Current = float64(stats.TotalWorkouts)
+	case BadgeKindTotalReps:
+		for _, e := range badgeExercises(badge, stats) {
+			progress.Current += float64(e.TotalReps)
+		}
+	case BadgeKindStreak:
+		progress.Current = float64(longestStreak(stats.ActiveDays))
+	case BadgeKindMaxScore:
+		for _, e := range badgeExercises(badge, stats) {
+			progress.Current = math.Max(progress.Current, float64(e.BestGrade))
+		}
+	case BadgeKindTime:
+		for _, e := range badgeExercises(badge, stats) {
+			if e.FastestDurationSeconds == nil {
+				continue
+			}
+			if fastest := float64(*e.FastestDurationSeconds); progress.Current == 0 || fastest < progress.Current {
+				progress.Current = fastest
+			}
+		}
+		// Lower is better: progress is how close the fastest time is to the target
+		if progress.Current > 0 {
+			progress.Earned = progress.Current <= badge.Target
+			progress.Percent = math.Min(100, badge.Target/progress.Current*100)
+		}
+		return progress
+	}
+
+	progress.Earned = progress.Current >= badge.Target
+	progress.Percent = math.Min(100, progress.Current/badge.Target*100)
+	return progress
+}
+
+// badgeExercises returns the user's stats in the exercises a badge counts.
+func badgeExercises(badge *Badge, stats *store.AchievementStats) []*store.ExerciseAchievementStats {
+	var exercises []*store.ExerciseAchievementStats
+	for _, exerciseType := range badge.ExerciseTypes {
+		if e, ok := stats.Exercises[exerciseType]; ok {
+			exercises = append(exercises, e)
+		}
+	}
+	return exercises
+}
+
+// longestStreak returns the longest daily streak among days, which are distinct local
+// dates in ascending order. Streak freezes apply as they do on the dashboard.
+func longestStreak(days []time.Time) int {
+	if len(days) == 0 {
+		return 0
+	}
+	return dailyStreak(days, days[len(days)-1]).Longest
+}
@@ -0,0 +1,163 @@
+package workouts
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"ptchampion/internal/store"
+)
+
+// activeDays returns the calendar dates n days after 2025-07-01 for each n.
+func activeDays(days ...int) []time.Time {
+	start := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+	dates := make([]time.Time, len(days))
+	for i, n := range days {
+		dates[i] = start.AddDate(0, 0, n)
+	}
+	return dates
+}
+
+func findBadge(t *testing.T, id string) *Badge {
+	t.Helper()
+	for _, badge := range badges {
+		if badge.ID == id {
+			return badge
+		}
+	}
+	t.Fatalf("badge %q not in catalog", id)
+	return nil
+}
+
+func TestBadgeCatalog(t *testing.T) {
+	seen := map[string]bool{}
+	for _, badge := range badges {
+		if seen[badge.ID] {
+			t.Errorf("duplicate badge ID %q", badge.ID)
+		}
+		seen[badge.ID] = true
+		if badge.Target <= 0 {
+			t.Errorf("badge %q has target %v, want positive", badge.ID, badge.Target)
+		}
+		switch badge.Kind {
+		case BadgeKindWorkouts, BadgeKindStreak:
+		case BadgeKindTotalReps, BadgeKindMaxScore, BadgeKindTime:
+			if len(badge.ExerciseTypes) == 0 {
+				t.Errorf("badge %q of kind %s counts no exercises", badge.ID, badge.Kind)
+			}
+		default:
+			t.Errorf("badge %q has unknown kind %q", badge.ID, badge.Kind)
+		}
+	}
+}
+
+func TestBadgeProgress(t *testing.T) {
+	stats := &store.AchievementStats{
+		TotalWorkouts: 12,
+		Exercises: map[string]*store.ExerciseAchievementStats{
+			"pushup":              {Workouts: 5, TotalReps: 60, BestGrade: 88},
+			"hand_release_pushup": {Workouts: 2, TotalReps: 45, BestGrade: 100},
+			"situp":               {Workouts: 3, TotalReps: 150, BestGrade: 92},
+			"running":             {Workouts: 2, FastestDurationSeconds: int32Ptr(800)},
+		},
+		ActiveDays: activeDays(0, 1, 2, 5, 6, 7, 8, 9, 20),
+	}
+
+	tests := []struct {
+		badge       string
+		wantEarned  bool
+		wantCurrent float64
+		wantPercent float64
+	}{
+		{badge: "first_workout", wantEarned: true, wantCurrent: 12, wantPercent: 100},
+		{badge: "workouts_50", wantCurrent: 12, wantPercent: 24},
+		{badge: "pushups_100", wantEarned: true, wantCurrent: 105, wantPercent: 100}, // Both push-up events count
+		{badge: "situps_1000", wantCurrent: 150, wantPercent: 15},
+		{badge: "pullups_500", wantCurrent: 0, wantPercent: 0},
+		{badge: "streak_7", wantCurrent: 5, wantPercent: 5.0 / 7 * 100},
+		{badge: "pushup_max_score", wantEarned: true, wantCurrent: 100, wantPercent: 100},
+		{badge: "situp_max_score", wantCurrent: 92, wantPercent: 92},
+		{badge: "run_sub_13", wantCurrent: 800, wantPercent: 780.0 / 800 * 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.badge, func(t *testing.T) {
+			got := badgeProgress(findBadge(t, tt.badge), stats)
+			if got.Earned != tt.wantEarned {
+				t.Errorf("Earned = %v, want %v", got.Earned, tt.wantEarned)
+			}
+			if got.Current != tt.wantCurrent {
+				t.Errorf("Current = %v, want %v", got.Current, tt.wantCurrent)
+			}
+			if math.Abs(got.Percent-tt.wantPercent) > 0.01 {
+				t.Errorf("Percent = %v, want %v", got.Percent, tt.wantPercent)
+			}
+		})
+	}
+}
+
+func TestBadgeProgressTimeTarget(t *testing.T) {
+	badge := findBadge(t, "run_sub_13")
+	run := func(seconds int32) *store.AchievementStats {
+		return &store.AchievementStats{Exercises: map[string]*store.ExerciseAchievementStats{
+			"running": {Workouts: 1, FastestDurationSeconds: int32Ptr(seconds)},
+		}}
+	}
+
+	if got := badgeProgress(badge, run(780)); !got.Earned || got.Percent != 100 {
+		t.Errorf("13:00 run = earned %v, %v%%, want earned at 100%%", got.Earned, got.Percent)
+	}
+	if got := badgeProgress(badge, run(781)); got.Earned {
+		t.Error("13:01 run earned the badge")
+	}
+	if got := badgeProgress(badge, &store.AchievementStats{}); got.Earned || got.Current != 0 || got.Percent != 0 {
+		t.Errorf("no runs = %+v, want no progress", got)
+	}
+}
+
+func TestEarnedBadges(t *testing.T) {
+	stats := &store.AchievementStats{
+		TotalWorkouts: 1,
+		Exercises: map[string]*store.ExerciseAchievementStats{
+			"situp": {Workouts: 1, TotalReps: 78, BestGrade: 100},
+		},
+		ActiveDays: activeDays(0),
+	}
+
+	got := earnedBadges(stats, nil)
+	var ids []string
+	for _, badge := range got {
+		ids = append(ids, badge.ID)
+	}
+	if len(ids) != 2 || ids[0] != "first_workout" || ids[1] != "situp_max_score" {
+		t.Errorf("earnedBadges() = %v, want [first_workout situp_max_score]", ids)
+	}
+
+	// Badges already awarded are not awarded again
+	got = earnedBadges(stats, []*store.Achievement{{BadgeID: "first_workout"}})
+	if len(got) != 1 || got[0].ID != "situp_max_score" {
+		t.Errorf("earnedBadges() with first_workout awarded = %v, want [situp_max_score]", got)
+	}
+}
+
+func TestLongestStreak(t *testing.T) {
+	tests := []struct {
+		name string
+		days []time.Time
+		want int
+	}{
+		{name: "no workouts", days: nil, want: 0},
+		{name: "single day", days: activeDays(3), want: 1},
+		{name: "gap resets", days: activeDays(0, 1, 3, 4, 5), want: 3},
+		{name: "longest earlier", days: activeDays(0, 1, 2, 3, 10, 11), want: 4},
+		{name: "across month end", days: activeDays(29, 30, 31, 32), want: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := longestStreak(tt.days); got != tt.want {
+				t.Errorf("longestStreak() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,189 @@
+package workouts
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"ptchampion/internal/grading"
+	"ptchampion/internal/store"
+)
+
+// UpdateWorkoutData defines an edit to a logged workout. Nil fields keep their stored
+// value; the exercise and scoring standard cannot be changed.
+type UpdateWorkoutData struct {
+	Reps            *int32
+	DurationSeconds *int32
+	WeightLbs       *int32
+	DistanceMeters  *float64
+	FormScore       *int32
+	CompletedAt     *time.Time
+	IsPublic        *bool
+	Visibility      *string // Takes precedence over IsPublic
+}
+
+// UpdateWorkout applies an edit to one of the user's workouts. The grade is recomputed
+// by the server from the edited measurement under the workout's scoring standard, the
+// change is audited, the user's personal records for the exercise are rebuilt and cached
+// leaderboards are invalidated.
+func (s *service) UpdateWorkout(ctx context.Context, userID int32, workoutID int32, data *UpdateWorkoutData) (*store.WorkoutRecord, error) {
+	s.logger.Debug(ctx, "WorkoutService: UpdateWorkout called", "userID", userID, "workoutID", workoutID)
+
+	record, err := s.ownedWorkoutRecord(ctx, userID, workoutID)
+	if err != nil {
+		return nil, err
+	}
+
+	edited := &LogWorkoutData{
+		ExerciseID:      record.ExerciseID,
+		Reps:            record.Reps,
+		DurationSeconds: record.DurationSeconds,
+		WeightLbs:       record.WeightLbs,
+		DistanceMeters:  record.DistanceMeters,
+		FormScore:       record.FormScore,
+		CompletedAt:     record.CompletedAt,
+		IsPublic:        record.IsPublic,
+		Visibility:      record.Visibility,
+		ScoringStandard: record.ScoringStandard,
+	}
+	applyWorkoutEdit(edited, data)
+	visibility, err := workoutVisibility(edited.Visibility, edited.IsPublic)
+	if err != nil {
+		return nil, err
+	}
+	edited.Visibility = visibility
+	edited.IsPublic = visibility == store.WorkoutVisibilityPublic
+
+	exercise, err := s.exerciseStore.GetExerciseDefinition(ctx, record.ExerciseID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get exercise: %w", err)
+	}
+	value, ok := performanceValue(exercise.Type, edited)
+	if !ok {
+		metric, _ := grading.MetricFor(grading.EventForExerciseType(exercise.Type))
+		return nil, fmt.Errorf("%s required for this exercise type: %w", metric, ErrMissingMeasurement)
+	}
+	if err := s.validateMeasurements(ctx, edited); err != nil {
+		return nil, err
+	}
+	standard, err := resolveStandard(edited.ScoringStandard, exercise.Type)
+	if err != nil {
+		return nil, err
+	}
+	recordToStore, err := s.serverGradedRecord(ctx, userID, exercise, standard, value, edited)
+	if err != nil {
+		return nil, err
+	}
+	recordToStore.ID = workoutID
+
+	updated, err := s.prStore.UpdateWorkoutRecordWithPersonalRecords(ctx, userID, recordToStore, storedRecordCandidates)
+	if err != nil {
+		if err == store.ErrWorkoutRecordNotFound || err == store.ErrWorkoutInTestSession {
+			return nil, err
+		}
+		s.logger.Error(ctx, "Failed to update workout record in store", "userID", userID, "workoutID", workoutID, "error", err)
+		return nil, fmt.Errorf("failed to update workout record: %w", err)
+	}
+
+	s.invalidateLeaderboards(ctx, userID)
+	s.logger.Info(ctx, "Workout record updated", "userID", userID, "workoutID", workoutID, "grade", updated.Grade)
+	return updated, nil
+}
+
+// DeleteWorkout deletes one of the user's workouts. The workout is kept as a tombstone
+// for sync clients, the deletion is audited, the user's personal records for the exercise
+// are rebuilt and cached leaderboards are invalidated.
+func (s *service) DeleteWorkout(ctx context.Context, userID int32, workoutID int32) error {
+	s.logger.Debug(ctx, "WorkoutService: DeleteWorkout called", "userID", userID, "workoutID", workoutID)
+
+	if _, err := s.ownedWorkoutRecord(ctx, userID, workoutID); err != nil {
+		return err
+	}
+
+	if err := s.prStore.DeleteWorkoutRecordWithPersonalRecords(ctx, userID, workoutID, storedRecordCandidates); err != nil {
+		if err == store.ErrWorkoutRecordNotFound || err == store.ErrWorkoutInTestSession {
+			return err
+		}
+		s.logger.Error(ctx, "Failed to delete workout record in store", "userID", userID, "workoutID", workoutID, "error", err)
+		return fmt.Errorf("failed to delete workout record: %w", err)
+	}
+
+	s.invalidateLeaderboards(ctx, userID)
+	s.logger.Info(ctx, "Workout record deleted", "userID", userID, "workoutID", workoutID)
+	return nil
+}
+
+// GetWorkoutAuditLog returns the change history of one of the user's workouts, oldest first.
+func (s *service) GetWorkoutAuditLog(ctx context.Context, userID int32, workoutID int32) ([]*store.WorkoutAuditEntry, error) {
+	s.logger.Debug(ctx, "WorkoutService: GetWorkoutAuditLog called", "userID", userID, "workoutID", workoutID)
+
+	if _, err := s.ownedWorkoutRecord(ctx, userID, workoutID); err != nil {
+		return nil, err
+	}
+
+	entries, err := s.workoutStore.GetWorkoutAuditLog(ctx, workoutID)
+	if err != nil {
+		s.logger.Error(ctx, "Failed to get workout audit log from store", "workoutID", workoutID, "error", err)
+		return nil, fmt.Errorf("failed to retrieve workout audit log: %w", err)
+	}
+	return entries, nil
+}
+
+// ownedWorkoutRecord loads a live workout and checks that the user owns it.
+func (s *service) ownedWorkoutRecord(ctx context.Context, userID int32, workoutID int32) (*store.WorkoutRecord, error) {
+	record, err := s.workoutStore.GetWorkoutRecordByID(ctx, workoutID)
+	if err != nil {
+		if err == store.ErrWorkoutRecordNotFound {
+			s.logger.Warn(ctx, "Workout record not found", "workoutID", workoutID)
+			return nil, err
+		}
+		s.logger.Error(ctx, "Failed to get workout record", "workoutID", workoutID, "error", err)
+		return nil, fmt.Errorf("failed to retrieve workout record: %w", err)
+	}
+
+	if record.UserID != userID {
+		s.logger.Warn(ctx, "User attempted to change workout they don't own", "userID", userID, "workoutID", workoutID, "ownerID", record.UserID)
+		return nil, fmt.Errorf("user does not have permission to modify this workout record")
+	}
+	return record, nil
+}
+
+// applyWorkoutEdit overwrites the fields of data that the edit sets.
+func applyWorkoutEdit(data *LogWorkoutData, edit *UpdateWorkoutData) {
+	if edit.Reps != nil {
+		data.Reps = edit.Reps
+	}
+	if edit.DurationSeconds != nil {
+		data.DurationSeconds = edit.DurationSeconds
+	}
+	if edit.WeightLbs != nil {
+		data.WeightLbs = edit.WeightLbs
+	}
+	if edit.DistanceMeters != nil {
+		data.DistanceMeters = edit.DistanceMeters
+	}
+	if edit.FormScore != nil {
+		data.FormScore = edit.FormScore
+	}
+	if edit.CompletedAt != nil {
+		data.CompletedAt = *edit.CompletedAt
+	}
+	if edit.IsPublic != nil {
+		data.IsPublic = *edit.IsPublic
+		data.Visibility = "" // Follow IsPublic
+	}
+	if edit.Visibility != nil {
+		data.Visibility = *edit.Visibility
+	}
+}
+
+// invalidateLeaderboards drops cached leaderboards after a change to the user's
+// workouts. Failures are logged; the cache entries expire on their own.
+func (s *service) invalidateLeaderboards(ctx context.Context, userID int32) {
+	if s.leaderboardCache == nil {
+		return
+	}
+	if err := s.leaderboardCache.InvalidateUserLeaderboards(ctx, int(userID)); err != nil {
+		s.logger.Error(ctx, "Failed to invalidate leaderboard cache after workout change", "userID", userID, "error", err)
+	}
+}
@@ -0,0 +1,381 @@
+package workouts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"ptchampion/internal/analytics"
+	"ptchampion/internal/grading"
+	"ptchampion/internal/store"
+)
+
+// goalTrendDays is how far back workouts count toward a goal's current level and trend.
+const goalTrendDays = 90
+
+// Goal statuses
+const (
+	GoalStatusAchieved = "achieved" // The current level meets the target
+	GoalStatusOnTrack  = "on_track" // The trend reaches the target by the target date
+	GoalStatusBehind   = "behind"   // The trend falls short, or there is no recent workout
+	GoalStatusMissed   = "missed"   // The target date passed without reaching the target
+)
+
+// ErrInvalidGoal is returned for a goal whose target, date or exercise is not valid.
+var ErrInvalidGoal = errors.New("invalid goal")
+
+// CreateGoalData defines a new goal at the service layer.
+type CreateGoalData struct {
+	Type            string // One of the store.GoalType* constants
+	ExerciseType    string // Exercise goals only
+	ScoringStandard string // Registry ID; empty for the default standard
+	TargetValue     float64
+	TargetDate      time.Time
+}
+
+// GoalEventProgress is one event of a total score goal: the best score among the
+// event and its alternates.
+type GoalEventProgress struct {
+	Event          string
+	CurrentScore   *int32   // nil without a recent workout
+	ProjectedScore *float64 // nil without a recent workout
+}
+
+// GoalProgress is a goal measured against the user's recent workouts.
+type GoalProgress struct {
+	Goal      *store.Goal
+	Status    string   // One of the GoalStatus* constants
+	Current   *float64 // Best recent measurement, or total score; nil without a recent workout
+	Projected *float64 // Trend value at the target date; nil without a recent workout
+	Percent   float64  // Progress toward the target in points, 0-100
+
+	// Exercise goals: the points the current level and the target are worth
+	CurrentScore *int32
+	TargetScore  int32
+
+	Events []*GoalEventProgress // Total score goals only
+}
+
+// CreateGoal validates and stores a goal and returns its progress.
+func (s *service) CreateGoal(ctx context.Context, userID int32, data *CreateGoalData) (*GoalProgress, error) {
+	goal := &store.Goal{
+		UserID:      userID,
+		Type:        data.Type,
+		TargetValue: data.TargetValue,
+		TargetDate:  calendarDate(data.TargetDate),
+	}
+	if data.TargetValue <= 0 {
+		return nil, fmt.Errorf("%w: target must be positive", ErrInvalidGoal)
+	}
+	if goal.TargetDate.Before(calendarDate(time.Now())) {
+		return nil, fmt.Errorf("%w: target date is in the past", ErrInvalidGoal)
+	}
+
+	switch data.Type {
+	case store.GoalTypeExercise:
+		if _, err := grading.MetricFor(grading.EventForExerciseType(data.ExerciseType)); err != nil {
+			return nil, fmt.Errorf("%w: unsupported exercise type %q", ErrInvalidGoal, data.ExerciseType)
+		}
+		standard, err := resolveStandard(data.ScoringStandard, data.ExerciseType)
+		if err != nil {
+			return nil, err
+		}
+		goal.ExerciseType = data.ExerciseType
+		goal.ScoringStandard = standard.ID()
+	case store.GoalTypeTotalScore:
+		standard, err := grading.LookupStandard(data.ScoringStandard)
+		if err != nil {
+			return nil, fmt.Errorf("invalid scoring standard %q: %w", data.ScoringStandard, err)
+		}
+		if maxScore := float64(grading.MaxPoints * len(standard.Protocol().Sequence)); data.TargetValue > maxScore {
+			return nil, fmt.Errorf("%w: %s total scores are at most %.0f", ErrInvalidGoal, standard.ID(), maxScore)
+		}
+		goal.ScoringStandard = standard.ID()
+	default:
+		return nil, fmt.Errorf("%w: unknown goal type %q", ErrInvalidGoal, data.Type)
+	}
+
+	created, err := s.workoutStore.CreateGoal(ctx, goal)
+	if err != nil {
+		s.logger.Error(ctx, "Failed to create goal", "userID", userID, "error", err)
+		return nil, fmt.Errorf("failed to create goal: %w", err)
+	}
+	s.logger.Info(ctx, "Goal created", "userID", userID, "goalID", created.ID, "type", created.Type)
+
+	progress, err := s.goalProgress(ctx, userID, []*store.Goal{created})
+	if err != nil {
+		return nil, err
+	}
+	return progress[0], nil
+}
+
+// ListGoals returns the user's goals with their progress, soonest target date first.
+func (s *service) ListGoals(ctx context.Context, userID int32) ([]*GoalProgress, error) {
+	goals, err := s.workoutStore.ListUserGoals(ctx, userID)
+	if err != nil {
+		s.logger.Error(ctx, "Failed to list goals", "userID", userID, "error", err)
+		return nil, fmt.Errorf("failed to retrieve goals: %w", err)
+	}
+	return s.goalProgress(ctx, userID, goals)
+}
+
+// GetGoal returns one of the user's goals with its progress.
+func (s *service) GetGoal(ctx context.Context, userID int32, goalID int32) (*GoalProgress, error) {
+	goal, err := s.getOwnGoal(ctx, userID, goalID)
+	if err != nil {
+		return nil, err
+	}
+	progress, err := s.goalProgress(ctx, userID, []*store.Goal{goal})
+	if err != nil {
+		return nil, err
+	}
+	return progress[0], nil
+}
+
+// DeleteGoal deletes one of the user's goals.
+func (s *service) DeleteGoal(ctx context.Context, userID int32, goalID int32) error {
+	if _, err := s.getOwnGoal(ctx, userID, goalID); err != nil {
+		return err
+	}
+	if err := s.workoutStore.DeleteGoal(ctx, goalID); err != nil {
+		if err == store.ErrGoalNotFound {
+			return err
+		}
+		s.logger.Error(ctx, "Failed to delete goal", "userID", userID, "goalID", goalID, "error", err)
+		return fmt.Errorf("failed to delete goal: %w", err)
+	}
+	s.logger.Info(ctx, "Goal deleted", "userID", userID, "goalID", goalID)
+	return nil
+}
+
+func (s *service) getOwnGoal(ctx context.Context, userID int32, goalID int32) (*store.Goal, error) {
+	goal, err := s.workoutStore.GetGoalByID(ctx, goalID)
+	if err != nil {
+		if err == store.ErrGoalNotFound {
+			return nil, err
+		}
+		s.logger.Error(ctx, "Failed to get goal", "goalID", goalID, "error", err)
+		return nil, fmt.Errorf("failed to retrieve goal: %w", err)
+	}
+	if goal.UserID != userID {
+		s.logger.Warn(ctx, "User attempted to access goal of another user", "userID", userID, "goalID", goalID, "ownerID", goal.UserID)
+		return nil, fmt.Errorf("user does not have permission to access this goal")
+	}
+	return goal, nil
+}
+
+// goalProgress measures the goals against the user's workouts from the last goalTrendDays,
+// scored with the user's normed tables.
+func (s *service) goalProgress(ctx context.Context, userID int32, goals []*store.Goal) ([]*GoalProgress, error) {
+	now := time.Now()
+	since := calendarDate(now).AddDate(0, 0, -goalTrendDays)
+
+	standards := make([]grading.ScoringStandard, len(goals))
+	var exerciseTypes []string
+	for i, goal := range goals {
+		standard, err := grading.LookupStandard(goal.ScoringStandard)
+		if err != nil {
+			return nil, fmt.Errorf("goal %d has unknown scoring standard %q: %w", goal.ID, goal.ScoringStandard, err)
+		}
+		standards[i] = standard
+		if goal.Type == store.GoalTypeExercise {
+			exerciseTypes = append(exerciseTypes, goal.ExerciseType)
+		} else {
+			for _, slot := range standard.Protocol().Sequence {
+				for _, event := range slot {
+					exerciseTypes = append(exerciseTypes, storedExerciseType(event))
+				}
+			}
+		}
+	}
+
+	var records []*store.WorkoutRecord
+	if len(exerciseTypes) > 0 {
+		var err error
+		records, err = s.workoutStore.ListUserWorkoutRecordsSince(ctx, userID, exerciseTypes, since)
+		if err != nil {
+			s.logger.Error(ctx, "Failed to list workouts for goals", "userID", userID, "error", err)
+			return nil, fmt.Errorf("failed to retrieve workouts: %w", err)
+		}
+	}
+
+	profile := s.scoringProfile(ctx, userID, now)
+	progress := make([]*GoalProgress, len(goals))
+	for i, goal := range goals {
+		if goal.Type == store.GoalTypeExercise {
+			progress[i] = exerciseGoalProgress(goal, standards[i], records, profile, since, now)
+		} else {
+			progress[i] = totalScoreGoalProgress(goal, standards[i], records, profile, since, now)
+		}
+	}
+	return progress, nil
+}
+
+// exerciseGoalProgress compares the best recent measurement in the goal's exercise with
+// the target and projects the trend of the measurements to the target date.
+func exerciseGoalProgress(goal *store.Goal, standard grading.ScoringStandard, records []*store.WorkoutRecord, profile grading.Profile, since, now time.Time) *GoalProgress {
+	event := grading.EventForExerciseType(goal.ExerciseType)
+	metric, _ := grading.MetricFor(event)
+	higher := higherIsBetter(standard, goal.ExerciseType)
+
+	progress := &GoalProgress{Goal: goal}
+	if score, err := standard.Score(event, goal.TargetValue, profile); err == nil {
+		progress.TargetScore = int32(score)
+	}
+
+	var xs, ys []float64
+	for _, r := range records {
+		if r.ExerciseType != goal.ExerciseType {
+			continue
+		}
+		if v, ok := measurementValue(metric, r.Reps, r.DurationSeconds, r.WeightLbs, r.DistanceMeters); ok && v > 0 {
+			xs = append(xs, daysBetween(since, r.CompletedAt))
+			ys = append(ys, v)
+		}
+	}
+	if len(ys) == 0 {
+		progress.Status = goalStatus(false, false, goal.TargetDate, now)
+		return progress
+	}
+
+	current := ys[0]
+	for _, v := range ys[1:] {
+		if higher && v > current || !higher && v < current {
+			current = v
+		}
+	}
+	projected := projectTrend(xs, ys, daysBetween(since, goal.TargetDate), current)
+	progress.Current = &current
+	progress.Projected = &projected
+
+	if score, err := standard.Score(event, current, profile); err == nil {
+		currentScore := int32(score)
+		progress.CurrentScore = &currentScore
+	}
+	achieved := meetsTarget(current, goal.TargetValue, higher)
+	switch {
+	case progress.TargetScore > 0 && progress.CurrentScore != nil:
+		progress.Percent = math.Min(100, float64(*progress.CurrentScore)/float64(progress.TargetScore)*100)
+	case achieved:
+		progress.Percent = 100
+	}
+	progress.Status = goalStatus(achieved, meetsTarget(projected, goal.TargetValue, higher), goal.TargetDate, now)
+	return progress
+}
+
+// totalScoreGoalProgress sums the best recent score in each event of the goal's standard,
+// taking the best of alternates, and projects each event's score trend to the target date.
+// Events without a recent workout score zero.
+func totalScoreGoalProgress(goal *store.Goal, standard grading.ScoringStandard, records []*store.WorkoutRecord, profile grading.Profile, since, now time.Time) *GoalProgress {
+	progress := &GoalProgress{Goal: goal}
+	targetX := daysBetween(since, goal.TargetDate)
+
+	var total, projectedTotal float64
+	var scored bool
+	for _, slot := range standard.Protocol().Sequence {
+		eventProgress := &GoalEventProgress{Event: slot[0]}
+		var xs, ys []float64
+		best := -1
+		for _, event := range slot {
+			metric, err := grading.MetricFor(grading.EventForExerciseType(event))
+			if err != nil {
+				continue
+			}
+			for _, r := range records {
+				if r.ExerciseType != storedExerciseType(event) {
+					continue
+				}
+				v, ok := measurementValue(metric, r.Reps, r.DurationSeconds, r.WeightLbs, r.DistanceMeters)
+				if !ok {
+					continue
+				}
+				score, err := standard.Score(event, v, profile)
+				if err != nil {
+					continue
+				}
+				xs = append(xs, daysBetween(since, r.CompletedAt))
+				ys = append(ys, float64(score))
+				if score > best {
+					best = score
+					eventProgress.Event = event
+				}
+			}
+		}
+
+		if best >= 0 {
+			scored = true
+			currentScore := int32(best)
+			projected := math.Min(100, projectTrend(xs, ys, targetX, float64(best)))
+			eventProgress.CurrentScore = &currentScore
+			eventProgress.ProjectedScore = &projected
+			total += float64(best)
+			projectedTotal += projected
+		}
+		progress.Events = append(progress.Events, eventProgress)
+	}
+
+	if !scored {
+		progress.Status = goalStatus(false, false, goal.TargetDate, now)
+		return progress
+	}
+	progress.Current = &total
+	progress.Projected = &projectedTotal
+	progress.Percent = math.Min(100, total/goal.TargetValue*100)
+	progress.Status = goalStatus(total >= goal.TargetValue, projectedTotal >= goal.TargetValue, goal.TargetDate, now)
+	return progress
+}
+
+// projectTrend evaluates the least-squares line through the samples at x, or returns
+// fallback when the samples do not determine a trend.
+func projectTrend(xs, ys []float64, x float64, fallback float64) float64 {
+	if len(xs) < 2 {
+		return fallback
+	}
+	slope, intercept, ok := analytics.LinearFit(xs, ys)
+	if !ok {
+		return fallback
+	}
+	return math.Max(0, intercept+slope*x)
+}
+
+func meetsTarget(value, target float64, higherIsBetter bool) bool {
+	if higherIsBetter {
+		return value >= target
+	}
+	return value <= target
+}
+
+// goalStatus classifies a goal; a goal is missed once its target date has passed.
+func goalStatus(achieved, onTrack bool, targetDate, now time.Time) string {
+	switch {
+	case achieved:
+		return GoalStatusAchieved
+	case calendarDate(now).After(targetDate):
+		return GoalStatusMissed
+	case onTrack:
+		return GoalStatusOnTrack
+	default:
+		return GoalStatusBehind
+	}
+}
+
+// storedExerciseType maps a scoring-standard event to the exercise type workouts are stored
+// under, the inverse of grading.EventForExerciseType.
+func storedExerciseType(event string) string {
+	if event == grading.ExerciseTypeRun {
+		return "running"
+	}
+	return event
+}
+
+// calendarDate truncates a time to its UTC calendar date.
+func calendarDate(t time.Time) time.Time {
+	y, m, d := t.UTC().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+func daysBetween(from, to time.Time) float64 {
+	return to.Sub(from).Hours() / 24
+}
@@ -0,0 +1,148 @@
+package workouts
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"ptchampion/internal/grading"
+	"ptchampion/internal/store"
+)
+
+var goalNow = time.Date(2025, 7, 1, 12, 0, 0, 0, time.UTC)
+
+// goalDay returns the day n days into the goal trend window ending at goalNow.
+func goalDay(n int) time.Time {
+	return calendarDate(goalNow).AddDate(0, 0, n-goalTrendDays)
+}
+
+func repsWorkout(exerciseType string, day int, reps int32) *store.WorkoutRecord {
+	return &store.WorkoutRecord{ExerciseType: exerciseType, Reps: int32Ptr(reps), CompletedAt: goalDay(day)}
+}
+
+func runWorkout(day int, seconds int32) *store.WorkoutRecord {
+	return &store.WorkoutRecord{ExerciseType: "running", DurationSeconds: int32Ptr(seconds), CompletedAt: goalDay(day)}
+}
+
+func TestExerciseGoalProgress(t *testing.T) {
+	apft := grading.DefaultStandard()
+	since := goalDay(0)
+
+	tests := []struct {
+		name          string
+		goal          *store.Goal
+		records       []*store.WorkoutRecord
+		wantStatus    string
+		wantCurrent   float64 // 0 when there is no recent workout
+		wantProjected float64
+	}{
+		{
+			name:          "improving toward target",
+			goal:          &store.Goal{ExerciseType: "pushup", TargetValue: 60, TargetDate: goalDay(120)},
+			records:       []*store.WorkoutRecord{repsWorkout("pushup", 0, 40), repsWorkout("pushup", 30, 45), repsWorkout("pushup", 60, 50), runWorkout(60, 800)},
+			wantStatus:    GoalStatusOnTrack,
+			wantCurrent:   50,
+			wantProjected: 60,
+		},
+		{
+			name:          "flat trend",
+			goal:          &store.Goal{ExerciseType: "pushup", TargetValue: 70, TargetDate: goalDay(120)},
+			records:       []*store.WorkoutRecord{repsWorkout("pushup", 0, 50), repsWorkout("pushup", 60, 50)},
+			wantStatus:    GoalStatusBehind,
+			wantCurrent:   50,
+			wantProjected: 50,
+		},
+		{
+			name:          "fastest run meets target",
+			goal:          &store.Goal{ExerciseType: "running", TargetValue: 840, TargetDate: goalDay(120)},
+			records:       []*store.WorkoutRecord{runWorkout(0, 900), runWorkout(60, 840)},
+			wantStatus:    GoalStatusAchieved,
+			wantCurrent:   840,
+			wantProjected: 780,
+		},
+		{
+			name:       "no recent workout",
+			goal:       &store.Goal{ExerciseType: "situp", TargetValue: 60, TargetDate: goalDay(120)},
+			records:    []*store.WorkoutRecord{repsWorkout("pushup", 10, 40)},
+			wantStatus: GoalStatusBehind,
+		},
+		{
+			name:          "past target date",
+			goal:          &store.Goal{ExerciseType: "pushup", TargetValue: 70, TargetDate: goalDay(60)},
+			records:       []*store.WorkoutRecord{repsWorkout("pushup", 0, 40), repsWorkout("pushup", 30, 60)},
+			wantStatus:    GoalStatusMissed,
+			wantCurrent:   60,
+			wantProjected: 80,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := exerciseGoalProgress(tt.goal, apft, tt.records, grading.Profile{}, since, goalNow)
+			if p.Status != tt.wantStatus {
+				t.Errorf("status = %s, want %s", p.Status, tt.wantStatus)
+			}
+			if tt.wantCurrent == 0 {
+				if p.Current != nil || p.Projected != nil || p.Percent != 0 {
+					t.Errorf("progress without workouts = %+v, want no current, projection or percent", p)
+				}
+				return
+			}
+			if p.Current == nil || *p.Current != tt.wantCurrent {
+				t.Errorf("current = %v, want %v", p.Current, tt.wantCurrent)
+			}
+			if p.Projected == nil || math.Abs(*p.Projected-tt.wantProjected) > 1e-9 {
+				t.Errorf("projected = %v, want %v", p.Projected, tt.wantProjected)
+			}
+			if p.CurrentScore == nil || p.TargetScore == 0 {
+				t.Fatalf("scores = %v/%d, want both set", p.CurrentScore, p.TargetScore)
+			}
+			wantPercent := math.Min(100, float64(*p.CurrentScore)/float64(p.TargetScore)*100)
+			if p.Percent != wantPercent {
+				t.Errorf("percent = %v, want %v", p.Percent, wantPercent)
+			}
+		})
+	}
+}
+
+func TestTotalScoreGoalProgress(t *testing.T) {
+	apft := grading.DefaultStandard()
+	since := goalDay(0)
+	score := func(event string, value float64) float64 {
+		s, err := apft.Score(event, value, grading.Profile{})
+		if err != nil {
+			t.Fatalf("score %s: %v", event, err)
+		}
+		return float64(s)
+	}
+
+	records := []*store.WorkoutRecord{
+		repsWorkout("pushup", 10, 40),
+		repsWorkout("pushup", 50, 60),
+		repsWorkout("situp", 20, 50),
+		// No run
+	}
+	goal := &store.Goal{Type: store.GoalTypeTotalScore, TargetValue: 270, TargetDate: goalDay(120)}
+
+	p := totalScoreGoalProgress(goal, apft, records, grading.Profile{}, since, goalNow)
+	if len(p.Events) != len(apft.Protocol().Sequence) {
+		t.Fatalf("got %d events, want %d", len(p.Events), len(apft.Protocol().Sequence))
+	}
+	wantCurrent := score("pushup", 60) + score("situp", 50)
+	if p.Current == nil || *p.Current != wantCurrent {
+		t.Errorf("current = %v, want %v", p.Current, wantCurrent)
+	}
+	for _, ev := range p.Events {
+		if ev.Event == grading.ExerciseTypeRun && ev.CurrentScore != nil {
+			t.Errorf("run score = %d, want none", *ev.CurrentScore)
+		}
+	}
+	if p.Status != GoalStatusBehind {
+		t.Errorf("status = %s, want %s", p.Status, GoalStatusBehind)
+	}
+
+	empty := totalScoreGoalProgress(goal, apft, nil, grading.Profile{}, since, goalNow)
+	if empty.Current != nil || empty.Status != GoalStatusBehind {
+		t.Errorf("progress without workouts = %+v, want no current and behind", empty)
+	}
+}
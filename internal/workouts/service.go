@@ -4,12 +4,41 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
+	"strconv"
 	"time"
 
+	"ptchampion/internal/grading"
 	"ptchampion/internal/logging"
 	"ptchampion/internal/store"
+	redis_cache "ptchampion/internal/store/redis"
+	"ptchampion/internal/validation"
 )
 
+// gradeMismatchTolerance is the number of points a client grade may differ from the
+// server-computed grade before the record is marked mismatched. It absorbs rounding
+// differences between client and server score tables.
+const gradeMismatchTolerance int32 = 1
+
+// ErrReplayRepMismatch is returned when replayed pose frames count a different number of reps than the workout claims.
+var ErrReplayRepMismatch = errors.New("replayed rep count does not match submitted reps")
+
+// ErrReplayUnsupported is returned when a workout's exercise cannot be graded from pose frames.
+var ErrReplayUnsupported = errors.New("exercise type does not support pose-frame replay")
+
+// ErrMissingMeasurement is returned when a workout lacks the measurement its exercise is scored on.
+var ErrMissingMeasurement = errors.New("missing workout measurement")
+
+// ErrInvalidMeasurement is returned when a workout's measurements are not valid or not
+// plausible for its exercise.
+var ErrInvalidMeasurement = errors.New("invalid workout measurement")
+
+// ErrEventNotInStandard is returned when a workout names a scoring standard that does not score its exercise.
+var ErrEventNotInStandard = errors.New("scoring standard does not include this exercise")
+
+// ErrInvalidVisibility is returned for a workout visibility other than private, friends or public.
+var ErrInvalidVisibility = errors.New("invalid workout visibility")
+
 // LogWorkoutData defines the data needed to log a workout at the service layer.
 // Updated to support client-side grading as per local grading implementation.
 type LogWorkoutData struct {
@@ -18,10 +47,21 @@ type LogWorkoutData struct {
 	ExerciseName    string // Will be fetched if not provided, based on ExerciseID
 	Reps            *int32
 	DurationSeconds *int32
-	Grade           int32     // Client-calculated APFT score (0-100)
+	WeightLbs       *int32   // Deadlift weight
+	DistanceMeters  *float64 // Power throw distance
+	Grade           int32    // Client-calculated score (0-100) under ScoringStandard
 	CompletedAt     time.Time
 	FormScore       *int32 // Form quality score (0-100)
 	IsPublic        bool   // Whether workout should appear on leaderboard
+	Visibility      string // One of the store.WorkoutVisibility* constants; empty to follow IsPublic
+	ScoringStandard string // Registry ID of the standard the client graded against; empty for the default
+}
+
+// LogWorkoutResult is a logged workout and the personal records it set.
+type LogWorkoutResult struct {
+	Workout         *store.WorkoutRecord
+	PersonalRecords []*store.PersonalRecord // Empty when the workout set no new best
+	Achievements    []*Badge                // Badges the workout earned
 }
 
 // ListWorkoutsFilters defines filter options for listing workouts
@@ -33,79 +73,379 @@ type ListWorkoutsFilters struct {
 
 // Service defines the interface for workout-related business logic.
 type Service interface {
-	LogWorkout(ctx context.Context, userID int32, data *LogWorkoutData) (*store.WorkoutRecord, error)
+	LogWorkout(ctx context.Context, userID int32, data *LogWorkoutData) (*LogWorkoutResult, error)
 	ListUserWorkouts(ctx context.Context, userID int32, page, pageSize int) (*store.PaginatedWorkoutRecords, error)
 	ListUserWorkoutsWithFilters(ctx context.Context, userID int32, page, pageSize int, filters ListWorkoutsFilters) (*store.PaginatedWorkoutRecords, error)
-	UpdateWorkoutVisibility(ctx context.Context, userID int32, workoutID int32, isPublic bool) error
+	UpdateWorkoutVisibility(ctx context.Context, userID int32, workoutID int32, visibility string) error
 	GetDashboardStats(ctx context.Context, userID int32) (*store.DashboardStats, error)
+	ListMismatchedWorkouts(ctx context.Context, page, pageSize int) (*store.PaginatedWorkoutRecords, error)
+	ReplayWorkoutFrames(ctx context.Context, userID int32, workoutID int32, poses []*grading.Pose) (*store.WorkoutRecord, error)
+	CreateTestSession(ctx context.Context, userID int32, data *CreateTestSessionData) (*store.TestSession, error)
+	GetTestSession(ctx context.Context, userID int32, sessionID int32) (*store.TestSession, error)
+	ListUserTestSessions(ctx context.Context, userID int32, page, pageSize int) (*store.PaginatedTestSessions, error)
+	SyncWorkouts(ctx context.Context, userID int32, data *SyncWorkoutsData) (*store.WorkoutSyncResult, error)
+	UpdateWorkout(ctx context.Context, userID int32, workoutID int32, data *UpdateWorkoutData) (*store.WorkoutRecord, error)
+	DeleteWorkout(ctx context.Context, userID int32, workoutID int32) error
+	GetWorkoutAuditLog(ctx context.Context, userID int32, workoutID int32) ([]*store.WorkoutAuditEntry, error)
+	GetPersonalRecords(ctx context.Context, userID int32) ([]*store.PersonalRecord, error)
+	GetPersonalRecordHistory(ctx context.Context, userID int32, exerciseType string, page, pageSize int) (*store.PaginatedPersonalRecords, error)
+	CreateGoal(ctx context.Context, userID int32, data *CreateGoalData) (*GoalProgress, error)
+	ListGoals(ctx context.Context, userID int32) ([]*GoalProgress, error)
+	GetGoal(ctx context.Context, userID int32, goalID int32) (*GoalProgress, error)
+	DeleteGoal(ctx context.Context, userID int32, goalID int32) error
+	GenerateTrainingPlan(ctx context.Context, userID int32, data *GenerateTrainingPlanData) (*TrainingPlanProgress, error)
+	GetTrainingPlan(ctx context.Context, userID int32) (*TrainingPlanProgress, error)
+	ListAchievements(ctx context.Context, userID int32) ([]*BadgeProgress, error)
+	BackfillAchievements(ctx context.Context) (*AchievementBackfillResult, error)
+	GetStreaks(ctx context.Context, userID int32) (*StreakStats, error)
 }
 
 type service struct {
 	workoutStore  store.WorkoutStore
 	exerciseStore store.ExerciseStore // To fetch exercise details if needed
-	logger        logging.Logger
+	userStore     store.UserStore     // To fetch the gender and age used for normed scoring
+	prStore       store.PersonalRecordStore
+	validator     *validation.ExerciseValidator // Checks measurements before workouts are graded
+	// Invalidated when workouts are edited or deleted; nil when Redis is not configured
+	leaderboardCache *redis_cache.LeaderboardCache
+	logger           logging.Logger
 }
 
 // NewService creates a new workout service instance.
-func NewService(workoutStore store.WorkoutStore, exerciseStore store.ExerciseStore, logger logging.Logger) Service {
+func NewService(workoutStore store.WorkoutStore, exerciseStore store.ExerciseStore, userStore store.UserStore, prStore store.PersonalRecordStore, leaderboardCache *redis_cache.LeaderboardCache, logger logging.Logger) Service {
 	return &service{
-		workoutStore:  workoutStore,
-		exerciseStore: exerciseStore,
-		logger:        logger,
+		workoutStore:     workoutStore,
+		exerciseStore:    exerciseStore,
+		userStore:        userStore,
+		prStore:          prStore,
+		validator:        validation.NewExerciseValidator(exerciseStore),
+		leaderboardCache: leaderboardCache,
+		logger:           logger,
 	}
 }
 
 // LogWorkout handles the business logic for logging a new workout record.
 // Updated to accept client-calculated grades as per local grading implementation.
-func (s *service) LogWorkout(ctx context.Context, userID int32, data *LogWorkoutData) (*store.WorkoutRecord, error) {
+// Personal records the workout sets are stored in the same transaction, and the badges
+// it earns are awarded after it.
+func (s *service) LogWorkout(ctx context.Context, userID int32, data *LogWorkoutData) (*LogWorkoutResult, error) {
 	// Validate exercise exists
 	exercise, err := s.exerciseStore.GetExerciseDefinition(ctx, data.ExerciseID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get exercise: %w", err)
 	}
 
-	// Validate required fields based on exercise type
-	switch exercise.Type {
-	case "pushup", "situp", "pullup":
-		if data.Reps == nil {
-			return nil, errors.New("reps required for this exercise type")
-		}
-	case "run":
-		if data.DurationSeconds == nil {
-			return nil, errors.New("duration required for running")
-		}
-	default:
+	// Validate the measurement the exercise is scored on is present
+	metric, err := grading.MetricFor(grading.EventForExerciseType(exercise.Type))
+	if err != nil {
 		return nil, fmt.Errorf("unsupported exercise type: %s", exercise.Type)
 	}
+	if _, ok := performanceValue(exercise.Type, data); !ok {
+		return nil, fmt.Errorf("%s required for this exercise type: %w", metric, ErrMissingMeasurement)
+	}
+	if err := s.validateMeasurements(ctx, data); err != nil {
+		return nil, err
+	}
 
 	// Validate grade is within acceptable range (0-100)
 	if data.Grade < 0 || data.Grade > 100 {
 		return nil, fmt.Errorf("grade must be between 0 and 100, got %d", data.Grade)
 	}
 
-	// Use client-provided grade directly
+	standard, err := resolveStandard(data.ScoringStandard, exercise.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	visibility, err := workoutVisibility(data.Visibility, data.IsPublic)
+	if err != nil {
+		return nil, err
+	}
+
+	// Recompute the grade server-side against the user's age- and gender-normed
+	// standard and flag records the client over- or under-scored
+	profile := s.scoringProfile(ctx, userID, data.CompletedAt)
+	status, expectedGrade, discrepancy := verifyGrade(standard, exercise.Type, data, profile)
+	if status == store.VerificationStatusMismatched {
+		s.logger.Warn(ctx, "Client grade does not match server-computed grade", "userID", userID, "exerciseType", exercise.Type, "clientGrade", data.Grade, "expectedGrade", *expectedGrade)
+	}
+
+	// Store the client-provided grade along with the verification outcome
 	recordToStore := &store.WorkoutRecord{
-		UserID:          userID,
-		ExerciseID:      data.ExerciseID,
-		ExerciseName:    exercise.Name,      // Use name from definition
-		ExerciseType:    exercise.Type,      // Use type from definition
-		Reps:            data.Reps,
-		DurationSeconds: data.DurationSeconds,
-		Grade:           data.Grade,         // Client-calculated APFT score
-		FormScore:       data.FormScore,     // Client-calculated form score
-		CompletedAt:     data.CompletedAt,
-		IsPublic:        data.IsPublic,      // For leaderboard visibility
+		UserID:             userID,
+		ExerciseID:         data.ExerciseID,
+		ExerciseName:       exercise.Name, // Use name from definition
+		ExerciseType:       exercise.Type, // Use type from definition
+		Reps:               data.Reps,
+		DurationSeconds:    data.DurationSeconds,
+		WeightLbs:          data.WeightLbs,
+		DistanceMeters:     data.DistanceMeters,
+		Grade:              data.Grade,     // Client-calculated APFT score
+		FormScore:          data.FormScore, // Client-calculated form score
+		CompletedAt:        data.CompletedAt,
+		IsPublic:           visibility == store.WorkoutVisibilityPublic, // For leaderboard visibility
+		Visibility:         visibility,
+		VerificationStatus: status,
+		ExpectedGrade:      expectedGrade,
+		GradeDiscrepancy:   discrepancy,
+		ScoringStandard:    standard.ID(),
+		ScoringVersion:     standard.Version(),
 		// CreatedAt will be set by the database
 	}
 
-	loggedRecord, err := s.workoutStore.CreateWorkoutRecord(ctx, recordToStore)
+	candidates := personalRecordCandidates(standard, exercise.Type, data, status)
+	loggedRecord, records, err := s.prStore.CreateWorkoutRecordWithPersonalRecords(ctx, recordToStore, candidates)
 	if err != nil {
 		s.logger.Error(ctx, "Failed to create workout record in store", "userID", userID, "exerciseID", data.ExerciseID, "error", err)
 		return nil, fmt.Errorf("failed to save workout record: %w", err)
 	}
 
-	s.logger.Info(ctx, "Workout record logged successfully", "userID", userID, "workoutRecordID", loggedRecord.ID)
-	return loggedRecord, nil
+	// The workout is already stored; badges it misses here are awarded by the next
+	// evaluation or a backfill
+	achievements, err := s.evaluateAchievements(ctx, userID, loggedRecord)
+	if err != nil {
+		s.logger.Warn(ctx, "Failed to evaluate achievements", "userID", userID, "workoutRecordID", loggedRecord.ID, "error", err)
+	}
+
+	s.logger.Info(ctx, "Workout record logged successfully", "userID", userID, "workoutRecordID", loggedRecord.ID, "personalRecords", len(records))
+	return &LogWorkoutResult{Workout: loggedRecord, PersonalRecords: records, Achievements: achievements}, nil
+}
+
+// validateMeasurements checks that a workout's measurements are complete and plausible
+// for its exercise, reporting failures with ErrInvalidMeasurement.
+func (s *service) validateMeasurements(ctx context.Context, data *LogWorkoutData) error {
+	if err := s.validator.ValidateWorkoutData(ctx, data.ExerciseID, data.Reps, data.DurationSeconds, data.WeightLbs, data.DistanceMeters, data.FormScore); err != nil {
+		return fmt.Errorf("%v: %w", err, ErrInvalidMeasurement)
+	}
+	if err := s.validator.ValidateExercisePerformance(ctx, data.ExerciseID, data.Reps, data.DurationSeconds, data.WeightLbs, data.DistanceMeters); err != nil {
+		return fmt.Errorf("%v: %w", err, ErrInvalidMeasurement)
+	}
+	return nil
+}
+
+// personalRecordCandidates returns the workout results that could set a personal record:
+// the measurement the exercise is scored on, in the direction the standard ranks it, and
+// the grade. Grades the server could not confirm are not eligible.
+func personalRecordCandidates(standard grading.ScoringStandard, exerciseType string, data *LogWorkoutData, status string) []store.PersonalRecordCandidate {
+	var candidates []store.PersonalRecordCandidate
+	if value, ok := performanceValue(exerciseType, data); ok && value > 0 {
+		metric, _ := grading.MetricFor(grading.EventForExerciseType(exerciseType))
+		candidates = append(candidates, store.PersonalRecordCandidate{
+			Metric:        string(metric),
+			Value:         value,
+			LowerIsBetter: !higherIsBetter(standard, exerciseType),
+		})
+	}
+	if status != store.VerificationStatusMismatched && data.Grade > 0 {
+		candidates = append(candidates, store.PersonalRecordCandidate{
+			Metric: store.PersonalRecordMetricGrade,
+			Value:  float64(data.Grade),
+		})
+	}
+	return candidates
+}
+
+// storedRecordCandidates returns the personal record candidates of a stored workout,
+// evaluated like a logged one under the standard it was scored by. Workouts whose
+// standard no longer scores the exercise set no records.
+func storedRecordCandidates(record *store.WorkoutRecord) []store.PersonalRecordCandidate {
+	standard, err := resolveStandard(record.ScoringStandard, record.ExerciseType)
+	if err != nil {
+		return nil
+	}
+	data := &LogWorkoutData{
+		Reps:            record.Reps,
+		DurationSeconds: record.DurationSeconds,
+		WeightLbs:       record.WeightLbs,
+		DistanceMeters:  record.DistanceMeters,
+		Grade:           record.Grade,
+	}
+	return personalRecordCandidates(standard, record.ExerciseType, data, record.VerificationStatus)
+}
+
+// higherIsBetter reports whether the standard ranks larger measurements of the exercise higher.
+func higherIsBetter(standard grading.ScoringStandard, exerciseType string) bool {
+	event := grading.EventForExerciseType(exerciseType)
+	for _, e := range standard.Events() {
+		if e.ID == event {
+			return e.HigherIsBetter
+		}
+	}
+	return true
+}
+
+// scoringProfile builds the grading profile for a user as of the given time. When the
+// user cannot be loaded the default (non-normed) profile is returned.
+func (s *service) scoringProfile(ctx context.Context, userID int32, at time.Time) grading.Profile {
+	user, err := s.userStore.GetUserByID(ctx, strconv.Itoa(int(userID)))
+	if err != nil {
+		s.logger.Warn(ctx, "Failed to load user for scoring profile, using default tables", "userID", userID, "error", err)
+		return grading.Profile{}
+	}
+	return grading.NewProfile(user.Gender, user.DateOfBirth, at)
+}
+
+// resolveStandard returns the requested standard, or the default standard for the exercise
+// when none is requested, and checks that it scores the exercise.
+func resolveStandard(requested string, exerciseType string) (grading.ScoringStandard, error) {
+	var standard grading.ScoringStandard
+	var err error
+	if requested == "" {
+		standard, err = grading.DefaultStandardFor(grading.EventForExerciseType(exerciseType))
+	} else {
+		standard, err = grading.LookupStandard(requested)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid scoring standard %q: %w", requested, err)
+	}
+	if !standardScores(standard, exerciseType) {
+		return nil, fmt.Errorf("%s does not score %s: %w", standard.ID(), exerciseType, ErrEventNotInStandard)
+	}
+	return standard, nil
+}
+
+// standardScores reports whether the standard has an event for the exercise type.
+func standardScores(standard grading.ScoringStandard, exerciseType string) bool {
+	event := grading.EventForExerciseType(exerciseType)
+	for _, e := range standard.Events() {
+		if e.ID == event {
+			return true
+		}
+	}
+	return false
+}
+
+// performanceValue returns the measurement a workout is scored on, or false when the
+// exercise type is unknown or the measurement is missing.
+func performanceValue(exerciseType string, data *LogWorkoutData) (float64, bool) {
+	metric, err := grading.MetricFor(grading.EventForExerciseType(exerciseType))
+	if err != nil {
+		return 0, false
+	}
+	return measurementValue(metric, data.Reps, data.DurationSeconds, data.WeightLbs, data.DistanceMeters)
+}
+
+// measurementValue returns the measurement for a metric, or false when it is missing.
+func measurementValue(metric grading.Metric, reps, durationSeconds, weightLbs *int32, distanceMeters *float64) (float64, bool) {
+	switch metric {
+	case grading.MetricReps:
+		if reps != nil {
+			return float64(*reps), true
+		}
+	case grading.MetricDuration:
+		if durationSeconds != nil {
+			return float64(*durationSeconds), true
+		}
+	case grading.MetricWeight:
+		if weightLbs != nil {
+			return float64(*weightLbs), true
+		}
+	case grading.MetricDistance:
+		if distanceMeters != nil {
+			return *distanceMeters, true
+		}
+	}
+	return 0, false
+}
+
+// verifyGrade recomputes the score for a workout from its measurement using the
+// user's normed table in the given standard and compares it against the client-submitted
+// grade. The default table is used only when the profile lacks the gender or age a normed
+// table needs. It returns the verification status together with the expected grade and
+// the discrepancy (client minus expected).
+func verifyGrade(standard grading.ScoringStandard, exerciseType string, data *LogWorkoutData, profile grading.Profile) (string, *int32, *int32) {
+	value, ok := performanceValue(exerciseType, data)
+	if !ok {
+		return store.VerificationStatusUnverified, nil, nil
+	}
+	event := grading.EventForExerciseType(exerciseType)
+
+	score, err := standard.Score(event, value, profile)
+	if err != nil {
+		return store.VerificationStatusUnverified, nil, nil
+	}
+
+	expected := int32(score)
+	discrepancy := data.Grade - expected
+	if withinTolerance(discrepancy) {
+		return store.VerificationStatusVerified, &expected, &discrepancy
+	}
+	return store.VerificationStatusMismatched, &expected, &discrepancy
+}
+
+// withinTolerance reports whether a grade difference is small enough to be accepted.
+func withinTolerance(diff int32) bool {
+	return diff <= gradeMismatchTolerance && diff >= -gradeMismatchTolerance
+}
+
+// ListMismatchedWorkouts retrieves paginated workout records whose client grade failed server-side verification.
+func (s *service) ListMismatchedWorkouts(ctx context.Context, page, pageSize int) (*store.PaginatedWorkoutRecords, error) {
+	s.logger.Debug(ctx, "WorkoutService: ListMismatchedWorkouts called", "page", page, "pageSize", pageSize)
+
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 { // Max page size constraint
+		pageSize = 20 // Default page size
+	}
+	limit := int32(pageSize)
+	offset := int32((page - 1) * pageSize)
+
+	paginatedRecords, err := s.workoutStore.ListMismatchedWorkoutRecords(ctx, limit, offset)
+	if err != nil {
+		s.logger.Error(ctx, "Failed to get mismatched workout records from store", "error", err)
+		return nil, fmt.Errorf("failed to retrieve mismatched workout records: %w", err)
+	}
+
+	s.logger.Info(ctx, "Mismatched workout records retrieved", "count", paginatedRecords.TotalCount)
+	return paginatedRecords, nil
+}
+
+// ReplayWorkoutFrames replays uploaded pose frames through the server-side grader for the
+// workout's exercise and stores the resulting rep count and form score. Submissions whose
+// replayed rep count differs from the workout's reps are rejected with ErrReplayRepMismatch.
+func (s *service) ReplayWorkoutFrames(ctx context.Context, userID int32, workoutID int32, poses []*grading.Pose) (*store.WorkoutRecord, error) {
+	s.logger.Debug(ctx, "WorkoutService: ReplayWorkoutFrames called", "userID", userID, "workoutID", workoutID, "frames", len(poses))
+
+	record, err := s.workoutStore.GetWorkoutRecordByID(ctx, workoutID)
+	if err != nil {
+		if err == store.ErrWorkoutRecordNotFound {
+			s.logger.Warn(ctx, "Workout record not found for frame replay", "workoutID", workoutID)
+			return nil, err
+		}
+		s.logger.Error(ctx, "Failed to get workout record for frame replay", "workoutID", workoutID, "error", err)
+		return nil, fmt.Errorf("failed to retrieve workout record: %w", err)
+	}
+
+	if record.UserID != userID {
+		s.logger.Warn(ctx, "User attempted to upload frames for workout they don't own", "userID", userID, "workoutID", workoutID, "ownerID", record.UserID)
+		return nil, fmt.Errorf("user does not have permission to update this workout record")
+	}
+
+	if _, err := grading.GraderFor(record.ExerciseType); err != nil || record.Reps == nil {
+		return nil, ErrReplayUnsupported
+	}
+
+	replay, err := grading.ReplayPoses(record.ExerciseType, poses)
+	if err != nil {
+		s.logger.Warn(ctx, "Failed to replay pose frames", "workoutID", workoutID, "error", err)
+		return nil, fmt.Errorf("failed to replay pose frames: %w", err)
+	}
+
+	if int32(replay.RepCount) != *record.Reps {
+		s.logger.Warn(ctx, "Replayed rep count does not match submitted reps", "userID", userID, "workoutID", workoutID, "submittedReps", *record.Reps, "replayedReps", replay.RepCount)
+		return nil, ErrReplayRepMismatch
+	}
+
+	formScore := int32(math.Round(replay.FormScore * 100))
+	updated, err := s.workoutStore.SetWorkoutReplayResult(ctx, workoutID, int32(replay.RepCount), formScore)
+	if err != nil {
+		s.logger.Error(ctx, "Failed to store workout replay result", "workoutID", workoutID, "error", err)
+		return nil, fmt.Errorf("failed to store replay result: %w", err)
+	}
+
+	s.logger.Info(ctx, "Workout frames replayed successfully", "userID", userID, "workoutID", workoutID, "reps", replay.RepCount, "formScore", formScore)
+	return updated, nil
 }
 
 // ListUserWorkouts retrieves paginated workout records for a user.
@@ -131,9 +471,16 @@ func (s *service) ListUserWorkouts(ctx context.Context, userID int32, page, page
 	return paginatedRecords, nil
 }
 
-// UpdateWorkoutVisibility handles the business logic for updating a workout's public visibility.
-func (s *service) UpdateWorkoutVisibility(ctx context.Context, userID int32, workoutID int32, isPublic bool) error {
-	s.logger.Debug(ctx, "WorkoutService: UpdateWorkoutVisibility called", "userID", userID, "workoutID", workoutID, "isPublic", isPublic)
+// UpdateWorkoutVisibility handles the business logic for updating a workout's visibility.
+func (s *service) UpdateWorkoutVisibility(ctx context.Context, userID int32, workoutID int32, visibility string) error {
+	s.logger.Debug(ctx, "WorkoutService: UpdateWorkoutVisibility called", "userID", userID, "workoutID", workoutID, "visibility", visibility)
+
+	if visibility == "" {
+		return fmt.Errorf("%w: visibility is required", ErrInvalidVisibility)
+	}
+	if _, err := workoutVisibility(visibility, false); err != nil {
+		return err
+	}
 
 	// 1. Verify the user owns the workout record
 	record, err := s.workoutStore.GetWorkoutRecordByID(ctx, workoutID)
@@ -152,16 +499,33 @@ func (s *service) UpdateWorkoutVisibility(ctx context.Context, userID int32, wor
 	}
 
 	// 2. Call the store to update visibility
-	err = s.workoutStore.UpdateWorkoutVisibility(ctx, userID, workoutID, isPublic)
+	err = s.workoutStore.UpdateWorkoutVisibility(ctx, userID, workoutID, visibility)
 	if err != nil {
 		s.logger.Error(ctx, "Failed to update workout visibility in store", "userID", userID, "workoutID", workoutID, "error", err)
 		return fmt.Errorf("failed to update workout visibility: %w", err)
 	}
 
-	s.logger.Info(ctx, "Workout visibility updated successfully", "userID", userID, "workoutID", workoutID, "isPublic", isPublic)
+	s.invalidateLeaderboards(ctx, userID)
+	s.logger.Info(ctx, "Workout visibility updated successfully", "userID", userID, "workoutID", workoutID, "visibility", visibility)
 	return nil
 }
 
+// workoutVisibility validates a requested visibility, falling back to public or private
+// from isPublic when none is given.
+func workoutVisibility(visibility string, isPublic bool) (string, error) {
+	switch visibility {
+	case store.WorkoutVisibilityPrivate, store.WorkoutVisibilityFriends, store.WorkoutVisibilityPublic:
+		return visibility, nil
+	case "":
+		if isPublic {
+			return store.WorkoutVisibilityPublic, nil
+		}
+		return store.WorkoutVisibilityPrivate, nil
+	default:
+		return "", fmt.Errorf("%w: %q", ErrInvalidVisibility, visibility)
+	}
+}
+
 // ListUserWorkoutsWithFilters retrieves paginated workout records for a user with filters.
 func (s *service) ListUserWorkoutsWithFilters(ctx context.Context, userID int32, page, pageSize int, filters ListWorkoutsFilters) (*store.PaginatedWorkoutRecords, error) {
 	s.logger.Debug(ctx, "WorkoutService: ListUserWorkoutsWithFilters called", "userID", userID, "page", page, "pageSize", pageSize, "filters", filters)
@@ -205,3 +569,33 @@ func (s *service) GetDashboardStats(ctx context.Context, userID int32) (*store.D
 	s.logger.Info(ctx, "Dashboard stats retrieved", "userID", userID, "totalWorkouts", stats.TotalWorkouts)
 	return stats, nil
 }
+
+// GetPersonalRecords returns the user's current best per exercise type and metric.
+func (s *service) GetPersonalRecords(ctx context.Context, userID int32) ([]*store.PersonalRecord, error) {
+	records, err := s.prStore.GetUserPersonalRecords(ctx, userID)
+	if err != nil {
+		s.logger.Error(ctx, "Failed to get personal records", "userID", userID, "error", err)
+		return nil, fmt.Errorf("failed to retrieve personal records: %w", err)
+	}
+	return records, nil
+}
+
+// GetPersonalRecordHistory retrieves a page of every record the user has set, newest first,
+// optionally limited to one exercise type.
+func (s *service) GetPersonalRecordHistory(ctx context.Context, userID int32, exerciseType string, page, pageSize int) (*store.PaginatedPersonalRecords, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 { // Max page size constraint
+		pageSize = 20 // Default page size
+	}
+	limit := int32(pageSize)
+	offset := int32((page - 1) * pageSize)
+
+	history, err := s.prStore.GetPersonalRecordHistory(ctx, userID, exerciseType, limit, offset)
+	if err != nil {
+		s.logger.Error(ctx, "Failed to get personal record history", "userID", userID, "exerciseType", exerciseType, "error", err)
+		return nil, fmt.Errorf("failed to retrieve personal record history: %w", err)
+	}
+	return history, nil
+}
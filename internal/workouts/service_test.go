@@ -0,0 +1,317 @@
+package workouts
+
+import (
+	"errors"
+	"testing"
+
+	"ptchampion/internal/grading"
+	"ptchampion/internal/store"
+)
+
+func int32Ptr(v int32) *int32 { return &v }
+
+func float64Ptr(v float64) *float64 { return &v }
+
+func TestVerifyGrade(t *testing.T) {
+	runScore := int32(grading.CalculateRunScore(780))
+
+	tests := []struct {
+		name            string
+		standard        string
+		exerciseType    string
+		reps            *int32
+		durationSeconds *int32
+		weightLbs       *int32
+		distanceMeters  *float64
+		clientGrade     int32
+		profile         grading.Profile
+		wantStatus      string
+		wantExpected    *int32
+		wantDiscrepancy *int32
+	}{
+		{
+			name:            "matching pushup grade",
+			exerciseType:    "pushup",
+			reps:            int32Ptr(68),
+			clientGrade:     100,
+			wantStatus:      store.VerificationStatusVerified,
+			wantExpected:    int32Ptr(100),
+			wantDiscrepancy: int32Ptr(0),
+		},
+		{
+			name:            "inflated grade with one rep",
+			exerciseType:    "pushup",
+			reps:            int32Ptr(1),
+			clientGrade:     100,
+			wantStatus:      store.VerificationStatusMismatched,
+			wantExpected:    int32Ptr(1),
+			wantDiscrepancy: int32Ptr(99),
+		},
+		{
+			name:            "within tolerance",
+			exerciseType:    "pullup",
+			reps:            int32Ptr(10),
+			clientGrade:     41,
+			wantStatus:      store.VerificationStatusVerified,
+			wantExpected:    int32Ptr(40),
+			wantDiscrepancy: int32Ptr(1),
+		},
+		{
+			name:            "run graded from duration",
+			exerciseType:    "running",
+			durationSeconds: int32Ptr(780),
+			clientGrade:     runScore,
+			wantStatus:      store.VerificationStatusVerified,
+			wantExpected:    int32Ptr(runScore),
+			wantDiscrepancy: int32Ptr(0),
+		},
+		{
+			name:            "female normed grade",
+			exerciseType:    "pushup",
+			reps:            int32Ptr(46),
+			clientGrade:     100,
+			profile:         grading.Profile{Gender: grading.GenderFemale, Age: 24},
+			wantStatus:      store.VerificationStatusVerified,
+			wantExpected:    int32Ptr(100),
+			wantDiscrepancy: int32Ptr(0),
+		},
+		{
+			name:            "default table grade for normed profile",
+			exerciseType:    "pushup",
+			reps:            int32Ptr(46),
+			clientGrade:     68,
+			profile:         grading.Profile{Gender: grading.GenderFemale, Age: 24},
+			wantStatus:      store.VerificationStatusMismatched,
+			wantExpected:    int32Ptr(100),
+			wantDiscrepancy: int32Ptr(-32),
+		},
+		{
+			name:            "default table without age",
+			exerciseType:    "pushup",
+			reps:            int32Ptr(46),
+			clientGrade:     68,
+			profile:         grading.Profile{Gender: grading.GenderFemale},
+			wantStatus:      store.VerificationStatusVerified,
+			wantExpected:    int32Ptr(68),
+			wantDiscrepancy: int32Ptr(0),
+		},
+		{
+			name:            "normed profile does not excuse inflated grade",
+			exerciseType:    "pushup",
+			reps:            int32Ptr(1),
+			clientGrade:     100,
+			profile:         grading.Profile{Gender: grading.GenderMale, Age: 19},
+			wantStatus:      store.VerificationStatusMismatched,
+			wantExpected:    int32Ptr(3),
+			wantDiscrepancy: int32Ptr(97),
+		},
+		{
+			name:            "acft deadlift graded from weight",
+			standard:        "acft-2022",
+			exerciseType:    "deadlift",
+			weightLbs:       int32Ptr(240),
+			clientGrade:     80,
+			profile:         grading.Profile{Gender: grading.GenderMale, Age: 19},
+			wantStatus:      store.VerificationStatusVerified,
+			wantExpected:    int32Ptr(80),
+			wantDiscrepancy: int32Ptr(0),
+		},
+		{
+			name:            "acft power throw graded from distance",
+			standard:        "acft-2022",
+			exerciseType:    "power_throw",
+			distanceMeters:  float64Ptr(12.6),
+			clientGrade:     60,
+			profile:         grading.Profile{Gender: grading.GenderMale, Age: 19},
+			wantStatus:      store.VerificationStatusMismatched,
+			wantExpected:    int32Ptr(100),
+			wantDiscrepancy: int32Ptr(-40),
+		},
+		{
+			name:         "missing weight left unverified",
+			standard:     "acft-2022",
+			exerciseType: "deadlift",
+			reps:         int32Ptr(3),
+			clientGrade:  60,
+			wantStatus:   store.VerificationStatusUnverified,
+		},
+		{
+			name:         "missing reps left unverified",
+			exerciseType: "situp",
+			clientGrade:  50,
+			wantStatus:   store.VerificationStatusUnverified,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			standard := grading.DefaultStandard()
+			if tt.standard != "" {
+				var err error
+				if standard, err = grading.LookupStandard(tt.standard); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			}
+			data := &LogWorkoutData{
+				Reps:            tt.reps,
+				DurationSeconds: tt.durationSeconds,
+				WeightLbs:       tt.weightLbs,
+				DistanceMeters:  tt.distanceMeters,
+				Grade:           tt.clientGrade,
+			}
+			status, expected, discrepancy := verifyGrade(standard, tt.exerciseType, data, tt.profile)
+			if status != tt.wantStatus {
+				t.Errorf("status = %q, want %q", status, tt.wantStatus)
+			}
+			if !equalInt32Ptr(expected, tt.wantExpected) {
+				t.Errorf("expected grade = %v, want %v", derefInt32(expected), derefInt32(tt.wantExpected))
+			}
+			if !equalInt32Ptr(discrepancy, tt.wantDiscrepancy) {
+				t.Errorf("discrepancy = %v, want %v", derefInt32(discrepancy), derefInt32(tt.wantDiscrepancy))
+			}
+		})
+	}
+}
+
+func equalInt32Ptr(a, b *int32) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func derefInt32(p *int32) interface{} {
+	if p == nil {
+		return nil
+	}
+	return *p
+}
+
+func TestStandardScores(t *testing.T) {
+	apft := grading.DefaultStandard()
+	usmc, err := grading.LookupStandard("usmc-pft-2022")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !standardScores(apft, "running") {
+		t.Error("apft should score the stored running type")
+	}
+	if standardScores(usmc, "situp") {
+		t.Error("usmc pft should not score sit-ups")
+	}
+	if !standardScores(usmc, "pullup") {
+		t.Error("usmc pft should score pull-ups")
+	}
+}
+
+func TestPersonalRecordCandidates(t *testing.T) {
+	apft := grading.DefaultStandard()
+	acft, err := grading.LookupStandard("acft-2022")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		standard     grading.ScoringStandard
+		exerciseType string
+		data         *LogWorkoutData
+		status       string
+		want         []store.PersonalRecordCandidate
+	}{
+		{
+			name:         "reps and grade",
+			standard:     apft,
+			exerciseType: "pushup",
+			data:         &LogWorkoutData{Reps: int32Ptr(50), Grade: 70},
+			status:       store.VerificationStatusVerified,
+			want: []store.PersonalRecordCandidate{
+				{Metric: "reps", Value: 50},
+				{Metric: store.PersonalRecordMetricGrade, Value: 70},
+			},
+		},
+		{
+			name:         "fastest run",
+			standard:     apft,
+			exerciseType: "running",
+			data:         &LogWorkoutData{DurationSeconds: int32Ptr(780), Grade: 80},
+			status:       store.VerificationStatusVerified,
+			want: []store.PersonalRecordCandidate{
+				{Metric: "duration_seconds", Value: 780, LowerIsBetter: true},
+				{Metric: store.PersonalRecordMetricGrade, Value: 80},
+			},
+		},
+		{
+			name:         "longest plank",
+			standard:     acft,
+			exerciseType: "plank",
+			data:         &LogWorkoutData{DurationSeconds: int32Ptr(200), Grade: 90},
+			status:       store.VerificationStatusVerified,
+			want: []store.PersonalRecordCandidate{
+				{Metric: "duration_seconds", Value: 200},
+				{Metric: store.PersonalRecordMetricGrade, Value: 90},
+			},
+		},
+		{
+			name:         "mismatched grade is not eligible",
+			standard:     apft,
+			exerciseType: "situp",
+			data:         &LogWorkoutData{Reps: int32Ptr(40), Grade: 100},
+			status:       store.VerificationStatusMismatched,
+			want: []store.PersonalRecordCandidate{
+				{Metric: "reps", Value: 40},
+			},
+		},
+		{
+			name:         "zero reps and grade",
+			standard:     apft,
+			exerciseType: "pushup",
+			data:         &LogWorkoutData{Reps: int32Ptr(0), Grade: 0},
+			status:       store.VerificationStatusVerified,
+			want:         nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := personalRecordCandidates(tt.standard, tt.exerciseType, tt.data, tt.status)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d candidates %+v, want %d", len(got), got, len(tt.want))
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("candidate %d = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestWorkoutVisibility(t *testing.T) {
+	tests := []struct {
+		name       string
+		visibility string
+		isPublic   bool
+		want       string
+		wantErr    bool
+	}{
+		{name: "public from is_public", isPublic: true, want: store.WorkoutVisibilityPublic},
+		{name: "private from is_public", want: store.WorkoutVisibilityPrivate},
+		{name: "friends overrides is_public", visibility: store.WorkoutVisibilityFriends, isPublic: true, want: store.WorkoutVisibilityFriends},
+		{name: "explicit private", visibility: store.WorkoutVisibilityPrivate, isPublic: true, want: store.WorkoutVisibilityPrivate},
+		{name: "unknown", visibility: "unit", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := workoutVisibility(tt.visibility, tt.isPublic)
+			if tt.wantErr != errors.Is(err, ErrInvalidVisibility) {
+				t.Fatalf("workoutVisibility(%q, %v) error = %v, wantErr %v", tt.visibility, tt.isPublic, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("workoutVisibility(%q, %v) = %q, want %q", tt.visibility, tt.isPublic, got, tt.want)
+			}
+		})
+	}
+}
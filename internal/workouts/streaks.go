@@ -0,0 +1,163 @@
+package workouts
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Streak freeze rules: a day without a workout inside a daily streak spends a banked
+// freeze instead of breaking the streak. A freeze is earned for every streakFreezeEarnDays
+// active days in a streak, and at most maxStreakFreezes are banked. Frozen days keep the
+// streak alive but do not add to its length.
+const (
+	streakFreezeEarnDays = 7
+	maxStreakFreezes     = 2
+)
+
+// DailyStreak is a user's run of consecutive days with a workout.
+type DailyStreak struct {
+	Current          int // Active days in the streak still alive today; 0 when broken
+	Longest          int
+	FreezesAvailable int // Banked freezes the current streak can spend
+	FreezesUsed      int // Freezes the current streak has spent
+	LastActiveDate   *time.Time
+}
+
+// WeeklyStreak is a user's run of consecutive weeks, Monday to Sunday, with a workout.
+type WeeklyStreak struct {
+	Current int // Weeks in the streak still alive this week; 0 when broken
+	Longest int
+}
+
+// StreakStats are a user's streaks, counted in calendar days of their timezone.
+type StreakStats struct {
+	Timezone string
+	Today    time.Time // The user's local calendar date
+	Daily    DailyStreak
+	Weekly   WeeklyStreak
+}
+
+// GetStreaks returns the user's daily and weekly streaks as of now in their timezone.
+func (s *service) GetStreaks(ctx context.Context, userID int32) (*StreakStats, error) {
+	days, err := s.workoutStore.ListUserActiveDays(ctx, userID)
+	if err != nil {
+		s.logger.Error(ctx, "Failed to list active days", "userID", userID, "error", err)
+		return nil, fmt.Errorf("failed to retrieve workout days: %w", err)
+	}
+
+	loc := s.userLocation(ctx, userID)
+	today := localDate(time.Now().In(loc))
+	for i, day := range days {
+		days[i] = localDate(day)
+	}
+	return &StreakStats{
+		Timezone: loc.String(),
+		Today:    today,
+		Daily:    dailyStreak(days, today),
+		Weekly:   weeklyStreak(days, today),
+	}, nil
+}
+
+// userLocation returns the user's timezone. When the user cannot be loaded or their
+// timezone is unknown, UTC is returned.
+func (s *service) userLocation(ctx context.Context, userID int32) *time.Location {
+	user, err := s.userStore.GetUserByID(ctx, strconv.Itoa(int(userID)))
+	if err != nil {
+		s.logger.Warn(ctx, "Failed to load user for timezone, using UTC", "userID", userID, "error", err)
+		return time.UTC
+	}
+	if user.Timezone == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(user.Timezone)
+	if err != nil {
+		s.logger.Warn(ctx, "Unknown user timezone, using UTC", "userID", userID, "timezone", user.Timezone, "error", err)
+		return time.UTC
+	}
+	return loc
+}
+
+// localDate returns the calendar date of t in its own location, as midnight UTC so dates
+// from different zones compare and step by whole days.
+func localDate(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// weekStart returns the Monday of the week containing the date.
+func weekStart(date time.Time) time.Time {
+	return date.AddDate(0, 0, -((int(date.Weekday()) + 6) % 7))
+}
+
+// dailyStreak walks the calendar from the first active day to today, applying the freeze
+// rules. days are distinct local dates in ascending order. Today counts only once it has a
+// workout; until it ends, a streak that reached yesterday is still current.
+func dailyStreak(days []time.Time, today time.Time) DailyStreak {
+	var streak DailyStreak
+	if len(days) == 0 {
+		return streak
+	}
+	active := make(map[time.Time]bool, len(days))
+	for _, day := range days {
+		active[day] = true
+	}
+
+	earning := 0 // Active days toward the next freeze
+	for day := days[0]; !day.After(today); day = day.AddDate(0, 0, 1) {
+		switch {
+		case active[day]:
+			streak.Current++
+			if streak.Current > streak.Longest {
+				streak.Longest = streak.Current
+			}
+			if earning++; earning == streakFreezeEarnDays {
+				earning = 0
+				if streak.FreezesAvailable < maxStreakFreezes {
+					streak.FreezesAvailable++
+				}
+			}
+		case day.Equal(today):
+			// Today is not over yet
+		case streak.Current > 0 && streak.FreezesAvailable > 0:
+			streak.FreezesAvailable--
+			streak.FreezesUsed++
+		default:
+			streak.Current, streak.FreezesUsed, earning = 0, 0, 0
+		}
+	}
+
+	last := days[len(days)-1]
+	streak.LastActiveDate = &last
+	return streak
+}
+
+// weeklyStreak counts consecutive weeks with a workout up to the week containing today,
+// which counts only once it has a workout.
+func weeklyStreak(days []time.Time, today time.Time) WeeklyStreak {
+	var streak WeeklyStreak
+	if len(days) == 0 {
+		return streak
+	}
+	active := make(map[time.Time]bool)
+	for _, day := range days {
+		active[weekStart(day)] = true
+	}
+
+	thisWeek := weekStart(today)
+	for week := weekStart(days[0]); !week.After(thisWeek); week = week.AddDate(0, 0, 7) {
+		switch {
+		case active[week]:
+			streak.Current++
+			if streak.Current > streak.Longest {
+				streak.Longest = streak.Current
+			}
+		case week.Equal(thisWeek):
+			// This week is not over yet
+		default:
+			streak.Current = 0
+		}
+	}
+	return streak
+}
@@ -0,0 +1,102 @@
+package workouts
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDailyStreak(t *testing.T) {
+	day := func(n int) time.Time { return activeDays(n)[0] }
+
+	tests := []struct {
+		name        string
+		days        []time.Time
+		today       time.Time
+		wantCurrent int
+		wantLongest int
+		wantFreezes int
+		wantUsed    int
+	}{
+		{name: "no workouts", days: nil, today: day(0)},
+		{name: "active today", days: activeDays(0, 1, 2), today: day(2), wantCurrent: 3, wantLongest: 3},
+		{name: "today still open", days: activeDays(0, 1, 2), today: day(3), wantCurrent: 3, wantLongest: 3},
+		{name: "missed yesterday without freeze", days: activeDays(0, 1, 2), today: day(4), wantCurrent: 0, wantLongest: 3},
+		{name: "seven days earn a freeze", days: activeDays(0, 1, 2, 3, 4, 5, 6), today: day(6), wantCurrent: 7, wantLongest: 7, wantFreezes: 1},
+		{
+			name:  "freeze bridges a missed day",
+			days:  activeDays(0, 1, 2, 3, 4, 5, 6, 8, 9),
+			today: day(9), wantCurrent: 9, wantLongest: 9, wantFreezes: 0, wantUsed: 1,
+		},
+		{
+			name:  "second missed day breaks the streak",
+			days:  activeDays(0, 1, 2, 3, 4, 5, 6, 9),
+			today: day(9), wantCurrent: 1, wantLongest: 7,
+		},
+		{
+			name:  "banked freezes are capped",
+			days:  activeDays(0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20),
+			today: day(20), wantCurrent: 21, wantLongest: 21, wantFreezes: maxStreakFreezes,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := dailyStreak(tt.days, tt.today)
+			if got.Current != tt.wantCurrent || got.Longest != tt.wantLongest {
+				t.Errorf("dailyStreak() current/longest = %d/%d, want %d/%d", got.Current, got.Longest, tt.wantCurrent, tt.wantLongest)
+			}
+			if got.FreezesAvailable != tt.wantFreezes || got.FreezesUsed != tt.wantUsed {
+				t.Errorf("dailyStreak() freezes available/used = %d/%d, want %d/%d", got.FreezesAvailable, got.FreezesUsed, tt.wantFreezes, tt.wantUsed)
+			}
+		})
+	}
+}
+
+func TestWeeklyStreak(t *testing.T) {
+	// 2025-07-01 is a Tuesday, so day 6 is the first Monday
+	day := func(n int) time.Time { return activeDays(n)[0] }
+
+	tests := []struct {
+		name        string
+		days        []time.Time
+		today       time.Time
+		wantCurrent int
+		wantLongest int
+	}{
+		{name: "no workouts", days: nil, today: day(0)},
+		{name: "same week", days: activeDays(0, 4), today: day(5), wantCurrent: 1, wantLongest: 1},
+		{name: "consecutive weeks", days: activeDays(0, 6, 13), today: day(13), wantCurrent: 3, wantLongest: 3},
+		{name: "this week still open", days: activeDays(0, 6), today: day(13), wantCurrent: 2, wantLongest: 2},
+		{name: "missed week breaks", days: activeDays(0, 6, 20), today: day(20), wantCurrent: 1, wantLongest: 2},
+		{name: "missed last week", days: activeDays(0, 6), today: day(20), wantCurrent: 0, wantLongest: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := weeklyStreak(tt.days, tt.today)
+			if got.Current != tt.wantCurrent || got.Longest != tt.wantLongest {
+				t.Errorf("weeklyStreak() current/longest = %d/%d, want %d/%d", got.Current, got.Longest, tt.wantCurrent, tt.wantLongest)
+			}
+		})
+	}
+}
+
+func TestLocalDate(t *testing.T) {
+	// 15:30 UTC on July 1 is already July 2 in Seoul and still July 1 in Berlin
+	instant := time.Date(2025, 7, 1, 15, 30, 0, 0, time.UTC)
+	seoul, err := time.LoadLocation("Asia/Seoul")
+	if err != nil {
+		t.Skipf("timezone data unavailable: %v", err)
+	}
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("timezone data unavailable: %v", err)
+	}
+
+	if got, want := localDate(instant.In(seoul)), time.Date(2025, 7, 2, 0, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("localDate() in Seoul = %v, want %v", got, want)
+	}
+	if got, want := localDate(instant.In(berlin)), time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("localDate() in Berlin = %v, want %v", got, want)
+	}
+}
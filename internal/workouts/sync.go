@@ -0,0 +1,217 @@
+package workouts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"ptchampion/internal/grading"
+	"ptchampion/internal/store"
+)
+
+// syncCursorOverlap widens each pull to before the client's cursor. A write whose
+// transaction started before the previous sync but committed after it is stamped
+// earlier than that cursor; re-sending a few seconds of changes keeps it from being
+// skipped. Clients apply pulled records by ID, so the overlap is harmless.
+const syncCursorOverlap = 5 * time.Second
+
+// ErrInvalidSyncRecord marks a pushed record that cannot be stored or graded. Such
+// records are reported as rejected rather than failing the batch.
+var ErrInvalidSyncRecord = errors.New("invalid sync record")
+
+// SyncPushData defines one pushed record at the service layer.
+type SyncPushData struct {
+	ClientID       string
+	WorkoutID      int32           // Server ID of a pulled record; 0 for a record the device created
+	BaseVersion    int32           // Server version the edit is based on; 0 for a record created on the client
+	Deleted        bool            // Delete the record; Workout is ignored
+	KeepVisibility bool            // The client sent no visibility; an edit keeps the stored one
+	Workout        *LogWorkoutData // Record contents for creates and edits; grades are computed server-side
+}
+
+// SyncWorkoutsData defines a sync exchange at the service layer.
+type SyncWorkoutsData struct {
+	DeviceID     string
+	LastSyncedAt *time.Time // Client's cursor from its previous sync; nil on first sync
+	Pushes       []*SyncPushData
+}
+
+// SyncWorkouts applies the creates, edits and deletes an offline client pushes and
+// returns an outcome per pushed record plus the records changed since its last sync.
+// Conflicts are resolved by store.ResolveWorkoutSync; grades are always computed by
+// the server from each record's measurement. Invalid records are rejected with a
+// reason without affecting the rest of the batch. Badges the created workouts earn are
+// awarded after the batch is stored.
+func (s *service) SyncWorkouts(ctx context.Context, userID int32, data *SyncWorkoutsData) (*store.WorkoutSyncResult, error) {
+	s.logger.Debug(ctx, "WorkoutService: SyncWorkouts called", "userID", userID, "deviceID", data.DeviceID, "pushed", len(data.Pushes))
+
+	batch := &store.WorkoutSyncBatch{
+		UserID:                   userID,
+		DeviceID:                 data.DeviceID,
+		Pushes:                   make([]*store.WorkoutSyncPush, 0, len(data.Pushes)),
+		PersonalRecordCandidates: storedRecordCandidates,
+	}
+	if data.LastSyncedAt != nil {
+		since := data.LastSyncedAt.Add(-syncCursorOverlap)
+		batch.Since = &since
+	}
+
+	// Rejected records keep their slot so outcomes stay in request order
+	outcomes := make([]*store.WorkoutSyncOutcome, len(data.Pushes))
+	for i, push := range data.Pushes {
+		storePush, err := s.buildSyncPush(ctx, userID, push)
+		if errors.Is(err, ErrInvalidSyncRecord) {
+			s.logger.Warn(ctx, "Rejected pushed workout", "userID", userID, "deviceID", data.DeviceID, "clientID", push.ClientID, "error", err)
+			outcomes[i] = &store.WorkoutSyncOutcome{
+				ClientID: push.ClientID,
+				Status:   store.SyncStatusRejected,
+				Reason:   err.Error(),
+			}
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("record %d (client ID %q): %w", i, push.ClientID, err)
+		}
+		batch.Pushes = append(batch.Pushes, storePush)
+	}
+
+	result, err := s.workoutStore.SyncWorkoutRecords(ctx, batch)
+	if err != nil {
+		s.logger.Error(ctx, "Failed to sync workout records in store", "userID", userID, "deviceID", data.DeviceID, "error", err)
+		return nil, fmt.Errorf("failed to sync workout records: %w", err)
+	}
+
+	stored := result.Outcomes
+	edited := false
+	var latest *store.WorkoutRecord // Most recently completed workout the batch created
+	for i := range outcomes {
+		if outcomes[i] == nil {
+			outcomes[i], stored = stored[0], stored[1:]
+			edited = edited || outcomes[i].Status == store.SyncStatusUpdated
+			if record := outcomes[i].Record; outcomes[i].Status == store.SyncStatusCreated && record != nil &&
+				(latest == nil || record.CompletedAt.After(latest.CompletedAt)) {
+				latest = record
+			}
+		}
+	}
+	result.Outcomes = outcomes
+	if edited {
+		s.invalidateLeaderboards(ctx, userID)
+	}
+
+	// Created workouts earn badges like logged ones; the workouts are already stored, so
+	// badges missed here are awarded by the next evaluation or a backfill
+	if latest != nil {
+		if _, err := s.evaluateAchievements(ctx, userID, latest); err != nil {
+			s.logger.Warn(ctx, "Failed to evaluate achievements", "userID", userID, "deviceID", data.DeviceID, "error", err)
+		}
+	}
+
+	s.logger.Info(ctx, "Workout records synced", "userID", userID, "deviceID", data.DeviceID, "pushed", len(result.Outcomes), "changes", len(result.Changes))
+	return result, nil
+}
+
+// buildSyncPush validates a pushed record and grades its contents. Records that
+// cannot be stored are reported with ErrInvalidSyncRecord.
+func (s *service) buildSyncPush(ctx context.Context, userID int32, data *SyncPushData) (*store.WorkoutSyncPush, error) {
+	if data.ClientID == "" {
+		return nil, fmt.Errorf("client ID is required: %w", ErrInvalidSyncRecord)
+	}
+	push := &store.WorkoutSyncPush{
+		WorkoutID:      data.WorkoutID,
+		ClientID:       data.ClientID,
+		BaseVersion:    data.BaseVersion,
+		Deleted:        data.Deleted,
+		KeepVisibility: data.KeepVisibility,
+	}
+	if data.Deleted {
+		return push, nil
+	}
+	if data.Workout == nil {
+		return nil, fmt.Errorf("workout is required unless deleting: %w", ErrInvalidSyncRecord)
+	}
+
+	record, err := s.gradeSyncRecord(ctx, userID, data.Workout)
+	if err != nil {
+		return nil, err
+	}
+	record.ClientID = data.ClientID
+	push.Record = record
+	return push, nil
+}
+
+// gradeSyncRecord builds the record to store for a pushed workout, graded by the server
+// under the requested standard or the default one for its exercise. Records that cannot
+// be graded are reported with ErrInvalidSyncRecord.
+func (s *service) gradeSyncRecord(ctx context.Context, userID int32, data *LogWorkoutData) (*store.WorkoutRecord, error) {
+	if data.CompletedAt.IsZero() {
+		return nil, fmt.Errorf("completion time is required: %w", ErrInvalidSyncRecord)
+	}
+
+	exercise, err := s.exerciseStore.GetExerciseDefinition(ctx, data.ExerciseID)
+	if err == store.ErrExerciseNotFound {
+		return nil, fmt.Errorf("exercise %d not found: %w", data.ExerciseID, ErrInvalidSyncRecord)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get exercise: %w", err)
+	}
+	value, ok := performanceValue(exercise.Type, data)
+	if !ok {
+		metric, _ := grading.MetricFor(grading.EventForExerciseType(exercise.Type))
+		return nil, fmt.Errorf("%s required for %s: %w", metric, exercise.Type, ErrInvalidSyncRecord)
+	}
+	if err := s.validateMeasurements(ctx, data); err != nil {
+		return nil, fmt.Errorf("%v: %w", err, ErrInvalidSyncRecord)
+	}
+	standard, err := resolveStandard(data.ScoringStandard, exercise.Type)
+	if err != nil {
+		return nil, fmt.Errorf("%v: %w", err, ErrInvalidSyncRecord)
+	}
+	visibility, err := workoutVisibility(data.Visibility, data.IsPublic)
+	if err != nil {
+		return nil, fmt.Errorf("%v: %w", err, ErrInvalidSyncRecord)
+	}
+
+	record, err := s.serverGradedRecord(ctx, userID, exercise, standard, value, data)
+	if err != nil {
+		return nil, fmt.Errorf("%v: %w", err, ErrInvalidSyncRecord)
+	}
+	record.Visibility = visibility
+	record.IsPublic = visibility == store.WorkoutVisibilityPublic
+	return record, nil
+}
+
+// serverGradedRecord builds the record to store for a workout whose grade is computed by
+// the server from its measurement value rather than taken from the client.
+func (s *service) serverGradedRecord(ctx context.Context, userID int32, exercise *store.Exercise, standard grading.ScoringStandard, value float64, data *LogWorkoutData) (*store.WorkoutRecord, error) {
+	profile := s.scoringProfile(ctx, userID, data.CompletedAt)
+	score, err := standard.Score(grading.EventForExerciseType(exercise.Type), value, profile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to score %s: %v", exercise.Type, err)
+	}
+
+	// The stored grade is the server's, so it always matches the expected grade
+	grade := int32(score)
+	discrepancy := int32(0)
+	return &store.WorkoutRecord{
+		UserID:             userID,
+		ExerciseID:         data.ExerciseID,
+		ExerciseName:       exercise.Name,
+		ExerciseType:       exercise.Type,
+		Reps:               data.Reps,
+		DurationSeconds:    data.DurationSeconds,
+		WeightLbs:          data.WeightLbs,
+		DistanceMeters:     data.DistanceMeters,
+		Grade:              grade,
+		FormScore:          data.FormScore,
+		CompletedAt:        data.CompletedAt,
+		IsPublic:           data.IsPublic,
+		Visibility:         data.Visibility,
+		VerificationStatus: store.VerificationStatusVerified,
+		ExpectedGrade:      &grade,
+		GradeDiscrepancy:   &discrepancy,
+		ScoringStandard:    standard.ID(),
+		ScoringVersion:     standard.Version(),
+	}, nil
+}
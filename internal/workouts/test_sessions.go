@@ -0,0 +1,248 @@
+package workouts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"ptchampion/internal/grading"
+	"ptchampion/internal/store"
+)
+
+// ErrTestEventOrder is returned when test events are missing, extra or taken out of the standard's order.
+var ErrTestEventOrder = errors.New("test events do not follow the standard's event order")
+
+// ErrTestRestInterval is returned when the rest between two test events is outside the standard's limits.
+var ErrTestRestInterval = errors.New("rest between test events is outside the allowed interval")
+
+// ErrTestSessionSpansDays is returned when the events of a test session are not taken on one day.
+var ErrTestSessionSpansDays = errors.New("test events must be taken on one day")
+
+// TestEventData is one event of a test session as performed by the athlete.
+type TestEventData struct {
+	ExerciseID      int32
+	Reps            *int32
+	DurationSeconds *int32
+	WeightLbs       *int32
+	DistanceMeters  *float64
+	FormScore       *int32
+	StartedAt       time.Time
+	CompletedAt     time.Time
+}
+
+// CreateTestSessionData defines a full test submitted at the service layer. Events
+// are listed in the order they were taken.
+type CreateTestSessionData struct {
+	ScoringStandard string // Registry ID; empty for the default standard
+	IsPublic        bool
+	Events          []TestEventData
+}
+
+// testEvent is a submitted event resolved to its scoring-standard event.
+type testEvent struct {
+	event       string
+	startedAt   time.Time
+	completedAt time.Time
+}
+
+// CreateTestSession validates a full test against the standard's protocol, scores every
+// event server-side with the user's normed tables and stores the session. The session
+// passes when every event meets the standard's passing score. Badges the events earn are
+// awarded after the session is stored.
+func (s *service) CreateTestSession(ctx context.Context, userID int32, data *CreateTestSessionData) (*store.TestSession, error) {
+	s.logger.Debug(ctx, "WorkoutService: CreateTestSession called", "userID", userID, "standard", data.ScoringStandard, "events", len(data.Events))
+
+	standard, err := grading.LookupStandard(data.ScoringStandard)
+	if err != nil {
+		return nil, fmt.Errorf("invalid scoring standard %q: %w", data.ScoringStandard, err)
+	}
+	if len(data.Events) == 0 {
+		return nil, fmt.Errorf("test session has no events: %w", ErrTestEventOrder)
+	}
+
+	exercises := make([]*store.Exercise, len(data.Events))
+	events := make([]testEvent, len(data.Events))
+	for i, ev := range data.Events {
+		exercise, err := s.exerciseStore.GetExerciseDefinition(ctx, ev.ExerciseID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get exercise: %w", err)
+		}
+		exercises[i] = exercise
+		events[i] = testEvent{event: grading.EventForExerciseType(exercise.Type), startedAt: ev.StartedAt, completedAt: ev.CompletedAt}
+	}
+
+	if err := validateTestSequence(standard.Protocol(), events); err != nil {
+		return nil, err
+	}
+
+	profile := s.scoringProfile(ctx, userID, events[0].startedAt)
+	session := &store.TestSession{
+		UserID:          userID,
+		ScoringStandard: standard.ID(),
+		ScoringVersion:  standard.Version(),
+		TestDate:        testDate(events[0].startedAt),
+		StartedAt:       events[0].startedAt,
+		CompletedAt:     events[len(events)-1].completedAt,
+		Passed:          true,
+		IsPublic:        data.IsPublic,
+	}
+
+	for i, ev := range data.Events {
+		exercise := exercises[i]
+		measurements := &LogWorkoutData{
+			ExerciseID:      ev.ExerciseID,
+			Reps:            ev.Reps,
+			DurationSeconds: ev.DurationSeconds,
+			WeightLbs:       ev.WeightLbs,
+			DistanceMeters:  ev.DistanceMeters,
+		}
+		value, ok := performanceValue(exercise.Type, measurements)
+		if !ok {
+			metric, _ := grading.MetricFor(events[i].event)
+			return nil, fmt.Errorf("%s required for %s: %w", metric, exercise.Type, ErrMissingMeasurement)
+		}
+		if err := s.validateMeasurements(ctx, measurements); err != nil {
+			return nil, err
+		}
+		score, err := standard.Score(events[i].event, value, profile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to score %s: %w", exercise.Type, err)
+		}
+
+		// Test events are graded by the server, so the stored grade is the expected grade
+		grade := int32(score)
+		discrepancy := int32(0)
+		session.Events = append(session.Events, &store.WorkoutRecord{
+			UserID:             userID,
+			ExerciseID:         ev.ExerciseID,
+			ExerciseName:       exercise.Name,
+			ExerciseType:       exercise.Type,
+			Reps:               ev.Reps,
+			DurationSeconds:    ev.DurationSeconds,
+			WeightLbs:          ev.WeightLbs,
+			DistanceMeters:     ev.DistanceMeters,
+			Grade:              grade,
+			FormScore:          ev.FormScore,
+			CompletedAt:        ev.CompletedAt,
+			IsPublic:           data.IsPublic,
+			VerificationStatus: store.VerificationStatusVerified,
+			ExpectedGrade:      &grade,
+			GradeDiscrepancy:   &discrepancy,
+			ScoringStandard:    standard.ID(),
+			ScoringVersion:     standard.Version(),
+		})
+		session.TotalScore += grade
+		if score < standard.PassPoints() {
+			session.Passed = false
+		}
+	}
+
+	created, err := s.workoutStore.CreateTestSession(ctx, session)
+	if err != nil {
+		s.logger.Error(ctx, "Failed to create test session in store", "userID", userID, "error", err)
+		return nil, fmt.Errorf("failed to save test session: %w", err)
+	}
+
+	// Test events earn badges like logged workouts, credited to the last event
+	if len(created.Events) > 0 {
+		if _, err := s.evaluateAchievements(ctx, userID, created.Events[len(created.Events)-1]); err != nil {
+			s.logger.Warn(ctx, "Failed to evaluate achievements", "userID", userID, "testSessionID", created.ID, "error", err)
+		}
+	}
+
+	s.logger.Info(ctx, "Test session recorded successfully", "userID", userID, "testSessionID", created.ID, "totalScore", created.TotalScore, "passed", created.Passed)
+	return created, nil
+}
+
+// validateTestSequence checks that the events fill every slot of the protocol in order,
+// that each rest between events is within the protocol's limits and that the whole test
+// is taken on one day.
+func validateTestSequence(protocol grading.TestProtocol, events []testEvent) error {
+	if len(events) != len(protocol.Sequence) {
+		return fmt.Errorf("got %d events, the test has %d: %w", len(events), len(protocol.Sequence), ErrTestEventOrder)
+	}
+
+	for i, ev := range events {
+		if !slotAccepts(protocol.Sequence[i], ev.event) {
+			return fmt.Errorf("event %d is %s, want one of %v: %w", i+1, ev.event, protocol.Sequence[i], ErrTestEventOrder)
+		}
+		if ev.completedAt.Before(ev.startedAt) {
+			return fmt.Errorf("event %d (%s) completes before it starts: %w", i+1, ev.event, ErrTestEventOrder)
+		}
+		if i == 0 {
+			continue
+		}
+		rest := ev.startedAt.Sub(events[i-1].completedAt)
+		if rest < protocol.MinRest || rest > protocol.MaxRest {
+			return fmt.Errorf("rest before %s was %s, allowed %s to %s: %w",
+				ev.event, rest, protocol.MinRest, protocol.MaxRest, ErrTestRestInterval)
+		}
+	}
+
+	first, last := events[0].startedAt, events[len(events)-1].completedAt
+	if !testDate(last.In(first.Location())).Equal(testDate(first)) {
+		return ErrTestSessionSpansDays
+	}
+	return nil
+}
+
+// slotAccepts reports whether an event may be taken in a protocol slot.
+func slotAccepts(slot []string, event string) bool {
+	for _, e := range slot {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// testDate returns the calendar date of t in its own location, i.e. the offset the
+// client submitted, as midnight UTC.
+func testDate(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// GetTestSession retrieves a test session owned by the user.
+func (s *service) GetTestSession(ctx context.Context, userID int32, sessionID int32) (*store.TestSession, error) {
+	s.logger.Debug(ctx, "WorkoutService: GetTestSession called", "userID", userID, "testSessionID", sessionID)
+
+	session, err := s.workoutStore.GetTestSessionByID(ctx, sessionID)
+	if err != nil {
+		if err == store.ErrTestSessionNotFound {
+			return nil, err
+		}
+		s.logger.Error(ctx, "Failed to get test session from store", "testSessionID", sessionID, "error", err)
+		return nil, fmt.Errorf("failed to retrieve test session: %w", err)
+	}
+
+	if session.UserID != userID {
+		s.logger.Warn(ctx, "User attempted to read test session they don't own", "userID", userID, "testSessionID", sessionID, "ownerID", session.UserID)
+		return nil, fmt.Errorf("user does not have permission to view this test session")
+	}
+	return session, nil
+}
+
+// ListUserTestSessions retrieves paginated test sessions for a user, newest first.
+func (s *service) ListUserTestSessions(ctx context.Context, userID int32, page, pageSize int) (*store.PaginatedTestSessions, error) {
+	s.logger.Debug(ctx, "WorkoutService: ListUserTestSessions called", "userID", userID, "page", page, "pageSize", pageSize)
+
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 { // Max page size constraint
+		pageSize = 20 // Default page size
+	}
+	limit := int32(pageSize)
+	offset := int32((page - 1) * pageSize)
+
+	sessions, err := s.workoutStore.GetUserTestSessions(ctx, userID, limit, offset)
+	if err != nil {
+		s.logger.Error(ctx, "Failed to get user test sessions from store", "userID", userID, "error", err)
+		return nil, fmt.Errorf("failed to retrieve test sessions: %w", err)
+	}
+
+	s.logger.Info(ctx, "User test sessions retrieved", "userID", userID, "count", sessions.TotalCount)
+	return sessions, nil
+}
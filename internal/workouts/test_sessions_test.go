@@ -0,0 +1,140 @@
+package workouts
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"ptchampion/internal/grading"
+)
+
+// sessionEvents builds test events starting at start, each lasting two minutes and
+// followed by the given rests.
+func sessionEvents(start time.Time, events []string, rests ...time.Duration) []testEvent {
+	session := make([]testEvent, len(events))
+	at := start
+	for i, event := range events {
+		if i > 0 {
+			at = at.Add(rests[i-1])
+		}
+		session[i] = testEvent{event: event, startedAt: at, completedAt: at.Add(2 * time.Minute)}
+		at = session[i].completedAt
+	}
+	return session
+}
+
+func TestValidateTestSequence(t *testing.T) {
+	protocol := grading.DefaultStandard().Protocol()
+	morning := time.Date(2025, 7, 1, 6, 0, 0, 0, time.UTC)
+	eastern := time.FixedZone("EDT", -4*60*60)
+	rest := 15 * time.Minute
+
+	tests := []struct {
+		name    string
+		events  []testEvent
+		wantErr error
+	}{
+		{
+			name:   "complete test in order",
+			events: sessionEvents(morning, []string{"pushup", "situp", "run"}, rest, rest),
+		},
+		{
+			name:    "events out of order",
+			events:  sessionEvents(morning, []string{"situp", "pushup", "run"}, rest, rest),
+			wantErr: ErrTestEventOrder,
+		},
+		{
+			name:    "missing event",
+			events:  sessionEvents(morning, []string{"pushup", "situp"}, rest),
+			wantErr: ErrTestEventOrder,
+		},
+		{
+			name:    "event outside the test",
+			events:  sessionEvents(morning, []string{"pushup", "pullup", "run"}, rest, rest),
+			wantErr: ErrTestEventOrder,
+		},
+		{
+			name:    "rest too short",
+			events:  sessionEvents(morning, []string{"pushup", "situp", "run"}, 2*time.Minute, rest),
+			wantErr: ErrTestRestInterval,
+		},
+		{
+			name:    "rest too long",
+			events:  sessionEvents(morning, []string{"pushup", "situp", "run"}, rest, time.Hour),
+			wantErr: ErrTestRestInterval,
+		},
+		{
+			name:    "overlapping events",
+			events:  sessionEvents(morning, []string{"pushup", "situp", "run"}, -time.Minute, rest),
+			wantErr: ErrTestRestInterval,
+		},
+		{
+			name:    "crosses midnight",
+			events:  sessionEvents(time.Date(2025, 7, 1, 23, 30, 0, 0, time.UTC), []string{"pushup", "situp", "run"}, rest, rest),
+			wantErr: ErrTestSessionSpansDays,
+		},
+		{
+			name:   "same local day across UTC midnight",
+			events: sessionEvents(time.Date(2025, 7, 1, 19, 30, 0, 0, eastern), []string{"pushup", "situp", "run"}, rest, rest),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateTestSequence(protocol, tt.events)
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("err = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateTestSequenceAlternates(t *testing.T) {
+	usmc, err := grading.LookupStandard("usmc-pft-2022")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	start := time.Date(2025, 7, 1, 6, 0, 0, 0, time.UTC)
+
+	// Events are resolved from the stored exercise types, as CreateTestSession does
+	for _, first := range []string{"pullup", "pushup"} {
+		var names []string
+		for _, exerciseType := range []string{first, grading.ExerciseTypePlank, grading.ExerciseTypeRun3Mile} {
+			names = append(names, grading.EventForExerciseType(exerciseType))
+		}
+		events := sessionEvents(start, names, 5*time.Minute, 5*time.Minute)
+		if err := validateTestSequence(usmc.Protocol(), events); err != nil {
+			t.Errorf("%s first: unexpected error: %v", first, err)
+		}
+	}
+}
+
+// TestStandardEventsAreStorable checks that every event of every registered standard maps
+// to a stored exercise type and back, and has a measurement to score it on.
+func TestStandardEventsAreStorable(t *testing.T) {
+	for _, standard := range grading.DefaultRegistry().List() {
+		for _, event := range standard.Events() {
+			if got := grading.EventForExerciseType(storedExerciseType(event.ID)); got != event.ID {
+				t.Errorf("%s %s: stored as %s, which is scored as %s", standard.ID(), event.ID, storedExerciseType(event.ID), got)
+			}
+			if _, err := grading.MetricFor(event.ID); err != nil {
+				t.Errorf("%s %s: no metric: %v", standard.ID(), event.ID, err)
+			}
+		}
+	}
+}
+
+func TestTestDate(t *testing.T) {
+	eastern := time.FixedZone("EDT", -4*60*60)
+	got := testDate(time.Date(2025, 7, 1, 22, 0, 0, 0, eastern))
+	want := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+	if !got.Equal(want) {
+		t.Errorf("testDate = %v, want %v (the local calendar date)", got, want)
+	}
+}
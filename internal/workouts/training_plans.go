@@ -0,0 +1,441 @@
+package workouts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"ptchampion/internal/grading"
+	"ptchampion/internal/store"
+)
+
+// Plan lengths in weeks
+const (
+	defaultPlanWeeks = 6
+	minPlanWeeks     = 2
+	maxPlanWeeks     = 12
+)
+
+// planBaselineDays is how far back a workout counts as an event's latest result.
+const planBaselineDays = 180
+
+// weakEventGap is the shortfall from the maximum score, in points, that makes an event
+// weak. The weakest event is always trained as weak unless it is already maxed.
+const weakEventGap = 20
+
+// Training intensities, as fractions of the baseline result. Build weeks ramp linearly
+// from the start to the end intensity of the event's focus; the final week tapers.
+const (
+	improveStartIntensity  = 0.75
+	improveEndIntensity    = 1.05
+	maintainStartIntensity = 0.75
+	maintainEndIntensity   = 0.90
+	taperIntensity         = 0.60
+)
+
+// Training session statuses
+const (
+	TrainingSessionCompleted = "completed" // A workout of the event was logged in the session's window
+	TrainingSessionMissed    = "missed"    // The window closed without a workout
+	TrainingSessionScheduled = "scheduled" // The window is still open
+)
+
+// ErrInvalidTrainingPlan is returned for a plan length outside the supported range.
+var ErrInvalidTrainingPlan = errors.New("invalid training plan")
+
+// GenerateTrainingPlanData defines a plan request at the service layer.
+type GenerateTrainingPlanData struct {
+	ScoringStandard string // Registry ID; empty for the standard of the latest test, or the default
+	Weeks           int    // 0 for the default length
+}
+
+// TrainingSessionProgress is a scheduled session matched against the user's workouts.
+type TrainingSessionProgress struct {
+	Session   *store.TrainingSession
+	Status    string // One of the TrainingSession* constants
+	WorkoutID *int32 // Workout that completed the session
+	TargetMet bool   // The completing workout reached the session's target
+}
+
+// TrainingPlanProgress is the user's active plan with each session's status.
+type TrainingPlanProgress struct {
+	Plan      *store.TrainingPlan
+	Adapted   bool // Regenerated because a test was taken since the plan was made
+	Sessions  []*TrainingSessionProgress
+	Completed int
+	Missed    int
+	Adherence float64 // Completed sessions as a percentage of those whose window closed
+}
+
+// GenerateTrainingPlan builds a plan from the user's latest grade in each event of the
+// standard, starting today, and makes it the user's active plan.
+func (s *service) GenerateTrainingPlan(ctx context.Context, userID int32, data *GenerateTrainingPlanData) (*TrainingPlanProgress, error) {
+	weeks := data.Weeks
+	if weeks == 0 {
+		weeks = defaultPlanWeeks
+	}
+	if weeks < minPlanWeeks || weeks > maxPlanWeeks {
+		return nil, fmt.Errorf("%w: plans run %d to %d weeks", ErrInvalidTrainingPlan, minPlanWeeks, maxPlanWeeks)
+	}
+
+	latestTest, err := s.latestTestSession(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	standardID := data.ScoringStandard
+	if standardID == "" && latestTest != nil {
+		standardID = latestTest.ScoringStandard
+	}
+	standard, err := grading.LookupStandard(standardID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid scoring standard %q: %w", standardID, err)
+	}
+
+	plan, err := s.createTrainingPlan(ctx, userID, standard, weeks, latestTest)
+	if err != nil {
+		return nil, err
+	}
+	return s.trainingPlanProgress(ctx, plan, false)
+}
+
+// GetTrainingPlan returns the user's active plan. When the user has taken a test since
+// the plan was generated, the plan is first regenerated from the new results.
+func (s *service) GetTrainingPlan(ctx context.Context, userID int32) (*TrainingPlanProgress, error) {
+	plan, err := s.workoutStore.GetActiveTrainingPlan(ctx, userID)
+	if err != nil {
+		if err == store.ErrTrainingPlanNotFound {
+			return nil, err
+		}
+		s.logger.Error(ctx, "Failed to get training plan", "userID", userID, "error", err)
+		return nil, fmt.Errorf("failed to retrieve training plan: %w", err)
+	}
+
+	latestTest, err := s.latestTestSession(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if latestTest == nil || plan.TestSessionID != nil && *plan.TestSessionID == latestTest.ID {
+		return s.trainingPlanProgress(ctx, plan, false)
+	}
+
+	standard, err := grading.LookupStandard(plan.ScoringStandard)
+	if err != nil {
+		return nil, fmt.Errorf("training plan %d has unknown scoring standard %q: %w", plan.ID, plan.ScoringStandard, err)
+	}
+	s.logger.Info(ctx, "Adapting training plan to new test", "userID", userID, "planID", plan.ID, "testSessionID", latestTest.ID)
+	adapted, err := s.createTrainingPlan(ctx, userID, standard, plan.Weeks, latestTest)
+	if err != nil {
+		return nil, err
+	}
+	return s.trainingPlanProgress(ctx, adapted, true)
+}
+
+func (s *service) latestTestSession(ctx context.Context, userID int32) (*store.TestSession, error) {
+	sessions, err := s.workoutStore.GetUserTestSessions(ctx, userID, 1, 0)
+	if err != nil {
+		s.logger.Error(ctx, "Failed to get latest test session", "userID", userID, "error", err)
+		return nil, fmt.Errorf("failed to retrieve test sessions: %w", err)
+	}
+	if len(sessions.Sessions) == 0 {
+		return nil, nil
+	}
+	return sessions.Sessions[0], nil
+}
+
+// createTrainingPlan generates and stores a plan from the user's latest results in the
+// standard's events.
+func (s *service) createTrainingPlan(ctx context.Context, userID int32, standard grading.ScoringStandard, weeks int, latestTest *store.TestSession) (*store.TrainingPlan, error) {
+	now := time.Now()
+	var exerciseTypes []string
+	for _, slot := range standard.Protocol().Sequence {
+		for _, event := range slot {
+			exerciseTypes = append(exerciseTypes, storedExerciseType(event))
+		}
+	}
+	records, err := s.workoutStore.ListUserWorkoutRecordsSince(ctx, userID, exerciseTypes, calendarDate(now).AddDate(0, 0, -planBaselineDays))
+	if err != nil {
+		s.logger.Error(ctx, "Failed to list workouts for training plan", "userID", userID, "error", err)
+		return nil, fmt.Errorf("failed to retrieve workouts: %w", err)
+	}
+
+	baselines := trainingBaselines(standard, records, s.scoringProfile(ctx, userID, now))
+	plan := generateTrainingPlan(standard, baselines, calendarDate(now), weeks)
+	plan.UserID = userID
+	if latestTest != nil {
+		plan.TestSessionID = &latestTest.ID
+	}
+
+	created, err := s.workoutStore.CreateTrainingPlan(ctx, plan)
+	if err != nil {
+		s.logger.Error(ctx, "Failed to create training plan", "userID", userID, "error", err)
+		return nil, fmt.Errorf("failed to create training plan: %w", err)
+	}
+	s.logger.Info(ctx, "Training plan created", "userID", userID, "planID", created.ID, "standard", created.ScoringStandard, "sessions", len(created.Sessions))
+	return created, nil
+}
+
+// trainingPlanProgress matches the plan's sessions against the workouts logged since it started.
+func (s *service) trainingPlanProgress(ctx context.Context, plan *store.TrainingPlan, adapted bool) (*TrainingPlanProgress, error) {
+	var exerciseTypes []string
+	for _, ev := range plan.Events {
+		exerciseTypes = append(exerciseTypes, storedExerciseType(ev.Event))
+	}
+	var records []*store.WorkoutRecord
+	if len(exerciseTypes) > 0 {
+		var err error
+		records, err = s.workoutStore.ListUserWorkoutRecordsSince(ctx, plan.UserID, exerciseTypes, plan.StartDate)
+		if err != nil {
+			s.logger.Error(ctx, "Failed to list workouts for training plan", "userID", plan.UserID, "planID", plan.ID, "error", err)
+			return nil, fmt.Errorf("failed to retrieve workouts: %w", err)
+		}
+	}
+
+	standard, err := grading.LookupStandard(plan.ScoringStandard)
+	if err != nil {
+		return nil, fmt.Errorf("training plan %d has unknown scoring standard %q: %w", plan.ID, plan.ScoringStandard, err)
+	}
+	progress := matchTrainingSessions(plan, standard, records, time.Now())
+	progress.Adapted = adapted
+	return progress, nil
+}
+
+// trainingBaselines returns the latest result in each slot of the standard's test, in
+// test order. A slot with alternates takes the most recently trained alternate; slots
+// without a result are listed under their first event with no score.
+func trainingBaselines(standard grading.ScoringStandard, records []*store.WorkoutRecord, profile grading.Profile) []*store.TrainingPlanEvent {
+	var baselines []*store.TrainingPlanEvent
+	for _, slot := range standard.Protocol().Sequence {
+		baseline := &store.TrainingPlanEvent{Event: slot[0]}
+		var latest time.Time
+		for _, event := range slot {
+			metric, err := grading.MetricFor(event)
+			if err != nil {
+				continue
+			}
+			for _, r := range records {
+				if r.ExerciseType != storedExerciseType(event) || r.CompletedAt.Before(latest) {
+					continue
+				}
+				v, ok := measurementValue(metric, r.Reps, r.DurationSeconds, r.WeightLbs, r.DistanceMeters)
+				if !ok || v <= 0 {
+					continue
+				}
+				score, err := standard.Score(event, v, profile)
+				if err != nil {
+					continue
+				}
+				points, value := int32(score), v
+				baseline.Event, baseline.Score, baseline.Value = event, &points, &value
+				latest = r.CompletedAt
+			}
+		}
+		baselines = append(baselines, baseline)
+	}
+	return baselines
+}
+
+// generateTrainingPlan lays out a plan from per-event baselines. It is deterministic:
+// the same baselines, start date and length always give the same plan.
+//
+// Events are ranked by their gap to the maximum score, weakest first, ties in test order.
+// Weak events train three times a week and events near the maximum once; an event without
+// a baseline opens with a max-effort attempt and otherwise trains as weak, without targets.
+// Targets scale the baseline by an intensity that rises each build week, and the final
+// week tapers to one light session per event ahead of the next test.
+func generateTrainingPlan(standard grading.ScoringStandard, baselines []*store.TrainingPlanEvent, start time.Time, weeks int) *store.TrainingPlan {
+	events := make([]*store.TrainingPlanEvent, len(baselines))
+	for i, b := range baselines {
+		ev := *b
+		ev.Gap = grading.MaxPoints
+		if ev.Score != nil {
+			ev.Gap = grading.MaxPoints - *ev.Score
+		}
+		events[i] = &ev
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].Gap > events[j].Gap })
+	for i, ev := range events {
+		switch {
+		case ev.Score == nil:
+			ev.Focus = store.TrainingFocusBaseline
+		case ev.Gap >= weakEventGap || i == 0 && ev.Gap > 0:
+			ev.Focus = store.TrainingFocusImprove
+		default:
+			ev.Focus = store.TrainingFocusMaintain
+		}
+	}
+
+	higher := make(map[string]bool)
+	for _, e := range standard.Events() {
+		higher[e.ID] = e.HigherIsBetter
+	}
+
+	buildWeeks := weeks
+	if weeks > 2 {
+		buildWeeks = weeks - 1
+	}
+	plan := &store.TrainingPlan{
+		ScoringStandard: standard.ID(),
+		StartDate:       start,
+		Weeks:           weeks,
+		Events:          events,
+		Sessions:        []*store.TrainingSession{},
+	}
+	baselineScheduled := make(map[string]bool)
+	for week := 1; week <= weeks; week++ {
+		taper := week > buildWeeks
+		for day := 0; day < 7; day++ {
+			weakRank := 0
+			for _, ev := range events {
+				onDay := trainsOn(ev.Focus, weakRank, day, taper)
+				if ev.Focus != store.TrainingFocusMaintain {
+					weakRank++
+				}
+				if !onDay {
+					continue
+				}
+
+				session := &store.TrainingSession{
+					ScheduledDate: start.AddDate(0, 0, (week-1)*7+day),
+					Week:          week,
+					Event:         ev.Event,
+					Focus:         ev.Focus,
+					Sets:          trainingSets(ev.Focus, higher[ev.Event], taper),
+				}
+				if ev.Focus == store.TrainingFocusBaseline {
+					if !baselineScheduled[ev.Event] {
+						baselineScheduled[ev.Event] = true
+						session.Sets = 1
+					} else {
+						session.Focus = store.TrainingFocusImprove
+					}
+				}
+				if ev.Value != nil {
+					intensity := trainingIntensity(ev.Focus, week, buildWeeks)
+					target := trainingTarget(ev.Event, *ev.Value, intensity, higher[ev.Event])
+					session.TargetValue = &target
+				}
+				plan.Sessions = append(plan.Sessions, session)
+			}
+		}
+	}
+	return plan
+}
+
+// trainsOn reports whether an event trains on a day of the week. Weak events train every
+// other day, alternate weak events offset by a day to spread the load; maintained events
+// train mid-week. In the taper week every event trains once, early in the week.
+func trainsOn(focus string, weakRank int, day int, taper bool) bool {
+	if taper {
+		return day == 1
+	}
+	if focus == store.TrainingFocusMaintain {
+		return day == 3
+	}
+	offset := day - weakRank%2
+	return offset >= 0 && offset <= 4 && offset%2 == 0
+}
+
+// trainingSets returns the sets of a session. Timed efforts scored lower-is-better (runs,
+// the sprint-drag-carry) are a single effort.
+func trainingSets(focus string, higherIsBetter bool, taper bool) int {
+	switch {
+	case !higherIsBetter || taper:
+		return 1
+	case focus == store.TrainingFocusMaintain:
+		return 2
+	default:
+		return 3
+	}
+}
+
+// trainingIntensity returns the fraction of the baseline a session targets in a week.
+func trainingIntensity(focus string, week, buildWeeks int) float64 {
+	if week > buildWeeks {
+		return taperIntensity
+	}
+	from, to := improveStartIntensity, improveEndIntensity
+	if focus == store.TrainingFocusMaintain {
+		from, to = maintainStartIntensity, maintainEndIntensity
+	}
+	if buildWeeks == 1 {
+		return to
+	}
+	return from + (to-from)*float64(week-1)/float64(buildWeeks-1)
+}
+
+// trainingTarget scales a baseline by an intensity and rounds it to a prescribable value:
+// whole reps and seconds, 5 lb plates and tenths of a meter. For events scored
+// lower-is-better, a lower intensity allows more time.
+func trainingTarget(event string, baseline, intensity float64, higherIsBetter bool) float64 {
+	target := baseline * intensity
+	if !higherIsBetter {
+		target = baseline / intensity
+	}
+	metric, _ := grading.MetricFor(event)
+	switch metric {
+	case grading.MetricWeight:
+		target = math.Round(target/5) * 5
+	case grading.MetricDistance:
+		target = math.Round(target*10) / 10
+	default:
+		target = math.Round(target)
+	}
+	return math.Max(1, target)
+}
+
+// matchTrainingSessions marks each session completed by the first unused workout of its
+// event logged in its window: from its scheduled date until the event's next session, or
+// the end of the plan for the last one.
+func matchTrainingSessions(plan *store.TrainingPlan, standard grading.ScoringStandard, records []*store.WorkoutRecord, now time.Time) *TrainingPlanProgress {
+	progress := &TrainingPlanProgress{Plan: plan}
+	planEnd := plan.StartDate.AddDate(0, 0, 7*plan.Weeks)
+	today := calendarDate(now)
+
+	used := make(map[int32]bool)
+	for i, session := range plan.Sessions {
+		windowEnd := planEnd
+		for _, next := range plan.Sessions[i+1:] {
+			if next.Event == session.Event && next.ScheduledDate.After(session.ScheduledDate) {
+				windowEnd = next.ScheduledDate
+				break
+			}
+		}
+
+		sp := &TrainingSessionProgress{Session: session, Status: TrainingSessionScheduled}
+		metric, _ := grading.MetricFor(session.Event)
+		for _, r := range records {
+			day := calendarDate(r.CompletedAt)
+			if used[r.ID] || r.ExerciseType != storedExerciseType(session.Event) || day.Before(session.ScheduledDate) || !day.Before(windowEnd) {
+				continue
+			}
+			used[r.ID] = true
+			id := r.ID
+			sp.Status = TrainingSessionCompleted
+			sp.WorkoutID = &id
+			sp.TargetMet = true
+			if session.TargetValue != nil {
+				v, ok := measurementValue(metric, r.Reps, r.DurationSeconds, r.WeightLbs, r.DistanceMeters)
+				sp.TargetMet = ok && meetsTarget(v, *session.TargetValue, higherIsBetter(standard, r.ExerciseType))
+			}
+			break
+		}
+		if sp.Status == TrainingSessionScheduled && !windowEnd.After(today) {
+			sp.Status = TrainingSessionMissed
+		}
+
+		switch sp.Status {
+		case TrainingSessionCompleted:
+			progress.Completed++
+		case TrainingSessionMissed:
+			progress.Missed++
+		}
+		progress.Sessions = append(progress.Sessions, sp)
+	}
+	if due := progress.Completed + progress.Missed; due > 0 {
+		progress.Adherence = float64(progress.Completed) / float64(due) * 100
+	}
+	return progress
+}
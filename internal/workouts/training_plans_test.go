@@ -0,0 +1,144 @@
+package workouts
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"ptchampion/internal/grading"
+	"ptchampion/internal/store"
+)
+
+var planStart = time.Date(2025, 7, 7, 0, 0, 0, 0, time.UTC)
+
+func planDay(n int) time.Time {
+	return planStart.AddDate(0, 0, n)
+}
+
+func TestGenerateTrainingPlan(t *testing.T) {
+	apft := grading.DefaultStandard()
+	pushupScore, situpScore := int32(74), int32(100)
+	pushupReps, situpReps := 50.0, 78.0
+	baselines := []*store.TrainingPlanEvent{
+		{Event: grading.ExerciseTypePushup, Score: &pushupScore, Value: &pushupReps},
+		{Event: grading.ExerciseTypeSitup, Score: &situpScore, Value: &situpReps},
+		{Event: grading.ExerciseTypeRun},
+	}
+
+	plan := generateTrainingPlan(apft, baselines, planStart, 6)
+	if again := generateTrainingPlan(apft, baselines, planStart, 6); !reflect.DeepEqual(plan, again) {
+		t.Fatal("generating the same plan twice gave different plans")
+	}
+
+	// Weakest first: no run result, then push-ups 26 points short, then maxed sit-ups
+	wantEvents := []struct {
+		event string
+		gap   int32
+		focus string
+	}{
+		{grading.ExerciseTypeRun, 100, store.TrainingFocusBaseline},
+		{grading.ExerciseTypePushup, 26, store.TrainingFocusImprove},
+		{grading.ExerciseTypeSitup, 0, store.TrainingFocusMaintain},
+	}
+	if len(plan.Events) != len(wantEvents) {
+		t.Fatalf("got %d events, want %d", len(plan.Events), len(wantEvents))
+	}
+	for i, want := range wantEvents {
+		got := plan.Events[i]
+		if got.Event != want.event || got.Gap != want.gap || got.Focus != want.focus {
+			t.Errorf("event %d = %s gap %d %s, want %s gap %d %s", i, got.Event, got.Gap, got.Focus, want.event, want.gap, want.focus)
+		}
+	}
+
+	// Five build weeks: three run and three push-up sessions and one sit-up session a
+	// week, then one taper session per event
+	if len(plan.Sessions) != 38 {
+		t.Fatalf("got %d sessions, want 38", len(plan.Sessions))
+	}
+	counts := make(map[string]int)
+	for i, s := range plan.Sessions {
+		counts[s.Event]++
+		if i > 0 && s.ScheduledDate.Before(plan.Sessions[i-1].ScheduledDate) {
+			t.Fatalf("session %d is scheduled before session %d", i, i-1)
+		}
+	}
+	if counts[grading.ExerciseTypeRun] != 16 || counts[grading.ExerciseTypePushup] != 16 || counts[grading.ExerciseTypeSitup] != 6 {
+		t.Errorf("sessions per event = %v, want 16 run, 16 pushup, 6 situp", counts)
+	}
+
+	first := plan.Sessions[0]
+	if first.Event != grading.ExerciseTypeRun || first.Focus != store.TrainingFocusBaseline || !first.ScheduledDate.Equal(planStart) || first.TargetValue != nil {
+		t.Errorf("first session = %+v, want a run baseline attempt on the start date", first)
+	}
+
+	targets := func(event string) []float64 {
+		var out []float64
+		for _, s := range plan.Sessions {
+			if s.Event == event && s.TargetValue != nil {
+				out = append(out, *s.TargetValue)
+			}
+		}
+		return out
+	}
+	pushups := targets(grading.ExerciseTypePushup)
+	if pushups[0] != 38 || pushups[14] != 53 || pushups[15] != 30 {
+		t.Errorf("push-up targets = %v, want 38 in week 1 rising to 53 in week 5 and 30 in the taper", pushups)
+	}
+	situps := targets(grading.ExerciseTypeSitup)
+	if situps[0] != 59 || situps[4] != 70 || situps[5] != 47 {
+		t.Errorf("sit-up targets = %v, want 59 in week 1 rising to 70 in week 5 and 47 in the taper", situps)
+	}
+	if len(targets(grading.ExerciseTypeRun)) != 0 {
+		t.Error("run sessions without a baseline should have no targets")
+	}
+}
+
+func TestMatchTrainingSessions(t *testing.T) {
+	apft := grading.DefaultStandard()
+	target := func(v float64) *float64 { return &v }
+	plan := &store.TrainingPlan{
+		ScoringStandard: apft.ID(),
+		StartDate:       planStart,
+		Weeks:           1,
+		Sessions: []*store.TrainingSession{
+			{ScheduledDate: planDay(0), Event: grading.ExerciseTypePushup, TargetValue: target(30)},
+			{ScheduledDate: planDay(0), Event: grading.ExerciseTypeSitup, TargetValue: target(40)},
+			{ScheduledDate: planDay(1), Event: grading.ExerciseTypeRun, TargetValue: target(900)},
+			{ScheduledDate: planDay(2), Event: grading.ExerciseTypePushup, TargetValue: target(35)},
+			{ScheduledDate: planDay(2), Event: grading.ExerciseTypeSitup, TargetValue: target(45)},
+		},
+	}
+	records := []*store.WorkoutRecord{
+		{ID: 1, ExerciseType: "pushup", Reps: int32Ptr(32), CompletedAt: planDay(1).Add(9 * time.Hour)},
+		{ID: 2, ExerciseType: "running", DurationSeconds: int32Ptr(880), CompletedAt: planDay(2).Add(7 * time.Hour)},
+		{ID: 3, ExerciseType: "pushup", Reps: int32Ptr(30), CompletedAt: planDay(3).Add(9 * time.Hour)},
+	}
+
+	progress := matchTrainingSessions(plan, apft, records, planDay(5).Add(12*time.Hour))
+
+	want := []struct {
+		status    string
+		workoutID int32
+		targetMet bool
+	}{
+		{TrainingSessionCompleted, 1, true},
+		{TrainingSessionMissed, 0, false}, // Window closed when the next sit-up session opened
+		{TrainingSessionCompleted, 2, true},
+		{TrainingSessionCompleted, 3, false},
+		{TrainingSessionScheduled, 0, false}, // Window runs to the end of the plan
+	}
+	for i, w := range want {
+		got := progress.Sessions[i]
+		var workoutID int32
+		if got.WorkoutID != nil {
+			workoutID = *got.WorkoutID
+		}
+		if got.Status != w.status || workoutID != w.workoutID || got.TargetMet != w.targetMet {
+			t.Errorf("session %d = %s workout %d target met %v, want %s workout %d target met %v",
+				i, got.Status, workoutID, got.TargetMet, w.status, w.workoutID, w.targetMet)
+		}
+	}
+	if progress.Completed != 3 || progress.Missed != 1 || progress.Adherence != 75 {
+		t.Errorf("completed %d, missed %d, adherence %.1f; want 3, 1, 75", progress.Completed, progress.Missed, progress.Adherence)
+	}
+}
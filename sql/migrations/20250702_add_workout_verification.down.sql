@@ -0,0 +1,12 @@
+-- +migrate Down
+-- Remove server-side grade verification columns
+
+DROP INDEX IF EXISTS idx_workouts_mismatched;
+
+ALTER TABLE workouts
+  DROP CONSTRAINT IF EXISTS check_verification_status;
+
+ALTER TABLE workouts
+  DROP COLUMN IF EXISTS grade_discrepancy,
+  DROP COLUMN IF EXISTS expected_grade,
+  DROP COLUMN IF EXISTS verification_status;
@@ -0,0 +1,14 @@
+-- +migrate Up
+-- Track server-side verification of client-submitted grades
+
+ALTER TABLE workouts
+  ADD COLUMN verification_status VARCHAR(20) NOT NULL DEFAULT 'unverified',
+  ADD COLUMN expected_grade INT,
+  ADD COLUMN grade_discrepancy INT;
+
+ALTER TABLE workouts
+  ADD CONSTRAINT check_verification_status
+  CHECK (verification_status IN ('unverified', 'verified', 'mismatched'));
+
+-- Mismatched records are excluded from leaderboards and listed for admin review
+CREATE INDEX IF NOT EXISTS idx_workouts_mismatched ON workouts(created_at DESC) WHERE verification_status = 'mismatched';
@@ -0,0 +1,10 @@
+-- +migrate Down
+-- Remove pose-frame replay results
+
+ALTER TABLE workouts
+  DROP CONSTRAINT IF EXISTS replay_form_score_range;
+
+ALTER TABLE workouts
+  DROP COLUMN IF EXISTS replayed_at,
+  DROP COLUMN IF EXISTS replay_form_score,
+  DROP COLUMN IF EXISTS replay_reps;
@@ -0,0 +1,11 @@
+-- +migrate Up
+-- Store the result of replaying uploaded pose frames through the server-side graders
+
+ALTER TABLE workouts
+  ADD COLUMN replay_reps INT,
+  ADD COLUMN replay_form_score INT,
+  ADD COLUMN replayed_at TIMESTAMPTZ;
+
+ALTER TABLE workouts
+  ADD CONSTRAINT replay_form_score_range
+  CHECK (replay_form_score IS NULL OR (replay_form_score >= 0 AND replay_form_score <= 100));
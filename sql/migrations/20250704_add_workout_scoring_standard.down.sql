@@ -0,0 +1,6 @@
+-- +migrate Down
+-- Remove the scoring standard recorded on workouts
+
+ALTER TABLE workouts
+  DROP COLUMN IF EXISTS scoring_version,
+  DROP COLUMN IF EXISTS scoring_standard;
@@ -0,0 +1,11 @@
+-- +migrate Up
+-- Record which scoring standard and version graded each workout
+
+ALTER TABLE workouts
+  ADD COLUMN scoring_standard VARCHAR(50),
+  ADD COLUMN scoring_version VARCHAR(20);
+
+-- Workouts logged before the registry existed were graded with the APFT tables
+UPDATE workouts
+  SET scoring_standard = 'apft-2013', scoring_version = '2013'
+  WHERE scoring_standard IS NULL;
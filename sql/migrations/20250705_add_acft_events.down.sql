@@ -0,0 +1,16 @@
+-- +migrate Down
+-- Remove the ACFT events and their measurements
+
+DELETE FROM workouts
+  WHERE exercise_type IN ('deadlift', 'power_throw', 'hand_release_pushup', 'sprint_drag_carry', 'plank');
+
+DELETE FROM exercises
+  WHERE type IN ('deadlift', 'power_throw', 'hand_release_pushup', 'sprint_drag_carry', 'plank');
+
+ALTER TABLE workouts
+  DROP CONSTRAINT IF EXISTS distance_meters_positive,
+  DROP CONSTRAINT IF EXISTS weight_lbs_positive;
+
+ALTER TABLE workouts
+  DROP COLUMN IF EXISTS distance_meters,
+  DROP COLUMN IF EXISTS weight_lbs;
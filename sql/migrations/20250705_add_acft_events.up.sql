@@ -0,0 +1,22 @@
+-- +migrate Up
+-- Add the ACFT events and the weight and distance measurements they are scored on
+
+ALTER TABLE workouts
+  ADD COLUMN weight_lbs INT,
+  ADD COLUMN distance_meters NUMERIC(5,2);
+
+ALTER TABLE workouts
+  ADD CONSTRAINT weight_lbs_positive CHECK (weight_lbs IS NULL OR weight_lbs > 0),
+  ADD CONSTRAINT distance_meters_positive CHECK (distance_meters IS NULL OR distance_meters > 0);
+
+-- The ACFT 2-mile run reuses the existing 'running' exercise
+INSERT INTO exercises (name, description, type)
+SELECT v.name, v.description, v.type
+FROM (VALUES
+  ('Deadlift', '3 repetition maximum hex bar deadlift, scored on weight', 'deadlift'),
+  ('Standing Power Throw', 'Backward overhead throw of a 10 lb medicine ball, scored on distance', 'power_throw'),
+  ('Hand-Release Push-up', 'Hand-release push-ups in two minutes', 'hand_release_pushup'),
+  ('Sprint-Drag-Carry', '5 x 50 m shuttle of sprint, drag, lateral, carry and sprint, scored on time', 'sprint_drag_carry'),
+  ('Plank', 'Forearm plank held for time', 'plank')
+) AS v(name, description, type)
+WHERE NOT EXISTS (SELECT 1 FROM exercises e WHERE e.type = v.type);
@@ -0,0 +1,12 @@
+-- +migrate Down
+-- Remove test sessions; their event workouts are deleted with them
+
+DELETE FROM workouts WHERE test_session_id IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_workouts_test_session_id;
+
+ALTER TABLE workouts
+  DROP COLUMN IF EXISTS test_event_order,
+  DROP COLUMN IF EXISTS test_session_id;
+
+DROP TABLE IF EXISTS test_sessions;
@@ -0,0 +1,25 @@
+-- +migrate Up
+-- A test session groups the events of one full PT test taken on one day
+
+CREATE TABLE IF NOT EXISTS test_sessions (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    scoring_standard VARCHAR(50) NOT NULL,
+    scoring_version VARCHAR(20) NOT NULL,
+    test_date DATE NOT NULL,
+    started_at TIMESTAMPTZ NOT NULL,
+    completed_at TIMESTAMPTZ NOT NULL,
+    total_score INT NOT NULL CHECK (total_score >= 0),
+    passed BOOLEAN NOT NULL,
+    is_public BOOLEAN NOT NULL DEFAULT false,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_test_sessions_user_id_test_date ON test_sessions(user_id, test_date DESC);
+
+-- Each event of a session is stored as a workout, numbered in the order it was taken
+ALTER TABLE workouts
+  ADD COLUMN test_session_id INT REFERENCES test_sessions(id) ON DELETE CASCADE,
+  ADD COLUMN test_event_order INT;
+
+CREATE INDEX IF NOT EXISTS idx_workouts_test_session_id ON workouts(test_session_id) WHERE test_session_id IS NOT NULL;
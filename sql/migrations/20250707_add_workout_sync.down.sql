@@ -0,0 +1,12 @@
+-- +migrate Down
+-- Remove the sync columns from workouts
+
+DROP TRIGGER IF EXISTS set_workouts_updated_at_on_update ON workouts;
+DROP FUNCTION IF EXISTS set_workouts_updated_at();
+
+DROP INDEX IF EXISTS idx_workouts_user_id_updated_at;
+DROP INDEX IF EXISTS idx_workouts_user_device_client;
+
+ALTER TABLE workouts
+  DROP COLUMN IF EXISTS updated_at,
+  DROP COLUMN IF EXISTS client_id;
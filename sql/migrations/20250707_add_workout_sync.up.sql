@@ -0,0 +1,33 @@
+-- +migrate Up
+-- Support idempotent offline sync: client-generated IDs and a change cursor on workouts
+
+ALTER TABLE workouts
+  ADD COLUMN client_id VARCHAR(64),
+  ADD COLUMN updated_at TIMESTAMPTZ;
+
+UPDATE workouts SET updated_at = created_at WHERE updated_at IS NULL;
+
+ALTER TABLE workouts
+  ALTER COLUMN updated_at SET DEFAULT NOW(),
+  ALTER COLUMN updated_at SET NOT NULL;
+
+-- A pushed record is identified by the device that created it and its client-generated ID
+CREATE UNIQUE INDEX IF NOT EXISTS idx_workouts_user_device_client
+  ON workouts(user_id, device_id, client_id) WHERE client_id IS NOT NULL;
+
+-- Sync pulls a user's workouts changed since the client's cursor
+CREATE INDEX IF NOT EXISTS idx_workouts_user_id_updated_at ON workouts(user_id, updated_at);
+
+CREATE OR REPLACE FUNCTION set_workouts_updated_at()
+RETURNS TRIGGER AS $$
+BEGIN
+    NEW.updated_at = NOW();
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS set_workouts_updated_at_on_update ON workouts;
+CREATE TRIGGER set_workouts_updated_at_on_update
+BEFORE UPDATE ON workouts
+FOR EACH ROW
+EXECUTE FUNCTION set_workouts_updated_at();
@@ -0,0 +1,14 @@
+-- +migrate Down
+-- Remove workout row versions and tombstones
+
+CREATE OR REPLACE FUNCTION set_workouts_updated_at()
+RETURNS TRIGGER AS $$
+BEGIN
+    NEW.updated_at = NOW();
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+ALTER TABLE workouts
+  DROP COLUMN IF EXISTS deleted_at,
+  DROP COLUMN IF EXISTS version;
@@ -0,0 +1,20 @@
+-- +migrate Up
+-- Row versions and soft-delete tombstones for offline sync conflict resolution
+
+ALTER TABLE workouts
+  ADD COLUMN version INT NOT NULL DEFAULT 1,
+  ADD COLUMN deleted_at TIMESTAMPTZ;
+
+-- Bump the version once per transaction that changes the row. Inserts through sync set
+-- several columns in follow-up updates within the same transaction; those rows already
+-- carry this transaction's NOW() as updated_at and keep their version.
+CREATE OR REPLACE FUNCTION set_workouts_updated_at()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF OLD.updated_at IS DISTINCT FROM NOW() THEN
+        NEW.version = OLD.version + 1;
+    END IF;
+    NEW.updated_at = NOW();
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
@@ -0,0 +1,6 @@
+-- +migrate Down
+-- Remove the workout audit trail
+
+DROP TRIGGER IF EXISTS prevent_workout_audit_log_changes ON workout_audit_log;
+DROP FUNCTION IF EXISTS prevent_workout_audit_log_changes();
+DROP TABLE IF EXISTS workout_audit_log;
@@ -0,0 +1,28 @@
+-- +migrate Up
+-- Immutable audit trail of workout edits and deletes
+
+-- No foreign key to workouts or users: entries outlive the rows they describe
+CREATE TABLE IF NOT EXISTS workout_audit_log (
+  id SERIAL PRIMARY KEY,
+  workout_id INT NOT NULL,
+  actor_user_id INT NOT NULL,
+  action VARCHAR(16) NOT NULL CHECK (action IN ('update', 'delete')),
+  workout_version INT NOT NULL,
+  changes JSONB NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_workout_audit_log_workout_id ON workout_audit_log(workout_id, id);
+
+CREATE OR REPLACE FUNCTION prevent_workout_audit_log_changes()
+RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'workout_audit_log entries are immutable';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS prevent_workout_audit_log_changes ON workout_audit_log;
+CREATE TRIGGER prevent_workout_audit_log_changes
+BEFORE UPDATE OR DELETE ON workout_audit_log
+FOR EACH ROW
+EXECUTE FUNCTION prevent_workout_audit_log_changes();
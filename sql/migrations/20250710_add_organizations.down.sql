@@ -0,0 +1,5 @@
+-- +migrate Down
+-- Remove organizations and memberships
+
+DROP TABLE IF EXISTS organization_memberships;
+DROP TABLE IF EXISTS organizations;
@@ -0,0 +1,5 @@
+-- +migrate Down
+-- Remove challenges and their participants
+
+DROP TABLE IF EXISTS challenge_participants;
+DROP TABLE IF EXISTS challenges;
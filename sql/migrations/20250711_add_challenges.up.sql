@@ -0,0 +1,38 @@
+-- +migrate Up
+-- Challenges: competitions on one exercise within a time window, between users or units
+
+CREATE TABLE IF NOT EXISTS challenges (
+    id SERIAL PRIMARY KEY,
+    creator_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    description TEXT,
+    exercise_id INT NOT NULL REFERENCES exercises(id),
+    scoring_rule VARCHAR(16) NOT NULL CHECK (scoring_rule IN ('max_reps', 'total_volume', 'best_time')),
+    participant_type VARCHAR(16) NOT NULL CHECK (participant_type IN ('individual', 'team')),
+    starts_at TIMESTAMPTZ NOT NULL,
+    ends_at TIMESTAMPTZ NOT NULL,
+    finalized_at TIMESTAMPTZ, -- Set when the results are frozen after ends_at
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK (ends_at > starts_at)
+);
+
+CREATE INDEX IF NOT EXISTS idx_challenges_ends_at ON challenges(ends_at);
+
+-- A participant is either a user or an organization (team), never both
+CREATE TABLE IF NOT EXISTS challenge_participants (
+    id SERIAL PRIMARY KEY,
+    challenge_id INT NOT NULL REFERENCES challenges(id) ON DELETE CASCADE,
+    user_id INT REFERENCES users(id) ON DELETE CASCADE,
+    organization_id INT REFERENCES organizations(id) ON DELETE CASCADE,
+    joined_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    -- Frozen results, set when the challenge is finalized
+    final_rank INT,
+    final_score DOUBLE PRECISION,
+    final_contributors INT,
+    CHECK ((user_id IS NULL) <> (organization_id IS NULL)),
+    UNIQUE (challenge_id, user_id),
+    UNIQUE (challenge_id, organization_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_challenge_participants_user_id ON challenge_participants(user_id);
+CREATE INDEX IF NOT EXISTS idx_challenge_participants_organization_id ON challenge_participants(organization_id);
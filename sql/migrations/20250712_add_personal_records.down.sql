@@ -0,0 +1,4 @@
+-- +migrate Down
+-- Remove personal records
+
+DROP TABLE IF EXISTS personal_records;
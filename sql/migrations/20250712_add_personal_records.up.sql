@@ -0,0 +1,16 @@
+-- +migrate Up
+-- Personal records: one row each time a user's best in an exercise type and metric improves
+
+CREATE TABLE IF NOT EXISTS personal_records (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    exercise_type VARCHAR(50) NOT NULL,
+    metric VARCHAR(32) NOT NULL, -- reps, duration_seconds, weight_lbs, distance_meters or grade
+    value DOUBLE PRECISION NOT NULL,
+    previous_value DOUBLE PRECISION, -- Best this record beat, NULL for the first record
+    workout_id INT NOT NULL REFERENCES workouts(id) ON DELETE CASCADE,
+    achieved_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_personal_records_user_type_metric ON personal_records(user_id, exercise_type, metric, id DESC);
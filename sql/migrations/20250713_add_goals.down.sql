@@ -0,0 +1,4 @@
+-- +migrate Down
+-- Remove goals
+
+DROP TABLE IF EXISTS goals;
@@ -0,0 +1,16 @@
+-- +migrate Up
+-- Goals: a target measurement in one exercise, or a total test score, by a date
+
+CREATE TABLE IF NOT EXISTS goals (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    goal_type VARCHAR(16) NOT NULL CHECK (goal_type IN ('exercise', 'total_score')),
+    exercise_type VARCHAR(50), -- Exercise goals only
+    scoring_standard VARCHAR(50) NOT NULL,
+    target_value DOUBLE PRECISION NOT NULL CHECK (target_value > 0),
+    target_date DATE NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK ((goal_type = 'exercise') = (exercise_type IS NOT NULL))
+);
+
+CREATE INDEX IF NOT EXISTS idx_goals_user_id ON goals(user_id, target_date);
@@ -0,0 +1,5 @@
+-- +migrate Down
+-- Remove training plans
+
+DROP TABLE IF EXISTS training_sessions;
+DROP TABLE IF EXISTS training_plans;
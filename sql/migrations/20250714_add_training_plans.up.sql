@@ -0,0 +1,28 @@
+-- +migrate Up
+-- Training plans: multi-week schedules generated from a user's latest per-event grades
+
+CREATE TABLE IF NOT EXISTS training_plans (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    scoring_standard VARCHAR(50) NOT NULL,
+    test_session_id INT REFERENCES test_sessions(id) ON DELETE SET NULL, -- Latest test when generated
+    start_date DATE NOT NULL,
+    weeks INT NOT NULL CHECK (weeks > 0),
+    events JSONB NOT NULL, -- Per-event baselines, weakest first
+    superseded_at TIMESTAMPTZ, -- Set when a newer plan replaces this one
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS training_sessions (
+    id SERIAL PRIMARY KEY,
+    plan_id INT NOT NULL REFERENCES training_plans(id) ON DELETE CASCADE,
+    scheduled_date DATE NOT NULL,
+    week INT NOT NULL CHECK (week > 0),
+    event VARCHAR(50) NOT NULL,
+    focus VARCHAR(16) NOT NULL CHECK (focus IN ('improve', 'maintain', 'baseline')),
+    sets INT NOT NULL CHECK (sets > 0),
+    target_value DOUBLE PRECISION -- Per set; NULL without a baseline to scale
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_training_plans_active_user_id ON training_plans(user_id) WHERE superseded_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_training_sessions_plan_id ON training_sessions(plan_id, scheduled_date);
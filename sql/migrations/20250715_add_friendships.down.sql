@@ -0,0 +1,11 @@
+-- +migrate Down
+-- Remove friendships and friends-only workout visibility
+
+DROP TRIGGER IF EXISTS sync_workouts_visibility ON workouts;
+DROP FUNCTION IF EXISTS sync_workouts_visibility();
+
+DROP INDEX IF EXISTS idx_workouts_visible_to_friends;
+
+ALTER TABLE workouts DROP COLUMN IF EXISTS visibility;
+
+DROP TABLE IF EXISTS friendships;
@@ -0,0 +1,50 @@
+-- +migrate Up
+-- Friendships between users, and a friends-only workout visibility beside is_public
+
+CREATE TABLE IF NOT EXISTS friendships (
+    id SERIAL PRIMARY KEY,
+    requester_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE, -- Sent the request, or blocked the addressee
+    addressee_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    status VARCHAR(16) NOT NULL CHECK (status IN ('pending', 'accepted', 'blocked')),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK (requester_id <> addressee_id)
+);
+
+-- A pair of users has one relationship, whichever of them started it
+CREATE UNIQUE INDEX IF NOT EXISTS idx_friendships_pair
+  ON friendships(LEAST(requester_id, addressee_id), GREATEST(requester_id, addressee_id));
+CREATE INDEX IF NOT EXISTS idx_friendships_addressee_id ON friendships(addressee_id);
+
+ALTER TABLE workouts ADD COLUMN visibility VARCHAR(16);
+
+UPDATE workouts SET visibility = CASE WHEN is_public THEN 'public' ELSE 'private' END;
+
+ALTER TABLE workouts
+  ALTER COLUMN visibility SET NOT NULL,
+  ADD CONSTRAINT workouts_visibility_check CHECK (visibility IN ('private', 'friends', 'public'));
+
+-- Keep is_public, which public leaderboards and older writers use, in step with visibility.
+-- Writers that only set is_public get the matching public or private visibility.
+CREATE OR REPLACE FUNCTION sync_workouts_visibility()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        IF NEW.visibility IS NULL THEN
+            NEW.visibility = CASE WHEN NEW.is_public THEN 'public' ELSE 'private' END;
+        END IF;
+    ELSIF NEW.visibility IS NOT DISTINCT FROM OLD.visibility AND NEW.is_public IS DISTINCT FROM OLD.is_public THEN
+        NEW.visibility = CASE WHEN NEW.is_public THEN 'public' ELSE 'private' END;
+    END IF;
+    NEW.is_public = NEW.visibility = 'public';
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS sync_workouts_visibility ON workouts;
+CREATE TRIGGER sync_workouts_visibility
+BEFORE INSERT OR UPDATE ON workouts
+FOR EACH ROW
+EXECUTE FUNCTION sync_workouts_visibility();
+
+CREATE INDEX IF NOT EXISTS idx_workouts_visible_to_friends ON workouts(user_id) WHERE visibility IN ('public', 'friends');
@@ -0,0 +1,7 @@
+-- +migrate Down
+-- Remove the activity feed
+
+DROP TABLE IF EXISTS activity_comments;
+DROP TABLE IF EXISTS activity_reactions;
+DROP TABLE IF EXISTS feed_entries;
+DROP TABLE IF EXISTS activity_events;
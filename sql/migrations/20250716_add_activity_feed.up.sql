@@ -0,0 +1,40 @@
+-- +migrate Up
+-- Activity feed: events fanned out to the feeds of the actor and their friends, with reactions and comments
+
+CREATE TABLE IF NOT EXISTS activity_events (
+    id SERIAL PRIMARY KEY,
+    actor_user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    event_type VARCHAR(32) NOT NULL CHECK (event_type IN ('workout', 'personal_record', 'challenge_result')),
+    source_id INT NOT NULL, -- Workout, personal record or challenge, per event type
+    workout_id INT REFERENCES workouts(id) ON DELETE CASCADE, -- Events tied to a workout follow its visibility
+    payload JSONB NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (event_type, source_id, actor_user_id)
+);
+
+CREATE TABLE IF NOT EXISTS feed_entries (
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    event_id INT NOT NULL REFERENCES activity_events(id) ON DELETE CASCADE,
+    PRIMARY KEY (user_id, event_id)
+);
+
+CREATE TABLE IF NOT EXISTS activity_reactions (
+    event_id INT NOT NULL REFERENCES activity_events(id) ON DELETE CASCADE,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    reaction VARCHAR(16) NOT NULL CHECK (reaction IN ('like', 'fire', 'strong', 'salute')),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (event_id, user_id)
+);
+
+CREATE TABLE IF NOT EXISTS activity_comments (
+    id SERIAL PRIMARY KEY,
+    event_id INT NOT NULL REFERENCES activity_events(id) ON DELETE CASCADE,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    body TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Feeds are read newest first from the fan-out entries
+CREATE INDEX IF NOT EXISTS idx_feed_entries_user_event ON feed_entries(user_id, event_id DESC);
+CREATE INDEX IF NOT EXISTS idx_activity_events_workout_id ON activity_events(workout_id) WHERE workout_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_activity_comments_event_id ON activity_comments(event_id, id);
@@ -0,0 +1,9 @@
+-- +migrate Down
+-- Remove achievements and their activity events
+
+DELETE FROM activity_events WHERE event_type = 'achievement';
+ALTER TABLE activity_events DROP CONSTRAINT IF EXISTS activity_events_event_type_check;
+ALTER TABLE activity_events ADD CONSTRAINT activity_events_event_type_check
+    CHECK (event_type IN ('workout', 'personal_record', 'challenge_result'));
+
+DROP TABLE IF EXISTS user_achievements;
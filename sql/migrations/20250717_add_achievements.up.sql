@@ -0,0 +1,15 @@
+-- +migrate Up
+-- Achievements: badges awarded to users, and achievement events in the activity feed
+
+CREATE TABLE IF NOT EXISTS user_achievements (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    badge_id VARCHAR(64) NOT NULL, -- Declared by the workouts service
+    workout_id INT REFERENCES workouts(id) ON DELETE SET NULL, -- Logged workout that earned the badge; NULL when backfilled
+    earned_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (user_id, badge_id)
+);
+
+ALTER TABLE activity_events DROP CONSTRAINT IF EXISTS activity_events_event_type_check;
+ALTER TABLE activity_events ADD CONSTRAINT activity_events_event_type_check
+    CHECK (event_type IN ('workout', 'personal_record', 'challenge_result', 'achievement'));
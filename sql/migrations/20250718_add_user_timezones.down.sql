@@ -0,0 +1,4 @@
+-- +migrate Down
+-- Remove per-user timezones
+
+ALTER TABLE users DROP COLUMN IF EXISTS timezone;
@@ -0,0 +1,4 @@
+-- +migrate Up
+-- Per-user IANA timezone for local calendar days (streaks, daily boundaries)
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS timezone VARCHAR(64) NOT NULL DEFAULT 'UTC';
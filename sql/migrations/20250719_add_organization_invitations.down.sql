@@ -0,0 +1,6 @@
+-- +migrate Down
+-- Remove organization invitations; pending invitations are dropped rather than granted
+
+DELETE FROM organization_memberships WHERE status = 'pending';
+
+ALTER TABLE organization_memberships DROP COLUMN IF EXISTS status;
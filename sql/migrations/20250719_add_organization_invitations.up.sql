@@ -0,0 +1,9 @@
+-- +migrate Up
+-- Organization memberships start as invitations the invited user must accept
+
+ALTER TABLE organization_memberships
+  ADD COLUMN IF NOT EXISTS status VARCHAR(16) NOT NULL DEFAULT 'accepted'
+  CHECK (status IN ('pending', 'accepted'));
+
+-- Existing memberships stay accepted; new ones are invitations unless created as accepted
+ALTER TABLE organization_memberships ALTER COLUMN status SET DEFAULT 'pending';
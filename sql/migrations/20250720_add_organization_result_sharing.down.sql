@@ -0,0 +1,4 @@
+-- +migrate Down
+-- Remove test result sharing from organization memberships
+
+ALTER TABLE organization_memberships DROP COLUMN IF EXISTS share_test_results;
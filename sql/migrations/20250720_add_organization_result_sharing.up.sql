@@ -0,0 +1,4 @@
+-- +migrate Up
+-- Members choose whether their private test results are shared with their units' leaders
+
+ALTER TABLE organization_memberships ADD COLUMN IF NOT EXISTS share_test_results BOOLEAN NOT NULL DEFAULT false;
@@ -0,0 +1,6 @@
+-- +migrate Down
+-- Remove the USMC PFT 3-mile run
+
+DELETE FROM workouts WHERE exercise_type = 'run_3mile';
+
+DELETE FROM exercises WHERE type = 'run_3mile';
@@ -0,0 +1,6 @@
+-- +migrate Up
+-- Add the USMC PFT 3-mile run, which is scored on its own tables rather than the 2-mile 'running' exercise
+
+INSERT INTO exercises (name, description, type)
+SELECT '3-Mile Run', 'USMC PFT 3-mile run, scored on time', 'run_3mile'
+WHERE NOT EXISTS (SELECT 1 FROM exercises e WHERE e.type = 'run_3mile');
@@ -55,6 +55,7 @@ JOIN
     exercises e ON w.exercise_id = e.id
 WHERE
     w.user_id = $1
+    AND w.deleted_at IS NULL
 ORDER BY
     w.created_at DESC
 LIMIT $2
@@ -64,7 +65,7 @@ OFFSET $3;
 SELECT w.*, e.name as exercise_name, e.type as exercise_type
 FROM workouts w
 JOIN exercises e ON e.id = w.exercise_id
-WHERE w.user_id = $1 AND e.type = $2
+WHERE w.user_id = $1 AND e.type = $2 AND w.deleted_at IS NULL
 ORDER BY w.created_at DESC;
 
 -- name: GetLeaderboard :many
@@ -84,6 +85,7 @@ WHERE
     e.type = $1
     AND w.grade IS NOT NULL
     AND w.is_public = true
+    AND w.deleted_at IS NULL
 GROUP BY 
     u.id, u.username, u.first_name, u.last_name
 ORDER BY 
@@ -3,16 +3,19 @@ SELECT
     u.username,
     CONCAT(u.first_name, ' ', u.last_name) as display_name,
     e.type as exercise_type,
-    MAX(w.grade) as best_grade -- Get the best grade for this exercise type per user
+    MAX(COALESCE(w.expected_grade, w.grade)) as best_grade -- Get the best grade for this exercise type per user
 FROM workouts w
 JOIN users u ON w.user_id = u.id
 JOIN exercises e ON w.exercise_id = e.id
 WHERE e.type = $1
+AND w.scoring_standard = $2 -- Grades under other standards are not comparable
 AND w.grade IS NOT NULL
 AND u.is_public = true
+AND w.verification_status <> 'mismatched'
+AND w.deleted_at IS NULL
 GROUP BY u.id, e.type -- Group by user to find their best score for this exercise
 ORDER BY best_grade DESC
-LIMIT $2; -- Limit the number of results (e.g., top 10, 20) 
+LIMIT $3; -- Limit the number of results (e.g., top 10, 20) 
 
 -- name: GetLocalLeaderboard :many
 SELECT
@@ -40,6 +43,8 @@ WHERE
         $4
     )
     AND w.is_public = true
+    AND w.verification_status <> 'mismatched'
+    AND w.deleted_at IS NULL
     AND u.is_public = true
 GROUP BY u.id, u.username, u.first_name, u.last_name, w.exercise_id
 ORDER BY score DESC
@@ -50,12 +55,15 @@ SELECT
     u.id as user_id,
     u.username,
     CONCAT(u.first_name, ' ', u.last_name) as display_name,
-    MAX(w.grade) as score
+    MAX(COALESCE(w.expected_grade, w.grade)) as score
 FROM workouts w
 JOIN users u ON w.user_id = u.id
 JOIN exercises e ON w.exercise_id = e.id
 WHERE e.type = @type
+  AND w.scoring_standard = @scoring_standard -- Grades under other standards are not comparable
   AND w.is_public = true
+  AND w.verification_status <> 'mismatched'
+  AND w.deleted_at IS NULL
   AND (sqlc.narg('start_date')::timestamptz IS NULL OR w.completed_at >= sqlc.narg('start_date')::timestamptz)
   AND (sqlc.narg('end_date')::timestamptz IS NULL OR w.completed_at < sqlc.narg('end_date')::timestamptz)
 GROUP BY u.id, u.username, u.first_name, u.last_name
@@ -67,12 +75,15 @@ WITH user_best_scores AS (
     SELECT 
         u.id as user_id,
         e.type as exercise_type,
-        MAX(w.grade) as best_score
+        MAX(COALESCE(w.expected_grade, w.grade)) as best_score
     FROM workouts w
     JOIN users u ON w.user_id = u.id
     JOIN exercises e ON w.exercise_id = e.id
     WHERE w.is_public = true
+      AND w.verification_status <> 'mismatched'
+      AND w.deleted_at IS NULL
       AND e.type IN ('pushup','situp','pullup','running')              -- NEW: limit to 4 core types
+      AND w.scoring_standard = 'apft-2013'                             -- Grades under other standards are not comparable
       AND (sqlc.narg('start_date')::timestamptz IS NULL OR w.completed_at >= sqlc.narg('start_date')::timestamptz)
       AND (sqlc.narg('end_date')::timestamptz IS NULL OR w.completed_at < sqlc.narg('end_date')::timestamptz)
     GROUP BY u.id, e.type
@@ -94,14 +105,17 @@ SELECT
     u.id as user_id,
     u.username,
     CONCAT(u.first_name, ' ', u.last_name) as display_name,
-    MAX(w.grade) as score,
+    MAX(COALESCE(w.expected_grade, w.grade)) as score,
     ST_Distance(u.last_location::geography, ST_MakePoint(@longitude, @latitude)::geography) as distance_meters
 FROM workouts w
 JOIN users u ON w.user_id = u.id
 JOIN exercises e ON w.exercise_id = e.id
 WHERE 
     e.type = @type 
+    AND w.scoring_standard = @scoring_standard -- Grades under other standards are not comparable
     AND w.is_public = true
+    AND w.verification_status <> 'mismatched'
+    AND w.deleted_at IS NULL
     AND ST_DWithin(
         u.last_location::geography,
         ST_MakePoint(@longitude, @latitude)::geography, -- longitude, then latitude for ST_MakePoint
@@ -118,13 +132,16 @@ WITH user_best_scores AS (
     SELECT 
         u.id as user_id,
         e.type as exercise_type,
-        MAX(w.grade) as best_score
+        MAX(COALESCE(w.expected_grade, w.grade)) as best_score
     FROM workouts w
     JOIN users u ON w.user_id = u.id
     JOIN exercises e ON w.exercise_id = e.id
     WHERE 
         w.is_public = true
+        AND w.verification_status <> 'mismatched'
+        AND w.deleted_at IS NULL
         AND e.type IN ('pushup','situp','pullup','running')              -- NEW: limit to 4 core types
+        AND w.scoring_standard = 'apft-2013'                             -- Grades under other standards are not comparable
         AND ST_DWithin(
             u.last_location::geography,
             ST_MakePoint(@longitude, @latitude)::geography, -- longitude, then latitude for ST_MakePoint
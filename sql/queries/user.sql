@@ -33,6 +33,7 @@ SET
   longitude = $8,
   gender = $9,
   date_of_birth = $10,
+  timezone = $11,
   updated_at = now()
 WHERE id = $1
 RETURNING *;
@@ -30,17 +30,17 @@ SELECT
     w.completed_at
 FROM workouts w
 JOIN exercises e ON w.exercise_id = e.id
-WHERE w.user_id = $1
+WHERE w.user_id = $1 AND w.deleted_at IS NULL
 ORDER BY w.completed_at DESC
 LIMIT $2 OFFSET $3;
 
 -- name: GetUserWorkoutsCount :one
-SELECT COUNT(*) FROM workouts WHERE user_id = $1;
+SELECT COUNT(*) FROM workouts WHERE user_id = $1 AND deleted_at IS NULL;
 
 -- name: UpdateWorkoutVisibility :exec
 UPDATE workouts
 SET is_public = $1
-WHERE id = $2 AND user_id = $3;
+WHERE id = $2 AND user_id = $3 AND deleted_at IS NULL;
 
 -- name: GetWorkoutRecordByID :one
-SELECT * FROM workouts WHERE id = $1 LIMIT 1; 
\ No newline at end of file
+SELECT * FROM workouts WHERE id = $1 AND deleted_at IS NULL LIMIT 1; 
\ No newline at end of file
@@ -16,6 +16,7 @@ CREATE TABLE IF NOT EXISTS users (
     latitude NUMERIC,
     longitude NUMERIC,
     last_location GEOGRAPHY, -- Use proper GEOGRAPHY type
+    timezone VARCHAR(64) NOT NULL DEFAULT 'UTC', -- IANA name; local calendar days are in this zone
     tokens_invalidated_at TIMESTAMP WITH TIME ZONE,
     last_synced_at TIMESTAMP WITH TIME ZONE DEFAULT now(),
     created_at TIMESTAMP WITH TIME ZONE DEFAULT now(),
@@ -52,6 +53,21 @@ CREATE TABLE IF NOT EXISTS user_exercises (
     UNIQUE(user_id, exercise_id, created_at)
 );
 
+-- Create test_sessions table (one full PT test taken on one day)
+CREATE TABLE IF NOT EXISTS test_sessions (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    scoring_standard VARCHAR(50) NOT NULL,
+    scoring_version VARCHAR(20) NOT NULL,
+    test_date DATE NOT NULL,
+    started_at TIMESTAMPTZ NOT NULL,
+    completed_at TIMESTAMPTZ NOT NULL,
+    total_score INT NOT NULL CHECK (total_score >= 0),
+    passed BOOLEAN NOT NULL,
+    is_public BOOLEAN NOT NULL DEFAULT false,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
 -- Create workouts table
 CREATE TABLE IF NOT EXISTS workouts (
     id SERIAL PRIMARY KEY,
@@ -67,7 +83,181 @@ CREATE TABLE IF NOT EXISTS workouts (
     created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
     device_id VARCHAR(255),
     metadata JSONB,
-    notes TEXT
+    notes TEXT,
+    verification_status VARCHAR(20) NOT NULL DEFAULT 'unverified' CHECK (verification_status IN ('unverified', 'verified', 'mismatched')),
+    expected_grade INT,
+    grade_discrepancy INT,
+    replay_reps INT,
+    replay_form_score INT NULL CHECK (replay_form_score >= 0 AND replay_form_score <= 100),
+    replayed_at TIMESTAMPTZ,
+    scoring_standard VARCHAR(50),
+    scoring_version VARCHAR(20),
+    weight_lbs INT CHECK (weight_lbs > 0),
+    distance_meters NUMERIC(5,2) CHECK (distance_meters > 0),
+    test_session_id INT REFERENCES test_sessions(id) ON DELETE CASCADE,
+    test_event_order INT,
+    client_id VARCHAR(64),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    version INT NOT NULL DEFAULT 1,
+    deleted_at TIMESTAMPTZ,
+    visibility VARCHAR(16) NOT NULL CHECK (visibility IN ('private', 'friends', 'public'))
+);
+
+CREATE TABLE IF NOT EXISTS organizations (
+    id SERIAL PRIMARY KEY,
+    parent_id INT REFERENCES organizations(id) ON DELETE RESTRICT,
+    name VARCHAR(255) NOT NULL,
+    type VARCHAR(16) NOT NULL CHECK (type IN ('battalion', 'company', 'platoon', 'squad')),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS organization_memberships (
+    organization_id INT NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role VARCHAR(16) NOT NULL CHECK (role IN ('member', 'leader', 'admin')),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    status VARCHAR(16) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'accepted')),
+    share_test_results BOOLEAN NOT NULL DEFAULT false,
+    PRIMARY KEY (organization_id, user_id)
+);
+
+CREATE TABLE IF NOT EXISTS workout_audit_log (
+    id SERIAL PRIMARY KEY,
+    workout_id INT NOT NULL,
+    actor_user_id INT NOT NULL,
+    action VARCHAR(16) NOT NULL CHECK (action IN ('update', 'delete')),
+    workout_version INT NOT NULL,
+    changes JSONB NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS challenges (
+    id SERIAL PRIMARY KEY,
+    creator_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    description TEXT,
+    exercise_id INT NOT NULL REFERENCES exercises(id),
+    scoring_rule VARCHAR(16) NOT NULL CHECK (scoring_rule IN ('max_reps', 'total_volume', 'best_time')),
+    participant_type VARCHAR(16) NOT NULL CHECK (participant_type IN ('individual', 'team')),
+    starts_at TIMESTAMPTZ NOT NULL,
+    ends_at TIMESTAMPTZ NOT NULL,
+    finalized_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK (ends_at > starts_at)
+);
+
+CREATE TABLE IF NOT EXISTS challenge_participants (
+    id SERIAL PRIMARY KEY,
+    challenge_id INT NOT NULL REFERENCES challenges(id) ON DELETE CASCADE,
+    user_id INT REFERENCES users(id) ON DELETE CASCADE,
+    organization_id INT REFERENCES organizations(id) ON DELETE CASCADE,
+    joined_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    final_rank INT,
+    final_score DOUBLE PRECISION,
+    final_contributors INT,
+    CHECK ((user_id IS NULL) <> (organization_id IS NULL)),
+    UNIQUE (challenge_id, user_id),
+    UNIQUE (challenge_id, organization_id)
+);
+
+CREATE TABLE IF NOT EXISTS personal_records (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    exercise_type VARCHAR(50) NOT NULL,
+    metric VARCHAR(32) NOT NULL,
+    value DOUBLE PRECISION NOT NULL,
+    previous_value DOUBLE PRECISION,
+    workout_id INT NOT NULL REFERENCES workouts(id) ON DELETE CASCADE,
+    achieved_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS goals (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    goal_type VARCHAR(16) NOT NULL CHECK (goal_type IN ('exercise', 'total_score')),
+    exercise_type VARCHAR(50),
+    scoring_standard VARCHAR(50) NOT NULL,
+    target_value DOUBLE PRECISION NOT NULL CHECK (target_value > 0),
+    target_date DATE NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK ((goal_type = 'exercise') = (exercise_type IS NOT NULL))
+);
+
+CREATE TABLE IF NOT EXISTS training_plans (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    scoring_standard VARCHAR(50) NOT NULL,
+    test_session_id INT REFERENCES test_sessions(id) ON DELETE SET NULL,
+    start_date DATE NOT NULL,
+    weeks INT NOT NULL CHECK (weeks > 0),
+    events JSONB NOT NULL,
+    superseded_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS training_sessions (
+    id SERIAL PRIMARY KEY,
+    plan_id INT NOT NULL REFERENCES training_plans(id) ON DELETE CASCADE,
+    scheduled_date DATE NOT NULL,
+    week INT NOT NULL CHECK (week > 0),
+    event VARCHAR(50) NOT NULL,
+    focus VARCHAR(16) NOT NULL CHECK (focus IN ('improve', 'maintain', 'baseline')),
+    sets INT NOT NULL CHECK (sets > 0),
+    target_value DOUBLE PRECISION
+);
+
+CREATE TABLE IF NOT EXISTS friendships (
+    id SERIAL PRIMARY KEY,
+    requester_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    addressee_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    status VARCHAR(16) NOT NULL CHECK (status IN ('pending', 'accepted', 'blocked')),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK (requester_id <> addressee_id)
+);
+
+CREATE TABLE IF NOT EXISTS activity_events (
+    id SERIAL PRIMARY KEY,
+    actor_user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    event_type VARCHAR(32) NOT NULL CHECK (event_type IN ('workout', 'personal_record', 'challenge_result', 'achievement')),
+    source_id INT NOT NULL,
+    workout_id INT REFERENCES workouts(id) ON DELETE CASCADE,
+    payload JSONB NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (event_type, source_id, actor_user_id)
+);
+
+CREATE TABLE IF NOT EXISTS feed_entries (
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    event_id INT NOT NULL REFERENCES activity_events(id) ON DELETE CASCADE,
+    PRIMARY KEY (user_id, event_id)
+);
+
+CREATE TABLE IF NOT EXISTS activity_reactions (
+    event_id INT NOT NULL REFERENCES activity_events(id) ON DELETE CASCADE,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    reaction VARCHAR(16) NOT NULL CHECK (reaction IN ('like', 'fire', 'strong', 'salute')),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (event_id, user_id)
+);
+
+CREATE TABLE IF NOT EXISTS activity_comments (
+    id SERIAL PRIMARY KEY,
+    event_id INT NOT NULL REFERENCES activity_events(id) ON DELETE CASCADE,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    body TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS user_achievements (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    badge_id VARCHAR(64) NOT NULL,
+    workout_id INT REFERENCES workouts(id) ON DELETE SET NULL,
+    earned_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (user_id, badge_id)
 );
 
 -- Create indexes for better performance
@@ -81,5 +271,35 @@ CREATE INDEX IF NOT EXISTS idx_workouts_completed_at ON workouts(completed_at);
 CREATE INDEX IF NOT EXISTS idx_workouts_is_public ON workouts(is_public) WHERE is_public = true;
 CREATE INDEX IF NOT EXISTS idx_workouts_user_id_completed_at ON workouts(user_id, completed_at DESC);
 CREATE INDEX IF NOT EXISTS idx_workouts_exercise_type ON workouts(exercise_type);
+CREATE INDEX IF NOT EXISTS idx_workouts_mismatched ON workouts(created_at DESC) WHERE verification_status = 'mismatched';
+CREATE UNIQUE INDEX IF NOT EXISTS idx_workouts_user_device_client ON workouts(user_id, device_id, client_id) WHERE client_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_workouts_user_id_updated_at ON workouts(user_id, updated_at);
+CREATE INDEX IF NOT EXISTS idx_workouts_test_session_id ON workouts(test_session_id) WHERE test_session_id IS NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_test_sessions_user_id_test_date ON test_sessions(user_id, test_date DESC);
+
+CREATE INDEX IF NOT EXISTS idx_workout_audit_log_workout_id ON workout_audit_log(workout_id, id);
+
+CREATE INDEX IF NOT EXISTS idx_organizations_parent_id ON organizations(parent_id);
+CREATE INDEX IF NOT EXISTS idx_organization_memberships_user_id ON organization_memberships(user_id);
+
+CREATE INDEX IF NOT EXISTS idx_challenges_ends_at ON challenges(ends_at);
+CREATE INDEX IF NOT EXISTS idx_challenge_participants_user_id ON challenge_participants(user_id);
+CREATE INDEX IF NOT EXISTS idx_challenge_participants_organization_id ON challenge_participants(organization_id);
+
+CREATE INDEX IF NOT EXISTS idx_personal_records_user_type_metric ON personal_records(user_id, exercise_type, metric, id DESC);
+
+CREATE INDEX IF NOT EXISTS idx_goals_user_id ON goals(user_id, target_date);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_training_plans_active_user_id ON training_plans(user_id) WHERE superseded_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_training_sessions_plan_id ON training_sessions(plan_id, scheduled_date);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_friendships_pair ON friendships(LEAST(requester_id, addressee_id), GREATEST(requester_id, addressee_id));
+CREATE INDEX IF NOT EXISTS idx_friendships_addressee_id ON friendships(addressee_id);
+CREATE INDEX IF NOT EXISTS idx_workouts_visible_to_friends ON workouts(user_id) WHERE visibility IN ('public', 'friends');
+
+CREATE INDEX IF NOT EXISTS idx_feed_entries_user_event ON feed_entries(user_id, event_id DESC);
+CREATE INDEX IF NOT EXISTS idx_activity_events_workout_id ON activity_events(workout_id) WHERE workout_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_activity_comments_event_id ON activity_comments(event_id, id);
 
 CREATE INDEX IF NOT EXISTS idx_users_last_location ON users USING GIST (last_location); 
\ No newline at end of file